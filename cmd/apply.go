@@ -0,0 +1,87 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: BSD-2-Clause
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/spec"
+)
+
+var (
+	ApplySpecFile string
+	ApplyYes      = false
+	ApplyDryRun   = false
+)
+
+func init() {
+	ProductCmd.AddCommand(ApplyCmd)
+
+	ApplyCmd.Flags().StringVarP(&ApplySpecFile, "file", "f", "", "Product spec file (required)")
+	_ = ApplyCmd.MarkFlagRequired("file")
+	ApplyCmd.Flags().BoolVarP(&ApplyYes, "yes", "y", false, "Apply the changes without asking for confirmation")
+	ApplyCmd.Flags().BoolVar(&ApplyDryRun, "dry-run", false, "Only show the changes, without applying them")
+	ApplyCmd.Flags().BoolVar(&SkipPermissionCheck, "skip-permission-check", false, "Do not check that you can modify the product before uploading files")
+}
+
+// confirm asks on stderr, and reads the answer from stdin
+func confirm(cmd *cobra.Command, question string) bool {
+	cmd.PrintErrf("%s [y/N] ", question)
+	answer, _ := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+var ApplyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Make a product match a spec",
+	Long: "Compares a product with the product details, versions and assets described in a spec file, shows what would change, " +
+		"and after confirmation, updates the details, creates the versions and attaches the assets that are missing.\n" +
+		"Assets that are attached but not in the spec are left alone",
+	Example: fmt.Sprintf(`%[1]s product apply -f product.yaml --dry-run
+%[1]s product apply -f product.yaml
+%[1]s product apply -f product.yaml --yes`, AppName),
+	Args:    cobra.NoArgs,
+	PreRunE: RunSerially(GetRefreshToken, ReadFreshProducts),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		productSpec, err := spec.Load(ApplySpecFile)
+		if err != nil {
+			return err
+		}
+
+		plan, err := spec.MakePlan(cmd.Context(), Marketplace, productSpec)
+		if err != nil {
+			return err
+		}
+
+		Output.PrintHeader(fmt.Sprintf("Plan for %s:", plan.Product))
+		err = Output.RenderApplyPlan(plan)
+		if err != nil {
+			return err
+		}
+		if !plan.HasChanges() || ApplyDryRun {
+			return nil
+		}
+
+		err = CheckPermission(plan.Current)
+		if err != nil {
+			return err
+		}
+
+		if !ApplyYes && !confirm(cmd, "Apply these changes?") {
+			cmd.PrintErrln("Nothing was changed")
+			return nil
+		}
+
+		product, err := plan.Apply(cmd.Context(), Marketplace, cmd.ErrOrStderr())
+		if err != nil {
+			return err
+		}
+		return Output.RenderProduct(product, product.GetLatestVersion())
+	},
+}
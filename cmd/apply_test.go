@@ -0,0 +1,131 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: BSD-2-Clause
+
+package cmd_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gbytes"
+	"github.com/vmware-labs/marketplace-cli/v2/cmd"
+	"github.com/vmware-labs/marketplace-cli/v2/cmd/output/outputfakes"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/models"
+	"github.com/vmware-labs/marketplace-cli/v2/pkg/pkgfakes"
+	"github.com/vmware-labs/marketplace-cli/v2/test"
+)
+
+var _ = Describe("ApplyCmd", func() {
+	var (
+		dir         string
+		stderr      *Buffer
+		product     *models.Product
+		marketplace *pkgfakes.FakeMarketplaceInterface
+		output      *outputfakes.FakeFormat
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "mkpcli-apply-test")
+		Expect(err).ToNot(HaveOccurred())
+		cmd.ApplySpecFile = filepath.Join(dir, "product.yaml")
+		Expect(os.WriteFile(cmd.ApplySpecFile, []byte("product: my-super-product\nsummary: The new summary\n"), 0600)).To(Succeed())
+
+		product = test.CreateFakeProduct("", "My Super Product", "my-super-product", models.SolutionTypeOthers)
+		product.Description = &models.Description{Summary: "The old summary"}
+		test.AddVerions(product, "1.2.3")
+
+		marketplace = &pkgfakes.FakeMarketplaceInterface{}
+		marketplace.GetProductReturns(product, nil)
+		marketplace.PutProductStub = func(ctx context.Context, product *models.Product, versionUpdate bool) (*models.Product, error) {
+			return product, nil
+		}
+		cmd.Marketplace = marketplace
+
+		output = &outputfakes.FakeFormat{}
+		cmd.Output = output
+
+		stderr = NewBuffer()
+		cmd.ApplyCmd.SetErr(stderr)
+		cmd.ApplyCmd.SetContext(context.Background())
+		cmd.AuthenticatedClaims = nil
+	})
+
+	AfterEach(func() {
+		cmd.ApplyYes = false
+		cmd.ApplyDryRun = false
+		cmd.ApplyCmd.SetIn(os.Stdin)
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	It("shows the plan and applies it when confirmed", func() {
+		cmd.ApplyCmd.SetIn(strings.NewReader("yes\n"))
+		err := cmd.ApplyCmd.RunE(cmd.ApplyCmd, []string{})
+		Expect(err).ToNot(HaveOccurred())
+
+		By("showing the plan", func() {
+			Expect(output.PrintHeaderArgsForCall(0)).To(Equal("Plan for my-super-product:"))
+			Expect(output.RenderApplyPlanCallCount()).To(Equal(1))
+			plan := output.RenderApplyPlanArgsForCall(0)
+			Expect(plan.Changes).To(HaveLen(1))
+		})
+
+		By("asking for confirmation", func() {
+			Expect(stderr).To(Say(`Apply these changes\? \[y/N\]`))
+		})
+
+		By("updating the product", func() {
+			Expect(marketplace.PutProductCallCount()).To(Equal(1))
+			_, updated, _ := marketplace.PutProductArgsForCall(0)
+			Expect(updated.Description.Summary).To(Equal("The new summary"))
+		})
+
+		By("outputting the updated product", func() {
+			Expect(output.RenderProductCallCount()).To(Equal(1))
+		})
+	})
+
+	When("the changes are not confirmed", func() {
+		It("does not change anything", func() {
+			cmd.ApplyCmd.SetIn(strings.NewReader("\n"))
+			err := cmd.ApplyCmd.RunE(cmd.ApplyCmd, []string{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stderr).To(Say("Nothing was changed"))
+			Expect(marketplace.PutProductCallCount()).To(Equal(0))
+		})
+	})
+
+	When("using --yes", func() {
+		It("applies the changes without asking", func() {
+			cmd.ApplyYes = true
+			err := cmd.ApplyCmd.RunE(cmd.ApplyCmd, []string{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stderr).ToNot(Say("Apply these changes"))
+			Expect(marketplace.PutProductCallCount()).To(Equal(1))
+		})
+	})
+
+	When("using --dry-run", func() {
+		It("only shows the plan", func() {
+			cmd.ApplyDryRun = true
+			err := cmd.ApplyCmd.RunE(cmd.ApplyCmd, []string{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(output.RenderApplyPlanCallCount()).To(Equal(1))
+			Expect(marketplace.PutProductCallCount()).To(Equal(0))
+		})
+	})
+
+	When("the product already matches the spec", func() {
+		It("does not ask for confirmation", func() {
+			product.Description.Summary = "The new summary"
+			err := cmd.ApplyCmd.RunE(cmd.ApplyCmd, []string{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stderr).ToNot(Say("Apply these changes"))
+			Expect(marketplace.PutProductCallCount()).To(Equal(0))
+		})
+	})
+})
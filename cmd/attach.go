@@ -41,6 +41,7 @@ var (
 func init() {
 	rootCmd.AddCommand(AttachCmd)
 	AttachCmd.AddCommand(AttachChartCmd, AttachContainerImageCmd, AttachMetaFileCmd, AttachOtherCmd, AttachVMCmd)
+	AttachCmd.PersistentFlags().BoolVar(&SkipPermissionCheck, "skip-permission-check", false, "Do not check that you can modify the product before uploading files")
 
 	AttachChartCmd.Flags().StringVarP(&AttachProductSlug, "product", "p", "", "Product slug (required)")
 	_ = AttachChartCmd.MarkFlagRequired("product")
@@ -106,10 +107,10 @@ var AttachChartCmd = &cobra.Command{
 	Long:    "Attaches a Helm Chart to a product in the VMware Marketplace",
 	Example: fmt.Sprintf("%s attach chart -p hyperspace-database-chart1 -v 1.2.3 --chart hyperspace-db-1.2.3.tgz --instructions \"helm install...\"", AppName),
 	Args:    cobra.NoArgs,
-	PreRunE: GetRefreshToken,
+	PreRunE: RunSerially(GetRefreshToken, ReadFreshProducts),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		cmd.SilenceUsage = true
-		product, version, err := Marketplace.GetProductWithVersion(AttachProductSlug, AttachProductVersion)
+		product, version, err := Marketplace.GetProductWithVersion(cmd.Context(), AttachProductSlug, AttachProductVersion)
 		if err != nil {
 			if errors.Is(err, &pkg.VersionDoesNotExistError{}) && AttachCreateVersion {
 				version = product.NewVersion(AttachProductVersion)
@@ -118,16 +119,21 @@ var AttachChartCmd = &cobra.Command{
 			}
 		}
 
+		err = CheckPermission(product)
+		if err != nil {
+			return err
+		}
+
 		if product.SolutionType != models.SolutionTypeChart {
 			return fmt.Errorf("cannot attach a chart to %s which is of type %s", product.Slug, product.SolutionType)
 		}
 
 		if AttachPCAFile != "" {
-			uploader, err := Marketplace.GetUploader(product.PublisherDetails.OrgId)
+			uploader, err := Marketplace.GetUploader(cmd.Context(), product.PublisherDetails.OrgId)
 			if err != nil {
 				return err
 			}
-			_, pcaUrl, err := uploader.UploadMediaFile(AttachPCAFile)
+			_, pcaUrl, err := uploader.UploadMediaFile(cmd.Context(), AttachPCAFile)
 			if err != nil {
 				return err
 			}
@@ -142,9 +148,9 @@ var AttachChartCmd = &cobra.Command{
 
 		var updatedProduct *models.Product
 		if chartURL.Scheme == "" || chartURL.Scheme == "file" {
-			updatedProduct, err = Marketplace.AttachLocalChart(AttachChartURL, AttachInstructions, product, version)
+			updatedProduct, err = Marketplace.AttachLocalChart(cmd.Context(), AttachChartURL, AttachInstructions, product, version)
 		} else if chartURL.Scheme == "http" || chartURL.Scheme == "https" {
-			updatedProduct, err = Marketplace.AttachPublicChart(chartURL, AttachInstructions, product, version)
+			updatedProduct, err = Marketplace.AttachPublicChart(cmd.Context(), chartURL, AttachInstructions, product, version)
 		} else {
 			return fmt.Errorf("unsupported protocol scheme: %s", chartURL.Scheme)
 		}
@@ -172,11 +178,11 @@ var AttachContainerImageCmd = &cobra.Command{
 	Long:    "Attaches a container image to a product in the VMware Marketplace",
 	Example: fmt.Sprintf("%s attach image -p hyperspace-database-image1 -v 1.2.3 --image hyperspace-labs/hyperspace-db --tag 1.2.3 --tag-type fixed --instructions \"docker run...\"", AppName),
 	Args:    cobra.NoArgs,
-	PreRunE: RunSerially(ValidateTagType, GetRefreshToken),
+	PreRunE: RunSerially(ValidateTagType, GetRefreshToken, ReadFreshProducts),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		cmd.SilenceUsage = true
 
-		product, version, err := Marketplace.GetProductWithVersion(AttachProductSlug, AttachProductVersion)
+		product, version, err := Marketplace.GetProductWithVersion(cmd.Context(), AttachProductSlug, AttachProductVersion)
 		if err != nil {
 			if errors.Is(err, &pkg.VersionDoesNotExistError{}) && AttachCreateVersion {
 				version = product.NewVersion(AttachProductVersion)
@@ -185,16 +191,21 @@ var AttachContainerImageCmd = &cobra.Command{
 			}
 		}
 
+		err = CheckPermission(product)
+		if err != nil {
+			return err
+		}
+
 		if product.SolutionType != models.SolutionTypeImage {
 			return fmt.Errorf("cannot attach an image to %s which is of type %s", product.Slug, product.SolutionType)
 		}
 
 		if AttachPCAFile != "" {
-			uploader, err := Marketplace.GetUploader(product.PublisherDetails.OrgId)
+			uploader, err := Marketplace.GetUploader(cmd.Context(), product.PublisherDetails.OrgId)
 			if err != nil {
 				return err
 			}
-			_, pcaUrl, err := uploader.UploadMediaFile(AttachPCAFile)
+			_, pcaUrl, err := uploader.UploadMediaFile(cmd.Context(), AttachPCAFile)
 			if err != nil {
 				return err
 			}
@@ -204,9 +215,9 @@ var AttachContainerImageCmd = &cobra.Command{
 
 		var updatedProduct *models.Product
 		if AttachContainerImageFile != "" {
-			updatedProduct, err = Marketplace.AttachLocalContainerImage(AttachContainerImageFile, AttachContainerImage, AttachContainerImageTag, AttachContainerImageTagType, AttachInstructions, product, version)
+			updatedProduct, err = Marketplace.AttachLocalContainerImage(cmd.Context(), AttachContainerImageFile, AttachContainerImage, AttachContainerImageTag, AttachContainerImageTagType, AttachInstructions, product, version)
 		} else {
-			updatedProduct, err = Marketplace.AttachPublicContainerImage(AttachContainerImage, AttachContainerImageTag, AttachContainerImageTagType, AttachInstructions, product, version)
+			updatedProduct, err = Marketplace.AttachPublicContainerImage(cmd.Context(), AttachContainerImage, AttachContainerImageTag, AttachContainerImageTagType, AttachInstructions, product, version)
 		}
 		if err != nil {
 			return err
@@ -223,11 +234,11 @@ var AttachOtherCmd = &cobra.Command{
 	Long:    "Upload and attach an other file (.pak, .vlcp, .zip, .tar, .gz, .tgz, .ova, .vmoapp) to a product in the VMware Marketplace",
 	Example: fmt.Sprintf("%s attach other -p hyperspace-database-vm1 -v 1.2.3 --file hyperspace-db-1.2.3.tgz", AppName),
 	Args:    cobra.NoArgs,
-	PreRunE: GetRefreshToken,
+	PreRunE: RunSerially(GetRefreshToken, ReadFreshProducts),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		cmd.SilenceUsage = true
 
-		product, version, err := Marketplace.GetProductWithVersion(AttachProductSlug, AttachProductVersion)
+		product, version, err := Marketplace.GetProductWithVersion(cmd.Context(), AttachProductSlug, AttachProductVersion)
 		if err != nil {
 			if errors.Is(err, &pkg.VersionDoesNotExistError{}) && AttachCreateVersion {
 				version = product.NewVersion(AttachProductVersion)
@@ -236,16 +247,21 @@ var AttachOtherCmd = &cobra.Command{
 			}
 		}
 
+		err = CheckPermission(product)
+		if err != nil {
+			return err
+		}
+
 		if product.SolutionType != models.SolutionTypeOthers {
 			return fmt.Errorf("cannot attach an other file to %s which is of type %s", product.Slug, product.SolutionType)
 		}
 
 		if AttachPCAFile != "" {
-			uploader, err := Marketplace.GetUploader(product.PublisherDetails.OrgId)
+			uploader, err := Marketplace.GetUploader(cmd.Context(), product.PublisherDetails.OrgId)
 			if err != nil {
 				return err
 			}
-			_, pcaUrl, err := uploader.UploadMediaFile(AttachPCAFile)
+			_, pcaUrl, err := uploader.UploadMediaFile(cmd.Context(), AttachPCAFile)
 			if err != nil {
 				return err
 			}
@@ -253,7 +269,7 @@ var AttachOtherCmd = &cobra.Command{
 			product.SetPCAFile(version.Number, pcaUrl)
 		}
 
-		updatedProduct, err := Marketplace.AttachOtherFile(AttachOtherFile, product, version)
+		updatedProduct, err := Marketplace.AttachOtherFile(cmd.Context(), AttachOtherFile, product, version)
 		if err != nil {
 			return err
 		}
@@ -269,11 +285,11 @@ var AttachMetaFileCmd = &cobra.Command{
 	Long:    "Upload and attach a meta file to a product in the VMware Marketplace",
 	Example: fmt.Sprintf("%s attach metafile -p hyperspace-database-vm1 -v 1.2.3 --metafile deploy.sh --metafile-type cli", AppName),
 	Args:    cobra.NoArgs,
-	PreRunE: RunSerially(ValidateMetaFileType, GetRefreshToken),
+	PreRunE: RunSerially(ValidateMetaFileType, GetRefreshToken, ReadFreshProducts),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		cmd.SilenceUsage = true
 
-		product, version, err := Marketplace.GetProductWithVersion(AttachProductSlug, AttachProductVersion)
+		product, version, err := Marketplace.GetProductWithVersion(cmd.Context(), AttachProductSlug, AttachProductVersion)
 		if err != nil {
 			if errors.Is(err, &pkg.VersionDoesNotExistError{}) && AttachCreateVersion {
 				version = product.NewVersion(AttachProductVersion)
@@ -282,10 +298,15 @@ var AttachMetaFileCmd = &cobra.Command{
 			}
 		}
 
+		err = CheckPermission(product)
+		if err != nil {
+			return err
+		}
+
 		if AttachMetaFileVersion == "" {
 			AttachMetaFileVersion = version.Number
 		}
-		updatedProduct, err := Marketplace.AttachMetaFile(AttachMetaFile, metaFileTypeMapping[MetaFileType], AttachMetaFileVersion, product, version)
+		updatedProduct, err := Marketplace.AttachMetaFile(cmd.Context(), AttachMetaFile, metaFileTypeMapping[MetaFileType], AttachMetaFileVersion, product, version)
 		if err != nil {
 			return err
 		}
@@ -301,11 +322,11 @@ var AttachVMCmd = &cobra.Command{
 	Long:    "Upload and attach a virtual machine file (ISO or OVA) to a product in the VMware Marketplace",
 	Example: fmt.Sprintf("%s attach vm -p hyperspace-database-vm1 -v 1.2.3 --file hyperspace-db-1.2.3.iso", AppName),
 	Args:    cobra.NoArgs,
-	PreRunE: GetRefreshToken,
+	PreRunE: RunSerially(GetRefreshToken, ReadFreshProducts),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		cmd.SilenceUsage = true
 
-		product, version, err := Marketplace.GetProductWithVersion(AttachProductSlug, AttachProductVersion)
+		product, version, err := Marketplace.GetProductWithVersion(cmd.Context(), AttachProductSlug, AttachProductVersion)
 		if err != nil {
 			if errors.Is(err, &pkg.VersionDoesNotExistError{}) && AttachCreateVersion {
 				version = product.NewVersion(AttachProductVersion)
@@ -314,16 +335,21 @@ var AttachVMCmd = &cobra.Command{
 			}
 		}
 
+		err = CheckPermission(product)
+		if err != nil {
+			return err
+		}
+
 		if product.SolutionType != models.SolutionTypeISO && product.SolutionType != models.SolutionTypeOVA {
 			return fmt.Errorf("cannot attach a vm to %s which is of type %s", product.Slug, product.SolutionType)
 		}
 
 		if AttachPCAFile != "" {
-			uploader, err := Marketplace.GetUploader(product.PublisherDetails.OrgId)
+			uploader, err := Marketplace.GetUploader(cmd.Context(), product.PublisherDetails.OrgId)
 			if err != nil {
 				return err
 			}
-			_, pcaUrl, err := uploader.UploadMediaFile(AttachPCAFile)
+			_, pcaUrl, err := uploader.UploadMediaFile(cmd.Context(), AttachPCAFile)
 			if err != nil {
 				return err
 			}
@@ -331,7 +357,7 @@ var AttachVMCmd = &cobra.Command{
 			product.SetPCAFile(version.Number, pcaUrl)
 		}
 
-		updatedProduct, err := Marketplace.UploadVM(AttachVMFile, product, version)
+		updatedProduct, err := Marketplace.UploadVM(cmd.Context(), AttachVMFile, product, version)
 		if err != nil {
 			return err
 		}
@@ -10,6 +10,7 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/vmware-labs/marketplace-cli/v2/cmd"
 	"github.com/vmware-labs/marketplace-cli/v2/cmd/output/outputfakes"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/csp"
 	"github.com/vmware-labs/marketplace-cli/v2/internal/internalfakes"
 	"github.com/vmware-labs/marketplace-cli/v2/internal/models"
 	"github.com/vmware-labs/marketplace-cli/v2/pkg"
@@ -59,6 +60,8 @@ var _ = Describe("AttachCmd", func() {
 		cmd.Output = output
 		cmd.AttachCreateVersion = false
 		cmd.AttachPCAFile = ""
+		cmd.AuthenticatedClaims = nil
+		cmd.SkipPermissionCheck = false
 	})
 
 	Describe("AttachChartCmd", func() {
@@ -95,14 +98,14 @@ var _ = Describe("AttachCmd", func() {
 
 				By("getting the product details", func() {
 					Expect(marketplace.GetProductWithVersionCallCount()).To(Equal(1))
-					slug, version := marketplace.GetProductWithVersionArgsForCall(0)
+					_, slug, version := marketplace.GetProductWithVersionArgsForCall(0)
 					Expect(slug).To(Equal("my-super-product"))
 					Expect(version).To(Equal("1.1.1"))
 				})
 
 				By("attaching the local chart", func() {
 					Expect(marketplace.AttachLocalChartCallCount()).To(Equal(1))
-					chartUrl, instructions, product, version := marketplace.AttachLocalChartArgsForCall(0)
+					_, chartUrl, instructions, product, version := marketplace.AttachLocalChartArgsForCall(0)
 					Expect(chartUrl).To(Equal("/path/to/my-chart"))
 					Expect(instructions).To(Equal("helm install it"))
 					Expect(product.Slug).To(Equal("my-super-product"))
@@ -158,11 +161,12 @@ var _ = Describe("AttachCmd", func() {
 					By("uploading the PCA file", func() {
 						Expect(marketplace.GetUploaderCallCount()).To(Equal(1))
 						Expect(uploader.UploadMediaFileCallCount()).To(Equal(1))
-						Expect(uploader.UploadMediaFileArgsForCall(0)).To(Equal("/path/to/pca.pdf"))
+						_, uploadedFile := uploader.UploadMediaFileArgsForCall(0)
+						Expect(uploadedFile).To(Equal("/path/to/pca.pdf"))
 					})
 
 					By("adding the url to the product", func() {
-						_, _, product, _ := marketplace.AttachLocalChartArgsForCall(0)
+						_, _, _, product, _ := marketplace.AttachLocalChartArgsForCall(0)
 						Expect(product.PCADetails).ToNot(BeNil())
 						Expect(product.PCADetails.URL).To(Equal("https://example.com/path/to/pca.pdf"))
 						Expect(product.PCADetails.Version).To(Equal("1.1.1"))
@@ -222,14 +226,14 @@ var _ = Describe("AttachCmd", func() {
 
 				By("getting the product details", func() {
 					Expect(marketplace.GetProductWithVersionCallCount()).To(Equal(1))
-					slug, version := marketplace.GetProductWithVersionArgsForCall(0)
+					_, slug, version := marketplace.GetProductWithVersionArgsForCall(0)
 					Expect(slug).To(Equal("my-super-product"))
 					Expect(version).To(Equal("1.1.1"))
 				})
 
 				By("attaching the local chart", func() {
 					Expect(marketplace.AttachPublicChartCallCount()).To(Equal(1))
-					chartUrl, instructions, product, version := marketplace.AttachPublicChartArgsForCall(0)
+					_, chartUrl, instructions, product, version := marketplace.AttachPublicChartArgsForCall(0)
 					Expect(chartUrl.String()).To(Equal("https://example.com/public/my-chart.tgz"))
 					Expect(instructions).To(Equal("helm install it"))
 					Expect(product.Slug).To(Equal("my-super-product"))
@@ -310,7 +314,7 @@ var _ = Describe("AttachCmd", func() {
 						Expect(err).ToNot(HaveOccurred())
 
 						By("passing a new version to upload vm", func() {
-							_, _, _, version := marketplace.AttachPublicChartArgsForCall(0)
+							_, _, _, _, version := marketplace.AttachPublicChartArgsForCall(0)
 							Expect(version.Number).To(Equal("9.9.9"))
 							Expect(version.IsNewVersion).To(BeTrue())
 						})
@@ -367,11 +371,12 @@ var _ = Describe("AttachCmd", func() {
 					By("uploading the PCA file", func() {
 						Expect(marketplace.GetUploaderCallCount()).To(Equal(1))
 						Expect(uploader.UploadMediaFileCallCount()).To(Equal(1))
-						Expect(uploader.UploadMediaFileArgsForCall(0)).To(Equal("/path/to/pca.pdf"))
+						_, uploadedFile := uploader.UploadMediaFileArgsForCall(0)
+						Expect(uploadedFile).To(Equal("/path/to/pca.pdf"))
 					})
 
 					By("adding the url to the product", func() {
-						_, _, product, _ := marketplace.AttachPublicChartArgsForCall(0)
+						_, _, _, product, _ := marketplace.AttachPublicChartArgsForCall(0)
 						Expect(product.PCADetails).ToNot(BeNil())
 						Expect(product.PCADetails.URL).To(Equal("https://example.com/path/to/pca.pdf"))
 						Expect(product.PCADetails.Version).To(Equal("1.1.1"))
@@ -444,14 +449,14 @@ var _ = Describe("AttachCmd", func() {
 
 			By("getting the product details", func() {
 				Expect(marketplace.GetProductWithVersionCallCount()).To(Equal(1))
-				slug, version := marketplace.GetProductWithVersionArgsForCall(0)
+				_, slug, version := marketplace.GetProductWithVersionArgsForCall(0)
 				Expect(slug).To(Equal("my-super-product"))
 				Expect(version).To(Equal("1.1.1"))
 			})
 
 			By("attaching the container image", func() {
 				Expect(marketplace.AttachPublicContainerImageCallCount()).To(Equal(1))
-				image, tag, tagType, instructions, product, version := marketplace.AttachPublicContainerImageArgsForCall(0)
+				_, image, tag, tagType, instructions, product, version := marketplace.AttachPublicContainerImageArgsForCall(0)
 				Expect(image).To(Equal("bitnami/nginx"))
 				Expect(tag).To(Equal("1.21.6"))
 				Expect(tagType).To(Equal("FIXED"))
@@ -497,11 +502,12 @@ var _ = Describe("AttachCmd", func() {
 				By("uploading the PCA file", func() {
 					Expect(marketplace.GetUploaderCallCount()).To(Equal(1))
 					Expect(uploader.UploadMediaFileCallCount()).To(Equal(1))
-					Expect(uploader.UploadMediaFileArgsForCall(0)).To(Equal("/path/to/pca.pdf"))
+					_, uploadedFile := uploader.UploadMediaFileArgsForCall(0)
+					Expect(uploadedFile).To(Equal("/path/to/pca.pdf"))
 				})
 
 				By("adding the url to the product", func() {
-					_, _, _, _, product, _ := marketplace.AttachPublicContainerImageArgsForCall(0)
+					_, _, _, _, _, product, _ := marketplace.AttachPublicContainerImageArgsForCall(0)
 					Expect(product.PCADetails).ToNot(BeNil())
 					Expect(product.PCADetails.URL).To(Equal("https://example.com/path/to/pca.pdf"))
 					Expect(product.PCADetails.Version).To(Equal("1.1.1"))
@@ -559,7 +565,7 @@ var _ = Describe("AttachCmd", func() {
 
 				By("uploadings and attaching the container image", func() {
 					Expect(marketplace.AttachLocalContainerImageCallCount()).To(Equal(1))
-					imageFile, image, tag, tagType, instructions, product, version := marketplace.AttachLocalContainerImageArgsForCall(0)
+					_, imageFile, image, tag, tagType, instructions, product, version := marketplace.AttachLocalContainerImageArgsForCall(0)
 					Expect(imageFile).To(Equal("/path/tp/image.tar"))
 					Expect(image).To(Equal("bitnami/nginx"))
 					Expect(tag).To(Equal("1.21.6"))
@@ -621,7 +627,7 @@ var _ = Describe("AttachCmd", func() {
 					Expect(err).ToNot(HaveOccurred())
 
 					By("passing a new version to upload vm", func() {
-						_, _, _, _, _, version := marketplace.AttachPublicContainerImageArgsForCall(0)
+						_, _, _, _, _, _, version := marketplace.AttachPublicContainerImageArgsForCall(0)
 						Expect(version.Number).To(Equal("9.9.9"))
 						Expect(version.IsNewVersion).To(BeTrue())
 					})
@@ -706,14 +712,14 @@ var _ = Describe("AttachCmd", func() {
 
 			By("getting the product details", func() {
 				Expect(marketplace.GetProductWithVersionCallCount()).To(Equal(1))
-				slug, version := marketplace.GetProductWithVersionArgsForCall(0)
+				_, slug, version := marketplace.GetProductWithVersionArgsForCall(0)
 				Expect(slug).To(Equal("my-super-product"))
 				Expect(version).To(Equal("1.1.1"))
 			})
 
 			By("uploading the other file", func() {
 				Expect(marketplace.AttachOtherFileCallCount()).To(Equal(1))
-				file, product, version := marketplace.AttachOtherFileArgsForCall(0)
+				_, file, product, version := marketplace.AttachOtherFileArgsForCall(0)
 				Expect(file).To(Equal("path/to/a/file.tgz"))
 				Expect(product.Slug).To(Equal("my-super-product"))
 				Expect(version.Number).To(Equal("1.1.1"))
@@ -750,11 +756,12 @@ var _ = Describe("AttachCmd", func() {
 				By("uploading the PCA file", func() {
 					Expect(marketplace.GetUploaderCallCount()).To(Equal(1))
 					Expect(uploader.UploadMediaFileCallCount()).To(Equal(1))
-					Expect(uploader.UploadMediaFileArgsForCall(0)).To(Equal("/path/to/pca.pdf"))
+					_, uploadedFile := uploader.UploadMediaFileArgsForCall(0)
+					Expect(uploadedFile).To(Equal("/path/to/pca.pdf"))
 				})
 
 				By("adding the url to the product", func() {
-					_, product, _ := marketplace.AttachOtherFileArgsForCall(0)
+					_, _, product, _ := marketplace.AttachOtherFileArgsForCall(0)
 					Expect(product.PCADetails).ToNot(BeNil())
 					Expect(product.PCADetails.URL).To(Equal("https://example.com/path/to/pca.pdf"))
 					Expect(product.PCADetails.Version).To(Equal("1.1.1"))
@@ -832,7 +839,7 @@ var _ = Describe("AttachCmd", func() {
 					Expect(err).ToNot(HaveOccurred())
 
 					By("passing a new version to attach other file", func() {
-						_, _, version := marketplace.AttachOtherFileArgsForCall(0)
+						_, _, _, version := marketplace.AttachOtherFileArgsForCall(0)
 						Expect(version.Number).To(Equal("9.9.9"))
 						Expect(version.IsNewVersion).To(BeTrue())
 					})
@@ -908,14 +915,14 @@ var _ = Describe("AttachCmd", func() {
 
 			By("getting the product details", func() {
 				Expect(marketplace.GetProductWithVersionCallCount()).To(Equal(1))
-				slug, version := marketplace.GetProductWithVersionArgsForCall(0)
+				_, slug, version := marketplace.GetProductWithVersionArgsForCall(0)
 				Expect(slug).To(Equal("my-super-product"))
 				Expect(version).To(Equal("1.1.1"))
 			})
 
 			By("uploading the vm", func() {
 				Expect(marketplace.UploadVMCallCount()).To(Equal(1))
-				vmFile, product, version := marketplace.UploadVMArgsForCall(0)
+				_, vmFile, product, version := marketplace.UploadVMArgsForCall(0)
 				Expect(vmFile).To(Equal("path/to/a/file.iso"))
 				Expect(product.Slug).To(Equal("my-super-product"))
 				Expect(version.Number).To(Equal("1.1.1"))
@@ -934,6 +941,22 @@ var _ = Describe("AttachCmd", func() {
 			})
 		})
 
+		When("the user belongs to a different organization", func() {
+			BeforeEach(func() {
+				cmd.AuthenticatedClaims = &csp.Claims{ContextName: "some-other-org-id"}
+			})
+
+			It("returns an error before uploading anything", func() {
+				cmd.AttachProductSlug = "my-super-product"
+				cmd.AttachProductVersion = "1.1.1"
+				cmd.AttachVMFile = "path/to/a/file.iso"
+				err := cmd.AttachVMCmd.RunE(cmd.AttachVMCmd, []string{""})
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(HavePrefix("you do not have permission to modify the product \"my-super-product\""))
+				Expect(marketplace.UploadVMCallCount()).To(Equal(0))
+			})
+		})
+
 		When("attaching a PCA file", func() {
 			var uploader *internalfakes.FakeUploader
 			BeforeEach(func() {
@@ -952,11 +975,12 @@ var _ = Describe("AttachCmd", func() {
 				By("uploading the PCA file", func() {
 					Expect(marketplace.GetUploaderCallCount()).To(Equal(1))
 					Expect(uploader.UploadMediaFileCallCount()).To(Equal(1))
-					Expect(uploader.UploadMediaFileArgsForCall(0)).To(Equal("/path/to/pca.pdf"))
+					_, uploadedFile := uploader.UploadMediaFileArgsForCall(0)
+					Expect(uploadedFile).To(Equal("/path/to/pca.pdf"))
 				})
 
 				By("adding the url to the product", func() {
-					_, product, _ := marketplace.UploadVMArgsForCall(0)
+					_, _, product, _ := marketplace.UploadVMArgsForCall(0)
 					Expect(product.PCADetails).ToNot(BeNil())
 					Expect(product.PCADetails.URL).To(Equal("https://example.com/path/to/pca.pdf"))
 					Expect(product.PCADetails.Version).To(Equal("1.1.1"))
@@ -1034,7 +1058,7 @@ var _ = Describe("AttachCmd", func() {
 					Expect(err).ToNot(HaveOccurred())
 
 					By("passing a new version to upload vm", func() {
-						_, _, version := marketplace.UploadVMArgsForCall(0)
+						_, _, _, version := marketplace.UploadVMArgsForCall(0)
 						Expect(version.Number).To(Equal("9.9.9"))
 						Expect(version.IsNewVersion).To(BeTrue())
 					})
@@ -4,55 +4,366 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"github.com/vmware-labs/marketplace-cli/v2/internal"
 	"github.com/vmware-labs/marketplace-cli/v2/internal/csp"
 )
 
 //go:generate counterfeiter . TokenServices
 type TokenServices interface {
-	Redeem(refreshToken string) (*csp.Claims, error)
+	Authenticate(ctx context.Context, credentials *csp.Credentials) (*csp.Claims, error)
 }
 
 //go:generate counterfeiter . TokenServicesInitializer
-type TokenServicesInitializer func(cspHost string) (TokenServices, error)
+type TokenServicesInitializer func(ctx context.Context, cspHost string) (TokenServices, error)
 
-var InitializeTokenServices TokenServicesInitializer = func(cspHost string) (TokenServices, error) {
-	return csp.NewTokenServices(cspHost, Client)
+var InitializeTokenServices TokenServicesInitializer = func(ctx context.Context, cspHost string) (TokenServices, error) {
+	var (
+		tokenServices *csp.TokenServices
+		err           error
+	)
+
+	publicKeyFile := viper.GetString("csp.public-key-file")
+	if publicKeyFile == "" {
+		tokenServices, err = csp.NewTokenServices(ctx, cspHost, Client)
+	} else {
+		publicKey, readErr := os.ReadFile(publicKeyFile)
+		if readErr != nil {
+			return nil, fmt.Errorf("failed to read the CSP public key file: %w", readErr)
+		}
+
+		if viper.GetBool("csp.check-public-key") {
+			tokenServices, err = csp.NewTokenServicesWithPinnedKey(ctx, cspHost, Client, publicKey)
+		} else {
+			tokenServices, err = csp.NewTokenServicesWithKey(cspHost, Client, publicKey)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	tokenServices.Issuer = viper.GetString("csp.token-issuer")
+	return tokenServices, nil
+}
+
+//go:generate counterfeiter . TokenCache
+type TokenCache interface {
+	Get(key string) (*csp.Claims, bool)
+	Set(key string, claims *csp.Claims) error
+	Clear() error
+}
+
+//go:generate counterfeiter . CredentialHelper
+type CredentialHelper interface {
+	Get(ctx context.Context, cspHost string) (string, error)
+}
+
+var NewCredentialHelper = func(command string) CredentialHelper {
+	return csp.NewCredentialHelper(command)
+}
+
+//go:generate counterfeiter . LoginStore
+type LoginStore interface {
+	Get(cspHost string) (*csp.Login, error)
+	Set(cspHost string, login *csp.Login) error
+	Delete(cspHost string) error
+}
+
+//go:generate counterfeiter . LoginFlow
+type LoginFlow interface {
+	Login(ctx context.Context) (*csp.Login, error)
+}
+
+//go:generate counterfeiter . LoginFlowInitializer
+type LoginFlowInitializer func(cspHost, clientID string, callbackPort int, output io.Writer) LoginFlow
+
+var InitializeLoginFlow LoginFlowInitializer = func(cspHost, clientID string, callbackPort int, output io.Writer) LoginFlow {
+	return &csp.LoginFlow{
+		CSPHost:      cspHost,
+		ClientID:     clientID,
+		CallbackPort: callbackPort,
+		Client:       Client,
+		OpenBrowser:  internal.OpenBrowser,
+		Output:       output,
+	}
+}
+
+var (
+	AccessTokenCache TokenCache
+	StoredLogins     LoginStore
+
+	// AuthenticatedClaims are the claims of the access token used for this command, set by GetRefreshToken
+	AuthenticatedClaims *csp.Claims
+)
+
+func defaultTokenCachePath() string {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(cacheDir, AppName, "csp-tokens.json")
+}
+
+func defaultLoginStorePath() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(configDir, AppName, "csp-logins.json")
 }
 
 func GetRefreshToken(cmd *cobra.Command, args []string) error {
-	tokenServices, err := InitializeTokenServices(viper.GetString("csp.host"))
+	if cassetteReplayer != nil {
+		claims, err := replayClaims()
+		if err != nil {
+			return err
+		}
+		useClaims(claims)
+		return nil
+	}
+
+	cspHost := viper.GetString("csp.host")
+	credentials, err := getCredentials(cmd.Context(), cspHost)
+	if err != nil {
+		return err
+	}
+	claims, err := authenticate(cmd.Context(), cspHost, credentials, true)
 	if err != nil {
-		return fmt.Errorf("failed to initialize token services: %w", err)
+		return err
 	}
+	useClaims(claims)
+	return recordClaims(claims)
+}
 
-	apiToken := viper.GetString("csp.api-token")
-	if apiToken == "" {
-		return fmt.Errorf("missing CSP API token")
+// Reauthenticate gets a new access token after the current one was rejected, without using the token cache
+// Requests may still be running while this happens, so it only returns the new token, and leaves the claims of this
+// command alone.
+func Reauthenticate(ctx context.Context) (string, error) {
+	if cassetteReplayer != nil {
+		claims, err := replayClaims()
+		if err != nil {
+			return "", err
+		}
+		return claims.Token, nil
 	}
 
-	claims, err := tokenServices.Redeem(apiToken)
+	cspHost := viper.GetString("csp.host")
+	credentials, err := getCredentials(ctx, cspHost)
+	if err != nil {
+		return "", err
+	}
+	claims, err := authenticate(ctx, cspHost, credentials, false)
 	if err != nil {
-		return fmt.Errorf("failed to exchange api token: %w", err)
+		return "", err
+	}
+	return claims.Token, recordClaims(claims)
+}
+
+func getCredentials(ctx context.Context, cspHost string) (*csp.Credentials, error) {
+	credentials := &csp.Credentials{
+		APIToken:     viper.GetString("csp.api-token"),
+		ClientID:     viper.GetString("csp.client-id"),
+		ClientSecret: viper.GetString("csp.client-secret"),
+	}
+
+	if credentials.APIToken == "" && !credentials.IsClientCredentials() {
+		if helper := viper.GetString("csp.credential-helper"); helper != "" {
+			apiToken, err := NewCredentialHelper(helper).Get(ctx, cspHost)
+			if err != nil {
+				return nil, err
+			}
+			credentials.APIToken = apiToken
+			return credentials, nil
+		}
+
+		login, err := StoredLogins.Get(cspHost)
+		if err != nil {
+			return nil, err
+		}
+		if login != nil {
+			credentials.ClientID = login.ClientID
+			credentials.RefreshToken = login.RefreshToken
+		}
 	}
 
+	return credentials, nil
+}
+
+// useClaims makes the command use the access token with these claims
+func useClaims(claims *csp.Claims) {
+	AuthenticatedClaims = claims
 	viper.Set("csp.refresh-token", claims.Token)
-	return nil
+	if Client != nil {
+		Client.SetAccessToken(claims.Token)
+	}
+}
+
+func authenticate(ctx context.Context, cspHost string, credentials *csp.Credentials, useCachedToken bool) (*csp.Claims, error) {
+	err := credentials.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	useCache := !viper.GetBool("csp.no-token-cache")
+	cacheKey := credentials.CacheKey(cspHost)
+	if useCache && useCachedToken {
+		if claims, ok := AccessTokenCache.Get(cacheKey); ok {
+			return claims, nil
+		}
+	}
+
+	tokenServices, err := InitializeTokenServices(ctx, cspHost)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize token services: %w", err)
+	}
+
+	claims, err := tokenServices.Authenticate(ctx, credentials)
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange %s: %w", credentials.Description(), err)
+	}
+
+	if credentials.IsLogin() && claims.RefreshToken != "" && claims.RefreshToken != credentials.RefreshToken {
+		// CSP rotated the refresh token, so the old one will not work again
+		credentials.RefreshToken = claims.RefreshToken
+		cacheKey = credentials.CacheKey(cspHost)
+		err = StoredLogins.Set(cspHost, &csp.Login{
+			ClientID:     credentials.ClientID,
+			RefreshToken: credentials.RefreshToken,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to store the new login refresh token: %w", err)
+		}
+	}
+
+	if useCache {
+		// Failing to cache the token only means the next command has to redeem it again
+		_ = AccessTokenCache.Set(cacheKey, claims)
+	}
+	return claims, nil
 }
 
 func init() {
 	rootCmd.AddCommand(AuthCmd)
+	AuthCmd.AddCommand(AuthTokenCmd)
+	AuthCmd.AddCommand(AuthStatusCmd)
+	AuthCmd.AddCommand(AuthClearCacheCmd)
+	AuthCmd.AddCommand(AuthLoginCmd)
+	AuthCmd.AddCommand(AuthLogoutCmd)
+
+	AuthLoginCmd.Flags().String("client-id", "", "ID of the CSP OAuth app to log in with [$CSP_LOGIN_CLIENT_ID]")
+	bindFlag("csp.login-client-id", AuthLoginCmd.Flags().Lookup("client-id"))
+	AuthLoginCmd.Flags().Int("callback-port", 0, "Port for receiving the login callback, must match the redirect URI of the OAuth app (default: any free port)")
+	bindFlag("csp.login-callback-port", AuthLoginCmd.Flags().Lookup("callback-port"))
 }
 
+// AuthCmd prints the access token when run without a subcommand, like "auth token", for scripts that relied on it
+// before there were subcommands
 var AuthCmd = &cobra.Command{
 	Use:     "auth",
-	Long:    "Fetch and return a valid CSP refresh token",
+	Short:   "Manage authentication",
+	Long:    "Log in to CSP, show who is authenticated, and manage the cached access tokens",
+	PreRunE: GetRefreshToken,
+	Run:     printAccessToken,
+}
+
+var AuthTokenCmd = &cobra.Command{
+	Use:     "token",
+	Short:   "Fetch a CSP access token",
+	Long:    "Fetch and return a valid CSP access token",
+	Args:    cobra.NoArgs,
 	Hidden:  true,
 	PreRunE: GetRefreshToken,
-	Run: func(cmd *cobra.Command, args []string) {
-		cmd.Println(viper.GetString("csp.refresh-token"))
+	Run:     printAccessToken,
+}
+
+func printAccessToken(cmd *cobra.Command, _ []string) {
+	cmd.Println(viper.GetString("csp.refresh-token"))
+}
+
+var AuthStatusCmd = &cobra.Command{
+	Use:     "status",
+	Aliases: []string{"whoami"},
+	Short:   "Show the authenticated identity",
+	Long:    "Shows the user, organization and permissions of the CSP access token used by this CLI",
+	Example: fmt.Sprintf("%s auth whoami", AppName),
+	Args:    cobra.NoArgs,
+	PreRunE: GetRefreshToken,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		return Output.RenderAuthStatus(AuthenticatedClaims)
+	},
+}
+
+var AuthClearCacheCmd = &cobra.Command{
+	Use:   "clear-cache",
+	Short: "Remove cached access tokens",
+	Long:  "Removes the CSP access tokens cached on disk, so the next command redeems the API token again",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		err := AccessTokenCache.Clear()
+		if err != nil {
+			return err
+		}
+		cmd.Println("Token cache cleared")
+		return nil
+	},
+}
+
+var AuthLoginCmd = &cobra.Command{
+	Use:   "login",
+	Short: "Log in with a web browser",
+	Long: "Logs in to VMware Cloud Service Platform with a web browser, and stores the refresh token for later commands.\n" +
+		"The stored login is used when no API token or OAuth client credentials are configured.",
+	Example: fmt.Sprintf("%s auth login --client-id my-oauth-app-id", AppName),
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		cspHost := viper.GetString("csp.host")
+		loginFlow := InitializeLoginFlow(cspHost, viper.GetString("csp.login-client-id"), viper.GetInt("csp.login-callback-port"), cmd.ErrOrStderr())
+		login, err := loginFlow.Login(cmd.Context())
+		if err != nil {
+			return err
+		}
+
+		err = StoredLogins.Set(cspHost, login)
+		if err != nil {
+			return err
+		}
+
+		claims, err := authenticate(cmd.Context(), cspHost, &csp.Credentials{
+			ClientID:     login.ClientID,
+			RefreshToken: login.RefreshToken,
+		}, false)
+		if err != nil {
+			return err
+		}
+		useClaims(claims)
+
+		cmd.Printf("Logged in as %s\n", AuthenticatedClaims.GetQualifiedUsername())
+		return nil
+	},
+}
+
+var AuthLogoutCmd = &cobra.Command{
+	Use:   "logout",
+	Short: "Remove the stored login",
+	Long:  "Removes the refresh token stored by logging in with a web browser",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		err := StoredLogins.Delete(viper.GetString("csp.host"))
+		if err != nil {
+			return err
+		}
+		cmd.Println("Logged out")
+		return nil
 	},
 }
@@ -4,14 +4,18 @@
 package cmd_test
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
 	. "github.com/vmware-labs/marketplace-cli/v2/cmd"
 	"github.com/vmware-labs/marketplace-cli/v2/cmd/cmdfakes"
+	"github.com/vmware-labs/marketplace-cli/v2/cmd/output/outputfakes"
 	"github.com/vmware-labs/marketplace-cli/v2/internal/csp"
 )
 
@@ -20,34 +24,276 @@ var _ = Describe("Auth", func() {
 		var (
 			initializer   *cmdfakes.FakeTokenServicesInitializer
 			tokenServices *cmdfakes.FakeTokenServices
+			tokenCache    *cmdfakes.FakeTokenCache
+			loginStore    *cmdfakes.FakeLoginStore
+			command       *cobra.Command
 		)
 
 		BeforeEach(func() {
+			command = &cobra.Command{}
+			command.SetContext(context.Background())
+
 			tokenServices = &cmdfakes.FakeTokenServices{}
 
 			initializer = &cmdfakes.FakeTokenServicesInitializer{}
 			initializer.Returns(tokenServices, nil)
 			InitializeTokenServices = initializer.Spy
+
+			tokenCache = &cmdfakes.FakeTokenCache{}
+			AccessTokenCache = tokenCache
+
+			loginStore = &cmdfakes.FakeLoginStore{}
+			StoredLogins = loginStore
 		})
 
 		BeforeEach(func() {
 			viper.Set("csp.api-token", "my-csp-api-token")
 			viper.Set("csp.host", "console.cloud.vmware.com.example")
-			tokenServices.RedeemReturns(&csp.Claims{
+			viper.Set("csp.no-token-cache", false)
+			viper.Set("csp.client-id", "")
+			viper.Set("csp.client-secret", "")
+			viper.Set("csp.credential-helper", "")
+			tokenServices.AuthenticateReturns(&csp.Claims{
 				Token: "my-refresh-token",
 			}, nil)
 		})
 
+		AfterEach(func() {
+			viper.Set("csp.credential-helper", nil)
+		})
+
 		It("gets the refresh token and puts it into viper", func() {
-			err := GetRefreshToken(nil, []string{})
+			err := GetRefreshToken(command, []string{})
 			Expect(err).ToNot(HaveOccurred())
 			Expect(viper.GetString("csp.refresh-token")).To(Equal("my-refresh-token"))
+			Expect(AuthenticatedClaims.Token).To(Equal("my-refresh-token"))
 
 			Expect(initializer.CallCount()).To(Equal(1))
-			Expect(initializer.ArgsForCall(0)).To(Equal("console.cloud.vmware.com.example"))
+			_, cspHost := initializer.ArgsForCall(0)
+			Expect(cspHost).To(Equal("console.cloud.vmware.com.example"))
+
+			Expect(tokenServices.AuthenticateCallCount()).To(Equal(1))
+			_, credentials := tokenServices.AuthenticateArgsForCall(0)
+			Expect(credentials.APIToken).To(Equal("my-csp-api-token"))
+
+			By("storing the redeemed token in the cache", func() {
+				Expect(tokenCache.GetCallCount()).To(Equal(1))
+				Expect(tokenCache.SetCallCount()).To(Equal(1))
+				key, claims := tokenCache.SetArgsForCall(0)
+				Expect(key).To(Equal(csp.TokenCacheKey("console.cloud.vmware.com.example", "my-csp-api-token")))
+				Expect(claims.Token).To(Equal("my-refresh-token"))
+			})
+		})
+
+		Context("the token is cached", func() {
+			BeforeEach(func() {
+				tokenCache.GetReturns(&csp.Claims{
+					Token: "my-cached-refresh-token",
+				}, true)
+			})
+
+			It("uses the cached token without redeeming the api token", func() {
+				err := GetRefreshToken(command, []string{})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(viper.GetString("csp.refresh-token")).To(Equal("my-cached-refresh-token"))
+
+				Expect(tokenCache.GetArgsForCall(0)).To(Equal(csp.TokenCacheKey("console.cloud.vmware.com.example", "my-csp-api-token")))
+				Expect(initializer.CallCount()).To(Equal(0))
+				Expect(tokenServices.AuthenticateCallCount()).To(Equal(0))
+			})
+
+			Context("the token cache is disabled", func() {
+				BeforeEach(func() {
+					viper.Set("csp.no-token-cache", true)
+				})
+
+				It("redeems the api token and does not touch the cache", func() {
+					err := GetRefreshToken(command, []string{})
+					Expect(err).ToNot(HaveOccurred())
+					Expect(viper.GetString("csp.refresh-token")).To(Equal("my-refresh-token"))
+
+					Expect(tokenServices.AuthenticateCallCount()).To(Equal(1))
+					Expect(tokenCache.GetCallCount()).To(Equal(0))
+					Expect(tokenCache.SetCallCount()).To(Equal(0))
+				})
+			})
+		})
+
+		Context("no api token is set", func() {
+			BeforeEach(func() {
+				viper.Set("csp.api-token", "")
+			})
+
+			It("returns an error", func() {
+				err := GetRefreshToken(command, []string{})
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("missing CSP API token or OAuth client credentials"))
+			})
+		})
+
+		Context("a credential helper is configured", func() {
+			var helper *cmdfakes.FakeCredentialHelper
+
+			BeforeEach(func() {
+				viper.Set("csp.api-token", "")
+				viper.Set("csp.credential-helper", "my-credential-helper")
+				helper = &cmdfakes.FakeCredentialHelper{}
+				helper.GetReturns("my-helper-api-token", nil)
+				NewCredentialHelper = func(command string) CredentialHelper {
+					Expect(command).To(Equal("my-credential-helper"))
+					return helper
+				}
+			})
+
+			It("redeems the api token from the helper", func() {
+				err := GetRefreshToken(command, []string{})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(viper.GetString("csp.refresh-token")).To(Equal("my-refresh-token"))
+
+				Expect(helper.GetCallCount()).To(Equal(1))
+				_, cspHost := helper.GetArgsForCall(0)
+				Expect(cspHost).To(Equal("console.cloud.vmware.com.example"))
+				_, credentials := tokenServices.AuthenticateArgsForCall(0)
+				Expect(credentials.APIToken).To(Equal("my-helper-api-token"))
+				Expect(loginStore.GetCallCount()).To(Equal(0))
+			})
+
+			Context("the helper fails", func() {
+				BeforeEach(func() {
+					helper.GetReturns("", fmt.Errorf("credential helper my-credential-helper failed: exit status 1: vault is sealed"))
+				})
 
-			Expect(tokenServices.RedeemCallCount()).To(Equal(1))
-			Expect(tokenServices.RedeemArgsForCall(0)).To(Equal("my-csp-api-token"))
+				It("returns the helper error", func() {
+					err := GetRefreshToken(command, []string{})
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(Equal("credential helper my-credential-helper failed: exit status 1: vault is sealed"))
+					Expect(tokenServices.AuthenticateCallCount()).To(Equal(0))
+				})
+			})
+
+			Context("an api token is also set", func() {
+				BeforeEach(func() {
+					viper.Set("csp.api-token", "my-csp-api-token")
+				})
+
+				It("does not run the helper", func() {
+					err := GetRefreshToken(command, []string{})
+					Expect(err).ToNot(HaveOccurred())
+					Expect(helper.GetCallCount()).To(Equal(0))
+				})
+			})
+		})
+
+		Context("there is a stored login", func() {
+			BeforeEach(func() {
+				viper.Set("csp.api-token", "")
+				loginStore.GetReturns(&csp.Login{
+					ClientID:     "my-login-client-id",
+					RefreshToken: "my-login-refresh-token",
+				}, nil)
+			})
+
+			It("redeems the stored login", func() {
+				err := GetRefreshToken(command, []string{})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(viper.GetString("csp.refresh-token")).To(Equal("my-refresh-token"))
+
+				Expect(loginStore.GetCallCount()).To(Equal(1))
+				Expect(loginStore.GetArgsForCall(0)).To(Equal("console.cloud.vmware.com.example"))
+
+				Expect(tokenServices.AuthenticateCallCount()).To(Equal(1))
+				_, credentials := tokenServices.AuthenticateArgsForCall(0)
+				Expect(credentials.IsLogin()).To(BeTrue())
+				Expect(credentials.ClientID).To(Equal("my-login-client-id"))
+				Expect(credentials.RefreshToken).To(Equal("my-login-refresh-token"))
+				Expect(loginStore.SetCallCount()).To(Equal(0))
+			})
+
+			Context("CSP rotates the refresh token", func() {
+				BeforeEach(func() {
+					tokenServices.AuthenticateReturns(&csp.Claims{
+						Token:        "my-refresh-token",
+						RefreshToken: "my-new-login-refresh-token",
+					}, nil)
+				})
+
+				It("stores the new refresh token", func() {
+					err := GetRefreshToken(command, []string{})
+					Expect(err).ToNot(HaveOccurred())
+
+					Expect(loginStore.SetCallCount()).To(Equal(1))
+					host, login := loginStore.SetArgsForCall(0)
+					Expect(host).To(Equal("console.cloud.vmware.com.example"))
+					Expect(login.ClientID).To(Equal("my-login-client-id"))
+					Expect(login.RefreshToken).To(Equal("my-new-login-refresh-token"))
+
+					key, _ := tokenCache.SetArgsForCall(0)
+					Expect(key).To(Equal(csp.TokenCacheKey("console.cloud.vmware.com.example", "my-new-login-refresh-token")))
+				})
+			})
+
+			Context("an api token is also set", func() {
+				BeforeEach(func() {
+					viper.Set("csp.api-token", "my-csp-api-token")
+				})
+
+				It("uses the api token", func() {
+					err := GetRefreshToken(command, []string{})
+					Expect(err).ToNot(HaveOccurred())
+					Expect(loginStore.GetCallCount()).To(Equal(0))
+					_, credentials := tokenServices.AuthenticateArgsForCall(0)
+					Expect(credentials.APIToken).To(Equal("my-csp-api-token"))
+				})
+			})
+		})
+
+		Context("OAuth client credentials are set", func() {
+			BeforeEach(func() {
+				viper.Set("csp.client-id", "my-client-id")
+				viper.Set("csp.client-secret", "my-client-secret")
+			})
+
+			It("exchanges the client credentials", func() {
+				err := GetRefreshToken(command, []string{})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(viper.GetString("csp.refresh-token")).To(Equal("my-refresh-token"))
+
+				Expect(tokenServices.AuthenticateCallCount()).To(Equal(1))
+				_, credentials := tokenServices.AuthenticateArgsForCall(0)
+				Expect(credentials.IsClientCredentials()).To(BeTrue())
+				Expect(credentials.ClientID).To(Equal("my-client-id"))
+				Expect(credentials.ClientSecret).To(Equal("my-client-secret"))
+
+				By("caching the token under the client credentials", func() {
+					key, _ := tokenCache.SetArgsForCall(0)
+					Expect(key).To(Equal(credentials.CacheKey("console.cloud.vmware.com.example")))
+					Expect(key).ToNot(Equal(csp.TokenCacheKey("console.cloud.vmware.com.example", "my-csp-api-token")))
+				})
+			})
+
+			Context("the client secret is missing", func() {
+				BeforeEach(func() {
+					viper.Set("csp.client-secret", "")
+				})
+
+				It("returns an error", func() {
+					err := GetRefreshToken(command, []string{})
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(Equal("missing CSP OAuth client secret"))
+				})
+			})
+
+			Context("fails to exchange the client credentials", func() {
+				BeforeEach(func() {
+					tokenServices.AuthenticateReturns(nil, fmt.Errorf("redeem failed"))
+				})
+
+				It("returns an error", func() {
+					err := GetRefreshToken(command, []string{})
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(Equal("failed to exchange client credentials: redeem failed"))
+				})
+			})
 		})
 
 		Context("fails to initialize token services", func() {
@@ -56,7 +302,7 @@ var _ = Describe("Auth", func() {
 			})
 
 			It("returns an error", func() {
-				err := GetRefreshToken(nil, []string{})
+				err := GetRefreshToken(command, []string{})
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(Equal("failed to initialize token services: initializer failed"))
 			})
@@ -64,14 +310,188 @@ var _ = Describe("Auth", func() {
 
 		Context("fails to exchange api token", func() {
 			BeforeEach(func() {
-				tokenServices.RedeemReturns(nil, fmt.Errorf("redeem failed"))
+				tokenServices.AuthenticateReturns(nil, fmt.Errorf("redeem failed"))
 			})
 
 			It("returns an error", func() {
-				err := GetRefreshToken(nil, []string{})
+				err := GetRefreshToken(command, []string{})
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(Equal("failed to exchange api token: redeem failed"))
 			})
 		})
 	})
+
+	Describe("Reauthenticate", func() {
+		var (
+			tokenServices *cmdfakes.FakeTokenServices
+			tokenCache    *cmdfakes.FakeTokenCache
+		)
+
+		BeforeEach(func() {
+			tokenServices = &cmdfakes.FakeTokenServices{}
+			tokenServices.AuthenticateReturns(&csp.Claims{Token: "my-new-access-token"}, nil)
+			initializer := &cmdfakes.FakeTokenServicesInitializer{}
+			initializer.Returns(tokenServices, nil)
+			InitializeTokenServices = initializer.Spy
+
+			tokenCache = &cmdfakes.FakeTokenCache{}
+			tokenCache.GetReturns(&csp.Claims{Token: "my-rejected-access-token"}, true)
+			AccessTokenCache = tokenCache
+
+			viper.Set("csp.api-token", "my-csp-api-token")
+			viper.Set("csp.host", "console.cloud.vmware.com.example")
+			viper.Set("csp.no-token-cache", false)
+			viper.Set("csp.client-id", "")
+			viper.Set("csp.client-secret", "")
+			viper.Set("csp.credential-helper", "")
+		})
+
+		AfterEach(func() {
+			viper.Set("csp.credential-helper", nil)
+		})
+
+		It("redeems the api token again and replaces the cached token", func() {
+			token, err := Reauthenticate(context.Background())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(token).To(Equal("my-new-access-token"))
+
+			Expect(tokenCache.GetCallCount()).To(Equal(0))
+			Expect(tokenServices.AuthenticateCallCount()).To(Equal(1))
+			Expect(tokenCache.SetCallCount()).To(Equal(1))
+			_, claims := tokenCache.SetArgsForCall(0)
+			Expect(claims.Token).To(Equal("my-new-access-token"))
+		})
+	})
+
+	Describe("AuthTokenCmd", func() {
+		It("prints the access token", func() {
+			viper.Set("csp.refresh-token", "my-access-token")
+			stdout := &bytes.Buffer{}
+			AuthTokenCmd.SetOut(stdout)
+
+			AuthTokenCmd.Run(AuthTokenCmd, []string{})
+			Expect(stdout.String()).To(Equal("my-access-token\n"))
+		})
+
+		It("is hidden, but the other auth commands are not", func() {
+			Expect(AuthTokenCmd.Hidden).To(BeTrue())
+			Expect(AuthCmd.Hidden).To(BeFalse())
+			Expect(AuthCmd.IsAvailableCommand()).To(BeTrue())
+			Expect(AuthStatusCmd.IsAvailableCommand()).To(BeTrue())
+			Expect(AuthLoginCmd.IsAvailableCommand()).To(BeTrue())
+		})
+	})
+
+	Describe("AuthStatusCmd", func() {
+		var output *outputfakes.FakeFormat
+
+		BeforeEach(func() {
+			output = &outputfakes.FakeFormat{}
+			Output = output
+			AuthenticatedClaims = &csp.Claims{
+				Username: "alice@example.com",
+				Context:  "my-org-id",
+			}
+		})
+
+		It("renders the claims of the access token", func() {
+			err := AuthStatusCmd.RunE(AuthStatusCmd, []string{})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(output.RenderAuthStatusCallCount()).To(Equal(1))
+			claims := output.RenderAuthStatusArgsForCall(0)
+			Expect(claims.Username).To(Equal("alice@example.com"))
+			Expect(claims.Context).To(Equal("my-org-id"))
+		})
+	})
+
+	Describe("AuthLoginCmd", func() {
+		var (
+			loginFlow     *cmdfakes.FakeLoginFlow
+			loginInit     *cmdfakes.FakeLoginFlowInitializer
+			loginStore    *cmdfakes.FakeLoginStore
+			tokenServices *cmdfakes.FakeTokenServices
+			stdout        *bytes.Buffer
+		)
+
+		BeforeEach(func() {
+			loginFlow = &cmdfakes.FakeLoginFlow{}
+			loginFlow.LoginReturns(&csp.Login{
+				ClientID:     "my-login-client-id",
+				RefreshToken: "my-login-refresh-token",
+			}, nil)
+			loginInit = &cmdfakes.FakeLoginFlowInitializer{}
+			loginInit.Returns(loginFlow)
+			InitializeLoginFlow = loginInit.Spy
+
+			loginStore = &cmdfakes.FakeLoginStore{}
+			StoredLogins = loginStore
+			AccessTokenCache = &cmdfakes.FakeTokenCache{}
+
+			tokenServices = &cmdfakes.FakeTokenServices{}
+			tokenServices.AuthenticateReturns(&csp.Claims{
+				Token:    "my-access-token",
+				Username: "alice@example.com",
+			}, nil)
+			initializer := &cmdfakes.FakeTokenServicesInitializer{}
+			initializer.Returns(tokenServices, nil)
+			InitializeTokenServices = initializer.Spy
+
+			viper.Set("csp.host", "console.cloud.vmware.com.example")
+			viper.Set("csp.login-client-id", "my-login-client-id")
+			viper.Set("csp.login-callback-port", 8750)
+			viper.Set("csp.no-token-cache", false)
+
+			stdout = &bytes.Buffer{}
+			AuthLoginCmd.SetOut(stdout)
+		})
+
+		It("logs in and stores the refresh token", func() {
+			err := AuthLoginCmd.RunE(AuthLoginCmd, []string{})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(loginInit.CallCount()).To(Equal(1))
+			cspHost, clientID, port, _ := loginInit.ArgsForCall(0)
+			Expect(cspHost).To(Equal("console.cloud.vmware.com.example"))
+			Expect(clientID).To(Equal("my-login-client-id"))
+			Expect(port).To(Equal(8750))
+
+			Expect(loginStore.SetCallCount()).To(Equal(1))
+			host, login := loginStore.SetArgsForCall(0)
+			Expect(host).To(Equal("console.cloud.vmware.com.example"))
+			Expect(login.RefreshToken).To(Equal("my-login-refresh-token"))
+
+			Expect(tokenServices.AuthenticateCallCount()).To(Equal(1))
+			_, credentials := tokenServices.AuthenticateArgsForCall(0)
+			Expect(credentials.RefreshToken).To(Equal("my-login-refresh-token"))
+			Expect(stdout.String()).To(Equal("Logged in as alice@example.com\n"))
+		})
+
+		Context("the login fails", func() {
+			BeforeEach(func() {
+				loginFlow.LoginReturns(nil, fmt.Errorf("login failed"))
+			})
+
+			It("returns an error and does not store anything", func() {
+				err := AuthLoginCmd.RunE(AuthLoginCmd, []string{})
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("login failed"))
+				Expect(loginStore.SetCallCount()).To(Equal(0))
+			})
+		})
+	})
+
+	Describe("AuthLogoutCmd", func() {
+		It("deletes the stored login", func() {
+			loginStore := &cmdfakes.FakeLoginStore{}
+			StoredLogins = loginStore
+			viper.Set("csp.host", "console.cloud.vmware.com.example")
+			AuthLogoutCmd.SetOut(&bytes.Buffer{})
+
+			err := AuthLogoutCmd.RunE(AuthLogoutCmd, []string{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(loginStore.DeleteCallCount()).To(Equal(1))
+			Expect(loginStore.DeleteArgsForCall(0)).To(Equal("console.cloud.vmware.com.example"))
+		})
+	})
 })
@@ -0,0 +1,74 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: BSD-2-Clause
+
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/vmware-labs/marketplace-cli/v2/pkg"
+)
+
+var ResponseCache *pkg.ResponseCache
+
+func defaultResponseCachePath() string {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(cacheDir, AppName, "responses")
+}
+
+// cacheIdentity keeps cached responses separate for each user and organization
+func cacheIdentity() string {
+	if AuthenticatedClaims == nil {
+		return ""
+	}
+	return AuthenticatedClaims.ContextName + "/" + AuthenticatedClaims.Username
+}
+
+// UseResponseCache is true if product responses should be cached. The cache is never used while recording or
+// replaying, so that every request is part of the recording.
+func UseResponseCache() bool {
+	return viper.GetBool("cache.enabled") &&
+		!viper.GetBool("cache.disabled") &&
+		viper.GetString("debugging.record") == "" &&
+		viper.GetString("debugging.replay") == ""
+}
+
+// ReadFreshProducts is for commands that change products. Cached products are revalidated before being used, so
+// that the command does not write back a stale copy.
+func ReadFreshProducts(cmd *cobra.Command, _ []string) error {
+	cmd.SetContext(pkg.WithFreshReads(cmd.Context()))
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(CacheCmd)
+	CacheCmd.AddCommand(CacheClearCmd)
+}
+
+var CacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the response cache",
+	Long:  "Manage the product responses cached on disk when the cache.enabled setting is on",
+}
+
+var CacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove cached responses",
+	Long:  "Removes the product responses cached on disk, so the next command fetches them again",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		err := ResponseCache.Clear()
+		if err != nil {
+			return err
+		}
+		cmd.Println("Response cache cleared")
+		return nil
+	},
+}
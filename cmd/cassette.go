@@ -0,0 +1,90 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: BSD-2-Clause
+
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/viper"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/csp"
+	"github.com/vmware-labs/marketplace-cli/v2/pkg"
+)
+
+// cassetteClaimsFile keeps who was authenticated while recording, because the access token itself is scrubbed
+const cassetteClaimsFile = "claims.json"
+
+var (
+	cassetteRecorder *pkg.Recorder
+	cassetteReplayer *pkg.Replayer
+)
+
+// StartCassette wraps performRequest to save every request to the --record directory, or to answer every request
+// from the --replay directory instead of the network
+func StartCassette(performRequest pkg.PerformRequestFunc) (pkg.PerformRequestFunc, error) {
+	cassetteRecorder = nil
+	cassetteReplayer = nil
+
+	recordDir := viper.GetString("debugging.record")
+	replayDir := viper.GetString("debugging.replay")
+	if recordDir != "" && replayDir != "" {
+		return nil, errors.New("--record and --replay cannot be used together")
+	}
+
+	var err error
+	if recordDir != "" {
+		cassetteRecorder, err = pkg.NewRecorder(recordDir, performRequest)
+		if err != nil {
+			return nil, err
+		}
+		return cassetteRecorder.Do, nil
+	}
+	if replayDir != "" {
+		cassetteReplayer, err = pkg.LoadCassette(replayDir)
+		if err != nil {
+			return nil, err
+		}
+		return cassetteReplayer.Do, nil
+	}
+	return performRequest, nil
+}
+
+func recordClaims(claims *csp.Claims) error {
+	if cassetteRecorder == nil || claims == nil {
+		return nil
+	}
+
+	encoded, err := json.MarshalIndent(claims, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode the recorded authentication: %w", err)
+	}
+	err = os.WriteFile(filepath.Join(cassetteRecorder.Dir, cassetteClaimsFile), encoded, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to save the recorded authentication: %w", err)
+	}
+	return nil
+}
+
+// replayClaims authenticates as whoever was authenticated while recording, without contacting CSP
+func replayClaims() (*csp.Claims, error) {
+	path := filepath.Join(cassetteReplayer.Dir, cassetteClaimsFile)
+	contents, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("the recording in %s was made without authenticating", cassetteReplayer.Dir)
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read the recorded authentication: %w", err)
+	}
+
+	claims := &csp.Claims{}
+	err = json.Unmarshal(contents, claims)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the recorded authentication %s: %w", path, err)
+	}
+	claims.Token = pkg.RedactedValue
+	return claims, nil
+}
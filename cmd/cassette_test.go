@@ -0,0 +1,172 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: BSD-2-Clause
+
+package cmd_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	. "github.com/vmware-labs/marketplace-cli/v2/cmd"
+	"github.com/vmware-labs/marketplace-cli/v2/cmd/cmdfakes"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/csp"
+	"github.com/vmware-labs/marketplace-cli/v2/pkg/pkgfakes"
+)
+
+var _ = Describe("Cassettes", func() {
+	var (
+		dir            string
+		command        *cobra.Command
+		performRequest *pkgfakes.FakePerformRequestFunc
+		initializer    *cmdfakes.FakeTokenServicesInitializer
+		tokenServices  *cmdfakes.FakeTokenServices
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "mkpcli-cassette-test")
+		Expect(err).ToNot(HaveOccurred())
+
+		command = &cobra.Command{}
+		command.SetContext(context.Background())
+
+		performRequest = &pkgfakes.FakePerformRequestFunc{}
+		performRequest.Returns(&http.Response{
+			Status:     "200 OK",
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"text/plain"}},
+			Body:       ioutil.NopCloser(strings.NewReader("everything is fine")),
+		}, nil)
+
+		tokenServices = &cmdfakes.FakeTokenServices{}
+		tokenServices.AuthenticateReturns(&csp.Claims{
+			Token:       "my-access-token",
+			Username:    "someone@example.com",
+			ContextName: "my-org-id",
+		}, nil)
+		initializer = &cmdfakes.FakeTokenServicesInitializer{}
+		initializer.Returns(tokenServices, nil)
+		InitializeTokenServices = initializer.Spy
+		AccessTokenCache = &cmdfakes.FakeTokenCache{}
+		StoredLogins = &cmdfakes.FakeLoginStore{}
+
+		viper.Set("csp.api-token", "my-csp-api-token")
+		viper.Set("csp.client-id", "")
+		viper.Set("csp.client-secret", "")
+		viper.Set("csp.credential-helper", "")
+	})
+
+	AfterEach(func() {
+		viper.Set("csp.credential-helper", nil)
+		viper.Set("debugging.record", nil)
+		viper.Set("debugging.replay", nil)
+		_, err := StartCassette(performRequest.Spy)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	It("uses the network when not recording or replaying", func() {
+		viper.Set("debugging.record", "")
+		viper.Set("debugging.replay", "")
+		doRequest, err := StartCassette(performRequest.Spy)
+		Expect(err).ToNot(HaveOccurred())
+
+		request, err := http.NewRequest("GET", "https://marketplace.example.com/api/v1/products", nil)
+		Expect(err).ToNot(HaveOccurred())
+		_, err = doRequest(request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(performRequest.CallCount()).To(Equal(1))
+		Expect(dir).To(BeADirectory())
+	})
+
+	Context("recording", func() {
+		BeforeEach(func() {
+			viper.Set("debugging.record", dir)
+			viper.Set("debugging.replay", "")
+		})
+
+		It("saves the requests and who was authenticated", func() {
+			doRequest, err := StartCassette(performRequest.Spy)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(GetRefreshToken(command, []string{})).To(Succeed())
+
+			request, err := http.NewRequest("GET", "https://marketplace.example.com/api/v1/products", nil)
+			Expect(err).ToNot(HaveOccurred())
+			_, err = doRequest(request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(performRequest.CallCount()).To(Equal(1))
+
+			Expect(filepath.Join(dir, "interaction-0001.json")).To(BeAnExistingFile())
+			claims, err := os.ReadFile(filepath.Join(dir, "claims.json"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(claims)).To(ContainSubstring("someone@example.com"))
+			Expect(string(claims)).ToNot(ContainSubstring("my-access-token"))
+		})
+	})
+
+	Context("replaying", func() {
+		BeforeEach(func() {
+			viper.Set("debugging.record", dir)
+			viper.Set("debugging.replay", "")
+			doRequest, err := StartCassette(performRequest.Spy)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(GetRefreshToken(command, []string{})).To(Succeed())
+			request, err := http.NewRequest("GET", "https://marketplace.example.com/api/v1/products", nil)
+			Expect(err).ToNot(HaveOccurred())
+			_, err = doRequest(request)
+			Expect(err).ToNot(HaveOccurred())
+
+			viper.Set("debugging.record", "")
+			viper.Set("debugging.replay", dir)
+			AuthenticatedClaims = nil
+		})
+
+		It("answers requests and authenticates from the recording", func() {
+			doRequest, err := StartCassette(performRequest.Spy)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(GetRefreshToken(command, []string{})).To(Succeed())
+			Expect(AuthenticatedClaims.Username).To(Equal("someone@example.com"))
+			Expect(AuthenticatedClaims.ContextName).To(Equal("my-org-id"))
+			Expect(initializer.CallCount()).To(Equal(1))
+
+			request, err := http.NewRequest("GET", "https://marketplace.example.com/api/v1/products", nil)
+			Expect(err).ToNot(HaveOccurred())
+			response, err := doRequest(request)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ioutil.ReadAll(response.Body)).To(Equal([]byte("everything is fine")))
+			Expect(performRequest.CallCount()).To(Equal(1))
+		})
+
+		When("the recording has no authentication", func() {
+			It("returns an error", func() {
+				Expect(os.Remove(filepath.Join(dir, "claims.json"))).To(Succeed())
+				_, err := StartCassette(performRequest.Spy)
+				Expect(err).ToNot(HaveOccurred())
+
+				err = GetRefreshToken(command, []string{})
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("the recording in " + dir + " was made without authenticating"))
+			})
+		})
+	})
+
+	When("recording and replaying at the same time", func() {
+		It("returns an error", func() {
+			viper.Set("debugging.record", dir)
+			viper.Set("debugging.replay", dir)
+			_, err := StartCassette(performRequest.Spy)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("--record and --replay cannot be used together"))
+		})
+	})
+})
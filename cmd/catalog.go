@@ -0,0 +1,122 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: BSD-2-Clause
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/catalog"
+)
+
+var (
+	CatalogStore *catalog.Store
+
+	CatalogFullSync     = false
+	CatalogPublisher    string
+	CatalogSolutionType string
+	CatalogCategory     string
+	CatalogTag          string
+	CatalogLimit        = catalog.DefaultSearchLimit
+)
+
+func defaultCatalogPath() string {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(cacheDir, AppName, "catalog")
+}
+
+func init() {
+	rootCmd.AddCommand(CatalogCmd)
+	CatalogCmd.AddCommand(CatalogSyncCmd)
+	CatalogCmd.AddCommand(CatalogSearchCmd)
+
+	CatalogSyncCmd.Flags().BoolVar(&CatalogFullSync, "full", false, "Download every product again, instead of only the ones updated since the last sync")
+
+	CatalogSearchCmd.Flags().StringVar(&CatalogPublisher, "publisher", "", "Only find products from this publisher")
+	CatalogSearchCmd.Flags().StringVar(&CatalogSolutionType, "type", "", "Only find products of this solution type")
+	CatalogSearchCmd.Flags().StringVar(&CatalogCategory, "category", "", "Only find products in this category")
+	CatalogSearchCmd.Flags().StringVar(&CatalogTag, "tag", "", "Only find products with this tag")
+	CatalogSearchCmd.Flags().IntVar(&CatalogLimit, "limit", CatalogLimit, "Show at most this many results")
+}
+
+var CatalogCmd = &cobra.Command{
+	Use:   "catalog",
+	Short: "Search a local copy of the Marketplace",
+	Long:  "Download the published products to a local catalog, and search it without connecting to the Marketplace",
+}
+
+var CatalogSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Download the published products",
+	Long: "Downloads the published products from every organization, with their details, to the local catalog.\n" +
+		"After the first sync, only the products updated since the last sync are downloaded, unless --full is used",
+	Example: fmt.Sprintf(`%[1]s catalog sync
+%[1]s catalog sync --full`, AppName),
+	Args:    cobra.NoArgs,
+	PreRunE: GetRefreshToken,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		current, err := CatalogStore.Load(Marketplace.GetHost())
+		if err != nil {
+			return err
+		}
+
+		result, err := catalog.Sync(cmd.Context(), Marketplace, current, CatalogFullSync)
+		if err != nil {
+			return err
+		}
+
+		err = CatalogStore.Save(current)
+		if err != nil {
+			return err
+		}
+
+		for _, warning := range result.Warnings {
+			cmd.PrintErrf("Warning: %s\n", warning)
+		}
+		cmd.Printf("Catalog synced: %d added, %d updated, %d removed, %d products in total\n", result.Added, result.Updated, result.Removed, result.Total)
+		return nil
+	},
+}
+
+var CatalogSearchCmd = &cobra.Command{
+	Use:   "search [query]",
+	Short: "Search the local catalog",
+	Long: "Searches the products in the local catalog by name, summary, description, categories, tags, publisher and solution type.\n" +
+		"Run \"" + AppName + " catalog sync\" first to download the catalog",
+	Example: fmt.Sprintf(`%[1]s catalog search postgres
+%[1]s catalog search "load balancer" --category Networking
+%[1]s catalog search --publisher VMware --type HELMCHARTS`, AppName),
+	Args: cobra.ArbitraryArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		current, err := CatalogStore.Load(Marketplace.GetHost())
+		if err != nil {
+			return err
+		}
+
+		query := &catalog.SearchQuery{
+			Text:         strings.Join(args, " "),
+			Publisher:    CatalogPublisher,
+			SolutionType: CatalogSolutionType,
+			Category:     CatalogCategory,
+			Tag:          CatalogTag,
+			Limit:        CatalogLimit,
+		}
+		results := current.Search(query)
+
+		header := "Products in the catalog"
+		if query.Text != "" {
+			header += fmt.Sprintf(" matching \"%s\"", query.Text)
+		}
+		Output.PrintHeader(header)
+		return Output.RenderCatalogSearch(results)
+	},
+}
@@ -0,0 +1,122 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: BSD-2-Clause
+
+package cmd_test
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gbytes"
+	"github.com/vmware-labs/marketplace-cli/v2/cmd"
+	"github.com/vmware-labs/marketplace-cli/v2/cmd/output/outputfakes"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/catalog"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/models"
+	"github.com/vmware-labs/marketplace-cli/v2/pkg"
+	"github.com/vmware-labs/marketplace-cli/v2/pkg/pkgfakes"
+	"github.com/vmware-labs/marketplace-cli/v2/test"
+)
+
+var _ = Describe("Catalog", func() {
+	var (
+		dir         string
+		stdout      *Buffer
+		stderr      *Buffer
+		product     *models.Product
+		marketplace *pkgfakes.FakeMarketplaceInterface
+		output      *outputfakes.FakeFormat
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "mkpcli-catalog-cmd-test")
+		Expect(err).ToNot(HaveOccurred())
+		cmd.CatalogStore = catalog.NewStore(dir)
+
+		product = test.CreateFakeProduct("", "Tanzu Postgres", "tanzu-postgres", models.SolutionTypeChart)
+		product.UpdatedDate = 1000
+
+		marketplace = &pkgfakes.FakeMarketplaceInterface{}
+		marketplace.GetHostReturns("marketplace.example.com")
+		marketplace.EachProductStub = func(ctx context.Context, options *pkg.ListProductsOptions, fn pkg.ProductFunc) error {
+			return fn(product)
+		}
+		marketplace.GetProductReturns(product, nil)
+		cmd.Marketplace = marketplace
+
+		output = &outputfakes.FakeFormat{}
+		cmd.Output = output
+
+		stdout = NewBuffer()
+		stderr = NewBuffer()
+		cmd.CatalogSyncCmd.SetOut(stdout)
+		cmd.CatalogSyncCmd.SetErr(stderr)
+		cmd.CatalogSyncCmd.SetContext(context.Background())
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	Describe("CatalogSyncCmd", func() {
+		It("downloads the products to the catalog", func() {
+			err := cmd.CatalogSyncCmd.RunE(cmd.CatalogSyncCmd, []string{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stdout).To(Say("Catalog synced: 1 added, 0 updated, 0 removed, 1 products in total"))
+
+			saved, err := cmd.CatalogStore.Load("marketplace.example.com")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(saved.Products).To(HaveLen(1))
+			Expect(saved.UpdatedOn).To(Equal(1000))
+		})
+
+		When("the details of a product cannot be downloaded", func() {
+			It("prints a warning", func() {
+				marketplace.GetProductReturns(nil, errors.New("get product failed"))
+				err := cmd.CatalogSyncCmd.RunE(cmd.CatalogSyncCmd, []string{})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(stderr).To(Say("Warning: failed to get the details for tanzu-postgres: get product failed"))
+			})
+		})
+
+		When("listing the products fails", func() {
+			It("returns an error", func() {
+				marketplace.EachProductStub = nil
+				marketplace.EachProductReturns(errors.New("list products failed"))
+				err := cmd.CatalogSyncCmd.RunE(cmd.CatalogSyncCmd, []string{})
+				Expect(err).To(MatchError("failed to list the products: list products failed"))
+			})
+		})
+	})
+
+	Describe("CatalogSearchCmd", func() {
+		BeforeEach(func() {
+			err := cmd.CatalogSyncCmd.RunE(cmd.CatalogSyncCmd, []string{})
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("searches the catalog", func() {
+			cmd.CatalogSolutionType = "helmcharts"
+			defer func() { cmd.CatalogSolutionType = "" }()
+
+			err := cmd.CatalogSearchCmd.RunE(cmd.CatalogSearchCmd, []string{"tanzu", "postgres"})
+			Expect(err).ToNot(HaveOccurred())
+
+			By("not connecting to the Marketplace", func() {
+				Expect(marketplace.EachProductCallCount()).To(Equal(1))
+				Expect(marketplace.GetProductCallCount()).To(Equal(1))
+			})
+
+			By("outputting the results", func() {
+				Expect(output.PrintHeaderArgsForCall(0)).To(Equal("Products in the catalog matching \"tanzu postgres\""))
+				Expect(output.RenderCatalogSearchCallCount()).To(Equal(1))
+				results := output.RenderCatalogSearchArgsForCall(0)
+				Expect(results.Total).To(Equal(1))
+				Expect(results.Results[0].Slug).To(Equal("tanzu-postgres"))
+			})
+		})
+	})
+})
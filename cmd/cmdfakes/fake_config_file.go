@@ -0,0 +1,187 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package cmdfakes
+
+import (
+	"sync"
+
+	"github.com/vmware-labs/marketplace-cli/v2/cmd"
+)
+
+type FakeConfigFile struct {
+	SetStub        func(string, string) error
+	setMutex       sync.RWMutex
+	setArgsForCall []struct {
+		arg1 string
+		arg2 string
+	}
+	setReturns struct {
+		result1 error
+	}
+	setReturnsOnCall map[int]struct {
+		result1 error
+	}
+	UnsetStub        func(string) error
+	unsetMutex       sync.RWMutex
+	unsetArgsForCall []struct {
+		arg1 string
+	}
+	unsetReturns struct {
+		result1 error
+	}
+	unsetReturnsOnCall map[int]struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeConfigFile) Set(arg1 string, arg2 string) error {
+	fake.setMutex.Lock()
+	ret, specificReturn := fake.setReturnsOnCall[len(fake.setArgsForCall)]
+	fake.setArgsForCall = append(fake.setArgsForCall, struct {
+		arg1 string
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.SetStub
+	fakeReturns := fake.setReturns
+	fake.recordInvocation("Set", []interface{}{arg1, arg2})
+	fake.setMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeConfigFile) SetCallCount() int {
+	fake.setMutex.RLock()
+	defer fake.setMutex.RUnlock()
+	return len(fake.setArgsForCall)
+}
+
+func (fake *FakeConfigFile) SetCalls(stub func(string, string) error) {
+	fake.setMutex.Lock()
+	defer fake.setMutex.Unlock()
+	fake.SetStub = stub
+}
+
+func (fake *FakeConfigFile) SetArgsForCall(i int) (string, string) {
+	fake.setMutex.RLock()
+	defer fake.setMutex.RUnlock()
+	argsForCall := fake.setArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeConfigFile) SetReturns(result1 error) {
+	fake.setMutex.Lock()
+	defer fake.setMutex.Unlock()
+	fake.SetStub = nil
+	fake.setReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeConfigFile) SetReturnsOnCall(i int, result1 error) {
+	fake.setMutex.Lock()
+	defer fake.setMutex.Unlock()
+	fake.SetStub = nil
+	if fake.setReturnsOnCall == nil {
+		fake.setReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.setReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeConfigFile) Unset(arg1 string) error {
+	fake.unsetMutex.Lock()
+	ret, specificReturn := fake.unsetReturnsOnCall[len(fake.unsetArgsForCall)]
+	fake.unsetArgsForCall = append(fake.unsetArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.UnsetStub
+	fakeReturns := fake.unsetReturns
+	fake.recordInvocation("Unset", []interface{}{arg1})
+	fake.unsetMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeConfigFile) UnsetCallCount() int {
+	fake.unsetMutex.RLock()
+	defer fake.unsetMutex.RUnlock()
+	return len(fake.unsetArgsForCall)
+}
+
+func (fake *FakeConfigFile) UnsetCalls(stub func(string) error) {
+	fake.unsetMutex.Lock()
+	defer fake.unsetMutex.Unlock()
+	fake.UnsetStub = stub
+}
+
+func (fake *FakeConfigFile) UnsetArgsForCall(i int) string {
+	fake.unsetMutex.RLock()
+	defer fake.unsetMutex.RUnlock()
+	argsForCall := fake.unsetArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeConfigFile) UnsetReturns(result1 error) {
+	fake.unsetMutex.Lock()
+	defer fake.unsetMutex.Unlock()
+	fake.UnsetStub = nil
+	fake.unsetReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeConfigFile) UnsetReturnsOnCall(i int, result1 error) {
+	fake.unsetMutex.Lock()
+	defer fake.unsetMutex.Unlock()
+	fake.UnsetStub = nil
+	if fake.unsetReturnsOnCall == nil {
+		fake.unsetReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.unsetReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeConfigFile) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.setMutex.RLock()
+	defer fake.setMutex.RUnlock()
+	fake.unsetMutex.RLock()
+	defer fake.unsetMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeConfigFile) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ cmd.ConfigFile = new(FakeConfigFile)
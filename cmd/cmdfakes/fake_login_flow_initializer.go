@@ -0,0 +1,117 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package cmdfakes
+
+import (
+	"io"
+	"sync"
+
+	"github.com/vmware-labs/marketplace-cli/v2/cmd"
+)
+
+type FakeLoginFlowInitializer struct {
+	Stub        func(string, string, int, io.Writer) cmd.LoginFlow
+	mutex       sync.RWMutex
+	argsForCall []struct {
+		arg1 string
+		arg2 string
+		arg3 int
+		arg4 io.Writer
+	}
+	returns struct {
+		result1 cmd.LoginFlow
+	}
+	returnsOnCall map[int]struct {
+		result1 cmd.LoginFlow
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeLoginFlowInitializer) Spy(arg1 string, arg2 string, arg3 int, arg4 io.Writer) cmd.LoginFlow {
+	fake.mutex.Lock()
+	ret, specificReturn := fake.returnsOnCall[len(fake.argsForCall)]
+	fake.argsForCall = append(fake.argsForCall, struct {
+		arg1 string
+		arg2 string
+		arg3 int
+		arg4 io.Writer
+	}{arg1, arg2, arg3, arg4})
+	stub := fake.Stub
+	returns := fake.returns
+	fake.recordInvocation("LoginFlowInitializer", []interface{}{arg1, arg2, arg3, arg4})
+	fake.mutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3, arg4)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return returns.result1
+}
+
+func (fake *FakeLoginFlowInitializer) CallCount() int {
+	fake.mutex.RLock()
+	defer fake.mutex.RUnlock()
+	return len(fake.argsForCall)
+}
+
+func (fake *FakeLoginFlowInitializer) Calls(stub func(string, string, int, io.Writer) cmd.LoginFlow) {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+	fake.Stub = stub
+}
+
+func (fake *FakeLoginFlowInitializer) ArgsForCall(i int) (string, string, int, io.Writer) {
+	fake.mutex.RLock()
+	defer fake.mutex.RUnlock()
+	return fake.argsForCall[i].arg1, fake.argsForCall[i].arg2, fake.argsForCall[i].arg3, fake.argsForCall[i].arg4
+}
+
+func (fake *FakeLoginFlowInitializer) Returns(result1 cmd.LoginFlow) {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+	fake.Stub = nil
+	fake.returns = struct {
+		result1 cmd.LoginFlow
+	}{result1}
+}
+
+func (fake *FakeLoginFlowInitializer) ReturnsOnCall(i int, result1 cmd.LoginFlow) {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+	fake.Stub = nil
+	if fake.returnsOnCall == nil {
+		fake.returnsOnCall = make(map[int]struct {
+			result1 cmd.LoginFlow
+		})
+	}
+	fake.returnsOnCall[i] = struct {
+		result1 cmd.LoginFlow
+	}{result1}
+}
+
+func (fake *FakeLoginFlowInitializer) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.mutex.RLock()
+	defer fake.mutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeLoginFlowInitializer) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ cmd.LoginFlowInitializer = new(FakeLoginFlowInitializer).Spy
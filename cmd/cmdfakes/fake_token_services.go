@@ -2,6 +2,7 @@
 package cmdfakes
 
 import (
+	"context"
 	"sync"
 
 	"github.com/vmware-labs/marketplace-cli/v2/cmd"
@@ -9,16 +10,17 @@ import (
 )
 
 type FakeTokenServices struct {
-	RedeemStub        func(string) (*csp.Claims, error)
-	redeemMutex       sync.RWMutex
-	redeemArgsForCall []struct {
-		arg1 string
+	AuthenticateStub        func(context.Context, *csp.Credentials) (*csp.Claims, error)
+	authenticateMutex       sync.RWMutex
+	authenticateArgsForCall []struct {
+		arg1 context.Context
+		arg2 *csp.Credentials
 	}
-	redeemReturns struct {
+	authenticateReturns struct {
 		result1 *csp.Claims
 		result2 error
 	}
-	redeemReturnsOnCall map[int]struct {
+	authenticateReturnsOnCall map[int]struct {
 		result1 *csp.Claims
 		result2 error
 	}
@@ -26,64 +28,66 @@ type FakeTokenServices struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeTokenServices) Redeem(arg1 string) (*csp.Claims, error) {
-	fake.redeemMutex.Lock()
-	ret, specificReturn := fake.redeemReturnsOnCall[len(fake.redeemArgsForCall)]
-	fake.redeemArgsForCall = append(fake.redeemArgsForCall, struct {
-		arg1 string
-	}{arg1})
-	fake.recordInvocation("Redeem", []interface{}{arg1})
-	fake.redeemMutex.Unlock()
-	if fake.RedeemStub != nil {
-		return fake.RedeemStub(arg1)
+func (fake *FakeTokenServices) Authenticate(arg1 context.Context, arg2 *csp.Credentials) (*csp.Claims, error) {
+	fake.authenticateMutex.Lock()
+	ret, specificReturn := fake.authenticateReturnsOnCall[len(fake.authenticateArgsForCall)]
+	fake.authenticateArgsForCall = append(fake.authenticateArgsForCall, struct {
+		arg1 context.Context
+		arg2 *csp.Credentials
+	}{arg1, arg2})
+	stub := fake.AuthenticateStub
+	fakeReturns := fake.authenticateReturns
+	fake.recordInvocation("Authenticate", []interface{}{arg1, arg2})
+	fake.authenticateMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	fakeReturns := fake.redeemReturns
 	return fakeReturns.result1, fakeReturns.result2
 }
 
-func (fake *FakeTokenServices) RedeemCallCount() int {
-	fake.redeemMutex.RLock()
-	defer fake.redeemMutex.RUnlock()
-	return len(fake.redeemArgsForCall)
+func (fake *FakeTokenServices) AuthenticateCallCount() int {
+	fake.authenticateMutex.RLock()
+	defer fake.authenticateMutex.RUnlock()
+	return len(fake.authenticateArgsForCall)
 }
 
-func (fake *FakeTokenServices) RedeemCalls(stub func(string) (*csp.Claims, error)) {
-	fake.redeemMutex.Lock()
-	defer fake.redeemMutex.Unlock()
-	fake.RedeemStub = stub
+func (fake *FakeTokenServices) AuthenticateCalls(stub func(context.Context, *csp.Credentials) (*csp.Claims, error)) {
+	fake.authenticateMutex.Lock()
+	defer fake.authenticateMutex.Unlock()
+	fake.AuthenticateStub = stub
 }
 
-func (fake *FakeTokenServices) RedeemArgsForCall(i int) string {
-	fake.redeemMutex.RLock()
-	defer fake.redeemMutex.RUnlock()
-	argsForCall := fake.redeemArgsForCall[i]
-	return argsForCall.arg1
+func (fake *FakeTokenServices) AuthenticateArgsForCall(i int) (context.Context, *csp.Credentials) {
+	fake.authenticateMutex.RLock()
+	defer fake.authenticateMutex.RUnlock()
+	argsForCall := fake.authenticateArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
 }
 
-func (fake *FakeTokenServices) RedeemReturns(result1 *csp.Claims, result2 error) {
-	fake.redeemMutex.Lock()
-	defer fake.redeemMutex.Unlock()
-	fake.RedeemStub = nil
-	fake.redeemReturns = struct {
+func (fake *FakeTokenServices) AuthenticateReturns(result1 *csp.Claims, result2 error) {
+	fake.authenticateMutex.Lock()
+	defer fake.authenticateMutex.Unlock()
+	fake.AuthenticateStub = nil
+	fake.authenticateReturns = struct {
 		result1 *csp.Claims
 		result2 error
 	}{result1, result2}
 }
 
-func (fake *FakeTokenServices) RedeemReturnsOnCall(i int, result1 *csp.Claims, result2 error) {
-	fake.redeemMutex.Lock()
-	defer fake.redeemMutex.Unlock()
-	fake.RedeemStub = nil
-	if fake.redeemReturnsOnCall == nil {
-		fake.redeemReturnsOnCall = make(map[int]struct {
+func (fake *FakeTokenServices) AuthenticateReturnsOnCall(i int, result1 *csp.Claims, result2 error) {
+	fake.authenticateMutex.Lock()
+	defer fake.authenticateMutex.Unlock()
+	fake.AuthenticateStub = nil
+	if fake.authenticateReturnsOnCall == nil {
+		fake.authenticateReturnsOnCall = make(map[int]struct {
 			result1 *csp.Claims
 			result2 error
 		})
 	}
-	fake.redeemReturnsOnCall[i] = struct {
+	fake.authenticateReturnsOnCall[i] = struct {
 		result1 *csp.Claims
 		result2 error
 	}{result1, result2}
@@ -92,8 +96,8 @@ func (fake *FakeTokenServices) RedeemReturnsOnCall(i int, result1 *csp.Claims, r
 func (fake *FakeTokenServices) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
-	fake.redeemMutex.RLock()
-	defer fake.redeemMutex.RUnlock()
+	fake.authenticateMutex.RLock()
+	defer fake.authenticateMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -2,16 +2,18 @@
 package cmdfakes
 
 import (
+	"context"
 	"sync"
 
 	"github.com/vmware-labs/marketplace-cli/v2/cmd"
 )
 
 type FakeTokenServicesInitializer struct {
-	Stub        func(string) (cmd.TokenServices, error)
+	Stub        func(context.Context, string) (cmd.TokenServices, error)
 	mutex       sync.RWMutex
 	argsForCall []struct {
-		arg1 string
+		arg1 context.Context
+		arg2 string
 	}
 	returns struct {
 		result1 cmd.TokenServices
@@ -25,21 +27,24 @@ type FakeTokenServicesInitializer struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeTokenServicesInitializer) Spy(arg1 string) (cmd.TokenServices, error) {
+func (fake *FakeTokenServicesInitializer) Spy(arg1 context.Context, arg2 string) (cmd.TokenServices, error) {
 	fake.mutex.Lock()
 	ret, specificReturn := fake.returnsOnCall[len(fake.argsForCall)]
 	fake.argsForCall = append(fake.argsForCall, struct {
-		arg1 string
-	}{arg1})
-	fake.recordInvocation("TokenServicesInitializer", []interface{}{arg1})
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.Stub
+	returns := fake.returns
+	fake.recordInvocation("TokenServicesInitializer", []interface{}{arg1, arg2})
 	fake.mutex.Unlock()
-	if fake.Stub != nil {
-		return fake.Stub(arg1)
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
 	}
-	return fake.returns.result1, fake.returns.result2
+	return returns.result1, returns.result2
 }
 
 func (fake *FakeTokenServicesInitializer) CallCount() int {
@@ -48,16 +53,16 @@ func (fake *FakeTokenServicesInitializer) CallCount() int {
 	return len(fake.argsForCall)
 }
 
-func (fake *FakeTokenServicesInitializer) Calls(stub func(string) (cmd.TokenServices, error)) {
+func (fake *FakeTokenServicesInitializer) Calls(stub func(context.Context, string) (cmd.TokenServices, error)) {
 	fake.mutex.Lock()
 	defer fake.mutex.Unlock()
 	fake.Stub = stub
 }
 
-func (fake *FakeTokenServicesInitializer) ArgsForCall(i int) string {
+func (fake *FakeTokenServicesInitializer) ArgsForCall(i int) (context.Context, string) {
 	fake.mutex.RLock()
 	defer fake.mutex.RUnlock()
-	return fake.argsForCall[i].arg1
+	return fake.argsForCall[i].arg1, fake.argsForCall[i].arg2
 }
 
 func (fake *FakeTokenServicesInitializer) Returns(result1 cmd.TokenServices, result2 error) {
@@ -5,22 +5,131 @@ package cmd
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/config"
 )
 
+//go:generate counterfeiter . ConfigFile
+type ConfigFile interface {
+	Set(key, value string) error
+	Unset(key string) error
+}
+
+var (
+	SettingsFile ConfigFile
+
+	// Where each setting can come from, recorded when binding them, so that "config view" can show the source
+	configEnvs    = map[string]string{}
+	configFlags   = map[string]*pflag.Flag{}
+	configProfile = map[string]bool{}
+)
+
+func bindEnv(key, env string) {
+	configEnvs[key] = env
+	_ = viper.BindEnv(key, env)
+}
+
+func bindFlag(key string, flag *pflag.Flag) {
+	configFlags[key] = flag
+	_ = viper.BindPFlag(key, flag)
+}
+
+func defaultConfigFilePath() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(configDir, AppName, "config.yaml")
+}
+
+// ReadConfigFile loads the config file into the settings. Flags and environment variables take precedence over it.
+func ReadConfigFile() error {
+	path := viper.GetString("config-file")
+	SettingsFile = config.NewFile(path)
+	if path == "" {
+		return nil
+	}
+
+	viper.SetConfigFile(path)
+	viper.SetConfigType("yaml")
+	err := viper.ReadInConfig()
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to read the config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// ConfigSource returns where the effective value of a setting came from
+func ConfigSource(key string) string {
+	if flag, ok := configFlags[key]; ok && flag.Changed {
+		return config.SourceFlag
+	}
+	if env, ok := configEnvs[key]; ok && os.Getenv(env) != "" {
+		return config.SourceEnv
+	}
+	if viper.InConfig(key) {
+		return config.SourceFile
+	}
+	if configProfile[key] {
+		return config.SourceProfile
+	}
+	return config.SourceDefault
+}
+
+func isKnownSetting(key string) bool {
+	for _, known := range viper.AllKeys() {
+		if key == known {
+			return true
+		}
+	}
+	return false
+}
+
+func redactAll(prefix string, settings map[string]interface{}) map[string]interface{} {
+	redacted := map[string]interface{}{}
+	for key, value := range settings {
+		if nested, ok := value.(map[string]interface{}); ok {
+			redacted[key] = redactAll(prefix+key+".", nested)
+		} else {
+			redacted[key] = config.Redact(prefix+key, value)
+		}
+	}
+	return redacted
+}
+
+func ValidateConfigKey(cmd *cobra.Command, args []string) error {
+	if !isKnownSetting(args[0]) {
+		return fmt.Errorf("unknown setting: %s", args[0])
+	}
+	if args[0] == "config-file" {
+		return errors.New("the config file location cannot be set in the config file")
+	}
+	return nil
+}
+
 func init() {
 	rootCmd.AddCommand(ConfigCmd)
 	ConfigCmd.SetOut(ConfigCmd.OutOrStdout())
+	ConfigCmd.AddCommand(ConfigViewCmd, ConfigGetCmd, ConfigSetCmd, ConfigUnsetCmd)
 }
 
 var ConfigCmd = &cobra.Command{
-	Use:    "config",
-	Long:   "Prints the current config",
-	Hidden: true,
+	Use:   "config",
+	Short: "Show and change settings",
+	Long:  "Prints the current config, with secrets redacted",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		config := viper.AllSettings()
+		config := redactAll("", viper.AllSettings())
 		formattedConfig, err := json.MarshalIndent(config, "", "  ")
 		if err != nil {
 			return err
@@ -29,3 +138,66 @@ var ConfigCmd = &cobra.Command{
 		return nil
 	},
 }
+
+var ConfigViewCmd = &cobra.Command{
+	Use:     "view",
+	Short:   "Show all settings and where they came from",
+	Long:    "Shows the effective value of each setting, and whether it came from a flag, an environment variable, the config file, the profile, or the default",
+	Example: fmt.Sprintf("%s config view", AppName),
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		keys := viper.AllKeys()
+		sort.Strings(keys)
+
+		var settings []*config.Setting
+		for _, key := range keys {
+			settings = append(settings, &config.Setting{
+				Key:    key,
+				Value:  config.Redact(key, viper.Get(key)),
+				Source: ConfigSource(key),
+			})
+		}
+		return Output.RenderConfig(settings)
+	},
+}
+
+var ConfigGetCmd = &cobra.Command{
+	Use:     "get <key>",
+	Short:   "Show a setting",
+	Long:    "Prints the effective value of a setting",
+	Example: fmt.Sprintf("%s config get marketplace.host", AppName),
+	Args:    cobra.ExactArgs(1),
+	PreRunE: ValidateConfigKey,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		cmd.Println(config.Redact(args[0], viper.Get(args[0])))
+		return nil
+	},
+}
+
+var ConfigSetCmd = &cobra.Command{
+	Use:     "set <key> <value>",
+	Short:   "Save a setting in the config file",
+	Long:    "Saves a setting in the config file. Flags and environment variables still take precedence over it.",
+	Example: fmt.Sprintf("%s config set output_format yaml", AppName),
+	Args:    cobra.ExactArgs(2),
+	PreRunE: ValidateConfigKey,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		return SettingsFile.Set(args[0], args[1])
+	},
+}
+
+var ConfigUnsetCmd = &cobra.Command{
+	Use:     "unset <key>",
+	Short:   "Remove a setting from the config file",
+	Long:    "Removes a setting from the config file, so the default is used again",
+	Example: fmt.Sprintf("%s config unset output_format", AppName),
+	Args:    cobra.ExactArgs(1),
+	PreRunE: ValidateConfigKey,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		return SettingsFile.Unset(args[0])
+	},
+}
@@ -0,0 +1,181 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: BSD-2-Clause
+
+package cmd_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gbytes"
+	"github.com/spf13/viper"
+
+	. "github.com/vmware-labs/marketplace-cli/v2/cmd"
+	"github.com/vmware-labs/marketplace-cli/v2/cmd/cmdfakes"
+	"github.com/vmware-labs/marketplace-cli/v2/cmd/output/outputfakes"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/config"
+)
+
+var _ = Describe("Config", func() {
+	var settingsFile *cmdfakes.FakeConfigFile
+
+	BeforeEach(func() {
+		settingsFile = &cmdfakes.FakeConfigFile{}
+		SettingsFile = settingsFile
+		viper.Set("csp.api-token", nil)
+	})
+
+	Describe("ReadConfigFile", func() {
+		var dir string
+
+		BeforeEach(func() {
+			var err error
+			dir, err = os.MkdirTemp("", "mkpcli-config-test")
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			// Replace the loaded config with an empty one, so it does not affect other tests
+			emptyConfig := filepath.Join(dir, "empty.yaml")
+			Expect(os.WriteFile(emptyConfig, []byte{}, 0600)).To(Succeed())
+			viper.Set("config-file", emptyConfig)
+			Expect(ReadConfigFile()).To(Succeed())
+
+			viper.Set("config-file", nil)
+			Expect(os.RemoveAll(dir)).To(Succeed())
+		})
+
+		It("loads the settings from the config file", func() {
+			configFile := filepath.Join(dir, "config.yaml")
+			Expect(os.WriteFile(configFile, []byte("csp:\n  credential-helper: my-helper\n"), 0600)).To(Succeed())
+			viper.Set("config-file", configFile)
+
+			Expect(ReadConfigFile()).To(Succeed())
+			Expect(viper.GetString("csp.credential-helper")).To(Equal("my-helper"))
+			Expect(ConfigSource("csp.credential-helper")).To(Equal(config.SourceFile))
+		})
+
+		It("ignores a config file that does not exist", func() {
+			viper.Set("config-file", filepath.Join(dir, "missing.yaml"))
+			Expect(ReadConfigFile()).To(Succeed())
+		})
+
+		When("the config file is not valid", func() {
+			It("returns an error", func() {
+				configFile := filepath.Join(dir, "config.yaml")
+				Expect(os.WriteFile(configFile, []byte("not: [valid"), 0600)).To(Succeed())
+				viper.Set("config-file", configFile)
+
+				err := ReadConfigFile()
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(HavePrefix("failed to read the config file " + configFile))
+			})
+		})
+	})
+
+	Describe("ConfigSource", func() {
+		AfterEach(func() {
+			Expect(os.Unsetenv("CSP_CREDENTIAL_HELPER")).To(Succeed())
+		})
+
+		It("reports settings from environment variables", func() {
+			Expect(os.Setenv("CSP_CREDENTIAL_HELPER", "my-helper")).To(Succeed())
+			Expect(ConfigSource("csp.credential-helper")).To(Equal(config.SourceEnv))
+		})
+
+		It("reports settings that were not changed as defaults", func() {
+			Expect(ConfigSource("csp.credential-helper")).To(Equal(config.SourceDefault))
+		})
+	})
+
+	Describe("ConfigCmd", func() {
+		It("redacts secrets", func() {
+			viper.Set("csp.api-token", "my-api-token")
+			stdout := NewBuffer()
+			ConfigCmd.SetOut(stdout)
+
+			Expect(ConfigCmd.RunE(ConfigCmd, []string{})).To(Succeed())
+			Expect(stdout).To(Say(`"api-token": "\[redacted\]"`))
+			Expect(stdout.Contents()).ToNot(ContainSubstring("my-api-token"))
+		})
+	})
+
+	Describe("ConfigViewCmd", func() {
+		It("renders each setting with its source", func() {
+			output := &outputfakes.FakeFormat{}
+			Output = output
+			viper.Set("csp.api-token", "my-api-token")
+
+			Expect(ConfigViewCmd.RunE(ConfigViewCmd, []string{})).To(Succeed())
+			Expect(output.RenderConfigCallCount()).To(Equal(1))
+
+			settings := map[string]*config.Setting{}
+			for _, setting := range output.RenderConfigArgsForCall(0) {
+				settings[setting.Key] = setting
+			}
+			Expect(settings["csp.api-token"].Value).To(Equal(config.RedactedValue))
+			Expect(settings["csp.credential-helper"].Source).To(Equal(config.SourceDefault))
+		})
+	})
+
+	Describe("ConfigGetCmd", func() {
+		var stdout *bytes.Buffer
+
+		BeforeEach(func() {
+			stdout = &bytes.Buffer{}
+			ConfigGetCmd.SetOut(stdout)
+		})
+
+		It("prints the setting", func() {
+			viper.Set("csp.credential-helper", "my-helper")
+			Expect(ConfigGetCmd.RunE(ConfigGetCmd, []string{"csp.credential-helper"})).To(Succeed())
+			Expect(stdout.String()).To(Equal("my-helper\n"))
+			viper.Set("csp.credential-helper", nil)
+		})
+
+		It("redacts secrets", func() {
+			viper.Set("csp.api-token", "my-api-token")
+			Expect(ConfigGetCmd.RunE(ConfigGetCmd, []string{"csp.api-token"})).To(Succeed())
+			Expect(stdout.String()).To(Equal("[redacted]\n"))
+		})
+
+		When("the setting does not exist", func() {
+			It("returns an error", func() {
+				err := ConfigGetCmd.PreRunE(ConfigGetCmd, []string{"bogus"})
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("unknown setting: bogus"))
+			})
+		})
+	})
+
+	Describe("ConfigSetCmd", func() {
+		It("saves the setting in the config file", func() {
+			Expect(ConfigSetCmd.PreRunE(ConfigSetCmd, []string{"csp.credential-helper", "my-helper"})).To(Succeed())
+			Expect(ConfigSetCmd.RunE(ConfigSetCmd, []string{"csp.credential-helper", "my-helper"})).To(Succeed())
+
+			Expect(settingsFile.SetCallCount()).To(Equal(1))
+			key, value := settingsFile.SetArgsForCall(0)
+			Expect(key).To(Equal("csp.credential-helper"))
+			Expect(value).To(Equal("my-helper"))
+		})
+
+		When("setting the config file location", func() {
+			It("returns an error", func() {
+				err := ConfigSetCmd.PreRunE(ConfigSetCmd, []string{"config-file", "other.yaml"})
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("the config file location cannot be set in the config file"))
+			})
+		})
+	})
+
+	Describe("ConfigUnsetCmd", func() {
+		It("removes the setting from the config file", func() {
+			Expect(ConfigUnsetCmd.RunE(ConfigUnsetCmd, []string{"csp.credential-helper"})).To(Succeed())
+			Expect(settingsFile.UnsetCallCount()).To(Equal(1))
+			Expect(settingsFile.UnsetArgsForCall(0)).To(Equal("csp.credential-helper"))
+		})
+	})
+})
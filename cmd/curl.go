@@ -5,33 +5,53 @@ package cmd
 
 import (
 	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net/http"
 	"net/url"
+	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/tidwall/gjson"
+	"github.com/vmware-labs/marketplace-cli/v2/internal"
 	"github.com/vmware-labs/marketplace-cli/v2/pkg"
 )
 
+// CurlPageItems is where the list of items is found in each page of a paginated marketplace response
+const CurlPageItems = "response.dataList"
+
 var (
-	method     = "GET"
-	payload    string
-	useAPIHost = false
+	CurlMethod     = "GET"
+	CurlPayload    string
+	CurlUseAPIHost = false
+	CurlHeaders    []string
+	CurlOutputFile string
+	CurlPaginate   = false
+	CurlQuery      string
 )
 
 func init() {
-	rootCmd.AddCommand(curlCmd)
-	curlCmd.SetOut(curlCmd.OutOrStdout())
-	curlCmd.Flags().StringVarP(&method, "method", "X", method, "HTTP verb to use")
-	curlCmd.Flags().StringVar(&payload, "payload", "", "JSON file containing the payload to send as a request body")
-	curlCmd.Flags().BoolVar(&useAPIHost, "use-api-host", false, "Send request to the API host, rather than the gateway host")
+	rootCmd.AddCommand(CurlCmd)
+	CurlCmd.SetOut(CurlCmd.OutOrStdout())
+	CurlCmd.Flags().StringVarP(&CurlMethod, "method", "X", CurlMethod, "HTTP verb to use")
+	CurlCmd.Flags().StringVar(&CurlPayload, "payload", "", "JSON file containing the payload to send as a request body, or - to read it from stdin")
+	CurlCmd.Flags().BoolVar(&CurlUseAPIHost, "use-api-host", false, "Send request to the API host, rather than the gateway host")
+	CurlCmd.Flags().StringArrayVarP(&CurlHeaders, "header", "H", []string{}, "Header to send, like \"Accept: application/json\". Can be repeated")
+	CurlCmd.Flags().StringVarP(&CurlOutputFile, "output", "o", "", "Write the response body to this file, instead of stdout")
+	CurlCmd.Flags().BoolVar(&CurlPaginate, "paginate", false, fmt.Sprintf("Fetch every page by following the pagination parameter, and print the combined list of items from %s", CurlPageItems))
+	CurlCmd.Flags().StringVar(&CurlQuery, "query", "", "Only print the part of the response matching this gjson path, like response.data.slug")
 }
 
-var curlCmd = &cobra.Command{
-	Use:     "curl [/api/v1/path]",
-	Long:    "Sends an HTTP request to the Marketplace",
-	Example: fmt.Sprintf("%s curl /api/v1/products", AppName),
+var CurlCmd = &cobra.Command{
+	Use:  "curl [/api/v1/path]",
+	Long: "Sends an HTTP request to the Marketplace. For this command, -o/--output is the file to write the response body to.",
+	Example: fmt.Sprintf(`%[1]s curl /api/v1/products
+%[1]s curl /api/v1/products --paginate --query '#.slug'
+%[1]s curl -X POST -H "X-Request-Id: 1234" --payload - /api/v1/products < product.json`, AppName),
 	Hidden:  true,
 	PreRunE: GetRefreshToken,
 	Args:    cobra.ExactArgs(1),
@@ -44,39 +64,183 @@ var curlCmd = &cobra.Command{
 		}
 
 		host := Marketplace.GetHost()
-		if useAPIHost {
+		if CurlUseAPIHost {
 			host = Marketplace.GetAPIHost()
 		}
 
 		requestURL := pkg.MakeURL(host, inputURL.Path, inputURL.Query())
 
-		cmd.PrintErrf("Sending %s request to %s...\n", method, requestURL.String())
+		headers, err := parseCurlHeaders(CurlHeaders)
+		if err != nil {
+			return err
+		}
 
-		var content io.Reader
-		headers := map[string]string{}
-		if payload != "" {
-			payloadBytes, err := ioutil.ReadFile(payload)
+		var content []byte
+		if CurlPayload != "" {
+			content, err = readCurlPayload(cmd, CurlPayload)
 			if err != nil {
-				return fmt.Errorf("failed to read payload file: %w", err)
+				return err
+			}
+			if _, ok := headers["Content-Type"]; !ok {
+				headers["Content-Type"] = "application/json"
 			}
-			content = bytes.NewReader(payloadBytes)
-			headers["Content-Type"] = "application/json"
 		}
 
-		resp, err := Client.SendRequest(method, requestURL, headers, content)
+		var body []byte
+		if CurlPaginate {
+			body, err = fetchAllCurlPages(cmd, requestURL, headers, content)
+		} else {
+			body, err = sendCurlRequest(cmd, requestURL, headers, content)
+		}
+		if body == nil {
+			return err
+		}
+
+		if CurlQuery != "" && err == nil {
+			body, err = queryCurlResponse(body, CurlQuery)
+			if err != nil {
+				return err
+			}
+		}
+
+		writeErr := writeCurlResponse(cmd, body)
 		if err != nil {
 			return err
 		}
+		return writeErr
+	},
+}
 
-		cmd.PrintErrf("Response status %d\n", resp.StatusCode)
+func parseCurlHeaders(values []string) (map[string]string, error) {
+	headers := map[string]string{}
+	for _, value := range values {
+		parts := strings.SplitN(value, ":", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return nil, fmt.Errorf("invalid header %q: must be in the form \"Name: value\"", value)
+		}
+		headers[http.CanonicalHeaderKey(strings.TrimSpace(parts[0]))] = strings.TrimSpace(parts[1])
+	}
+	return headers, nil
+}
 
-		body, err := ioutil.ReadAll(resp.Body)
+func readCurlPayload(cmd *cobra.Command, source string) ([]byte, error) {
+	if source == "-" {
+		content, err := ioutil.ReadAll(cmd.InOrStdin())
 		if err != nil {
-			return fmt.Errorf("failed to read response: %w", err)
+			return nil, fmt.Errorf("failed to read payload from stdin: %w", err)
 		}
+		return content, nil
+	}
+
+	content, err := ioutil.ReadFile(source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read payload file: %w", err)
+	}
+	return content, nil
+}
+
+// sendCurlRequest returns the response body. If the response is an error status, the body is returned along with an
+// error, so that the reason can still be printed.
+func sendCurlRequest(cmd *cobra.Command, requestURL *url.URL, headers map[string]string, content []byte) ([]byte, error) {
+	cmd.PrintErrf("Sending %s request to %s...\n", CurlMethod, requestURL.String())
+
+	var reader io.Reader
+	if content != nil {
+		reader = bytes.NewReader(content)
+	}
+	resp, err := Client.SendRequest(cmd.Context(), CurlMethod, requestURL, headers, reader)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	cmd.PrintErrf("Response status %d\n", resp.StatusCode)
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
 
-		cmd.PrintErrln("Body:")
+	if resp.StatusCode >= http.StatusBadRequest {
+		return body, fmt.Errorf("the request failed with status %d", resp.StatusCode)
+	}
+	return body, nil
+}
+
+// fetchAllCurlPages sends the request for every page, starting from the page in the pagination query parameter,
+// until a page is empty or short, or every item has been fetched. It returns all of the items as one JSON list.
+func fetchAllCurlPages(cmd *cobra.Command, requestURL *url.URL, headers map[string]string, content []byte) ([]byte, error) {
+	pagination := &internal.Pagination{
+		Page:     1,
+		PageSize: 20,
+	}
+	query := requestURL.Query()
+	if value := query.Get("pagination"); value != "" {
+		err := json.Unmarshal([]byte(value), pagination)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pagination parameter %q: %w", value, err)
+		}
+		if pagination.Page < 1 || pagination.PageSize < 1 {
+			return nil, fmt.Errorf("invalid pagination parameter %q: the page and page size must be at least 1", value)
+		}
+	}
+	query.Del("pagination")
+
+	items := []json.RawMessage{}
+	for {
+		pageURL := *requestURL
+		pageURL.RawQuery = query.Encode()
+		pkg.ApplyParameters(&pageURL, pagination)
+
+		body, err := sendCurlRequest(cmd, &pageURL, headers, content)
+		if err != nil {
+			return body, err
+		}
+
+		page := gjson.GetBytes(body, CurlPageItems)
+		if !page.IsArray() {
+			return body, fmt.Errorf("cannot paginate: the response has no list of items in %s", CurlPageItems)
+		}
+		pageItems := page.Array()
+		for _, item := range pageItems {
+			items = append(items, json.RawMessage(item.Raw))
+		}
+
+		total := gjson.GetBytes(body, "response.params.itemsnumber")
+		if len(pageItems) < int(pagination.PageSize) || (total.Exists() && len(items) >= int(total.Int())) {
+			break
+		}
+		pagination.Page++
+	}
+
+	return json.Marshal(items)
+}
+
+func queryCurlResponse(body []byte, query string) ([]byte, error) {
+	if !gjson.ValidBytes(body) {
+		return nil, errors.New("cannot query the response: it is not valid JSON")
+	}
+
+	result := gjson.GetBytes(body, query)
+	if !result.Exists() {
+		return nil, fmt.Errorf("nothing in the response matches the query %q", query)
+	}
+	if result.Type == gjson.String {
+		return []byte(result.String()), nil
+	}
+	return []byte(result.Raw), nil
+}
+
+func writeCurlResponse(cmd *cobra.Command, body []byte) error {
+	if CurlOutputFile == "" {
 		cmd.Println(string(body))
 		return nil
-	},
+	}
+
+	err := os.WriteFile(CurlOutputFile, body, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to write the response to %s: %w", CurlOutputFile, err)
+	}
+	cmd.PrintErrf("Saved the response to %s\n", CurlOutputFile)
+	return nil
 }
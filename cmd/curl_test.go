@@ -0,0 +1,223 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: BSD-2-Clause
+
+package cmd_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gbytes"
+
+	. "github.com/vmware-labs/marketplace-cli/v2/cmd"
+	"github.com/vmware-labs/marketplace-cli/v2/pkg/pkgfakes"
+)
+
+func makeCurlResponse(statusCode int, body string) *http.Response {
+	return &http.Response{
+		Status:     fmt.Sprintf("%d %s", statusCode, http.StatusText(statusCode)),
+		StatusCode: statusCode,
+		Body:       ioutil.NopCloser(strings.NewReader(body)),
+	}
+}
+
+func makeProductsPage(total int, slugs ...string) string {
+	var products []string
+	for _, slug := range slugs {
+		products = append(products, fmt.Sprintf(`{"slug":%q}`, slug))
+	}
+	return fmt.Sprintf(`{"response":{"dataList":[%s],"params":{"itemsnumber":%d}}}`, strings.Join(products, ","), total)
+}
+
+var _ = Describe("CurlCmd", func() {
+	var (
+		httpClient  *pkgfakes.FakeHTTPClient
+		marketplace *pkgfakes.FakeMarketplaceInterface
+		stdout      *Buffer
+		stderr      *Buffer
+	)
+
+	BeforeEach(func() {
+		httpClient = &pkgfakes.FakeHTTPClient{}
+		httpClient.SendRequestReturns(makeCurlResponse(http.StatusOK, `{"response":{"data":{"slug":"hyperspace-database","displayname":"Hyperspace Database"}}}`), nil)
+		Client = httpClient
+
+		marketplace = &pkgfakes.FakeMarketplaceInterface{}
+		marketplace.GetHostReturns("gtw.marketplace.example.com")
+		marketplace.GetAPIHostReturns("api.marketplace.example.com")
+		Marketplace = marketplace
+
+		CurlMethod = "GET"
+		CurlPayload = ""
+		CurlUseAPIHost = false
+		CurlHeaders = []string{}
+		CurlOutputFile = ""
+		CurlPaginate = false
+		CurlQuery = ""
+
+		stdout = NewBuffer()
+		stderr = NewBuffer()
+		CurlCmd.SetOut(stdout)
+		CurlCmd.SetErr(stderr)
+		CurlCmd.SetIn(strings.NewReader(""))
+		CurlCmd.SetContext(context.Background())
+	})
+
+	It("sends the request and prints the response", func() {
+		err := CurlCmd.RunE(CurlCmd, []string{"/api/v1/products/hyperspace-database?isSlug=true"})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(httpClient.SendRequestCallCount()).To(Equal(1))
+		_, method, requestURL, headers, content := httpClient.SendRequestArgsForCall(0)
+		Expect(method).To(Equal("GET"))
+		Expect(requestURL.String()).To(Equal("https://gtw.marketplace.example.com/api/v1/products/hyperspace-database?isSlug=true"))
+		Expect(headers).To(BeEmpty())
+		Expect(content).To(BeNil())
+
+		Expect(stderr).To(Say("Sending GET request to https://gtw.marketplace.example.com/api/v1/products/hyperspace-database\\?isSlug=true..."))
+		Expect(stderr).To(Say("Response status 200"))
+		Expect(stdout).To(Say(`"displayname":"Hyperspace Database"`))
+	})
+
+	It("sends the headers", func() {
+		CurlHeaders = []string{"x-request-id: 1234", "Accept: text/plain"}
+		err := CurlCmd.RunE(CurlCmd, []string{"/api/v1/products"})
+		Expect(err).ToNot(HaveOccurred())
+
+		_, _, _, headers, _ := httpClient.SendRequestArgsForCall(0)
+		Expect(headers).To(Equal(map[string]string{
+			"X-Request-Id": "1234",
+			"Accept":       "text/plain",
+		}))
+	})
+
+	When("a header is not valid", func() {
+		It("returns an error", func() {
+			CurlHeaders = []string{"no colon"}
+			err := CurlCmd.RunE(CurlCmd, []string{"/api/v1/products"})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("invalid header \"no colon\": must be in the form \"Name: value\""))
+		})
+	})
+
+	It("reads the payload from stdin", func() {
+		CurlMethod = "POST"
+		CurlPayload = "-"
+		CurlCmd.SetIn(strings.NewReader(`{"slug":"hyperspace-database"}`))
+		err := CurlCmd.RunE(CurlCmd, []string{"/api/v1/products"})
+		Expect(err).ToNot(HaveOccurred())
+
+		_, method, _, headers, content := httpClient.SendRequestArgsForCall(0)
+		Expect(method).To(Equal("POST"))
+		Expect(headers["Content-Type"]).To(Equal("application/json"))
+		Expect(ioutil.ReadAll(content)).To(MatchJSON(`{"slug":"hyperspace-database"}`))
+	})
+
+	It("prints only the part of the response matching the query", func() {
+		CurlQuery = "response.data.displayname"
+		err := CurlCmd.RunE(CurlCmd, []string{"/api/v1/products/hyperspace-database"})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(stdout.Contents())).To(Equal("Hyperspace Database\n"))
+	})
+
+	When("nothing matches the query", func() {
+		It("returns an error", func() {
+			CurlQuery = "response.data.nothing"
+			err := CurlCmd.RunE(CurlCmd, []string{"/api/v1/products/hyperspace-database"})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("nothing in the response matches the query \"response.data.nothing\""))
+		})
+	})
+
+	It("writes the response to the output file", func() {
+		dir, err := os.MkdirTemp("", "mkpcli-curl-test")
+		Expect(err).ToNot(HaveOccurred())
+		defer os.RemoveAll(dir)
+
+		CurlOutputFile = filepath.Join(dir, "product.json")
+		err = CurlCmd.RunE(CurlCmd, []string{"/api/v1/products/hyperspace-database"})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(stdout.Contents()).To(BeEmpty())
+		Expect(os.ReadFile(CurlOutputFile)).To(MatchJSON(`{"response":{"data":{"slug":"hyperspace-database","displayname":"Hyperspace Database"}}}`))
+	})
+
+	When("the response is an error", func() {
+		BeforeEach(func() {
+			httpClient.SendRequestReturns(makeCurlResponse(http.StatusNotFound, `{"message":"product not found"}`), nil)
+		})
+
+		It("prints the response and returns an error", func() {
+			err := CurlCmd.RunE(CurlCmd, []string{"/api/v1/products/nothing"})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("the request failed with status 404"))
+			Expect(stderr).To(Say("Response status 404"))
+			Expect(stdout).To(Say("product not found"))
+		})
+	})
+
+	When("the request fails", func() {
+		It("returns an error", func() {
+			httpClient.SendRequestReturns(nil, errors.New("connection refused"))
+			err := CurlCmd.RunE(CurlCmd, []string{"/api/v1/products"})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("connection refused"))
+		})
+	})
+
+	Context("paginating", func() {
+		BeforeEach(func() {
+			CurlPaginate = true
+			httpClient.SendRequestReturnsOnCall(0, makeCurlResponse(http.StatusOK, makeProductsPage(5, "one", "two")), nil)
+			httpClient.SendRequestReturnsOnCall(1, makeCurlResponse(http.StatusOK, makeProductsPage(5, "three", "four")), nil)
+			httpClient.SendRequestReturnsOnCall(2, makeCurlResponse(http.StatusOK, makeProductsPage(5, "five")), nil)
+		})
+
+		It("fetches every page and prints all of the items", func() {
+			err := CurlCmd.RunE(CurlCmd, []string{`/api/v1/products?managed=true&pagination={"page":1,"pageSize":2}`})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(httpClient.SendRequestCallCount()).To(Equal(3))
+			for i := 0; i < 3; i++ {
+				_, _, requestURL, _, _ := httpClient.SendRequestArgsForCall(i)
+				Expect(requestURL.Query().Get("managed")).To(Equal("true"))
+				Expect(requestURL.Query().Get("pagination")).To(MatchJSON(fmt.Sprintf(`{"page":%d,"pageSize":2}`, i+1)))
+			}
+
+			Expect(stdout.Contents()).To(MatchJSON(`[{"slug":"one"},{"slug":"two"},{"slug":"three"},{"slug":"four"},{"slug":"five"}]`))
+		})
+
+		It("applies the query to all of the items", func() {
+			CurlQuery = "#.slug"
+			err := CurlCmd.RunE(CurlCmd, []string{`/api/v1/products?pagination={"page":1,"pageSize":2}`})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stdout.Contents()).To(MatchJSON(`["one","two","three","four","five"]`))
+		})
+
+		When("the response is not a list", func() {
+			It("returns an error", func() {
+				httpClient.SendRequestReturnsOnCall(0, makeCurlResponse(http.StatusOK, `{"response":{"data":{}}}`), nil)
+				err := CurlCmd.RunE(CurlCmd, []string{"/api/v1/products/hyperspace-database"})
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("cannot paginate: the response has no list of items in response.dataList"))
+			})
+		})
+
+		When("the pagination parameter is not valid", func() {
+			It("returns an error", func() {
+				err := CurlCmd.RunE(CurlCmd, []string{"/api/v1/products?" + url.Values{"pagination": []string{"page 2"}}.Encode()})
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(HavePrefix("invalid pagination parameter \"page 2\""))
+			})
+		})
+	})
+})
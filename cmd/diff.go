@@ -0,0 +1,199 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: BSD-2-Clause
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/config"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/csp"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/diff"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/models"
+	"github.com/vmware-labs/marketplace-cli/v2/pkg"
+)
+
+var (
+	DiffFromVersion string
+	DiffToVersion   string
+	DiffToProduct   string
+	DiffToProfile   string
+)
+
+// NewMarketplace makes a Marketplace for the hosts of another profile. Hosts missing from the profile are the
+// production hosts.
+var NewMarketplace = func(profile *config.Profile) pkg.MarketplaceInterface {
+	production := config.BuiltinProfiles[config.ProfileProduction]
+	orDefault := func(value, defaultValue string) string {
+		if value == "" {
+			return defaultValue
+		}
+		return value
+	}
+
+	marketplace := &pkg.Marketplace{
+		Host:          orDefault(profile.MarketplaceHost, production.MarketplaceHost),
+		APIHost:       orDefault(profile.APIHost, production.APIHost),
+		UIHost:        orDefault(profile.UIHost, production.UIHost),
+		StorageBucket: orDefault(profile.StorageBucket, production.StorageBucket),
+		StorageRegion: orDefault(profile.StorageRegion, production.StorageRegion),
+		Client:        Client,
+		Output:        os.Stderr,
+	}
+	if viper.GetBool("marketplace.strict-decoding") {
+		marketplace.EnableStrictDecoding()
+	}
+	return marketplace
+}
+
+func init() {
+	ProductCmd.AddCommand(ProductDiffCmd)
+
+	ProductDiffCmd.Flags().StringVarP(&ProductSlug, "product", "p", "", "Product slug (required)")
+	_ = ProductDiffCmd.MarkFlagRequired("product")
+	ProductDiffCmd.Flags().StringVar(&DiffFromVersion, "from-version", "", "Product version to compare from. Defaults to the latest version")
+	ProductDiffCmd.Flags().StringVar(&DiffToVersion, "to-version", "", "Product version to compare to")
+	ProductDiffCmd.Flags().StringVar(&DiffToProduct, "to-product", "", "Slug of the product to compare to. Defaults to the same product")
+	ProductDiffCmd.Flags().StringVar(&DiffToProfile, "to-profile", "", "Profile of the environment to compare to, like staging. Defaults to the active profile")
+}
+
+// getProductVersion gets a product with the details of a version, or of the latest version if none is given
+func getProductVersion(ctx context.Context, marketplace pkg.MarketplaceInterface, slug, version string) (*models.Product, string, error) {
+	if version == "" {
+		product, err := marketplace.GetProduct(ctx, slug)
+		if err != nil {
+			return nil, "", err
+		}
+		latest := product.GetLatestVersion()
+		if latest == nil {
+			return nil, "", fmt.Errorf("%s has no versions", slug)
+		}
+		version = latest.Number
+	}
+
+	product, _, err := marketplace.GetProductWithVersion(ctx, slug, version)
+	if err != nil {
+		return nil, "", err
+	}
+	return product, version, nil
+}
+
+// useProfileCredentials authenticates with the credentials that a profile refers to. A profile without its own
+// credentials, on the same CSP host, uses the credentials of this command.
+func useProfileCredentials(ctx context.Context, profile *config.Profile) error {
+	cspHost := profile.CSPHost
+	if cspHost == "" {
+		cspHost = viper.GetString("csp.host")
+	}
+
+	credentials := &csp.Credentials{}
+	if profile.APITokenEnv != "" {
+		credentials.APIToken = os.Getenv(profile.APITokenEnv)
+	}
+	if profile.ClientIDEnv != "" {
+		credentials.ClientID = os.Getenv(profile.ClientIDEnv)
+	}
+	if profile.ClientSecretEnv != "" {
+		credentials.ClientSecret = os.Getenv(profile.ClientSecretEnv)
+	}
+
+	if credentials.APIToken == "" && !credentials.IsClientCredentials() {
+		if profile.CredentialHelper != "" {
+			apiToken, err := NewCredentialHelper(profile.CredentialHelper).Get(ctx, cspHost)
+			if err != nil {
+				return err
+			}
+			credentials.APIToken = apiToken
+		} else if cspHost != viper.GetString("csp.host") {
+			login, err := StoredLogins.Get(cspHost)
+			if err != nil {
+				return err
+			}
+			if login == nil {
+				return fmt.Errorf("no credentials for the %s profile. Run \"%s auth login --profile %s\"", profile.Name, AppName, profile.Name)
+			}
+			credentials.ClientID = login.ClientID
+			credentials.RefreshToken = login.RefreshToken
+		} else {
+			return nil
+		}
+	}
+
+	claims, err := authenticate(ctx, cspHost, credentials, true)
+	if err != nil {
+		return err
+	}
+	useClaims(claims)
+	return nil
+}
+
+var ProductDiffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Compare two product versions",
+	Long: "Compares two versions of a product, two products, or the same product in two environments.\n" +
+		"Shows the assets that were added, removed or changed, and the differences in the EULA, open source " +
+		"disclosures, PCA, export compliance, encryption and compatibility details",
+	Example: fmt.Sprintf(`%[1]s product diff -p my-product --from-version 1.0.0 --to-version 1.1.0
+%[1]s product diff -p my-product --to-product my-other-product
+%[1]s --profile staging product diff -p my-product --from-version 1.1.0 --to-profile production`, AppName),
+	Args:    cobra.NoArgs,
+	PreRunE: GetRefreshToken,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		if DiffToProduct == "" && DiffToProfile == "" && DiffToVersion == "" {
+			return errors.New("nothing to compare to. Set --to-version, --to-product or --to-profile")
+		}
+
+		fromProduct, fromVersion, err := getProductVersion(cmd.Context(), Marketplace, ProductSlug, DiffFromVersion)
+		if err != nil {
+			return err
+		}
+		from := &diff.Side{Product: ProductSlug, Version: fromVersion}
+
+		toSlug := DiffToProduct
+		if toSlug == "" {
+			toSlug = ProductSlug
+		}
+		to := &diff.Side{Product: toSlug, Version: DiffToVersion}
+
+		toMarketplace := Marketplace
+		if DiffToProfile != "" {
+			profiles, err := StoredProfiles.Load()
+			if err != nil {
+				return err
+			}
+			profile, ok := profiles.Get(DiffToProfile)
+			if !ok {
+				return fmt.Errorf("unknown profile: %s", DiffToProfile)
+			}
+
+			err = useProfileCredentials(cmd.Context(), profile)
+			if err != nil {
+				return fmt.Errorf("failed to authenticate for the %s profile: %w", profile.Name, err)
+			}
+			toMarketplace = NewMarketplace(profile)
+			from.Host = Marketplace.GetHost()
+			to.Host = toMarketplace.GetHost()
+
+			// The same product in another environment is compared at the same version
+			if to.Version == "" && toSlug == ProductSlug {
+				to.Version = fromVersion
+			}
+		}
+
+		toProduct, toVersion, err := getProductVersion(cmd.Context(), toMarketplace, to.Product, to.Version)
+		if err != nil {
+			return err
+		}
+		to.Version = toVersion
+
+		productDiff := diff.Compare(from, fromProduct, to, toProduct)
+		Output.PrintHeader(fmt.Sprintf("Comparing %s to %s:", from, to))
+		return Output.RenderProductDiff(productDiff)
+	},
+}
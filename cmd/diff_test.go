@@ -0,0 +1,300 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: BSD-2-Clause
+
+package cmd_test
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/viper"
+
+	. "github.com/vmware-labs/marketplace-cli/v2/cmd"
+	"github.com/vmware-labs/marketplace-cli/v2/cmd/cmdfakes"
+	"github.com/vmware-labs/marketplace-cli/v2/cmd/output/outputfakes"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/config"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/csp"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/diff"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/models"
+	"github.com/vmware-labs/marketplace-cli/v2/pkg"
+	"github.com/vmware-labs/marketplace-cli/v2/pkg/pkgfakes"
+	"github.com/vmware-labs/marketplace-cli/v2/test"
+)
+
+var _ = Describe("ProductDiffCmd", func() {
+	var (
+		marketplace *pkgfakes.FakeMarketplaceInterface
+		output      *outputfakes.FakeFormat
+		product     *models.Product
+	)
+
+	BeforeEach(func() {
+		product = test.CreateFakeProduct("", "My Super Product", "my-super-product", models.SolutionTypeImage)
+		test.AddVerions(product, "1.0.0", "1.1.0")
+		test.AddContainerImages(product, "1.0.0", "docker run it", test.CreateFakeContainerImage("nginx", "1.0"))
+		test.AddContainerImages(product, "1.1.0", "docker run it", test.CreateFakeContainerImage("nginx", "1.1"))
+
+		marketplace = &pkgfakes.FakeMarketplaceInterface{}
+		marketplace.GetHostReturns("gtw.marketplace.example.com")
+		marketplace.GetProductReturns(product, nil)
+		marketplace.GetProductWithVersionStub = func(ctx context.Context, slug string, version string) (*models.Product, *models.Version, error) {
+			if product.HasVersion(version) {
+				return product, product.GetVersion(version), nil
+			}
+			return nil, nil, &pkg.VersionDoesNotExistError{Product: slug, Version: version}
+		}
+		Marketplace = marketplace
+
+		output = &outputfakes.FakeFormat{}
+		Output = output
+
+		ProductSlug = "my-super-product"
+		DiffFromVersion = "1.0.0"
+		DiffToVersion = "1.1.0"
+		DiffToProduct = ""
+		DiffToProfile = ""
+		ProductDiffCmd.SetContext(context.Background())
+	})
+
+	It("compares two versions of the product", func() {
+		err := ProductDiffCmd.RunE(ProductDiffCmd, []string{})
+		Expect(err).ToNot(HaveOccurred())
+
+		By("getting both versions", func() {
+			Expect(marketplace.GetProductWithVersionCallCount()).To(Equal(2))
+			_, slug, version := marketplace.GetProductWithVersionArgsForCall(0)
+			Expect(slug).To(Equal("my-super-product"))
+			Expect(version).To(Equal("1.0.0"))
+			_, slug, version = marketplace.GetProductWithVersionArgsForCall(1)
+			Expect(slug).To(Equal("my-super-product"))
+			Expect(version).To(Equal("1.1.0"))
+		})
+
+		By("rendering the differences", func() {
+			Expect(output.PrintHeaderCallCount()).To(Equal(1))
+			Expect(output.PrintHeaderArgsForCall(0)).To(Equal("Comparing my-super-product 1.0.0 to my-super-product 1.1.0:"))
+
+			Expect(output.RenderProductDiffCallCount()).To(Equal(1))
+			productDiff := output.RenderProductDiffArgsForCall(0)
+			Expect(productDiff.From).To(Equal(&diff.Side{Product: "my-super-product", Version: "1.0.0"}))
+			Expect(productDiff.To).To(Equal(&diff.Side{Product: "my-super-product", Version: "1.1.0"}))
+			Expect(productDiff.Assets).To(HaveLen(1))
+			Expect(productDiff.Assets[0].Change).To(Equal(diff.ChangeChanged))
+			Expect(productDiff.Assets[0].Name).To(Equal("nginx:1.1"))
+		})
+	})
+
+	When("there is nothing to compare to", func() {
+		BeforeEach(func() {
+			DiffToVersion = ""
+		})
+
+		It("returns an error", func() {
+			err := ProductDiffCmd.RunE(ProductDiffCmd, []string{})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("nothing to compare to. Set --to-version, --to-product or --to-profile"))
+		})
+	})
+
+	When("the from version is not given", func() {
+		BeforeEach(func() {
+			DiffFromVersion = ""
+			DiffToVersion = "1.0.0"
+		})
+
+		It("compares from the latest version", func() {
+			err := ProductDiffCmd.RunE(ProductDiffCmd, []string{})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(marketplace.GetProductCallCount()).To(Equal(1))
+			_, slug := marketplace.GetProductArgsForCall(0)
+			Expect(slug).To(Equal("my-super-product"))
+			Expect(output.PrintHeaderArgsForCall(0)).To(Equal("Comparing my-super-product 1.1.0 to my-super-product 1.0.0:"))
+		})
+	})
+
+	When("a version does not exist", func() {
+		BeforeEach(func() {
+			DiffToVersion = "9.9.9"
+		})
+
+		It("returns an error", func() {
+			err := ProductDiffCmd.RunE(ProductDiffCmd, []string{})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("product \"my-super-product\" does not have version 9.9.9"))
+			Expect(output.RenderProductDiffCallCount()).To(Equal(0))
+		})
+	})
+
+	When("comparing with another profile", func() {
+		var (
+			otherMarketplace *pkgfakes.FakeMarketplaceInterface
+			profileStore     *cmdfakes.FakeProfileStore
+			tokenServices    *cmdfakes.FakeTokenServices
+			profileUsed      *config.Profile
+		)
+
+		BeforeEach(func() {
+			DiffFromVersion = "1.1.0"
+			DiffToVersion = ""
+			DiffToProfile = "partner"
+
+			profileStore = &cmdfakes.FakeProfileStore{}
+			profileStore.LoadReturns(&config.Profiles{
+				Profiles: map[string]*config.Profile{
+					"partner": {
+						MarketplaceHost: "gtw.partner.example.com",
+						CSPHost:         "console.partner.example.com",
+						APITokenEnv:     "PARTNER_CSP_API_TOKEN",
+					},
+				},
+			}, nil)
+			StoredProfiles = profileStore
+			Expect(os.Setenv("PARTNER_CSP_API_TOKEN", "my-partner-api-token")).To(Succeed())
+
+			tokenServices = &cmdfakes.FakeTokenServices{}
+			tokenServices.AuthenticateReturns(&csp.Claims{Token: "my-partner-token"}, nil)
+			initializer := &cmdfakes.FakeTokenServicesInitializer{}
+			initializer.Returns(tokenServices, nil)
+			InitializeTokenServices = initializer.Spy
+			viper.Set("csp.host", "console.cloud.vmware.com.example")
+			viper.Set("csp.no-token-cache", true)
+
+			otherProduct := test.CreateFakeProduct(product.ProductId, "My Super Product", "my-super-product", models.SolutionTypeImage)
+			test.AddVerions(otherProduct, "1.1.0")
+			otherProduct.ExportCompliance = &models.ProductExportCompliance{Eccn: "5D002"}
+			otherMarketplace = &pkgfakes.FakeMarketplaceInterface{}
+			otherMarketplace.GetHostReturns("gtw.partner.example.com")
+			otherMarketplace.GetProductWithVersionReturns(otherProduct, otherProduct.GetVersion("1.1.0"), nil)
+			NewMarketplace = func(profile *config.Profile) pkg.MarketplaceInterface {
+				profileUsed = profile
+				return otherMarketplace
+			}
+		})
+
+		AfterEach(func() {
+			Expect(os.Unsetenv("PARTNER_CSP_API_TOKEN")).To(Succeed())
+			viper.Set("csp.refresh-token", "")
+			viper.Set("csp.no-token-cache", false)
+			AuthenticatedClaims = nil
+		})
+
+		It("compares the same version in the other environment", func() {
+			err := ProductDiffCmd.RunE(ProductDiffCmd, []string{})
+			Expect(err).ToNot(HaveOccurred())
+
+			By("authenticating with the credentials of the other profile", func() {
+				Expect(tokenServices.AuthenticateCallCount()).To(Equal(1))
+				_, credentials := tokenServices.AuthenticateArgsForCall(0)
+				Expect(credentials.APIToken).To(Equal("my-partner-api-token"))
+				Expect(viper.GetString("csp.refresh-token")).To(Equal("my-partner-token"))
+			})
+
+			By("getting the product from the other environment", func() {
+				Expect(profileUsed.Name).To(Equal("partner"))
+				Expect(otherMarketplace.GetProductWithVersionCallCount()).To(Equal(1))
+				_, slug, version := otherMarketplace.GetProductWithVersionArgsForCall(0)
+				Expect(slug).To(Equal("my-super-product"))
+				Expect(version).To(Equal("1.1.0"))
+			})
+
+			By("rendering the differences", func() {
+				Expect(output.PrintHeaderArgsForCall(0)).To(Equal("Comparing my-super-product 1.1.0 on gtw.marketplace.example.com to my-super-product 1.1.0 on gtw.partner.example.com:"))
+				productDiff := output.RenderProductDiffArgsForCall(0)
+				Expect(productDiff.Assets).To(HaveLen(1))
+				Expect(productDiff.Assets[0].Change).To(Equal(diff.ChangeRemoved))
+				Expect(productDiff.Details).To(Equal([]*diff.DetailChange{
+					{Change: diff.ChangeAdded, Field: diff.FieldExportECCN, To: "5D002"},
+				}))
+			})
+		})
+
+		When("the other profile has no credentials of its own", func() {
+			BeforeEach(func() {
+				profileStore.LoadReturns(&config.Profiles{
+					Profiles: map[string]*config.Profile{
+						"partner": {MarketplaceHost: "gtw.partner.example.com"},
+					},
+				}, nil)
+			})
+
+			It("uses the current credentials", func() {
+				err := ProductDiffCmd.RunE(ProductDiffCmd, []string{})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(tokenServices.AuthenticateCallCount()).To(Equal(0))
+				Expect(otherMarketplace.GetProductWithVersionCallCount()).To(Equal(1))
+			})
+		})
+
+		When("the other profile overrides some settings of a built-in profile", func() {
+			BeforeEach(func() {
+				DiffToProfile = "staging"
+				profileStore.LoadReturns(&config.Profiles{
+					Profiles: map[string]*config.Profile{
+						"staging": {
+							MarketplaceHost: "gtw.staging-proxy.example.com",
+							APITokenEnv:     "PARTNER_CSP_API_TOKEN",
+						},
+					},
+				}, nil)
+			})
+
+			It("uses the other settings of the built-in profile", func() {
+				err := ProductDiffCmd.RunE(ProductDiffCmd, []string{})
+				Expect(err).ToNot(HaveOccurred())
+
+				staging := config.BuiltinProfiles[config.ProfileStaging]
+				Expect(profileUsed.MarketplaceHost).To(Equal("gtw.staging-proxy.example.com"))
+				Expect(profileUsed.APIHost).To(Equal(staging.APIHost))
+				Expect(profileUsed.StorageBucket).To(Equal(staging.StorageBucket))
+				Expect(profileUsed.CSPHost).To(Equal(staging.CSPHost))
+
+				Expect(tokenServices.AuthenticateCallCount()).To(Equal(1))
+				_, credentials := tokenServices.AuthenticateArgsForCall(0)
+				Expect(credentials.APIToken).To(Equal("my-partner-api-token"))
+			})
+		})
+
+		When("the other profile uses another CSP host without a login", func() {
+			BeforeEach(func() {
+				Expect(os.Unsetenv("PARTNER_CSP_API_TOKEN")).To(Succeed())
+				StoredLogins = &cmdfakes.FakeLoginStore{}
+			})
+
+			It("returns an error", func() {
+				err := ProductDiffCmd.RunE(ProductDiffCmd, []string{})
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("failed to authenticate for the partner profile: no credentials for the partner profile. Run \"mkpcli auth login --profile partner\""))
+				Expect(otherMarketplace.GetProductWithVersionCallCount()).To(Equal(0))
+			})
+		})
+
+		When("the profile does not exist", func() {
+			BeforeEach(func() {
+				DiffToProfile = "nope"
+			})
+
+			It("returns an error", func() {
+				err := ProductDiffCmd.RunE(ProductDiffCmd, []string{})
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("unknown profile: nope"))
+			})
+		})
+
+		When("authenticating fails", func() {
+			BeforeEach(func() {
+				tokenServices.AuthenticateReturns(nil, errors.New("token rejected"))
+			})
+
+			It("returns an error", func() {
+				err := ProductDiffCmd.RunE(ProductDiffCmd, []string{})
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("failed to authenticate for the partner profile"))
+				Expect(err.Error()).To(ContainSubstring("token rejected"))
+			})
+		})
+	})
+})
@@ -0,0 +1,152 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: BSD-2-Clause
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/doctor"
+	"github.com/vmware-labs/marketplace-cli/v2/pkg"
+)
+
+const (
+	CheckMarketplaceHost   = "Marketplace host"
+	CheckAPIHost           = "API host"
+	CheckUIHost            = "UI host"
+	CheckCSPHost           = "CSP host"
+	CheckCSPPublicKey      = "CSP public key"
+	CheckCSPCredentials    = "CSP credentials"
+	CheckUploadCredentials = "Upload credentials"
+	CheckStorageBucket     = "Storage bucket"
+)
+
+var LookupHost = net.DefaultResolver.LookupHost
+
+func init() {
+	rootCmd.AddCommand(DoctorCmd)
+	DoctorCmd.SetOut(DoctorCmd.OutOrStdout())
+}
+
+var DoctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the environment for problems",
+	Long: "Checks that the Marketplace and CSP hosts respond, that the CSP public key can be fetched, that the credentials can be redeemed, " +
+		"that upload credentials can be fetched, and that the storage bucket is in the storage region",
+	Example: fmt.Sprintf("%s doctor", AppName),
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		results := doctor.Run(DoctorChecks(cmd.Context()))
+
+		err := Output.RenderDiagnostics(results)
+		if err != nil {
+			return err
+		}
+		if !doctor.Passed(results) {
+			return errors.New("some checks failed")
+		}
+		return nil
+	},
+}
+
+// DoctorChecks are the checks run by the doctor command, in order
+func DoctorChecks(ctx context.Context) []*doctor.Check {
+	cspHost := viper.GetString("csp.host")
+	return []*doctor.Check{
+		hostCheck(ctx, CheckMarketplaceHost, Marketplace.GetHost()),
+		hostCheck(ctx, CheckAPIHost, Marketplace.GetAPIHost()),
+		hostCheck(ctx, CheckUIHost, Marketplace.GetUIHost()),
+		hostCheck(ctx, CheckCSPHost, cspHost),
+		{
+			Name:     CheckCSPPublicKey,
+			Requires: []string{CheckCSPHost},
+			Run: func() (string, error) {
+				_, err := InitializeTokenServices(ctx, cspHost)
+				if err != nil {
+					return "", err
+				}
+				if publicKeyFile := viper.GetString("csp.public-key-file"); publicKeyFile != "" {
+					return fmt.Sprintf("read from %s", publicKeyFile), nil
+				}
+				return fmt.Sprintf("fetched from %s", cspHost), nil
+			},
+		},
+		{
+			Name:     CheckCSPCredentials,
+			Requires: []string{CheckCSPPublicKey},
+			Run: func() (string, error) {
+				credentials, err := getCredentials(ctx, cspHost)
+				if err != nil {
+					return "", err
+				}
+				claims, err := authenticate(ctx, cspHost, credentials, false)
+				if err != nil {
+					return "", err
+				}
+				useClaims(claims)
+				return fmt.Sprintf("authenticated as %s in the organization %s", AuthenticatedClaims.GetQualifiedUsername(), AuthenticatedClaims.ContextName), nil
+			},
+		},
+		{
+			Name:     CheckUploadCredentials,
+			Requires: []string{CheckAPIHost, CheckCSPCredentials},
+			Run: func() (string, error) {
+				credentials, err := Marketplace.GetUploadCredentials(ctx)
+				if err != nil {
+					return "", err
+				}
+				if credentials.Expiration.IsZero() {
+					return "received", nil
+				}
+				return fmt.Sprintf("received, expiring at %s", credentials.Expiration.Format(time.RFC3339)), nil
+			},
+		},
+		{
+			Name: CheckStorageBucket,
+			Run: func() (string, error) {
+				bucket := viper.GetString("marketplace.storage.bucket")
+				region := viper.GetString("marketplace.storage.region")
+				bucketRegion, err := Marketplace.GetStorageBucketRegion(ctx)
+				if err != nil {
+					return "", err
+				}
+				if bucketRegion != region {
+					return "", fmt.Errorf("the storage bucket %s is in %s, but the storage region is %s", bucket, bucketRegion, region)
+				}
+				return fmt.Sprintf("%s is in %s", bucket, region), nil
+			},
+		},
+	}
+}
+
+func hostCheck(ctx context.Context, name, host string) *doctor.Check {
+	return &doctor.Check{
+		Name: name,
+		Run: func() (string, error) {
+			if host == "" {
+				return "", errors.New("no host is configured")
+			}
+
+			_, err := LookupHost(ctx, host)
+			if err != nil {
+				return "", fmt.Errorf("failed to resolve %s: %w", host, err)
+			}
+
+			resp, err := Client.Get(ctx, pkg.MakeURL(host, "/", nil))
+			if err != nil {
+				return "", fmt.Errorf("%s did not respond: %w", host, err)
+			}
+			if resp.Body != nil {
+				_ = resp.Body.Close()
+			}
+			return fmt.Sprintf("%s responded with %s", host, resp.Status), nil
+		},
+	}
+}
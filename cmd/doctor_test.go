@@ -0,0 +1,170 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: BSD-2-Clause
+
+package cmd_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/viper"
+
+	. "github.com/vmware-labs/marketplace-cli/v2/cmd"
+	"github.com/vmware-labs/marketplace-cli/v2/cmd/cmdfakes"
+	"github.com/vmware-labs/marketplace-cli/v2/cmd/output/outputfakes"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/csp"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/doctor"
+	"github.com/vmware-labs/marketplace-cli/v2/pkg"
+	"github.com/vmware-labs/marketplace-cli/v2/pkg/pkgfakes"
+)
+
+var _ = Describe("DoctorCmd", func() {
+	var (
+		output        *outputfakes.FakeFormat
+		httpClient    *pkgfakes.FakeHTTPClient
+		marketplace   *pkgfakes.FakeMarketplaceInterface
+		initializer   *cmdfakes.FakeTokenServicesInitializer
+		tokenServices *cmdfakes.FakeTokenServices
+	)
+
+	BeforeEach(func() {
+		output = &outputfakes.FakeFormat{}
+		Output = output
+
+		httpClient = &pkgfakes.FakeHTTPClient{}
+		httpClient.GetReturns(&http.Response{Status: "200 OK", StatusCode: http.StatusOK}, nil)
+		Client = httpClient
+
+		marketplace = &pkgfakes.FakeMarketplaceInterface{}
+		marketplace.GetHostReturns("gtw.marketplace.example.com")
+		marketplace.GetAPIHostReturns("api.marketplace.example.com")
+		marketplace.GetUIHostReturns("marketplace.example.com")
+		marketplace.GetUploadCredentialsReturns(&pkg.CredentialsResponse{AccessID: "my-access-id"}, nil)
+		marketplace.GetStorageBucketRegionReturns("us-west-2", nil)
+		Marketplace = marketplace
+
+		tokenServices = &cmdfakes.FakeTokenServices{}
+		tokenServices.AuthenticateReturns(&csp.Claims{
+			Token:       "my-access-token",
+			Username:    "someone@example.com",
+			ContextName: "my-org-id",
+		}, nil)
+		initializer = &cmdfakes.FakeTokenServicesInitializer{}
+		initializer.Returns(tokenServices, nil)
+		InitializeTokenServices = initializer.Spy
+
+		AccessTokenCache = &cmdfakes.FakeTokenCache{}
+		StoredLogins = &cmdfakes.FakeLoginStore{}
+
+		LookupHost = func(ctx context.Context, host string) ([]string, error) {
+			return []string{"127.0.0.1"}, nil
+		}
+
+		viper.Set("csp.host", "console.example.com")
+		viper.Set("csp.api-token", "my-api-token")
+		viper.Set("marketplace.storage.bucket", "my-bucket")
+		viper.Set("marketplace.storage.region", "us-west-2")
+	})
+
+	AfterEach(func() {
+		viper.Set("csp.host", nil)
+		viper.Set("csp.api-token", nil)
+		viper.Set("marketplace.storage.bucket", nil)
+		viper.Set("marketplace.storage.region", nil)
+	})
+
+	statuses := func() map[string]string {
+		statuses := map[string]string{}
+		for _, result := range output.RenderDiagnosticsArgsForCall(0) {
+			statuses[result.Name] = result.Status
+		}
+		return statuses
+	}
+
+	It("runs all checks in order", func() {
+		err := DoctorCmd.RunE(DoctorCmd, []string{})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(output.RenderDiagnosticsCallCount()).To(Equal(1))
+		results := output.RenderDiagnosticsArgsForCall(0)
+		Expect(results).To(Equal([]*doctor.Result{
+			{Name: CheckMarketplaceHost, Status: doctor.StatusPass, Message: "gtw.marketplace.example.com responded with 200 OK"},
+			{Name: CheckAPIHost, Status: doctor.StatusPass, Message: "api.marketplace.example.com responded with 200 OK"},
+			{Name: CheckUIHost, Status: doctor.StatusPass, Message: "marketplace.example.com responded with 200 OK"},
+			{Name: CheckCSPHost, Status: doctor.StatusPass, Message: "console.example.com responded with 200 OK"},
+			{Name: CheckCSPPublicKey, Status: doctor.StatusPass, Message: "fetched from console.example.com"},
+			{Name: CheckCSPCredentials, Status: doctor.StatusPass, Message: "authenticated as someone@example.com in the organization my-org-id"},
+			{Name: CheckUploadCredentials, Status: doctor.StatusPass, Message: "received"},
+			{Name: CheckStorageBucket, Status: doctor.StatusPass, Message: "my-bucket is in us-west-2"},
+		}))
+
+		By("redeeming the credentials without using the token cache", func() {
+			Expect(tokenServices.AuthenticateCallCount()).To(Equal(1))
+			_, credentials := tokenServices.AuthenticateArgsForCall(0)
+			Expect(credentials.APIToken).To(Equal("my-api-token"))
+		})
+	})
+
+	When("the CSP host does not resolve", func() {
+		BeforeEach(func() {
+			LookupHost = func(ctx context.Context, host string) ([]string, error) {
+				if host == "console.example.com" {
+					return nil, errors.New("no such host")
+				}
+				return []string{"127.0.0.1"}, nil
+			}
+		})
+
+		It("skips the checks that need CSP", func() {
+			err := DoctorCmd.RunE(DoctorCmd, []string{})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("some checks failed"))
+
+			Expect(statuses()).To(Equal(map[string]string{
+				CheckMarketplaceHost:   doctor.StatusPass,
+				CheckAPIHost:           doctor.StatusPass,
+				CheckUIHost:            doctor.StatusPass,
+				CheckCSPHost:           doctor.StatusFail,
+				CheckCSPPublicKey:      doctor.StatusSkip,
+				CheckCSPCredentials:    doctor.StatusSkip,
+				CheckUploadCredentials: doctor.StatusSkip,
+				CheckStorageBucket:     doctor.StatusPass,
+			}))
+			Expect(output.RenderDiagnosticsArgsForCall(0)[3].Message).To(Equal("failed to resolve console.example.com: no such host"))
+		})
+	})
+
+	When("the API token cannot be redeemed", func() {
+		BeforeEach(func() {
+			tokenServices.AuthenticateReturns(nil, errors.New("invalid token"))
+		})
+
+		It("reports the failure", func() {
+			err := DoctorCmd.RunE(DoctorCmd, []string{})
+			Expect(err).To(HaveOccurred())
+
+			Expect(statuses()[CheckCSPCredentials]).To(Equal(doctor.StatusFail))
+			Expect(statuses()[CheckUploadCredentials]).To(Equal(doctor.StatusSkip))
+			Expect(output.RenderDiagnosticsArgsForCall(0)[5].Message).To(Equal("failed to exchange api token: invalid token"))
+		})
+	})
+
+	When("the storage bucket is in a different region", func() {
+		BeforeEach(func() {
+			marketplace.GetStorageBucketRegionReturns("us-east-2", nil)
+		})
+
+		It("reports the failure", func() {
+			err := DoctorCmd.RunE(DoctorCmd, []string{})
+			Expect(err).To(HaveOccurred())
+			Expect(output.RenderDiagnosticsArgsForCall(0)[7]).To(Equal(&doctor.Result{
+				Name:    CheckStorageBucket,
+				Status:  doctor.StatusFail,
+				Message: "the storage bucket my-bucket is in us-east-2, but the storage region is us-west-2",
+			}))
+		})
+	})
+})
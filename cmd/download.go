@@ -50,7 +50,7 @@ var DownloadCmd = &cobra.Command{
 	PreRunE: RunSerially(ValidateAssetTypeFilter, GetRefreshToken),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		cmd.SilenceUsage = true
-		product, version, err := Marketplace.GetProductWithVersion(DownloadProductSlug, DownloadProductVersion)
+		product, version, err := Marketplace.GetProductWithVersion(cmd.Context(), DownloadProductSlug, DownloadProductVersion)
 		if err != nil {
 			return err
 		}
@@ -100,6 +100,6 @@ var DownloadCmd = &cobra.Command{
 		}
 
 		asset.DownloadRequestPayload.EulaAccepted = DownloadAcceptEULA
-		return Marketplace.Download(filename, asset.DownloadRequestPayload)
+		return Marketplace.Download(cmd.Context(), filename, asset.DownloadRequestPayload)
 	},
 }
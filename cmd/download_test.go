@@ -4,6 +4,7 @@
 package cmd_test
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/google/uuid"
@@ -51,7 +52,7 @@ var _ = Describe("DownloadCmd", func() {
 		marketplace = &pkgfakes.FakeMarketplaceInterface{}
 		cmd.Marketplace = marketplace
 
-		marketplace.GetProductWithVersionStub = func(slug string, version string) (*models.Product, *models.Version, error) {
+		marketplace.GetProductWithVersionStub = func(ctx context.Context, slug string, version string) (*models.Product, *models.Version, error) {
 			Expect(slug).To(Equal("my-super-product"))
 			return product, &models.Version{Number: version}, nil
 		}
@@ -74,7 +75,7 @@ var _ = Describe("DownloadCmd", func() {
 
 		By("downloading the asset", func() {
 			Expect(marketplace.DownloadCallCount()).To(Equal(1))
-			filename, assetPayload := marketplace.DownloadArgsForCall(0)
+			_, filename, assetPayload := marketplace.DownloadArgsForCall(0)
 			Expect(filename).To(Equal("my-db.ova"))
 			Expect(assetPayload.ProductId).To(Equal(productId))
 			Expect(assetPayload.AppVersion).To(Equal("1.1.1"))
@@ -133,7 +134,7 @@ var _ = Describe("DownloadCmd", func() {
 			Expect(err).ToNot(HaveOccurred())
 
 			Expect(marketplace.DownloadCallCount()).To(Equal(1))
-			filename, _ := marketplace.DownloadArgsForCall(0)
+			_, filename, _ := marketplace.DownloadArgsForCall(0)
 			Expect(filename).To(Equal("overridden-filename.ova"))
 		})
 	})
@@ -229,7 +230,7 @@ var _ = Describe("DownloadCmd", func() {
 
 			By("downloading the chosen asset", func() {
 				Expect(marketplace.DownloadCallCount()).To(Equal(1))
-				filename, assetPayload := marketplace.DownloadArgsForCall(0)
+				_, filename, assetPayload := marketplace.DownloadArgsForCall(0)
 				Expect(filename).To(Equal("bbb.txt"))
 				Expect(assetPayload.ProductId).To(Equal(productId))
 				Expect(assetPayload.AppVersion).To(Equal("3.3.3"))
@@ -293,7 +294,7 @@ var _ = Describe("DownloadCmd", func() {
 
 			By("downloading the chosen asset", func() {
 				Expect(marketplace.DownloadCallCount()).To(Equal(1))
-				filename, assetPayload := marketplace.DownloadArgsForCall(0)
+				_, filename, assetPayload := marketplace.DownloadArgsForCall(0)
 				Expect(filename).To(Equal("deploy.sh"))
 				Expect(assetPayload.ProductId).To(Equal(productId))
 				Expect(assetPayload.AppVersion).To(Equal("4.4.4"))
@@ -7,8 +7,15 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"time"
 
+	"github.com/vmware-labs/marketplace-cli/v2/internal/catalog"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/config"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/csp"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/diff"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/doctor"
 	"github.com/vmware-labs/marketplace-cli/v2/internal/models"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/spec"
 	"github.com/vmware-labs/marketplace-cli/v2/pkg"
 	"gopkg.in/yaml.v3"
 )
@@ -54,6 +61,22 @@ func (o *EncodedOutput) RenderProducts(products []*models.Product) error {
 	return o.Print(products)
 }
 
+func (o *EncodedOutput) RenderProductFacets(facets []*pkg.ProductFacet) error {
+	return o.Print(facets)
+}
+
+func (o *EncodedOutput) RenderCatalogSearch(results *catalog.SearchResults) error {
+	return o.Print(results)
+}
+
+func (o *EncodedOutput) RenderApplyPlan(plan *spec.Plan) error {
+	return o.Print(plan)
+}
+
+func (o *EncodedOutput) RenderProductDiff(productDiff *diff.ProductDiff) error {
+	return o.Print(productDiff)
+}
+
 func (o *EncodedOutput) RenderVersions(product *models.Product) error {
 	return o.Print(product.AllVersions)
 }
@@ -81,3 +104,53 @@ func (o *EncodedOutput) RenderFiles(files []*models.ProductDeploymentFile) error
 func (o *EncodedOutput) RenderAssets(assets []*pkg.Asset) error {
 	return o.Print(assets)
 }
+
+type authStatus struct {
+	Username         string     `json:"username" yaml:"username"`
+	ContextName      string     `json:"context_name" yaml:"context_name"`
+	Context          string     `json:"context" yaml:"context"`
+	OrgOwner         bool       `json:"org_owner" yaml:"org_owner"`
+	PlatformOperator bool       `json:"platform_operator" yaml:"platform_operator"`
+	Perms            []string   `json:"perms" yaml:"perms"`
+	ExpiresAt        *time.Time `json:"expires_at,omitempty" yaml:"expires_at,omitempty"`
+}
+
+func (o *EncodedOutput) RenderAuthStatus(claims *csp.Claims) error {
+	status := &authStatus{
+		Username:         claims.GetQualifiedUsername(),
+		ContextName:      claims.ContextName,
+		Context:          claims.Context,
+		OrgOwner:         claims.IsOrgOwner(),
+		PlatformOperator: claims.IsPlatformOperator(),
+		Perms:            claims.Perms,
+	}
+	if claims.ExpiresAt != 0 {
+		expiresAt := time.Unix(claims.ExpiresAt, 0).UTC()
+		status.ExpiresAt = &expiresAt
+	}
+	return o.Print(status)
+}
+
+type profileList struct {
+	Active   string            `json:"active" yaml:"active"`
+	Profiles []*config.Profile `json:"profiles" yaml:"profiles"`
+}
+
+func (o *EncodedOutput) RenderProfiles(profiles []*config.Profile, active string) error {
+	return o.Print(&profileList{
+		Active:   active,
+		Profiles: profiles,
+	})
+}
+
+func (o *EncodedOutput) RenderProfile(profile *config.Profile) error {
+	return o.Print(profile)
+}
+
+func (o *EncodedOutput) RenderConfig(settings []*config.Setting) error {
+	return o.Print(settings)
+}
+
+func (o *EncodedOutput) RenderDiagnostics(results []*doctor.Result) error {
+	return o.Print(results)
+}
@@ -6,10 +6,13 @@ package output_test
 import (
 	"errors"
 
+	"github.com/golang-jwt/jwt"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	. "github.com/onsi/gomega/gbytes"
 	"github.com/vmware-labs/marketplace-cli/v2/cmd/output"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/config"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/csp"
 )
 
 var _ = Describe("EncodedOutput", func() {
@@ -72,4 +75,42 @@ var _ = Describe("EncodedOutput", func() {
 			})
 		})
 	})
+
+	Describe("RenderAuthStatus", func() {
+		It("prints the identity as JSON", func() {
+			encodedOutput := output.NewJSONOutput(writer)
+			err := encodedOutput.RenderAuthStatus(&csp.Claims{
+				StandardClaims: jwt.StandardClaims{
+					ExpiresAt: 1700000000,
+				},
+				Username:    "alice@example.com",
+				Context:     "my-context",
+				ContextName: "my-org",
+				Perms:       []string{"csp:platform_operator"},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(writer).To(Say(`{"username":"alice@example.com","context_name":"my-org","context":"my-context","org_owner":false,"platform_operator":true,"perms":\["csp:platform_operator"\],"expires_at":"2023-11-14T22:13:20Z"}`))
+		})
+	})
+
+	Describe("RenderProfiles", func() {
+		It("prints the profiles and the active profile as YAML", func() {
+			encodedOutput := output.NewYAMLOutput(writer)
+			err := encodedOutput.RenderProfiles([]*config.Profile{
+				{Name: "partner", MarketplaceHost: "gtw.partner.example.com", APITokenEnv: "PARTNER_CSP_API_TOKEN"},
+			}, "partner")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(writer).To(Say("active: partner\nprofiles:\n    - name: partner\n      marketplace_host: gtw.partner.example.com\n      api_token_env: PARTNER_CSP_API_TOKEN\n"))
+		})
+	})
+	Describe("RenderConfig", func() {
+		It("prints the settings as JSON", func() {
+			encodedOutput := output.NewJSONOutput(writer)
+			err := encodedOutput.RenderConfig([]*config.Setting{
+				{Key: "output_format", Value: "yaml", Source: config.SourceFile},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(writer).To(Say(`\[{"key":"output_format","value":"yaml","source":"file"}\]`))
+		})
+	})
 })
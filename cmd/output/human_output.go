@@ -9,9 +9,16 @@ import (
 	"io"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/olekukonko/tablewriter"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/catalog"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/config"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/csp"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/diff"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/doctor"
 	"github.com/vmware-labs/marketplace-cli/v2/internal/models"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/spec"
 	"github.com/vmware-labs/marketplace-cli/v2/pkg"
 	"jaytaylor.com/html2text"
 )
@@ -19,6 +26,9 @@ import (
 type HumanOutput struct {
 	writer          io.Writer
 	marketplaceHost string
+
+	// Now is the time that token expiry is measured from
+	Now func() time.Time
 }
 
 func (o *HumanOutput) Printf(format string, a ...interface{}) {
@@ -37,6 +47,7 @@ func NewHumanOutput(writer io.Writer, marketplaceHost string) *HumanOutput {
 	return &HumanOutput{
 		writer:          writer,
 		marketplaceHost: marketplaceHost,
+		Now:             time.Now,
 	}
 }
 
@@ -102,6 +113,127 @@ func (o *HumanOutput) RenderProducts(products []*models.Product) error {
 	return nil
 }
 
+func (o *HumanOutput) RenderProductFacets(facets []*pkg.ProductFacet) error {
+	if len(facets) == 0 {
+		o.Println("No filters available")
+		return nil
+	}
+
+	table := o.NewTable("Filter", "Value", "Products")
+	for _, facet := range facets {
+		for _, value := range facet.Values {
+			count := ""
+			if value.Count > 0 {
+				count = strconv.Itoa(value.Count)
+			}
+			table.Append([]string{facet.Key, value.Value, count})
+		}
+	}
+	table.Render()
+	o.Println("Use these with --filter <filter>=<value>")
+	return nil
+}
+
+func (o *HumanOutput) RenderCatalogSearch(results *catalog.SearchResults) error {
+	if results.SyncedAt.IsZero() {
+		o.Println("The catalog is empty. Run \"mkpcli catalog sync\" to download it.")
+		return nil
+	}
+
+	table := o.NewTable("Slug", "Name", "Publisher", "Type", "Score")
+	for _, result := range results.Results {
+		table.Append([]string{result.Slug, result.DisplayName, result.Publisher, result.SolutionType, strconv.FormatFloat(result.Score, 'f', 1, 64)})
+	}
+	table.Render()
+	o.Printf("Showing %d of %d results, from the catalog synced %s\n", len(results.Results), results.Total, results.SyncedAt.Local().Format(time.RFC1123))
+
+	if results.Total == 0 {
+		return nil
+	}
+	o.Println()
+	o.Println("Refine with:")
+	facets := o.NewTable("Flag", "Value", "Products")
+	for _, facet := range []struct {
+		flag string
+		key  string
+	}{
+		{flag: "--publisher", key: catalog.FacetPublisher},
+		{flag: "--type", key: catalog.FacetSolutionType},
+		{flag: "--category", key: catalog.FacetCategory},
+		{flag: "--tag", key: catalog.FacetTag},
+	} {
+		for _, value := range results.Facets[facet.key] {
+			facets.Append([]string{facet.flag, value.Value, strconv.Itoa(value.Count)})
+		}
+	}
+	facets.Render()
+	return nil
+}
+
+func (o *HumanOutput) RenderApplyPlan(plan *spec.Plan) error {
+	if !plan.HasChanges() {
+		o.Printf("%s is up to date\n", plan.Product)
+		return nil
+	}
+
+	for _, change := range plan.Changes {
+		switch change.Action {
+		case spec.ActionUpdate:
+			o.Printf("  ~ %s\n", change)
+		default:
+			o.Printf("  + %s\n", change)
+		}
+	}
+	o.Printf("%d to change, %d assets already attached\n", len(plan.Changes), plan.Unchanged)
+	return nil
+}
+
+// maxDiffValueLength keeps long values, like EULA text, from filling the screen
+const maxDiffValueLength = 60
+
+func diffValue(value string) string {
+	value = strings.Join(strings.Fields(value), " ")
+	if value == "" {
+		return "(none)"
+	}
+	if len(value) > maxDiffValueLength {
+		return value[:maxDiffValueLength-3] + "..."
+	}
+	return value
+}
+
+func (o *HumanOutput) RenderProductDiff(productDiff *diff.ProductDiff) error {
+	if !productDiff.HasChanges() {
+		o.Println("No differences")
+		return nil
+	}
+
+	if len(productDiff.Assets) > 0 {
+		o.Println("Assets:")
+		table := o.NewTable("Change", "Type", "Name", "Differences")
+		for _, asset := range productDiff.Assets {
+			table.Append([]string{asset.Change, asset.Type, asset.Name, strings.Join(asset.Differences, "\n")})
+		}
+		table.Render()
+	}
+
+	if len(productDiff.Details) > 0 {
+		if len(productDiff.Assets) > 0 {
+			o.Println()
+		}
+		o.Println("Details:")
+		table := o.NewTable("Change", "Field", "From", "To")
+		for _, detail := range productDiff.Details {
+			table.Append([]string{detail.Change, detail.Field, diffValue(detail.From), diffValue(detail.To)})
+		}
+		table.Render()
+	}
+
+	o.Println()
+	o.Printf("%d asset changes, %d detail changes\n", len(productDiff.Assets), len(productDiff.Details))
+	return nil
+}
+
 func (o *HumanOutput) RenderVersions(product *models.Product) error {
 	table := o.NewTable("Number", "Status")
 
@@ -311,6 +443,91 @@ func (o *HumanOutput) RenderAssets(assets []*pkg.Asset) error {
 	return nil
 }
 
+func (o *HumanOutput) RenderAuthStatus(claims *csp.Claims) error {
+	o.Printf("Username:          %s\n", claims.GetQualifiedUsername())
+	o.Printf("Org context name:  %s\n", claims.ContextName)
+	o.Printf("Org context:       %s\n", claims.Context)
+	o.Printf("Org owner:         %s\n", YesNo(claims.IsOrgOwner()))
+	o.Printf("Platform operator: %s\n", YesNo(claims.IsPlatformOperator()))
+
+	if claims.ExpiresAt == 0 {
+		o.Println("Token expires:     unknown")
+	} else {
+		expiresAt := time.Unix(claims.ExpiresAt, 0)
+		o.Printf("Token expires:     %s (in %s)\n", expiresAt.UTC().Format(time.RFC3339), expiresAt.Sub(o.Now()).Round(time.Second))
+	}
+
+	o.Println()
+	o.Println("Permissions:")
+	if len(claims.Perms) == 0 {
+		o.Println("None")
+	}
+	for _, perm := range claims.Perms {
+		o.Printf("  %s\n", perm)
+	}
+	return nil
+}
+
+func (o *HumanOutput) RenderProfiles(profiles []*config.Profile, active string) error {
+	table := o.NewTable("Active", "Name", "Marketplace Host", "CSP Host")
+	for _, profile := range profiles {
+		marker := ""
+		if profile.Name == active {
+			marker = "*"
+		}
+		table.Append([]string{marker, profile.Name, OrDefault(profile.MarketplaceHost), OrDefault(profile.CSPHost)})
+	}
+	table.Render()
+	return nil
+}
+
+func (o *HumanOutput) RenderProfile(profile *config.Profile) error {
+	o.Printf("Name:              %s\n", profile.Name)
+	o.Printf("Marketplace host:  %s\n", OrDefault(profile.MarketplaceHost))
+	o.Printf("API host:          %s\n", OrDefault(profile.APIHost))
+	o.Printf("UI host:           %s\n", OrDefault(profile.UIHost))
+	o.Printf("Storage bucket:    %s\n", OrDefault(profile.StorageBucket))
+	o.Printf("Storage region:    %s\n", OrDefault(profile.StorageRegion))
+	o.Printf("CSP host:          %s\n", OrDefault(profile.CSPHost))
+	o.Printf("API token env:     %s\n", OrDefault(profile.APITokenEnv))
+	o.Printf("Client ID env:     %s\n", OrDefault(profile.ClientIDEnv))
+	o.Printf("Client secret env: %s\n", OrDefault(profile.ClientSecretEnv))
+	o.Printf("Credential helper: %s\n", OrDefault(profile.CredentialHelper))
+	return nil
+}
+
+func (o *HumanOutput) RenderConfig(settings []*config.Setting) error {
+	table := o.NewTable("Key", "Value", "Source")
+	for _, setting := range settings {
+		table.Append([]string{setting.Key, fmt.Sprintf("%v", setting.Value), setting.Source})
+	}
+	table.Render()
+	return nil
+}
+
+func (o *HumanOutput) RenderDiagnostics(results []*doctor.Result) error {
+	table := o.NewTable("Check", "Result", "Details")
+	for _, result := range results {
+		table.Append([]string{result.Name, strings.ToUpper(result.Status), result.Message})
+	}
+	table.Render()
+	return nil
+}
+
+func OrDefault(value string) string {
+	if value == "" {
+		return "(default)"
+	}
+	return value
+}
+
+func YesNo(value bool) string {
+	if value {
+		return "yes"
+	}
+	return "no"
+}
+
 func LatestVersionString(product *models.Product) string {
 	if version := product.GetLatestVersion(); version != nil {
 		return version.Number
@@ -5,12 +5,22 @@ package output_test
 
 import (
 	"regexp"
+	"strings"
+	"time"
 
+	"github.com/golang-jwt/jwt"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	. "github.com/onsi/gomega/gbytes"
 	"github.com/vmware-labs/marketplace-cli/v2/cmd/output"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/catalog"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/config"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/csp"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/diff"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/doctor"
 	"github.com/vmware-labs/marketplace-cli/v2/internal/models"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/spec"
+	"github.com/vmware-labs/marketplace-cli/v2/pkg"
 )
 
 var _ = Describe("HumanOutput", func() {
@@ -85,4 +95,192 @@ var _ = Describe("HumanOutput", func() {
 			})
 		})
 	})
+
+	Describe("RenderProductFacets", func() {
+		It("renders each value of each filter", func() {
+			err := humanOutput.RenderProductFacets([]*pkg.ProductFacet{
+				{Key: "categories", Values: []*pkg.ProductFacetValue{{Value: "Networking", Count: 12}, {Value: "Security"}}},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(writer).To(Say(`FILTER\s+VALUE\s+PRODUCTS`))
+			Expect(writer).To(Say(`categories\s+Networking\s+12`))
+			Expect(writer).To(Say(`categories\s+Security`))
+			Expect(writer).To(Say("Use these with --filter <filter>=<value>"))
+		})
+	})
+
+	Describe("RenderCatalogSearch", func() {
+		It("renders the results and how to refine them", func() {
+			err := humanOutput.RenderCatalogSearch(&catalog.SearchResults{
+				Query:    "database",
+				SyncedAt: time.Now(),
+				Total:    2,
+				Results: []*catalog.SearchResult{
+					{Slug: "tanzu-postgres", DisplayName: "Tanzu Postgres", Publisher: "my-org", SolutionType: models.SolutionTypeChart, Score: 5.5},
+				},
+				Facets: map[string][]*catalog.FacetCount{
+					catalog.FacetPublisher: {{Value: "my-org", Count: 2}},
+					catalog.FacetCategory:  {{Value: "Databases", Count: 1}},
+				},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(writer).To(Say(`SLUG\s+NAME\s+PUBLISHER\s+TYPE\s+SCORE`))
+			Expect(writer).To(Say(`tanzu-postgres\s+Tanzu Postgres\s+my-org\s+HELMCHARTS\s+5.5`))
+			Expect(writer).To(Say("Showing 1 of 2 results, from the catalog synced "))
+			Expect(writer).To(Say(`--publisher\s+my-org\s+2`))
+			Expect(writer).To(Say(`--category\s+Databases\s+1`))
+		})
+
+		When("the catalog was never synced", func() {
+			It("says how to sync it", func() {
+				err := humanOutput.RenderCatalogSearch(&catalog.SearchResults{Results: []*catalog.SearchResult{}})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(writer).To(Say(`The catalog is empty. Run "mkpcli catalog sync" to download it.`))
+			})
+		})
+	})
+
+	Describe("RenderApplyPlan", func() {
+		It("renders each change", func() {
+			err := humanOutput.RenderApplyPlan(&spec.Plan{
+				Product: "my-super-product",
+				Changes: []*spec.Change{
+					{Action: spec.ActionUpdate, Field: "summary", From: "The old summary", To: "The new summary"},
+					{Action: spec.ActionCreate, Version: "1.3.0", Field: "version", To: "1.3.0"},
+					{Action: spec.ActionAttach, Version: "1.3.0", Field: "chart", To: "my-chart-1.3.0.tgz"},
+				},
+				Unchanged: 2,
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(writer).To(Say(`  ~ update summary: "The old summary" => "The new summary"`))
+			Expect(writer).To(Say(`  \+ create version 1.3.0`))
+			Expect(writer).To(Say(`  \+ attach chart my-chart-1.3.0.tgz to version 1.3.0`))
+			Expect(writer).To(Say("3 to change, 2 assets already attached"))
+		})
+
+		When("there is nothing to change", func() {
+			It("says so", func() {
+				err := humanOutput.RenderApplyPlan(&spec.Plan{Product: "my-super-product"})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(writer).To(Say("my-super-product is up to date"))
+			})
+		})
+	})
+
+	Describe("RenderProductDiff", func() {
+		It("renders the asset and detail changes", func() {
+			err := humanOutput.RenderProductDiff(&diff.ProductDiff{
+				From: &diff.Side{Product: "my-super-product", Version: "1.0.0"},
+				To:   &diff.Side{Product: "my-super-product", Version: "1.1.0"},
+				Assets: []*diff.AssetChange{
+					{Change: diff.ChangeChanged, Type: pkg.AssetTypeContainerImage, Name: "nginx:1.1", Differences: []string{"version: 1.0 => 1.1"}},
+					{Change: diff.ChangeAdded, Type: pkg.AssetTypeOther, Name: "notes.txt"},
+				},
+				Details: []*diff.DetailChange{
+					{Change: diff.ChangeChanged, Field: diff.FieldEULAText, From: "The old EULA", To: strings.Repeat("A very long EULA. ", 10)},
+					{Change: diff.ChangeAdded, Field: diff.FieldExportECCN, To: "5D002"},
+				},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(writer).To(Say("Assets:"))
+			Expect(writer).To(Say(`CHANGE\s+TYPE\s+NAME\s+DIFFERENCES`))
+			Expect(writer).To(Say(`changed\s+Container Image\s+nginx:1.1\s+version: 1.0 => 1.1`))
+			Expect(writer).To(Say(`added\s+Other\s+notes.txt`))
+			Expect(writer).To(Say("Details:"))
+			Expect(writer).To(Say(`CHANGE\s+FIELD\s+FROM\s+TO`))
+			Expect(writer).To(Say(`changed\s+EULA text\s+The old EULA\s+A very long EULA. A very long EULA. A very long EULA. A v\.\.\.`))
+			Expect(writer).To(Say(`added\s+Export compliance ECCN\s+\(none\)\s+5D002`))
+			Expect(writer).To(Say("2 asset changes, 2 detail changes"))
+		})
+
+		When("there are no differences", func() {
+			It("says so", func() {
+				err := humanOutput.RenderProductDiff(&diff.ProductDiff{
+					From: &diff.Side{Product: "my-super-product", Version: "1.0.0"},
+					To:   &diff.Side{Product: "my-super-product", Version: "1.0.0"},
+				})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(writer).To(Say("No differences"))
+			})
+		})
+	})
+
+	Describe("RenderAuthStatus", func() {
+		It("renders the identity", func() {
+			now := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+			humanOutput.Now = func() time.Time { return now }
+			err := humanOutput.RenderAuthStatus(&csp.Claims{
+				StandardClaims: jwt.StandardClaims{
+					ExpiresAt: now.Add(time.Hour + 30*time.Minute).Unix(),
+				},
+				Username:    "alice",
+				Domain:      "example.com",
+				Context:     "aaaaaaaa-bbbb-cccc-dddd-eeeeeeeeeeee",
+				ContextName: "ffffffff-0000-1111-2222-333333333333",
+				Perms:       []string{"csp:org_owner", "external/marketplace/publisher"},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(writer).To(Say("Username:          alice@example.com"))
+			Expect(writer).To(Say("Org context name:  ffffffff-0000-1111-2222-333333333333"))
+			Expect(writer).To(Say("Org context:       aaaaaaaa-bbbb-cccc-dddd-eeeeeeeeeeee"))
+			Expect(writer).To(Say("Org owner:         yes"))
+			Expect(writer).To(Say("Platform operator: no"))
+			Expect(writer).To(Say(`Token expires:     2022-06-01T13:30:00Z \(in 1h30m0s\)`))
+			Expect(writer).To(Say("Permissions:"))
+			Expect(writer).To(Say("  csp:org_owner"))
+			Expect(writer).To(Say("  external/marketplace/publisher"))
+		})
+	})
+
+	Describe("RenderProfiles", func() {
+		It("renders the profiles and marks the active one", func() {
+			err := humanOutput.RenderProfiles([]*config.Profile{
+				{Name: "partner", MarketplaceHost: "gtw.partner.example.com"},
+				{Name: "production", MarketplaceHost: "gtw.marketplace.example.com", CSPHost: "console.example.com"},
+			}, "production")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(writer).To(Say(`ACTIVE\s+NAME\s+MARKETPLACE HOST\s+CSP HOST`))
+			Expect(writer).To(Say(`\s+partner\s+gtw.partner.example.com\s+\(default\)`))
+			Expect(writer).To(Say(`\*\s+production\s+gtw.marketplace.example.com\s+console.example.com`))
+		})
+	})
+
+	Describe("RenderProfile", func() {
+		It("renders the profile settings", func() {
+			err := humanOutput.RenderProfile(&config.Profile{
+				Name:            "partner",
+				MarketplaceHost: "gtw.partner.example.com",
+				APITokenEnv:     "PARTNER_CSP_API_TOKEN",
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(writer).To(Say("Name:              partner"))
+			Expect(writer).To(Say("Marketplace host:  gtw.partner.example.com"))
+			Expect(writer).To(Say(`API host:          \(default\)`))
+			Expect(writer).To(Say("API token env:     PARTNER_CSP_API_TOKEN"))
+		})
+	})
+	Describe("RenderConfig", func() {
+		It("renders the settings and their sources", func() {
+			err := humanOutput.RenderConfig([]*config.Setting{
+				{Key: "csp.api-token", Value: config.RedactedValue, Source: config.SourceEnv},
+				{Key: "output_format", Value: "yaml", Source: config.SourceFile},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(writer).To(Say(`KEY\s+VALUE\s+SOURCE`))
+			Expect(writer).To(Say(`csp.api-token\s+\[redacted\]\s+env`))
+			Expect(writer).To(Say(`output_format\s+yaml\s+file`))
+		})
+	})
+	Describe("RenderDiagnostics", func() {
+		It("renders the result of each check", func() {
+			err := humanOutput.RenderDiagnostics([]*doctor.Result{
+				{Name: "CSP host", Status: doctor.StatusFail, Message: "failed to resolve console.example.com"},
+				{Name: "CSP public key", Status: doctor.StatusSkip, Message: "requires CSP host"},
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(writer).To(Say(`CHECK\s+RESULT\s+DETAILS`))
+			Expect(writer).To(Say(`CSP host\s+FAIL\s+failed to resolve console.example.com`))
+			Expect(writer).To(Say(`CSP public key\s+SKIP\s+requires CSP host`))
+		})
+	})
 })
@@ -4,7 +4,13 @@
 package output
 
 import (
+	"github.com/vmware-labs/marketplace-cli/v2/internal/catalog"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/config"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/csp"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/diff"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/doctor"
 	"github.com/vmware-labs/marketplace-cli/v2/internal/models"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/spec"
 	"github.com/vmware-labs/marketplace-cli/v2/pkg"
 )
 
@@ -22,6 +28,7 @@ type Format interface {
 
 	RenderProduct(product *models.Product, version *models.Version) error
 	RenderProducts(products []*models.Product) error
+	RenderProductFacets(facets []*pkg.ProductFacet) error
 	RenderVersions(product *models.Product) error
 	RenderChart(chart *models.ChartVersion) error
 	RenderCharts(charts []*models.ChartVersion) error
@@ -30,4 +37,17 @@ type Format interface {
 	RenderFiles(files []*models.ProductDeploymentFile) error
 
 	RenderAssets(assets []*pkg.Asset) error
+
+	RenderCatalogSearch(results *catalog.SearchResults) error
+
+	RenderApplyPlan(plan *spec.Plan) error
+
+	RenderProductDiff(productDiff *diff.ProductDiff) error
+
+	RenderAuthStatus(claims *csp.Claims) error
+
+	RenderProfiles(profiles []*config.Profile, active string) error
+	RenderProfile(profile *config.Profile) error
+	RenderConfig(settings []*config.Setting) error
+	RenderDiagnostics(results []*doctor.Result) error
 }
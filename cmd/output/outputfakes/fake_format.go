@@ -5,7 +5,13 @@ import (
 	"sync"
 
 	"github.com/vmware-labs/marketplace-cli/v2/cmd/output"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/catalog"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/config"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/csp"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/diff"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/doctor"
 	"github.com/vmware-labs/marketplace-cli/v2/internal/models"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/spec"
 	"github.com/vmware-labs/marketplace-cli/v2/pkg"
 )
 
@@ -15,6 +21,17 @@ type FakeFormat struct {
 	printHeaderArgsForCall []struct {
 		arg1 string
 	}
+	RenderApplyPlanStub        func(*spec.Plan) error
+	renderApplyPlanMutex       sync.RWMutex
+	renderApplyPlanArgsForCall []struct {
+		arg1 *spec.Plan
+	}
+	renderApplyPlanReturns struct {
+		result1 error
+	}
+	renderApplyPlanReturnsOnCall map[int]struct {
+		result1 error
+	}
 	RenderAssetsStub        func([]*pkg.Asset) error
 	renderAssetsMutex       sync.RWMutex
 	renderAssetsArgsForCall []struct {
@@ -26,6 +43,28 @@ type FakeFormat struct {
 	renderAssetsReturnsOnCall map[int]struct {
 		result1 error
 	}
+	RenderAuthStatusStub        func(*csp.Claims) error
+	renderAuthStatusMutex       sync.RWMutex
+	renderAuthStatusArgsForCall []struct {
+		arg1 *csp.Claims
+	}
+	renderAuthStatusReturns struct {
+		result1 error
+	}
+	renderAuthStatusReturnsOnCall map[int]struct {
+		result1 error
+	}
+	RenderCatalogSearchStub        func(*catalog.SearchResults) error
+	renderCatalogSearchMutex       sync.RWMutex
+	renderCatalogSearchArgsForCall []struct {
+		arg1 *catalog.SearchResults
+	}
+	renderCatalogSearchReturns struct {
+		result1 error
+	}
+	renderCatalogSearchReturnsOnCall map[int]struct {
+		result1 error
+	}
 	RenderChartStub        func(*models.ChartVersion) error
 	renderChartMutex       sync.RWMutex
 	renderChartArgsForCall []struct {
@@ -48,6 +87,17 @@ type FakeFormat struct {
 	renderChartsReturnsOnCall map[int]struct {
 		result1 error
 	}
+	RenderConfigStub        func([]*config.Setting) error
+	renderConfigMutex       sync.RWMutex
+	renderConfigArgsForCall []struct {
+		arg1 []*config.Setting
+	}
+	renderConfigReturns struct {
+		result1 error
+	}
+	renderConfigReturnsOnCall map[int]struct {
+		result1 error
+	}
 	RenderContainerImagesStub        func([]*models.DockerVersionList) error
 	renderContainerImagesMutex       sync.RWMutex
 	renderContainerImagesArgsForCall []struct {
@@ -59,6 +109,17 @@ type FakeFormat struct {
 	renderContainerImagesReturnsOnCall map[int]struct {
 		result1 error
 	}
+	RenderDiagnosticsStub        func([]*doctor.Result) error
+	renderDiagnosticsMutex       sync.RWMutex
+	renderDiagnosticsArgsForCall []struct {
+		arg1 []*doctor.Result
+	}
+	renderDiagnosticsReturns struct {
+		result1 error
+	}
+	renderDiagnosticsReturnsOnCall map[int]struct {
+		result1 error
+	}
 	RenderFileStub        func(*models.ProductDeploymentFile) error
 	renderFileMutex       sync.RWMutex
 	renderFileArgsForCall []struct {
@@ -93,6 +154,28 @@ type FakeFormat struct {
 	renderProductReturnsOnCall map[int]struct {
 		result1 error
 	}
+	RenderProductDiffStub        func(*diff.ProductDiff) error
+	renderProductDiffMutex       sync.RWMutex
+	renderProductDiffArgsForCall []struct {
+		arg1 *diff.ProductDiff
+	}
+	renderProductDiffReturns struct {
+		result1 error
+	}
+	renderProductDiffReturnsOnCall map[int]struct {
+		result1 error
+	}
+	RenderProductFacetsStub        func([]*pkg.ProductFacet) error
+	renderProductFacetsMutex       sync.RWMutex
+	renderProductFacetsArgsForCall []struct {
+		arg1 []*pkg.ProductFacet
+	}
+	renderProductFacetsReturns struct {
+		result1 error
+	}
+	renderProductFacetsReturnsOnCall map[int]struct {
+		result1 error
+	}
 	RenderProductsStub        func([]*models.Product) error
 	renderProductsMutex       sync.RWMutex
 	renderProductsArgsForCall []struct {
@@ -104,6 +187,29 @@ type FakeFormat struct {
 	renderProductsReturnsOnCall map[int]struct {
 		result1 error
 	}
+	RenderProfileStub        func(*config.Profile) error
+	renderProfileMutex       sync.RWMutex
+	renderProfileArgsForCall []struct {
+		arg1 *config.Profile
+	}
+	renderProfileReturns struct {
+		result1 error
+	}
+	renderProfileReturnsOnCall map[int]struct {
+		result1 error
+	}
+	RenderProfilesStub        func([]*config.Profile, string) error
+	renderProfilesMutex       sync.RWMutex
+	renderProfilesArgsForCall []struct {
+		arg1 []*config.Profile
+		arg2 string
+	}
+	renderProfilesReturns struct {
+		result1 error
+	}
+	renderProfilesReturnsOnCall map[int]struct {
+		result1 error
+	}
 	RenderVersionsStub        func(*models.Product) error
 	renderVersionsMutex       sync.RWMutex
 	renderVersionsArgsForCall []struct {
@@ -124,9 +230,10 @@ func (fake *FakeFormat) PrintHeader(arg1 string) {
 	fake.printHeaderArgsForCall = append(fake.printHeaderArgsForCall, struct {
 		arg1 string
 	}{arg1})
+	stub := fake.PrintHeaderStub
 	fake.recordInvocation("PrintHeader", []interface{}{arg1})
 	fake.printHeaderMutex.Unlock()
-	if fake.PrintHeaderStub != nil {
+	if stub != nil {
 		fake.PrintHeaderStub(arg1)
 	}
 }
@@ -150,6 +257,67 @@ func (fake *FakeFormat) PrintHeaderArgsForCall(i int) string {
 	return argsForCall.arg1
 }
 
+func (fake *FakeFormat) RenderApplyPlan(arg1 *spec.Plan) error {
+	fake.renderApplyPlanMutex.Lock()
+	ret, specificReturn := fake.renderApplyPlanReturnsOnCall[len(fake.renderApplyPlanArgsForCall)]
+	fake.renderApplyPlanArgsForCall = append(fake.renderApplyPlanArgsForCall, struct {
+		arg1 *spec.Plan
+	}{arg1})
+	stub := fake.RenderApplyPlanStub
+	fakeReturns := fake.renderApplyPlanReturns
+	fake.recordInvocation("RenderApplyPlan", []interface{}{arg1})
+	fake.renderApplyPlanMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeFormat) RenderApplyPlanCallCount() int {
+	fake.renderApplyPlanMutex.RLock()
+	defer fake.renderApplyPlanMutex.RUnlock()
+	return len(fake.renderApplyPlanArgsForCall)
+}
+
+func (fake *FakeFormat) RenderApplyPlanCalls(stub func(*spec.Plan) error) {
+	fake.renderApplyPlanMutex.Lock()
+	defer fake.renderApplyPlanMutex.Unlock()
+	fake.RenderApplyPlanStub = stub
+}
+
+func (fake *FakeFormat) RenderApplyPlanArgsForCall(i int) *spec.Plan {
+	fake.renderApplyPlanMutex.RLock()
+	defer fake.renderApplyPlanMutex.RUnlock()
+	argsForCall := fake.renderApplyPlanArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeFormat) RenderApplyPlanReturns(result1 error) {
+	fake.renderApplyPlanMutex.Lock()
+	defer fake.renderApplyPlanMutex.Unlock()
+	fake.RenderApplyPlanStub = nil
+	fake.renderApplyPlanReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeFormat) RenderApplyPlanReturnsOnCall(i int, result1 error) {
+	fake.renderApplyPlanMutex.Lock()
+	defer fake.renderApplyPlanMutex.Unlock()
+	fake.RenderApplyPlanStub = nil
+	if fake.renderApplyPlanReturnsOnCall == nil {
+		fake.renderApplyPlanReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.renderApplyPlanReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeFormat) RenderAssets(arg1 []*pkg.Asset) error {
 	var arg1Copy []*pkg.Asset
 	if arg1 != nil {
@@ -161,15 +329,16 @@ func (fake *FakeFormat) RenderAssets(arg1 []*pkg.Asset) error {
 	fake.renderAssetsArgsForCall = append(fake.renderAssetsArgsForCall, struct {
 		arg1 []*pkg.Asset
 	}{arg1Copy})
+	stub := fake.RenderAssetsStub
+	fakeReturns := fake.renderAssetsReturns
 	fake.recordInvocation("RenderAssets", []interface{}{arg1Copy})
 	fake.renderAssetsMutex.Unlock()
-	if fake.RenderAssetsStub != nil {
-		return fake.RenderAssetsStub(arg1)
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.renderAssetsReturns
 	return fakeReturns.result1
 }
 
@@ -215,21 +384,144 @@ func (fake *FakeFormat) RenderAssetsReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeFormat) RenderAuthStatus(arg1 *csp.Claims) error {
+	fake.renderAuthStatusMutex.Lock()
+	ret, specificReturn := fake.renderAuthStatusReturnsOnCall[len(fake.renderAuthStatusArgsForCall)]
+	fake.renderAuthStatusArgsForCall = append(fake.renderAuthStatusArgsForCall, struct {
+		arg1 *csp.Claims
+	}{arg1})
+	stub := fake.RenderAuthStatusStub
+	fakeReturns := fake.renderAuthStatusReturns
+	fake.recordInvocation("RenderAuthStatus", []interface{}{arg1})
+	fake.renderAuthStatusMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeFormat) RenderAuthStatusCallCount() int {
+	fake.renderAuthStatusMutex.RLock()
+	defer fake.renderAuthStatusMutex.RUnlock()
+	return len(fake.renderAuthStatusArgsForCall)
+}
+
+func (fake *FakeFormat) RenderAuthStatusCalls(stub func(*csp.Claims) error) {
+	fake.renderAuthStatusMutex.Lock()
+	defer fake.renderAuthStatusMutex.Unlock()
+	fake.RenderAuthStatusStub = stub
+}
+
+func (fake *FakeFormat) RenderAuthStatusArgsForCall(i int) *csp.Claims {
+	fake.renderAuthStatusMutex.RLock()
+	defer fake.renderAuthStatusMutex.RUnlock()
+	argsForCall := fake.renderAuthStatusArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeFormat) RenderAuthStatusReturns(result1 error) {
+	fake.renderAuthStatusMutex.Lock()
+	defer fake.renderAuthStatusMutex.Unlock()
+	fake.RenderAuthStatusStub = nil
+	fake.renderAuthStatusReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeFormat) RenderAuthStatusReturnsOnCall(i int, result1 error) {
+	fake.renderAuthStatusMutex.Lock()
+	defer fake.renderAuthStatusMutex.Unlock()
+	fake.RenderAuthStatusStub = nil
+	if fake.renderAuthStatusReturnsOnCall == nil {
+		fake.renderAuthStatusReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.renderAuthStatusReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeFormat) RenderCatalogSearch(arg1 *catalog.SearchResults) error {
+	fake.renderCatalogSearchMutex.Lock()
+	ret, specificReturn := fake.renderCatalogSearchReturnsOnCall[len(fake.renderCatalogSearchArgsForCall)]
+	fake.renderCatalogSearchArgsForCall = append(fake.renderCatalogSearchArgsForCall, struct {
+		arg1 *catalog.SearchResults
+	}{arg1})
+	stub := fake.RenderCatalogSearchStub
+	fakeReturns := fake.renderCatalogSearchReturns
+	fake.recordInvocation("RenderCatalogSearch", []interface{}{arg1})
+	fake.renderCatalogSearchMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeFormat) RenderCatalogSearchCallCount() int {
+	fake.renderCatalogSearchMutex.RLock()
+	defer fake.renderCatalogSearchMutex.RUnlock()
+	return len(fake.renderCatalogSearchArgsForCall)
+}
+
+func (fake *FakeFormat) RenderCatalogSearchCalls(stub func(*catalog.SearchResults) error) {
+	fake.renderCatalogSearchMutex.Lock()
+	defer fake.renderCatalogSearchMutex.Unlock()
+	fake.RenderCatalogSearchStub = stub
+}
+
+func (fake *FakeFormat) RenderCatalogSearchArgsForCall(i int) *catalog.SearchResults {
+	fake.renderCatalogSearchMutex.RLock()
+	defer fake.renderCatalogSearchMutex.RUnlock()
+	argsForCall := fake.renderCatalogSearchArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeFormat) RenderCatalogSearchReturns(result1 error) {
+	fake.renderCatalogSearchMutex.Lock()
+	defer fake.renderCatalogSearchMutex.Unlock()
+	fake.RenderCatalogSearchStub = nil
+	fake.renderCatalogSearchReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeFormat) RenderCatalogSearchReturnsOnCall(i int, result1 error) {
+	fake.renderCatalogSearchMutex.Lock()
+	defer fake.renderCatalogSearchMutex.Unlock()
+	fake.RenderCatalogSearchStub = nil
+	if fake.renderCatalogSearchReturnsOnCall == nil {
+		fake.renderCatalogSearchReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.renderCatalogSearchReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeFormat) RenderChart(arg1 *models.ChartVersion) error {
 	fake.renderChartMutex.Lock()
 	ret, specificReturn := fake.renderChartReturnsOnCall[len(fake.renderChartArgsForCall)]
 	fake.renderChartArgsForCall = append(fake.renderChartArgsForCall, struct {
 		arg1 *models.ChartVersion
 	}{arg1})
+	stub := fake.RenderChartStub
+	fakeReturns := fake.renderChartReturns
 	fake.recordInvocation("RenderChart", []interface{}{arg1})
 	fake.renderChartMutex.Unlock()
-	if fake.RenderChartStub != nil {
-		return fake.RenderChartStub(arg1)
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.renderChartReturns
 	return fakeReturns.result1
 }
 
@@ -286,15 +578,16 @@ func (fake *FakeFormat) RenderCharts(arg1 []*models.ChartVersion) error {
 	fake.renderChartsArgsForCall = append(fake.renderChartsArgsForCall, struct {
 		arg1 []*models.ChartVersion
 	}{arg1Copy})
+	stub := fake.RenderChartsStub
+	fakeReturns := fake.renderChartsReturns
 	fake.recordInvocation("RenderCharts", []interface{}{arg1Copy})
 	fake.renderChartsMutex.Unlock()
-	if fake.RenderChartsStub != nil {
-		return fake.RenderChartsStub(arg1)
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.renderChartsReturns
 	return fakeReturns.result1
 }
 
@@ -340,6 +633,72 @@ func (fake *FakeFormat) RenderChartsReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeFormat) RenderConfig(arg1 []*config.Setting) error {
+	var arg1Copy []*config.Setting
+	if arg1 != nil {
+		arg1Copy = make([]*config.Setting, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.renderConfigMutex.Lock()
+	ret, specificReturn := fake.renderConfigReturnsOnCall[len(fake.renderConfigArgsForCall)]
+	fake.renderConfigArgsForCall = append(fake.renderConfigArgsForCall, struct {
+		arg1 []*config.Setting
+	}{arg1Copy})
+	stub := fake.RenderConfigStub
+	fakeReturns := fake.renderConfigReturns
+	fake.recordInvocation("RenderConfig", []interface{}{arg1Copy})
+	fake.renderConfigMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeFormat) RenderConfigCallCount() int {
+	fake.renderConfigMutex.RLock()
+	defer fake.renderConfigMutex.RUnlock()
+	return len(fake.renderConfigArgsForCall)
+}
+
+func (fake *FakeFormat) RenderConfigCalls(stub func([]*config.Setting) error) {
+	fake.renderConfigMutex.Lock()
+	defer fake.renderConfigMutex.Unlock()
+	fake.RenderConfigStub = stub
+}
+
+func (fake *FakeFormat) RenderConfigArgsForCall(i int) []*config.Setting {
+	fake.renderConfigMutex.RLock()
+	defer fake.renderConfigMutex.RUnlock()
+	argsForCall := fake.renderConfigArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeFormat) RenderConfigReturns(result1 error) {
+	fake.renderConfigMutex.Lock()
+	defer fake.renderConfigMutex.Unlock()
+	fake.RenderConfigStub = nil
+	fake.renderConfigReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeFormat) RenderConfigReturnsOnCall(i int, result1 error) {
+	fake.renderConfigMutex.Lock()
+	defer fake.renderConfigMutex.Unlock()
+	fake.RenderConfigStub = nil
+	if fake.renderConfigReturnsOnCall == nil {
+		fake.renderConfigReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.renderConfigReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeFormat) RenderContainerImages(arg1 []*models.DockerVersionList) error {
 	var arg1Copy []*models.DockerVersionList
 	if arg1 != nil {
@@ -351,15 +710,16 @@ func (fake *FakeFormat) RenderContainerImages(arg1 []*models.DockerVersionList)
 	fake.renderContainerImagesArgsForCall = append(fake.renderContainerImagesArgsForCall, struct {
 		arg1 []*models.DockerVersionList
 	}{arg1Copy})
+	stub := fake.RenderContainerImagesStub
+	fakeReturns := fake.renderContainerImagesReturns
 	fake.recordInvocation("RenderContainerImages", []interface{}{arg1Copy})
 	fake.renderContainerImagesMutex.Unlock()
-	if fake.RenderContainerImagesStub != nil {
-		return fake.RenderContainerImagesStub(arg1)
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.renderContainerImagesReturns
 	return fakeReturns.result1
 }
 
@@ -405,21 +765,88 @@ func (fake *FakeFormat) RenderContainerImagesReturnsOnCall(i int, result1 error)
 	}{result1}
 }
 
+func (fake *FakeFormat) RenderDiagnostics(arg1 []*doctor.Result) error {
+	var arg1Copy []*doctor.Result
+	if arg1 != nil {
+		arg1Copy = make([]*doctor.Result, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.renderDiagnosticsMutex.Lock()
+	ret, specificReturn := fake.renderDiagnosticsReturnsOnCall[len(fake.renderDiagnosticsArgsForCall)]
+	fake.renderDiagnosticsArgsForCall = append(fake.renderDiagnosticsArgsForCall, struct {
+		arg1 []*doctor.Result
+	}{arg1Copy})
+	stub := fake.RenderDiagnosticsStub
+	fakeReturns := fake.renderDiagnosticsReturns
+	fake.recordInvocation("RenderDiagnostics", []interface{}{arg1Copy})
+	fake.renderDiagnosticsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeFormat) RenderDiagnosticsCallCount() int {
+	fake.renderDiagnosticsMutex.RLock()
+	defer fake.renderDiagnosticsMutex.RUnlock()
+	return len(fake.renderDiagnosticsArgsForCall)
+}
+
+func (fake *FakeFormat) RenderDiagnosticsCalls(stub func([]*doctor.Result) error) {
+	fake.renderDiagnosticsMutex.Lock()
+	defer fake.renderDiagnosticsMutex.Unlock()
+	fake.RenderDiagnosticsStub = stub
+}
+
+func (fake *FakeFormat) RenderDiagnosticsArgsForCall(i int) []*doctor.Result {
+	fake.renderDiagnosticsMutex.RLock()
+	defer fake.renderDiagnosticsMutex.RUnlock()
+	argsForCall := fake.renderDiagnosticsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeFormat) RenderDiagnosticsReturns(result1 error) {
+	fake.renderDiagnosticsMutex.Lock()
+	defer fake.renderDiagnosticsMutex.Unlock()
+	fake.RenderDiagnosticsStub = nil
+	fake.renderDiagnosticsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeFormat) RenderDiagnosticsReturnsOnCall(i int, result1 error) {
+	fake.renderDiagnosticsMutex.Lock()
+	defer fake.renderDiagnosticsMutex.Unlock()
+	fake.RenderDiagnosticsStub = nil
+	if fake.renderDiagnosticsReturnsOnCall == nil {
+		fake.renderDiagnosticsReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.renderDiagnosticsReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeFormat) RenderFile(arg1 *models.ProductDeploymentFile) error {
 	fake.renderFileMutex.Lock()
 	ret, specificReturn := fake.renderFileReturnsOnCall[len(fake.renderFileArgsForCall)]
 	fake.renderFileArgsForCall = append(fake.renderFileArgsForCall, struct {
 		arg1 *models.ProductDeploymentFile
 	}{arg1})
+	stub := fake.RenderFileStub
+	fakeReturns := fake.renderFileReturns
 	fake.recordInvocation("RenderFile", []interface{}{arg1})
 	fake.renderFileMutex.Unlock()
-	if fake.RenderFileStub != nil {
-		return fake.RenderFileStub(arg1)
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.renderFileReturns
 	return fakeReturns.result1
 }
 
@@ -476,15 +903,16 @@ func (fake *FakeFormat) RenderFiles(arg1 []*models.ProductDeploymentFile) error
 	fake.renderFilesArgsForCall = append(fake.renderFilesArgsForCall, struct {
 		arg1 []*models.ProductDeploymentFile
 	}{arg1Copy})
+	stub := fake.RenderFilesStub
+	fakeReturns := fake.renderFilesReturns
 	fake.recordInvocation("RenderFiles", []interface{}{arg1Copy})
 	fake.renderFilesMutex.Unlock()
-	if fake.RenderFilesStub != nil {
-		return fake.RenderFilesStub(arg1)
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.renderFilesReturns
 	return fakeReturns.result1
 }
 
@@ -537,15 +965,16 @@ func (fake *FakeFormat) RenderProduct(arg1 *models.Product, arg2 *models.Version
 		arg1 *models.Product
 		arg2 *models.Version
 	}{arg1, arg2})
+	stub := fake.RenderProductStub
+	fakeReturns := fake.renderProductReturns
 	fake.recordInvocation("RenderProduct", []interface{}{arg1, arg2})
 	fake.renderProductMutex.Unlock()
-	if fake.RenderProductStub != nil {
-		return fake.RenderProductStub(arg1, arg2)
+	if stub != nil {
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.renderProductReturns
 	return fakeReturns.result1
 }
 
@@ -591,6 +1020,133 @@ func (fake *FakeFormat) RenderProductReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeFormat) RenderProductDiff(arg1 *diff.ProductDiff) error {
+	fake.renderProductDiffMutex.Lock()
+	ret, specificReturn := fake.renderProductDiffReturnsOnCall[len(fake.renderProductDiffArgsForCall)]
+	fake.renderProductDiffArgsForCall = append(fake.renderProductDiffArgsForCall, struct {
+		arg1 *diff.ProductDiff
+	}{arg1})
+	stub := fake.RenderProductDiffStub
+	fakeReturns := fake.renderProductDiffReturns
+	fake.recordInvocation("RenderProductDiff", []interface{}{arg1})
+	fake.renderProductDiffMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeFormat) RenderProductDiffCallCount() int {
+	fake.renderProductDiffMutex.RLock()
+	defer fake.renderProductDiffMutex.RUnlock()
+	return len(fake.renderProductDiffArgsForCall)
+}
+
+func (fake *FakeFormat) RenderProductDiffCalls(stub func(*diff.ProductDiff) error) {
+	fake.renderProductDiffMutex.Lock()
+	defer fake.renderProductDiffMutex.Unlock()
+	fake.RenderProductDiffStub = stub
+}
+
+func (fake *FakeFormat) RenderProductDiffArgsForCall(i int) *diff.ProductDiff {
+	fake.renderProductDiffMutex.RLock()
+	defer fake.renderProductDiffMutex.RUnlock()
+	argsForCall := fake.renderProductDiffArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeFormat) RenderProductDiffReturns(result1 error) {
+	fake.renderProductDiffMutex.Lock()
+	defer fake.renderProductDiffMutex.Unlock()
+	fake.RenderProductDiffStub = nil
+	fake.renderProductDiffReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeFormat) RenderProductDiffReturnsOnCall(i int, result1 error) {
+	fake.renderProductDiffMutex.Lock()
+	defer fake.renderProductDiffMutex.Unlock()
+	fake.RenderProductDiffStub = nil
+	if fake.renderProductDiffReturnsOnCall == nil {
+		fake.renderProductDiffReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.renderProductDiffReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeFormat) RenderProductFacets(arg1 []*pkg.ProductFacet) error {
+	var arg1Copy []*pkg.ProductFacet
+	if arg1 != nil {
+		arg1Copy = make([]*pkg.ProductFacet, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.renderProductFacetsMutex.Lock()
+	ret, specificReturn := fake.renderProductFacetsReturnsOnCall[len(fake.renderProductFacetsArgsForCall)]
+	fake.renderProductFacetsArgsForCall = append(fake.renderProductFacetsArgsForCall, struct {
+		arg1 []*pkg.ProductFacet
+	}{arg1Copy})
+	stub := fake.RenderProductFacetsStub
+	fakeReturns := fake.renderProductFacetsReturns
+	fake.recordInvocation("RenderProductFacets", []interface{}{arg1Copy})
+	fake.renderProductFacetsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeFormat) RenderProductFacetsCallCount() int {
+	fake.renderProductFacetsMutex.RLock()
+	defer fake.renderProductFacetsMutex.RUnlock()
+	return len(fake.renderProductFacetsArgsForCall)
+}
+
+func (fake *FakeFormat) RenderProductFacetsCalls(stub func([]*pkg.ProductFacet) error) {
+	fake.renderProductFacetsMutex.Lock()
+	defer fake.renderProductFacetsMutex.Unlock()
+	fake.RenderProductFacetsStub = stub
+}
+
+func (fake *FakeFormat) RenderProductFacetsArgsForCall(i int) []*pkg.ProductFacet {
+	fake.renderProductFacetsMutex.RLock()
+	defer fake.renderProductFacetsMutex.RUnlock()
+	argsForCall := fake.renderProductFacetsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeFormat) RenderProductFacetsReturns(result1 error) {
+	fake.renderProductFacetsMutex.Lock()
+	defer fake.renderProductFacetsMutex.Unlock()
+	fake.RenderProductFacetsStub = nil
+	fake.renderProductFacetsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeFormat) RenderProductFacetsReturnsOnCall(i int, result1 error) {
+	fake.renderProductFacetsMutex.Lock()
+	defer fake.renderProductFacetsMutex.Unlock()
+	fake.RenderProductFacetsStub = nil
+	if fake.renderProductFacetsReturnsOnCall == nil {
+		fake.renderProductFacetsReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.renderProductFacetsReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeFormat) RenderProducts(arg1 []*models.Product) error {
 	var arg1Copy []*models.Product
 	if arg1 != nil {
@@ -602,15 +1158,16 @@ func (fake *FakeFormat) RenderProducts(arg1 []*models.Product) error {
 	fake.renderProductsArgsForCall = append(fake.renderProductsArgsForCall, struct {
 		arg1 []*models.Product
 	}{arg1Copy})
+	stub := fake.RenderProductsStub
+	fakeReturns := fake.renderProductsReturns
 	fake.recordInvocation("RenderProducts", []interface{}{arg1Copy})
 	fake.renderProductsMutex.Unlock()
-	if fake.RenderProductsStub != nil {
-		return fake.RenderProductsStub(arg1)
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.renderProductsReturns
 	return fakeReturns.result1
 }
 
@@ -656,21 +1213,150 @@ func (fake *FakeFormat) RenderProductsReturnsOnCall(i int, result1 error) {
 	}{result1}
 }
 
+func (fake *FakeFormat) RenderProfile(arg1 *config.Profile) error {
+	fake.renderProfileMutex.Lock()
+	ret, specificReturn := fake.renderProfileReturnsOnCall[len(fake.renderProfileArgsForCall)]
+	fake.renderProfileArgsForCall = append(fake.renderProfileArgsForCall, struct {
+		arg1 *config.Profile
+	}{arg1})
+	stub := fake.RenderProfileStub
+	fakeReturns := fake.renderProfileReturns
+	fake.recordInvocation("RenderProfile", []interface{}{arg1})
+	fake.renderProfileMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeFormat) RenderProfileCallCount() int {
+	fake.renderProfileMutex.RLock()
+	defer fake.renderProfileMutex.RUnlock()
+	return len(fake.renderProfileArgsForCall)
+}
+
+func (fake *FakeFormat) RenderProfileCalls(stub func(*config.Profile) error) {
+	fake.renderProfileMutex.Lock()
+	defer fake.renderProfileMutex.Unlock()
+	fake.RenderProfileStub = stub
+}
+
+func (fake *FakeFormat) RenderProfileArgsForCall(i int) *config.Profile {
+	fake.renderProfileMutex.RLock()
+	defer fake.renderProfileMutex.RUnlock()
+	argsForCall := fake.renderProfileArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeFormat) RenderProfileReturns(result1 error) {
+	fake.renderProfileMutex.Lock()
+	defer fake.renderProfileMutex.Unlock()
+	fake.RenderProfileStub = nil
+	fake.renderProfileReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeFormat) RenderProfileReturnsOnCall(i int, result1 error) {
+	fake.renderProfileMutex.Lock()
+	defer fake.renderProfileMutex.Unlock()
+	fake.RenderProfileStub = nil
+	if fake.renderProfileReturnsOnCall == nil {
+		fake.renderProfileReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.renderProfileReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeFormat) RenderProfiles(arg1 []*config.Profile, arg2 string) error {
+	var arg1Copy []*config.Profile
+	if arg1 != nil {
+		arg1Copy = make([]*config.Profile, len(arg1))
+		copy(arg1Copy, arg1)
+	}
+	fake.renderProfilesMutex.Lock()
+	ret, specificReturn := fake.renderProfilesReturnsOnCall[len(fake.renderProfilesArgsForCall)]
+	fake.renderProfilesArgsForCall = append(fake.renderProfilesArgsForCall, struct {
+		arg1 []*config.Profile
+		arg2 string
+	}{arg1Copy, arg2})
+	stub := fake.RenderProfilesStub
+	fakeReturns := fake.renderProfilesReturns
+	fake.recordInvocation("RenderProfiles", []interface{}{arg1Copy, arg2})
+	fake.renderProfilesMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeFormat) RenderProfilesCallCount() int {
+	fake.renderProfilesMutex.RLock()
+	defer fake.renderProfilesMutex.RUnlock()
+	return len(fake.renderProfilesArgsForCall)
+}
+
+func (fake *FakeFormat) RenderProfilesCalls(stub func([]*config.Profile, string) error) {
+	fake.renderProfilesMutex.Lock()
+	defer fake.renderProfilesMutex.Unlock()
+	fake.RenderProfilesStub = stub
+}
+
+func (fake *FakeFormat) RenderProfilesArgsForCall(i int) ([]*config.Profile, string) {
+	fake.renderProfilesMutex.RLock()
+	defer fake.renderProfilesMutex.RUnlock()
+	argsForCall := fake.renderProfilesArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeFormat) RenderProfilesReturns(result1 error) {
+	fake.renderProfilesMutex.Lock()
+	defer fake.renderProfilesMutex.Unlock()
+	fake.RenderProfilesStub = nil
+	fake.renderProfilesReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeFormat) RenderProfilesReturnsOnCall(i int, result1 error) {
+	fake.renderProfilesMutex.Lock()
+	defer fake.renderProfilesMutex.Unlock()
+	fake.RenderProfilesStub = nil
+	if fake.renderProfilesReturnsOnCall == nil {
+		fake.renderProfilesReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.renderProfilesReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeFormat) RenderVersions(arg1 *models.Product) error {
 	fake.renderVersionsMutex.Lock()
 	ret, specificReturn := fake.renderVersionsReturnsOnCall[len(fake.renderVersionsArgsForCall)]
 	fake.renderVersionsArgsForCall = append(fake.renderVersionsArgsForCall, struct {
 		arg1 *models.Product
 	}{arg1})
+	stub := fake.RenderVersionsStub
+	fakeReturns := fake.renderVersionsReturns
 	fake.recordInvocation("RenderVersions", []interface{}{arg1})
 	fake.renderVersionsMutex.Unlock()
-	if fake.RenderVersionsStub != nil {
-		return fake.RenderVersionsStub(arg1)
+	if stub != nil {
+		return stub(arg1)
 	}
 	if specificReturn {
 		return ret.result1
 	}
-	fakeReturns := fake.renderVersionsReturns
 	return fakeReturns.result1
 }
 
@@ -721,22 +1407,40 @@ func (fake *FakeFormat) Invocations() map[string][][]interface{} {
 	defer fake.invocationsMutex.RUnlock()
 	fake.printHeaderMutex.RLock()
 	defer fake.printHeaderMutex.RUnlock()
+	fake.renderApplyPlanMutex.RLock()
+	defer fake.renderApplyPlanMutex.RUnlock()
 	fake.renderAssetsMutex.RLock()
 	defer fake.renderAssetsMutex.RUnlock()
+	fake.renderAuthStatusMutex.RLock()
+	defer fake.renderAuthStatusMutex.RUnlock()
+	fake.renderCatalogSearchMutex.RLock()
+	defer fake.renderCatalogSearchMutex.RUnlock()
 	fake.renderChartMutex.RLock()
 	defer fake.renderChartMutex.RUnlock()
 	fake.renderChartsMutex.RLock()
 	defer fake.renderChartsMutex.RUnlock()
+	fake.renderConfigMutex.RLock()
+	defer fake.renderConfigMutex.RUnlock()
 	fake.renderContainerImagesMutex.RLock()
 	defer fake.renderContainerImagesMutex.RUnlock()
+	fake.renderDiagnosticsMutex.RLock()
+	defer fake.renderDiagnosticsMutex.RUnlock()
 	fake.renderFileMutex.RLock()
 	defer fake.renderFileMutex.RUnlock()
 	fake.renderFilesMutex.RLock()
 	defer fake.renderFilesMutex.RUnlock()
 	fake.renderProductMutex.RLock()
 	defer fake.renderProductMutex.RUnlock()
+	fake.renderProductDiffMutex.RLock()
+	defer fake.renderProductDiffMutex.RUnlock()
+	fake.renderProductFacetsMutex.RLock()
+	defer fake.renderProductFacetsMutex.RUnlock()
 	fake.renderProductsMutex.RLock()
 	defer fake.renderProductsMutex.RUnlock()
+	fake.renderProfileMutex.RLock()
+	defer fake.renderProfileMutex.RUnlock()
+	fake.renderProfilesMutex.RLock()
+	defer fake.renderProfilesMutex.RUnlock()
 	fake.renderVersionsMutex.RLock()
 	defer fake.renderVersionsMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
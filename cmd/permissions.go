@@ -0,0 +1,37 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: BSD-2-Clause
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/vmware-labs/marketplace-cli/v2/internal/models"
+)
+
+var SkipPermissionCheck bool
+
+// CheckPermission fails early if the authenticated user cannot modify the product, so that nothing gets uploaded
+// before the Marketplace rejects the change. The Marketplace still makes the final decision.
+func CheckPermission(product *models.Product) error {
+	if SkipPermissionCheck || AuthenticatedClaims == nil || product.PublisherDetails == nil || product.PublisherDetails.OrgId == "" {
+		return nil
+	}
+
+	if AuthenticatedClaims.IsPlatformOperator() {
+		return nil
+	}
+
+	if AuthenticatedClaims.ContextName != product.PublisherDetails.OrgId {
+		return fmt.Errorf(
+			"you do not have permission to modify the product \"%s\": it belongs to the organization %s (%s), but you are authenticated to the organization %s.\n"+
+				"Use credentials for the organization %s, or pass --skip-permission-check to try anyway",
+			product.Slug,
+			product.PublisherDetails.OrgDisplayName,
+			product.PublisherDetails.OrgId,
+			AuthenticatedClaims.ContextName,
+			product.PublisherDetails.OrgDisplayName,
+		)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: BSD-2-Clause
+
+package cmd_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	. "github.com/vmware-labs/marketplace-cli/v2/cmd"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/csp"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/models"
+	"github.com/vmware-labs/marketplace-cli/v2/test"
+)
+
+var _ = Describe("CheckPermission", func() {
+	var product *models.Product
+
+	BeforeEach(func() {
+		product = test.CreateFakeProduct("", "My Super Product", "my-super-product", models.SolutionTypeChart)
+		product.PublisherDetails.OrgId = "my-org-id"
+		product.PublisherDetails.OrgDisplayName = "My Org"
+		SkipPermissionCheck = false
+	})
+
+	It("allows users in the publisher's organization", func() {
+		AuthenticatedClaims = &csp.Claims{ContextName: "my-org-id"}
+		Expect(CheckPermission(product)).To(Succeed())
+	})
+
+	It("allows platform operators", func() {
+		AuthenticatedClaims = &csp.Claims{
+			ContextName: "some-other-org-id",
+			Perms:       []string{csp.RolePlatformOperator},
+		}
+		Expect(CheckPermission(product)).To(Succeed())
+	})
+
+	It("rejects users in other organizations", func() {
+		AuthenticatedClaims = &csp.Claims{ContextName: "some-other-org-id"}
+		err := CheckPermission(product)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(Equal("you do not have permission to modify the product \"my-super-product\": it belongs to the organization My Org (my-org-id), but you are authenticated to the organization some-other-org-id.\n" +
+			"Use credentials for the organization My Org, or pass --skip-permission-check to try anyway"))
+	})
+
+	When("the check is skipped", func() {
+		It("allows anyone", func() {
+			SkipPermissionCheck = true
+			AuthenticatedClaims = &csp.Claims{ContextName: "some-other-org-id"}
+			Expect(CheckPermission(product)).To(Succeed())
+		})
+	})
+
+	When("the product does not have publisher details", func() {
+		It("does not block the change", func() {
+			AuthenticatedClaims = &csp.Claims{ContextName: "some-other-org-id"}
+			product.PublisherDetails = nil
+			Expect(CheckPermission(product)).To(Succeed())
+		})
+	})
+})
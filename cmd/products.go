@@ -5,19 +5,40 @@ package cmd
 
 import (
 	"fmt"
+	"os"
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/vmware-labs/marketplace-cli/v2/internal"
 	"github.com/vmware-labs/marketplace-cli/v2/internal/models"
 	"github.com/vmware-labs/marketplace-cli/v2/pkg"
 )
 
 var (
-	allOrgs        = false
-	searchTerm     string
-	ProductSlug    string
-	ProductVersion string
-	SetOSLFile     string
+	allOrgs           = false
+	searchTerm        string
+	ListSortBy        = internal.SortKeyDisplayName
+	ListSortDirection = internal.SortDirectionAscending
+	ListPageSize      = int32(pkg.DefaultListPageSize)
+	ListFilters       []string
+	ListMaxResults    = 0
+	ListShowFilters   = false
+	ProductSlug       string
+	ProductVersion    string
+	SetOSLFile        string
+
+	// The product set flags for lists are nil when not given, so that an empty value can clear the list
+	SetSummary         string
+	SetDescriptionFile string
+	SetHighlights      []string
+	SetTags            []string
+	SetCategories      []string
+	SetSupportURL      string
+	SetSupportEmail    string
+	SetSupportPhone    string
+	SetWebsite         string
+	SetLogoURL         string
+	SetVideoURLs       []string
 )
 
 func init() {
@@ -30,6 +51,12 @@ func init() {
 
 	ListProductsCmd.Flags().StringVar(&searchTerm, "search-text", "", "Filter product list by text")
 	ListProductsCmd.Flags().BoolVarP(&allOrgs, "all-orgs", "a", false, "Show published products from all organizations")
+	ListProductsCmd.Flags().StringVar(&ListSortBy, "sort-by", ListSortBy, "Sort products by this field (one of "+strings.Join(listSortKeys, ", ")+")")
+	ListProductsCmd.Flags().StringVar(&ListSortDirection, "sort-direction", ListSortDirection, "Sort direction (one of "+internal.SortDirectionAscending+", "+internal.SortDirectionDescending+")")
+	ListProductsCmd.Flags().Int32Var(&ListPageSize, "page-size", ListPageSize, "Number of products to fetch in each request")
+	ListProductsCmd.Flags().StringArrayVar(&ListFilters, "filter", []string{}, "Only list products matching this filter, like categories=Networking. Can be repeated")
+	ListProductsCmd.Flags().IntVar(&ListMaxResults, "max-results", 0, "Stop after listing this many products. No limit if 0")
+	ListProductsCmd.Flags().BoolVar(&ListShowFilters, "show-filters", false, "Show the filters that can be used with --filter, instead of the products")
 
 	GetProductCmd.Flags().StringVarP(&ProductSlug, "product", "p", "", "Product slug (required)")
 	_ = GetProductCmd.MarkFlagRequired("product")
@@ -45,9 +72,20 @@ func init() {
 
 	SetCmd.Flags().StringVarP(&ProductSlug, "product", "p", "", "Product slug (required)")
 	_ = SetCmd.MarkFlagRequired("product")
-	SetCmd.Flags().StringVarP(&ProductVersion, "product-version", "v", "", "Product version (required)")
-	_ = SetCmd.MarkFlagRequired("product-version")
+	SetCmd.Flags().StringVarP(&ProductVersion, "product-version", "v", "", "Product version (required for --osl-file)")
 	SetCmd.Flags().StringVar(&SetOSLFile, "osl-file", "", "File with OSL disclosures")
+	SetCmd.Flags().StringVar(&SetSummary, "summary", "", "Short summary of the product")
+	SetCmd.Flags().StringVar(&SetDescriptionFile, "description-file", "", "File with the description of the product, in HTML")
+	SetCmd.Flags().StringArrayVar(&SetHighlights, "highlight", nil, "Highlight of the product. Can be repeated, and replaces all highlights. Pass \"\" to remove them")
+	SetCmd.Flags().StringArrayVar(&SetTags, "tag", nil, "Tag for the product. Can be repeated, and replaces all tags. Pass \"\" to remove them")
+	SetCmd.Flags().StringArrayVar(&SetCategories, "category", nil, "Category of the product. Can be repeated, and replaces all categories. Pass \"\" to remove them")
+	SetCmd.Flags().StringVar(&SetSupportURL, "support-url", "", "URL for getting support")
+	SetCmd.Flags().StringVar(&SetSupportEmail, "support-email", "", "Email address for getting support")
+	SetCmd.Flags().StringVar(&SetSupportPhone, "support-phone", "", "Phone number for getting support")
+	SetCmd.Flags().StringVar(&SetWebsite, "website", "", "URL of the product's website")
+	SetCmd.Flags().StringVar(&SetLogoURL, "logo-url", "", "URL of the product's logo")
+	SetCmd.Flags().StringArrayVar(&SetVideoURLs, "video-url", nil, "URL of a video about the product. Can be repeated, and replaces all videos. Pass \"\" to remove them")
+	SetCmd.Flags().BoolVar(&SkipPermissionCheck, "skip-permission-check", false, "Do not check that you can modify the product before uploading files")
 }
 
 var ProductCmd = &cobra.Command{
@@ -59,16 +97,89 @@ var ProductCmd = &cobra.Command{
 	ValidArgs: []string{ListProductsCmd.Use, GetProductCmd.Use},
 }
 
+var listSortKeys = []string{internal.SortKeyDisplayName, internal.SortKeyCreationDate, internal.SortKeyUpdateDate}
+
+// ValidateListOptions checks the sorting, paging and filtering flags for the list of products
+func ValidateListOptions(cmd *cobra.Command, args []string) error {
+	validKey := false
+	for _, key := range listSortKeys {
+		if strings.EqualFold(ListSortBy, key) {
+			ListSortBy = key
+			validKey = true
+		}
+	}
+	if !validKey {
+		return fmt.Errorf("invalid sort field %q: must be one of %s", ListSortBy, strings.Join(listSortKeys, ", "))
+	}
+
+	ListSortDirection = strings.ToUpper(ListSortDirection)
+	if ListSortDirection != internal.SortDirectionAscending && ListSortDirection != internal.SortDirectionDescending {
+		return fmt.Errorf("invalid sort direction %q: must be %s or %s", ListSortDirection, internal.SortDirectionAscending, internal.SortDirectionDescending)
+	}
+
+	if ListPageSize < 1 {
+		return fmt.Errorf("invalid page size %d: must be at least 1", ListPageSize)
+	}
+	if ListMaxResults < 0 {
+		return fmt.Errorf("invalid maximum number of results %d: must not be negative", ListMaxResults)
+	}
+
+	_, err := parseListFilters(ListFilters)
+	return err
+}
+
+func parseListFilters(values []string) (internal.Filters, error) {
+	filters := internal.Filters{}
+	for _, value := range values {
+		parts := strings.SplitN(value, "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return nil, fmt.Errorf("invalid filter %q: must be in the form key=value", value)
+		}
+		filters.Add(strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1]))
+	}
+	return filters, nil
+}
+
+func listOptions() *pkg.ListProductsOptions {
+	filters, _ := parseListFilters(ListFilters)
+	return &pkg.ListProductsOptions{
+		AllOrgs:    allOrgs,
+		SearchTerm: searchTerm,
+		Sorting: &internal.Sorting{
+			Order:     1,
+			Key:       ListSortBy,
+			Direction: ListSortDirection,
+		},
+		PageSize:     ListPageSize,
+		Filters:      filters,
+		MaxResults:   ListMaxResults,
+		ShowProgress: true,
+	}
+}
+
 var ListProductsCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List products",
 	Long: "List and search for products in the VMware Marketplace\n" +
 		"Default without --all-orgs is to list all products (including unpublished) from your organization",
+	Example: fmt.Sprintf(`%[1]s product list --sort-by updatedOn --sort-direction DESC
+%[1]s product list --all-orgs --show-filters
+%[1]s product list --all-orgs --filter categories=Networking --filter categories=Security`, AppName),
 	Args:    cobra.NoArgs,
-	PreRunE: GetRefreshToken,
+	PreRunE: RunSerially(ValidateListOptions, GetRefreshToken),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		cmd.SilenceUsage = true
-		products, err := Marketplace.ListProducts(allOrgs, searchTerm)
+		options := listOptions()
+
+		if ListShowFilters {
+			facets, err := Marketplace.ListProductFilters(cmd.Context(), options)
+			if err != nil {
+				return err
+			}
+			return Output.RenderProductFacets(facets)
+		}
+
+		products, err := Marketplace.ListProducts(cmd.Context(), options)
 		if err != nil {
 			return err
 		}
@@ -82,6 +193,9 @@ var ListProductsCmd = &cobra.Command{
 		if searchTerm != "" {
 			header += fmt.Sprintf(" filtered by \"%s\"", searchTerm)
 		}
+		if len(options.Filters) > 0 {
+			header += fmt.Sprintf(" where %s", options.Filters)
+		}
 
 		Output.PrintHeader(header)
 		return Output.RenderProducts(products)
@@ -98,13 +212,13 @@ var GetProductCmd = &cobra.Command{
 		cmd.SilenceUsage = true
 
 		if ProductVersion == "" {
-			product, err := Marketplace.GetProduct(ProductSlug)
+			product, err := Marketplace.GetProduct(cmd.Context(), ProductSlug)
 			if err != nil {
 				return err
 			}
 			return Output.RenderProduct(product, product.GetLatestVersion())
 		} else {
-			product, version, err := Marketplace.GetProductWithVersion(ProductSlug, ProductVersion)
+			product, version, err := Marketplace.GetProductWithVersion(cmd.Context(), ProductSlug, ProductVersion)
 			if err != nil {
 				return err
 			}
@@ -121,7 +235,7 @@ var ListAssetsCmd = &cobra.Command{
 	PreRunE: RunSerially(ValidateAssetTypeFilter, GetRefreshToken),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		cmd.SilenceUsage = true
-		product, version, err := Marketplace.GetProductWithVersion(ProductSlug, ProductVersion)
+		product, version, err := Marketplace.GetProductWithVersion(cmd.Context(), ProductSlug, ProductVersion)
 		if err != nil {
 			return err
 		}
@@ -148,7 +262,7 @@ var ListProductVersionsCmd = &cobra.Command{
 	PreRunE: GetRefreshToken,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		cmd.SilenceUsage = true
-		product, err := Marketplace.GetProduct(ProductSlug)
+		product, err := Marketplace.GetProduct(cmd.Context(), ProductSlug)
 		if err != nil {
 			return err
 		}
@@ -159,44 +273,151 @@ var ListProductVersionsCmd = &cobra.Command{
 	},
 }
 
+// nonEmpty drops empty values, so that passing "" for a list flag clears the list
+func nonEmpty(values []string) []string {
+	result := []string{}
+	for _, value := range values {
+		if value != "" {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+// hasProductEdits is true if any flag that changes the product itself was given
+func hasProductEdits() bool {
+	return SetSummary != "" || SetDescriptionFile != "" ||
+		SetHighlights != nil || SetTags != nil || SetCategories != nil ||
+		SetSupportURL != "" || SetSupportEmail != "" || SetSupportPhone != "" ||
+		SetWebsite != "" || SetLogoURL != "" || SetVideoURLs != nil
+}
+
+// editProduct changes the product's details from the product set flags
+func editProduct(product *models.Product) error {
+	if product.Description == nil {
+		product.Description = &models.Description{}
+	}
+	if SetSummary != "" {
+		product.Description.Summary = SetSummary
+	}
+	if SetDescriptionFile != "" {
+		description, err := os.ReadFile(SetDescriptionFile)
+		if err != nil {
+			return fmt.Errorf("failed to read the description file: %w", err)
+		}
+		product.Description.Description = string(description)
+	}
+	if SetVideoURLs != nil {
+		product.Description.VideoUrls = nonEmpty(SetVideoURLs)
+	}
+
+	if SetHighlights != nil {
+		product.Highlights = nonEmpty(SetHighlights)
+	}
+	if SetTags != nil {
+		product.Tags = nonEmpty(SetTags)
+	}
+	if SetCategories != nil {
+		product.Categories = nonEmpty(SetCategories)
+	}
+
+	if SetSupportURL != "" || SetSupportEmail != "" || SetSupportPhone != "" {
+		if product.SupportDetails == nil {
+			product.SupportDetails = &models.SupportDetails{}
+		}
+		product.SupportAvailable = true
+	}
+	if SetSupportURL != "" {
+		product.SupportDetails.Url = SetSupportURL
+	}
+	if SetSupportEmail != "" {
+		product.SupportDetails.Email = []string{SetSupportEmail}
+	}
+	if SetSupportPhone != "" {
+		product.SupportDetails.PhoneNumber = []string{SetSupportPhone}
+	}
+
+	if SetWebsite != "" {
+		if product.MetaDetails == nil {
+			product.MetaDetails = &models.AppProductMetaDetails{}
+		}
+		product.MetaDetails.WebsiteURL = SetWebsite
+	}
+	if SetLogoURL != "" {
+		product.Logo = SetLogoURL
+		product.ProductLogo = &models.Logo{URL: SetLogoURL}
+	}
+	return nil
+}
+
 var SetCmd = &cobra.Command{
-	Use:     "set",
-	Short:   "Modify product details",
-	Long:    "Modify fields in a given product",
+	Use:   "set",
+	Short: "Modify product details",
+	Long:  "Modify fields in a given product",
+	Example: fmt.Sprintf(`%[1]s product set -p my-product --summary "A better summary" --description-file description.html
+%[1]s product set -p my-product --tag networking --tag security --category Networking
+%[1]s product set -p my-product --support-email support@example.com --website https://example.com
+%[1]s product set -p my-product -v 1.2.3 --osl-file osl.txt`, AppName),
 	Args:    cobra.NoArgs,
-	PreRunE: GetRefreshToken,
+	PreRunE: RunSerially(GetRefreshToken, ReadFreshProducts),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		if SetOSLFile == "" {
+		if SetOSLFile == "" && !hasProductEdits() {
 			return fmt.Errorf("nothing specified to set")
 		}
+		if SetOSLFile != "" && ProductVersion == "" {
+			return fmt.Errorf("--product-version is required with --osl-file")
+		}
 		cmd.SilenceUsage = true
 
-		product, _, err := Marketplace.GetProductWithVersion(ProductSlug, ProductVersion)
+		var (
+			product *models.Product
+			version *models.Version
+			err     error
+		)
+		if ProductVersion == "" {
+			product, err = Marketplace.GetProduct(cmd.Context(), ProductSlug)
+		} else {
+			product, version, err = Marketplace.GetProductWithVersion(cmd.Context(), ProductSlug, ProductVersion)
+		}
+		if err != nil {
+			return err
+		}
+
+		err = CheckPermission(product)
 		if err != nil {
 			return err
 		}
 		product.PrepForUpdate()
 
+		err = editProduct(product)
+		if err != nil {
+			return err
+		}
+
 		if SetOSLFile != "" {
-			uploader, err := Marketplace.GetUploader(product.PublisherDetails.OrgId)
+			uploader, err := Marketplace.GetUploader(cmd.Context(), product.PublisherDetails.OrgId)
 			if err != nil {
 				return err
 			}
-			_, oslUrl, err := uploader.UploadMediaFile(SetOSLFile)
+			_, oslUrl, err := uploader.UploadMediaFile(cmd.Context(), SetOSLFile)
 			if err != nil {
 				return err
 			}
 
+			if product.OpenSourceDisclosure == nil {
+				product.OpenSourceDisclosure = &models.OpenSourceDisclosureURLS{}
+			}
 			product.OpenSourceDisclosure.LicenseDisclosureURL = oslUrl
 		}
 
-		_, err = Marketplace.PutProduct(product, false)
+		updatedProduct, err := Marketplace.PutProduct(cmd.Context(), product, false)
 		if err != nil {
 			return err
 		}
 
-		// TODO: what do we render?
-
-		return nil
+		if version == nil {
+			version = updatedProduct.GetLatestVersion()
+		}
+		return Output.RenderProduct(updatedProduct, version)
 	},
 }
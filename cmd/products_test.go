@@ -4,13 +4,18 @@
 package cmd_test
 
 import (
+	"context"
 	"fmt"
+	"os"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	"github.com/vmware-labs/marketplace-cli/v2/cmd"
 	"github.com/vmware-labs/marketplace-cli/v2/cmd/output/outputfakes"
+	"github.com/vmware-labs/marketplace-cli/v2/internal"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/internalfakes"
 	"github.com/vmware-labs/marketplace-cli/v2/internal/models"
+	"github.com/vmware-labs/marketplace-cli/v2/pkg"
 	"github.com/vmware-labs/marketplace-cli/v2/pkg/pkgfakes"
 	"github.com/vmware-labs/marketplace-cli/v2/test"
 )
@@ -64,6 +69,102 @@ var _ = Describe("Products", func() {
 			})
 		})
 
+		It("sends the sorting, page size and filters", func() {
+			cmd.ListSortBy = "updatedon"
+			cmd.ListSortDirection = "desc"
+			cmd.ListPageSize = 50
+			cmd.ListFilters = []string{"categories=Networking", "categories=Security", "publishers = VMware"}
+			cmd.ListMaxResults = 100
+			defer func() {
+				cmd.ListSortBy = internal.SortKeyDisplayName
+				cmd.ListSortDirection = internal.SortDirectionAscending
+				cmd.ListPageSize = pkg.DefaultListPageSize
+				cmd.ListFilters = []string{}
+				cmd.ListMaxResults = 0
+			}()
+
+			Expect(cmd.ValidateListOptions(cmd.ListProductsCmd, []string{})).To(Succeed())
+			err := cmd.ListProductsCmd.RunE(cmd.ListProductsCmd, []string{})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(marketplace.ListProductsCallCount()).To(Equal(1))
+			_, options := marketplace.ListProductsArgsForCall(0)
+			Expect(options.Sorting.Key).To(Equal(internal.SortKeyUpdateDate))
+			Expect(options.Sorting.Direction).To(Equal(internal.SortDirectionDescending))
+			Expect(options.PageSize).To(Equal(int32(50)))
+			Expect(options.MaxResults).To(Equal(100))
+			Expect(options.Filters).To(Equal(internal.Filters{
+				"categories": []string{"Networking", "Security"},
+				"publishers": []string{"VMware"},
+			}))
+			Expect(output.PrintHeaderArgsForCall(0)).To(ContainSubstring("where categories=Networking,Security publishers=VMware"))
+		})
+
+		Context("invalid options", func() {
+			AfterEach(func() {
+				cmd.ListSortBy = internal.SortKeyDisplayName
+				cmd.ListSortDirection = internal.SortDirectionAscending
+				cmd.ListPageSize = pkg.DefaultListPageSize
+				cmd.ListFilters = []string{}
+				cmd.ListMaxResults = 0
+			})
+
+			It("rejects an unknown sort field", func() {
+				cmd.ListSortBy = "price"
+				err := cmd.ValidateListOptions(cmd.ListProductsCmd, []string{})
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("invalid sort field \"price\": must be one of displayName, createdOn, updatedOn"))
+			})
+
+			It("rejects an unknown sort direction", func() {
+				cmd.ListSortDirection = "up"
+				err := cmd.ValidateListOptions(cmd.ListProductsCmd, []string{})
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("invalid sort direction \"UP\": must be ASC or DESC"))
+			})
+
+			It("rejects an empty page", func() {
+				cmd.ListPageSize = 0
+				err := cmd.ValidateListOptions(cmd.ListProductsCmd, []string{})
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("invalid page size 0: must be at least 1"))
+			})
+
+			It("rejects a negative maximum", func() {
+				cmd.ListMaxResults = -1
+				err := cmd.ValidateListOptions(cmd.ListProductsCmd, []string{})
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("invalid maximum number of results -1: must not be negative"))
+			})
+
+			It("rejects a filter without a value", func() {
+				cmd.ListFilters = []string{"categories"}
+				err := cmd.ValidateListOptions(cmd.ListProductsCmd, []string{})
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("invalid filter \"categories\": must be in the form key=value"))
+			})
+		})
+
+		Context("showing the filters", func() {
+			BeforeEach(func() {
+				cmd.ListShowFilters = true
+				marketplace.ListProductFiltersReturns([]*pkg.ProductFacet{{Key: "categories"}}, nil)
+			})
+			AfterEach(func() {
+				cmd.ListShowFilters = false
+			})
+
+			It("outputs the filters instead of the products", func() {
+				err := cmd.ListProductsCmd.RunE(cmd.ListProductsCmd, []string{})
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(marketplace.ListProductsCallCount()).To(Equal(0))
+				Expect(marketplace.ListProductFiltersCallCount()).To(Equal(1))
+				Expect(output.RenderProductFacetsCallCount()).To(Equal(1))
+				Expect(output.RenderProductFacetsArgsForCall(0)[0].Key).To(Equal("categories"))
+			})
+		})
+
 		Context("Error getting the product list", func() {
 			BeforeEach(func() {
 				marketplace.ListProductsReturns([]*models.Product{}, fmt.Errorf("gettings products failed"))
@@ -86,7 +187,7 @@ var _ = Describe("Products", func() {
 				models.SolutionTypeOthers)
 			test.AddVerions(product, "1.2.3", "2.3.4")
 			marketplace.GetProductReturns(product, nil)
-			marketplace.GetProductWithVersionStub = func(slug string, version string) (*models.Product, *models.Version, error) {
+			marketplace.GetProductWithVersionStub = func(ctx context.Context, slug string, version string) (*models.Product, *models.Version, error) {
 				Expect(slug).To(Equal("my-super-product"))
 				Expect(version).To(Equal("1.2.3"))
 				return product, &models.Version{Number: "1.2.3"}, nil
@@ -172,7 +273,7 @@ var _ = Describe("Products", func() {
 
 			By("getting the product from the Marketplace", func() {
 				Expect(marketplace.GetProductWithVersionCallCount()).To(Equal(1))
-				slug, version := marketplace.GetProductWithVersionArgsForCall(0)
+				_, slug, version := marketplace.GetProductWithVersionArgsForCall(0)
 				Expect(slug).To(Equal("my-super-product"))
 				Expect(version).To(Equal("1"))
 			})
@@ -219,7 +320,8 @@ var _ = Describe("Products", func() {
 
 			By("getting the product from the Marketplace", func() {
 				Expect(marketplace.GetProductCallCount()).To(Equal(1))
-				Expect(marketplace.GetProductArgsForCall(0)).To(Equal("my-super-product"))
+				_, slug := marketplace.GetProductArgsForCall(0)
+				Expect(slug).To(Equal("my-super-product"))
 			})
 
 			By("outputting the response", func() {
@@ -242,4 +344,144 @@ var _ = Describe("Products", func() {
 			})
 		})
 	})
+
+	Describe("SetCmd", func() {
+		var product *models.Product
+
+		BeforeEach(func() {
+			product = test.CreateFakeProduct("", "My Super Product", "my-super-product", models.SolutionTypeOthers)
+			product.Description = &models.Description{Summary: "The old summary"}
+			product.Tags = []string{"old-tag"}
+			test.AddVerions(product, "1.2.3")
+			marketplace.GetProductReturns(product, nil)
+			marketplace.GetProductWithVersionReturns(product, product.GetVersion("1.2.3"), nil)
+			marketplace.PutProductStub = func(ctx context.Context, product *models.Product, versionUpdate bool) (*models.Product, error) {
+				return product, nil
+			}
+			cmd.ProductSlug = "my-super-product"
+			cmd.ProductVersion = ""
+			cmd.AuthenticatedClaims = nil
+		})
+
+		AfterEach(func() {
+			cmd.ProductVersion = ""
+			cmd.SetOSLFile = ""
+			cmd.SetSummary = ""
+			cmd.SetDescriptionFile = ""
+			cmd.SetTags = nil
+			cmd.SetCategories = nil
+			cmd.SetSupportEmail = ""
+			cmd.SetWebsite = ""
+			cmd.SetLogoURL = ""
+		})
+
+		It("updates the product's details", func() {
+			descriptionFile, err := os.CreateTemp("", "description-*.html")
+			Expect(err).ToNot(HaveOccurred())
+			defer os.Remove(descriptionFile.Name())
+			_, err = descriptionFile.WriteString("<p>The new description</p>")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(descriptionFile.Close()).To(Succeed())
+
+			cmd.SetSummary = "The new summary"
+			cmd.SetDescriptionFile = descriptionFile.Name()
+			cmd.SetCategories = []string{"Networking", "Security"}
+			cmd.SetSupportEmail = "support@example.com"
+			cmd.SetWebsite = "https://example.com"
+			cmd.SetLogoURL = "https://example.com/logo.png"
+			err = cmd.SetCmd.RunE(cmd.SetCmd, []string{})
+			Expect(err).ToNot(HaveOccurred())
+
+			By("getting the product without a version", func() {
+				Expect(marketplace.GetProductCallCount()).To(Equal(1))
+				Expect(marketplace.GetProductWithVersionCallCount()).To(Equal(0))
+			})
+
+			By("sending the updated product", func() {
+				Expect(marketplace.PutProductCallCount()).To(Equal(1))
+				_, updated, versionUpdate := marketplace.PutProductArgsForCall(0)
+				Expect(versionUpdate).To(BeFalse())
+				Expect(updated.Description.Summary).To(Equal("The new summary"))
+				Expect(updated.Description.Description).To(Equal("<p>The new description</p>"))
+				Expect(updated.Categories).To(Equal([]string{"Networking", "Security"}))
+				Expect(updated.Tags).To(Equal([]string{"old-tag"}))
+				Expect(updated.SupportAvailable).To(BeTrue())
+				Expect(updated.SupportDetails.Email).To(Equal([]string{"support@example.com"}))
+				Expect(updated.MetaDetails.WebsiteURL).To(Equal("https://example.com"))
+				Expect(updated.ProductLogo.URL).To(Equal("https://example.com/logo.png"))
+				Expect(updated.Encryption).ToNot(BeNil(), "the product was prepared for the update")
+			})
+
+			By("outputting the updated product", func() {
+				Expect(output.RenderProductCallCount()).To(Equal(1))
+				rendered, version := output.RenderProductArgsForCall(0)
+				Expect(rendered.Description.Summary).To(Equal("The new summary"))
+				Expect(version.Number).To(Equal("1.2.3"))
+			})
+		})
+
+		It("clears a list when given an empty value", func() {
+			cmd.SetTags = []string{""}
+			err := cmd.SetCmd.RunE(cmd.SetCmd, []string{})
+			Expect(err).ToNot(HaveOccurred())
+
+			_, updated, _ := marketplace.PutProductArgsForCall(0)
+			Expect(updated.Tags).To(BeEmpty())
+		})
+
+		When("setting the OSL file", func() {
+			var uploader *internalfakes.FakeUploader
+
+			BeforeEach(func() {
+				uploader = &internalfakes.FakeUploader{}
+				uploader.UploadMediaFileReturns("", "https://example.com/path/to/osl.txt", nil)
+				marketplace.GetUploaderReturns(uploader, nil)
+			})
+
+			It("uploads it and adds it to the version", func() {
+				cmd.ProductVersion = "1.2.3"
+				cmd.SetOSLFile = "/path/to/osl.txt"
+				err := cmd.SetCmd.RunE(cmd.SetCmd, []string{})
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(marketplace.GetProductWithVersionCallCount()).To(Equal(1))
+				_, updated, _ := marketplace.PutProductArgsForCall(0)
+				Expect(updated.OpenSourceDisclosure.LicenseDisclosureURL).To(Equal("https://example.com/path/to/osl.txt"))
+			})
+
+			It("requires a version", func() {
+				cmd.SetOSLFile = "/path/to/osl.txt"
+				err := cmd.SetCmd.RunE(cmd.SetCmd, []string{})
+				Expect(err).To(MatchError("--product-version is required with --osl-file"))
+			})
+		})
+
+		When("nothing is set", func() {
+			It("returns an error", func() {
+				err := cmd.SetCmd.RunE(cmd.SetCmd, []string{})
+				Expect(err).To(MatchError("nothing specified to set"))
+				Expect(marketplace.PutProductCallCount()).To(Equal(0))
+			})
+		})
+
+		When("the description file cannot be read", func() {
+			It("returns an error", func() {
+				cmd.SetDescriptionFile = "/this/file/does/not/exist.html"
+				err := cmd.SetCmd.RunE(cmd.SetCmd, []string{})
+				Expect(err).To(MatchError(ContainSubstring("failed to read the description file")))
+				Expect(marketplace.PutProductCallCount()).To(Equal(0))
+			})
+		})
+
+		When("updating the product fails", func() {
+			It("returns an error", func() {
+				marketplace.PutProductStub = nil
+				marketplace.PutProductReturns(nil, fmt.Errorf("put product failed"))
+				cmd.SetSummary = "The new summary"
+				err := cmd.SetCmd.RunE(cmd.SetCmd, []string{})
+				Expect(err).To(MatchError("put product failed"))
+				Expect(output.RenderProductCallCount()).To(Equal(0))
+			})
+		})
+	})
 })
@@ -0,0 +1,172 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: BSD-2-Clause
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/config"
+)
+
+//go:generate counterfeiter . ProfileStore
+type ProfileStore interface {
+	Load() (*config.Profiles, error)
+	Save(profiles *config.Profiles) error
+}
+
+var (
+	StoredProfiles ProfileStore
+
+	// ActiveProfile is the profile used for this command, set by ApplyProfile
+	ActiveProfile *config.Profile
+)
+
+func defaultProfilesPath() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(configDir, AppName, "profiles.yaml")
+}
+
+func defaultProfileName() string {
+	if os.Getenv("MARKETPLACE_ENV") == "staging" {
+		return config.ProfileStaging
+	}
+	return config.ProfileProduction
+}
+
+func setDefaultIfNotEmpty(key, value string) {
+	if value != "" {
+		viper.SetDefault(key, value)
+		configProfile[key] = true
+	}
+}
+
+// ApplyProfile uses the hosts and credential references of the selected profile as defaults.
+// Flags and environment variables still take precedence over the profile.
+func ApplyProfile() error {
+	profiles, err := StoredProfiles.Load()
+	if err != nil {
+		return err
+	}
+
+	name := viper.GetString("profile")
+	if name == "" {
+		name = profiles.Current
+	}
+	if name == "" {
+		// The profiles file can override the settings of the default profile, too
+		name = defaultProfileName()
+	}
+
+	profile, ok := profiles.Get(name)
+	if !ok {
+		return fmt.Errorf("unknown profile: %s", name)
+	}
+	ActiveProfile = profile
+
+	setDefaultIfNotEmpty("marketplace.host", profile.MarketplaceHost)
+	setDefaultIfNotEmpty("marketplace.api-host", profile.APIHost)
+	setDefaultIfNotEmpty("marketplace.ui-host", profile.UIHost)
+	setDefaultIfNotEmpty("marketplace.storage.bucket", profile.StorageBucket)
+	setDefaultIfNotEmpty("marketplace.storage.region", profile.StorageRegion)
+	setDefaultIfNotEmpty("csp.host", profile.CSPHost)
+	setDefaultIfNotEmpty("csp.credential-helper", profile.CredentialHelper)
+
+	if profile.APITokenEnv != "" {
+		bindEnv("csp.api-token", profile.APITokenEnv)
+	}
+	if profile.ClientIDEnv != "" {
+		bindEnv("csp.client-id", profile.ClientIDEnv)
+	}
+	if profile.ClientSecretEnv != "" {
+		bindEnv("csp.client-secret", profile.ClientSecretEnv)
+	}
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(ProfileCmd)
+	ProfileCmd.AddCommand(ListProfilesCmd)
+	ProfileCmd.AddCommand(ShowProfileCmd)
+	ProfileCmd.AddCommand(UseProfileCmd)
+}
+
+var ProfileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "Manage environment profiles",
+	Long: "Profiles bundle the Marketplace hosts, CSP host and credential references for an environment.\n" +
+		"Select one with --profile, or make it the default with \"profile use\".",
+}
+
+var ListProfilesCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List profiles",
+	Long:    "Lists the profiles from the profiles file, along with the built-in production and staging profiles",
+	Example: fmt.Sprintf("%s profile list", AppName),
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		profiles, err := StoredProfiles.Load()
+		if err != nil {
+			return err
+		}
+		return Output.RenderProfiles(profiles.All(), ActiveProfile.Name)
+	},
+}
+
+var ShowProfileCmd = &cobra.Command{
+	Use:     "show [profile name]",
+	Short:   "Show a profile",
+	Long:    "Shows the settings of a profile, or of the active profile if no name is given",
+	Example: fmt.Sprintf("%s profile show staging", AppName),
+	Args:    cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		if len(args) == 0 {
+			return Output.RenderProfile(ActiveProfile)
+		}
+
+		profiles, err := StoredProfiles.Load()
+		if err != nil {
+			return err
+		}
+		profile, ok := profiles.Get(args[0])
+		if !ok {
+			return fmt.Errorf("unknown profile: %s", args[0])
+		}
+		return Output.RenderProfile(profile)
+	},
+}
+
+var UseProfileCmd = &cobra.Command{
+	Use:     "use <profile name>",
+	Short:   "Set the default profile",
+	Long:    "Sets the profile used by commands that are not given --profile",
+	Example: fmt.Sprintf("%s profile use staging", AppName),
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		profiles, err := StoredProfiles.Load()
+		if err != nil {
+			return err
+		}
+		if _, ok := profiles.Get(args[0]); !ok {
+			return fmt.Errorf("unknown profile: %s", args[0])
+		}
+
+		profiles.Current = args[0]
+		err = StoredProfiles.Save(profiles)
+		if err != nil {
+			return err
+		}
+		cmd.Printf("Now using profile %s\n", args[0])
+		return nil
+	},
+}
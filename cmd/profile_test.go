@@ -0,0 +1,206 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: BSD-2-Clause
+
+package cmd_test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/spf13/viper"
+
+	. "github.com/vmware-labs/marketplace-cli/v2/cmd"
+	"github.com/vmware-labs/marketplace-cli/v2/cmd/cmdfakes"
+	"github.com/vmware-labs/marketplace-cli/v2/cmd/output/outputfakes"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/config"
+)
+
+var _ = Describe("Profiles", func() {
+	var profileStore *cmdfakes.FakeProfileStore
+
+	BeforeEach(func() {
+		profileStore = &cmdfakes.FakeProfileStore{}
+		profileStore.LoadReturns(&config.Profiles{
+			Profiles: map[string]*config.Profile{
+				"partner": {
+					MarketplaceHost: "gtw.partner.example.com",
+					CSPHost:         "console.partner.example.com",
+					APITokenEnv:     "PARTNER_CSP_API_TOKEN",
+				},
+			},
+		}, nil)
+		StoredProfiles = profileStore
+		viper.Set("profile", "")
+	})
+
+	Describe("ApplyProfile", func() {
+		var production *config.Profile
+
+		BeforeEach(func() {
+			production = config.BuiltinProfiles[config.ProfileProduction]
+
+			// Clear values set by other tests, so the profile defaults are visible
+			viper.Set("marketplace.host", nil)
+			viper.Set("csp.host", nil)
+			viper.Set("csp.api-token", nil)
+		})
+
+		AfterEach(func() {
+			viper.SetDefault("marketplace.host", production.MarketplaceHost)
+			viper.SetDefault("marketplace.api-host", production.APIHost)
+			viper.SetDefault("marketplace.ui-host", production.UIHost)
+			viper.SetDefault("marketplace.storage.bucket", production.StorageBucket)
+			viper.SetDefault("marketplace.storage.region", production.StorageRegion)
+			viper.SetDefault("csp.host", production.CSPHost)
+			_ = viper.BindEnv("csp.api-token", "CSP_API_TOKEN")
+			Expect(os.Unsetenv("PARTNER_CSP_API_TOKEN")).To(Succeed())
+		})
+
+		It("uses the built-in default profile when none is selected", func() {
+			Expect(ApplyProfile()).To(Succeed())
+			Expect(ActiveProfile.Name).To(Equal("production"))
+		})
+
+		When("the profiles file overrides the default profile", func() {
+			BeforeEach(func() {
+				profiles, _ := profileStore.Load()
+				profiles.Profiles["production"] = &config.Profile{
+					MarketplaceHost: "gtw.proxy.example.com",
+					CSPHost:         "console.proxy.example.com",
+				}
+			})
+
+			It("uses the settings from the profiles file", func() {
+				Expect(ApplyProfile()).To(Succeed())
+				Expect(ActiveProfile.Name).To(Equal("production"))
+				Expect(ActiveProfile.MarketplaceHost).To(Equal("gtw.proxy.example.com"))
+				Expect(viper.GetString("marketplace.host")).To(Equal("gtw.proxy.example.com"))
+				Expect(viper.GetString("csp.host")).To(Equal("console.proxy.example.com"))
+			})
+		})
+
+		When("the profiles file overrides some settings of a built-in profile", func() {
+			BeforeEach(func() {
+				profiles, _ := profileStore.Load()
+				profiles.Profiles["staging"] = &config.Profile{
+					MarketplaceHost: "gtw.staging-proxy.example.com",
+				}
+				viper.Set("profile", "staging")
+			})
+
+			It("keeps the other settings of the built-in profile", func() {
+				staging := config.BuiltinProfiles[config.ProfileStaging]
+				Expect(ApplyProfile()).To(Succeed())
+				Expect(ActiveProfile.Name).To(Equal("staging"))
+				Expect(viper.GetString("marketplace.host")).To(Equal("gtw.staging-proxy.example.com"))
+				Expect(viper.GetString("marketplace.api-host")).To(Equal(staging.APIHost))
+				Expect(viper.GetString("marketplace.ui-host")).To(Equal(staging.UIHost))
+				Expect(viper.GetString("marketplace.storage.bucket")).To(Equal(staging.StorageBucket))
+				Expect(viper.GetString("marketplace.storage.region")).To(Equal(staging.StorageRegion))
+			})
+		})
+
+		It("uses the profile selected with --profile", func() {
+			viper.Set("profile", "partner")
+			Expect(os.Setenv("PARTNER_CSP_API_TOKEN", "my-partner-api-token")).To(Succeed())
+
+			Expect(ApplyProfile()).To(Succeed())
+			Expect(ActiveProfile.Name).To(Equal("partner"))
+			Expect(viper.GetString("marketplace.host")).To(Equal("gtw.partner.example.com"))
+			Expect(viper.GetString("csp.host")).To(Equal("console.partner.example.com"))
+			Expect(viper.GetString("csp.api-token")).To(Equal("my-partner-api-token"))
+		})
+
+		It("uses the current profile from the profiles file", func() {
+			profiles, _ := profileStore.Load()
+			profiles.Current = "staging"
+
+			Expect(ApplyProfile()).To(Succeed())
+			Expect(ActiveProfile.Name).To(Equal("staging"))
+			Expect(viper.GetString("marketplace.host")).To(Equal("gtwstg.market.csp.vmware.com"))
+		})
+
+		When("the profile does not exist", func() {
+			It("returns an error", func() {
+				viper.Set("profile", "unknown")
+				err := ApplyProfile()
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("unknown profile: unknown"))
+			})
+		})
+
+		When("the profiles file cannot be loaded", func() {
+			It("returns an error", func() {
+				profileStore.LoadReturns(nil, fmt.Errorf("load failed"))
+				err := ApplyProfile()
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("load failed"))
+			})
+		})
+	})
+
+	Describe("ListProfilesCmd", func() {
+		It("renders all profiles", func() {
+			output := &outputfakes.FakeFormat{}
+			Output = output
+			ActiveProfile = &config.Profile{Name: "partner"}
+
+			err := ListProfilesCmd.RunE(ListProfilesCmd, []string{})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(output.RenderProfilesCallCount()).To(Equal(1))
+			profiles, active := output.RenderProfilesArgsForCall(0)
+			Expect(profiles).To(HaveLen(3))
+			Expect(profiles[0].Name).To(Equal("partner"))
+			Expect(active).To(Equal("partner"))
+		})
+	})
+
+	Describe("ShowProfileCmd", func() {
+		var output *outputfakes.FakeFormat
+
+		BeforeEach(func() {
+			output = &outputfakes.FakeFormat{}
+			Output = output
+			ActiveProfile = config.BuiltinProfiles[config.ProfileProduction]
+		})
+
+		It("renders the active profile", func() {
+			err := ShowProfileCmd.RunE(ShowProfileCmd, []string{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(output.RenderProfileArgsForCall(0).Name).To(Equal("production"))
+		})
+
+		It("renders the named profile", func() {
+			err := ShowProfileCmd.RunE(ShowProfileCmd, []string{"partner"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(output.RenderProfileArgsForCall(0).MarketplaceHost).To(Equal("gtw.partner.example.com"))
+		})
+	})
+
+	Describe("UseProfileCmd", func() {
+		BeforeEach(func() {
+			UseProfileCmd.SetOut(&bytes.Buffer{})
+		})
+
+		It("saves the current profile", func() {
+			err := UseProfileCmd.RunE(UseProfileCmd, []string{"partner"})
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(profileStore.SaveCallCount()).To(Equal(1))
+			Expect(profileStore.SaveArgsForCall(0).Current).To(Equal("partner"))
+		})
+
+		When("the profile does not exist", func() {
+			It("returns an error", func() {
+				err := UseProfileCmd.RunE(UseProfileCmd, []string{"unknown"})
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("unknown profile: unknown"))
+				Expect(profileStore.SaveCallCount()).To(Equal(0))
+			})
+		})
+	})
+})
@@ -4,13 +4,19 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
 	"strings"
+	"syscall"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"github.com/vmware-labs/marketplace-cli/v2/cmd/output"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/catalog"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/config"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/csp"
 	"github.com/vmware-labs/marketplace-cli/v2/pkg"
 )
 
@@ -47,12 +53,79 @@ var rootCmd = &cobra.Command{
 enabling users to view, get, and manage their Marketplace products.`, AppName),
 	PersistentPreRunE: RunSerially(
 		func(cmd *cobra.Command, args []string) error {
-			Client = pkg.NewClient(
+			err := ReadConfigFile()
+			if err != nil {
+				return err
+			}
+
+			StoredProfiles = config.NewProfileStore(viper.GetString("profiles-path"))
+			err = ApplyProfile()
+			if err != nil {
+				return err
+			}
+
+			if timeout := viper.GetDuration("timeout"); timeout > 0 {
+				ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+				cmd.SetContext(ctx)
+				cancelTimeout = cancel
+			}
+
+			httpClient, err := pkg.NewHTTPClient(&pkg.TransportConfig{
+				ResponseTimeout:    viper.GetDuration("http.request-timeout"),
+				ProxyURL:           viper.GetString("http.proxy"),
+				CABundle:           viper.GetString("http.ca-bundle"),
+				ClientCert:         viper.GetString("http.client-cert"),
+				ClientKey:          viper.GetString("http.client-key"),
+				InsecureSkipVerify: viper.GetBool("http.insecure-skip-verify"),
+			})
+			if err != nil {
+				return err
+			}
+			if viper.GetBool("http.insecure-skip-verify") {
+				cmd.PrintErrln("Warning: not verifying server certificates. Only use --insecure-skip-verify for debugging.")
+			}
+
+			client := pkg.NewClient(
 				os.Stderr,
 				viper.GetBool("debugging.enabled"),
 				viper.GetBool("debugging.print-request-payloads"),
 				viper.GetBool("debugging.print-response-payloads"),
 			)
+			performRequest, err := StartCassette(httpClient.Do)
+			if err != nil {
+				return err
+			}
+			client.PerformRequest = performRequest
+			switch viper.GetString("debugging.format") {
+			case pkg.LogFormatText, pkg.LogFormatJSON:
+				client.LogFormat = viper.GetString("debugging.format")
+			default:
+				return fmt.Errorf("debug format not supported: %s", viper.GetString("debugging.format"))
+			}
+			if viper.GetString("debugging.har-file") != "" {
+				harRecorder = pkg.NewHARRecorder(AppName, version)
+				client.HAR = harRecorder
+			}
+			client.Retry = &pkg.RetryPolicy{
+				MaxAttempts:    viper.GetInt("http.retry.max-attempts"),
+				InitialBackoff: viper.GetDuration("http.retry.initial-backoff"),
+				MaxBackoff:     viper.GetDuration("http.retry.max-backoff"),
+				MaxRetryAfter:  viper.GetDuration("http.retry.max-retry-after"),
+				RetryWrites:    viper.GetBool("http.retry.writes"),
+			}
+			client.SetAccessToken(viper.GetString("csp.refresh-token"))
+			client.Reauthenticate = Reauthenticate
+			client.ReauthenticateHosts = []string{
+				viper.GetString("marketplace.host"),
+				viper.GetString("marketplace.api-host"),
+			}
+			Client = client
+
+			ResponseCache = pkg.NewResponseCache(viper.GetString("cache.path"), viper.GetDuration("cache.ttl"), cacheIdentity)
+			var marketplaceCache *pkg.ResponseCache
+			if UseResponseCache() {
+				marketplaceCache = ResponseCache
+			}
 
 			Marketplace = &pkg.Marketplace{
 				Host:          viper.GetString("marketplace.host"),
@@ -61,12 +134,18 @@ enabling users to view, get, and manage their Marketplace products.`, AppName),
 				StorageBucket: viper.GetString("marketplace.storage.bucket"),
 				StorageRegion: viper.GetString("marketplace.storage.region"),
 				Client:        Client,
+				StorageClient: performRequest,
 				Output:        os.Stderr,
+				Cache:         marketplaceCache,
 			}
 
 			if viper.GetBool("marketplace.strict-decoding") {
 				Marketplace.EnableStrictDecoding()
 			}
+
+			AccessTokenCache = csp.NewTokenCache(viper.GetString("csp.token-cache-path"))
+			StoredLogins = csp.NewLoginStore(viper.GetString("csp.login-store-path"))
+			CatalogStore = catalog.NewStore(viper.GetString("catalog.path"))
 			return nil
 		},
 		ValidateOutputFormatFlag,
@@ -74,62 +153,210 @@ enabling users to view, get, and manage their Marketplace products.`, AppName),
 }
 
 func init() {
+	viper.SetDefault("config-file", defaultConfigFilePath())
+	bindEnv("config-file", "MKPCLI_CONFIG")
+	rootCmd.PersistentFlags().String("config", "", "Path to the config file [$MKPCLI_CONFIG]")
+	bindFlag("config-file", rootCmd.PersistentFlags().Lookup("config"))
+
 	viper.SetDefault("debugging.enabled", false)
-	_ = viper.BindEnv("debugging.enabled", "MKPCLI_DEBUG")
+	bindEnv("debugging.enabled", "MKPCLI_DEBUG")
 	rootCmd.PersistentFlags().Bool("debug", false, "Enable debug output [$MKPCLI_DEBUG}")
 	_ = rootCmd.PersistentFlags().MarkHidden("debug")
-	_ = viper.BindPFlag("debugging.enabled", rootCmd.PersistentFlags().Lookup("debug"))
+	bindFlag("debugging.enabled", rootCmd.PersistentFlags().Lookup("debug"))
 
 	viper.SetDefault("debugging.print-request-payloads", false)
-	_ = viper.BindEnv("debugging.print-request-payloads", "MKPCLI_DEBUG_REQUEST_PAYLOADS")
+	bindEnv("debugging.print-request-payloads", "MKPCLI_DEBUG_REQUEST_PAYLOADS")
 	rootCmd.PersistentFlags().Bool("debug-request-payloads", false, "Also print request payloads [$MKPCLI_DEBUG_REQUEST_PAYLOADS]")
 	_ = rootCmd.PersistentFlags().MarkHidden("debug-request-payloads")
-	_ = viper.BindPFlag("debugging.print-request-payloads", rootCmd.PersistentFlags().Lookup("debug-request-payloads"))
+	bindFlag("debugging.print-request-payloads", rootCmd.PersistentFlags().Lookup("debug-request-payloads"))
 
 	viper.SetDefault("debugging.print-response-payloads", false)
 
+	viper.SetDefault("debugging.format", pkg.LogFormatText)
+	bindEnv("debugging.format", "MKPCLI_DEBUG_FORMAT")
+	rootCmd.PersistentFlags().String("debug-format", pkg.LogFormatText, fmt.Sprintf("Format of the debug output. One of %s|%s [$MKPCLI_DEBUG_FORMAT]", pkg.LogFormatText, pkg.LogFormatJSON))
+	_ = rootCmd.PersistentFlags().MarkHidden("debug-format")
+	bindFlag("debugging.format", rootCmd.PersistentFlags().Lookup("debug-format"))
+
+	viper.SetDefault("debugging.har-file", "")
+	bindEnv("debugging.har-file", "MKPCLI_DEBUG_HAR")
+	rootCmd.PersistentFlags().String("debug-har", "", "Save every request and response to this file as a HAR archive, with credentials removed [$MKPCLI_DEBUG_HAR]")
+	bindFlag("debugging.har-file", rootCmd.PersistentFlags().Lookup("debug-har"))
+
+	viper.SetDefault("debugging.record", "")
+	bindEnv("debugging.record", "MKPCLI_RECORD")
+	rootCmd.PersistentFlags().String("record", "", "Save every request and response to this directory, with credentials removed, to replay later [$MKPCLI_RECORD]")
+	bindFlag("debugging.record", rootCmd.PersistentFlags().Lookup("record"))
+
+	viper.SetDefault("debugging.replay", "")
+	bindEnv("debugging.replay", "MKPCLI_REPLAY")
+	rootCmd.PersistentFlags().String("replay", "", "Answer requests from a directory saved with --record, instead of the network [$MKPCLI_REPLAY]")
+	bindFlag("debugging.replay", rootCmd.PersistentFlags().Lookup("replay"))
+
+	viper.SetDefault("timeout", 0)
+	bindEnv("timeout", "MKPCLI_TIMEOUT")
+	rootCmd.PersistentFlags().Duration("timeout", 0, "Stop the command if it takes longer than this, like 10m. No limit if 0 [$MKPCLI_TIMEOUT]")
+	bindFlag("timeout", rootCmd.PersistentFlags().Lookup("timeout"))
+
+	viper.SetDefault("http.request-timeout", "5m")
+	bindEnv("http.request-timeout", "MKPCLI_REQUEST_TIMEOUT")
+
+	viper.SetDefault("http.proxy", "")
+	bindEnv("http.proxy", "MKPCLI_PROXY")
+	rootCmd.PersistentFlags().String("proxy", "", "URL of the proxy to send requests through. Uses $HTTPS_PROXY if not set [$MKPCLI_PROXY]")
+	bindFlag("http.proxy", rootCmd.PersistentFlags().Lookup("proxy"))
+
+	viper.SetDefault("http.ca-bundle", "")
+	bindEnv("http.ca-bundle", "MKPCLI_CA_BUNDLE")
+	rootCmd.PersistentFlags().String("ca-bundle", "", "PEM file with certificate authorities to trust, in addition to the system ones [$MKPCLI_CA_BUNDLE]")
+	bindFlag("http.ca-bundle", rootCmd.PersistentFlags().Lookup("ca-bundle"))
+
+	viper.SetDefault("http.client-cert", "")
+	bindEnv("http.client-cert", "MKPCLI_CLIENT_CERT")
+	rootCmd.PersistentFlags().String("client-cert", "", "PEM file with a client certificate, for servers that ask for one [$MKPCLI_CLIENT_CERT]")
+	bindFlag("http.client-cert", rootCmd.PersistentFlags().Lookup("client-cert"))
+
+	viper.SetDefault("http.client-key", "")
+	bindEnv("http.client-key", "MKPCLI_CLIENT_KEY")
+	rootCmd.PersistentFlags().String("client-key", "", "PEM file with the private key for --client-cert [$MKPCLI_CLIENT_KEY]")
+	bindFlag("http.client-key", rootCmd.PersistentFlags().Lookup("client-key"))
+
+	viper.SetDefault("http.insecure-skip-verify", false)
+	bindEnv("http.insecure-skip-verify", "MKPCLI_INSECURE_SKIP_VERIFY")
+	rootCmd.PersistentFlags().Bool("insecure-skip-verify", false, "Do not verify server certificates. Only for debugging [$MKPCLI_INSECURE_SKIP_VERIFY]")
+	_ = rootCmd.PersistentFlags().MarkHidden("insecure-skip-verify")
+	bindFlag("http.insecure-skip-verify", rootCmd.PersistentFlags().Lookup("insecure-skip-verify"))
+
+	viper.SetDefault("http.retry.max-attempts", 3)
+	bindEnv("http.retry.max-attempts", "MKPCLI_RETRY_MAX_ATTEMPTS")
+	viper.SetDefault("http.retry.initial-backoff", "1s")
+	bindEnv("http.retry.initial-backoff", "MKPCLI_RETRY_INITIAL_BACKOFF")
+	viper.SetDefault("http.retry.max-backoff", "30s")
+	bindEnv("http.retry.max-backoff", "MKPCLI_RETRY_MAX_BACKOFF")
+	viper.SetDefault("http.retry.max-retry-after", "5m")
+	bindEnv("http.retry.max-retry-after", "MKPCLI_RETRY_MAX_RETRY_AFTER")
+	viper.SetDefault("http.retry.writes", false)
+	bindEnv("http.retry.writes", "MKPCLI_RETRY_WRITES")
+
+	viper.SetDefault("cache.enabled", false)
+	bindEnv("cache.enabled", "MKPCLI_CACHE")
+	viper.SetDefault("cache.ttl", "5m")
+	bindEnv("cache.ttl", "MKPCLI_CACHE_TTL")
+	viper.SetDefault("cache.path", defaultResponseCachePath())
+	bindEnv("cache.path", "MKPCLI_CACHE_PATH")
+
+	viper.SetDefault("cache.disabled", false)
+	bindEnv("cache.disabled", "MKPCLI_NO_CACHE")
+	rootCmd.PersistentFlags().Bool("no-cache", false, "Do not reuse or store product responses on disk, even if cache.enabled is set [$MKPCLI_NO_CACHE]")
+	bindFlag("cache.disabled", rootCmd.PersistentFlags().Lookup("no-cache"))
+
+	viper.SetDefault("catalog.path", defaultCatalogPath())
+	bindEnv("catalog.path", "MKPCLI_CATALOG_PATH")
+
 	viper.SetDefault("csp.api-token", "")
-	_ = viper.BindEnv("csp.api-token", "CSP_API_TOKEN")
+	bindEnv("csp.api-token", "CSP_API_TOKEN")
 	rootCmd.PersistentFlags().String("csp-api-token", "", "VMware Cloud Service Platform API Token, used for authenticating to the VMware Marketplace [$CSP_API_TOKEN]")
-	_ = viper.BindPFlag("csp.api-token", rootCmd.PersistentFlags().Lookup("csp-api-token"))
+	bindFlag("csp.api-token", rootCmd.PersistentFlags().Lookup("csp-api-token"))
+
+	viper.SetDefault("csp.client-id", "")
+	bindEnv("csp.client-id", "CSP_CLIENT_ID")
+	rootCmd.PersistentFlags().String("csp-client-id", "", "VMware Cloud Service Platform OAuth app ID, used instead of an API Token [$CSP_CLIENT_ID]")
+	bindFlag("csp.client-id", rootCmd.PersistentFlags().Lookup("csp-client-id"))
+
+	viper.SetDefault("csp.client-secret", "")
+	bindEnv("csp.client-secret", "CSP_CLIENT_SECRET")
+	rootCmd.PersistentFlags().String("csp-client-secret", "", "VMware Cloud Service Platform OAuth app secret [$CSP_CLIENT_SECRET]")
+	bindFlag("csp.client-secret", rootCmd.PersistentFlags().Lookup("csp-client-secret"))
+
+	viper.SetDefault("csp.credential-helper", "")
+	bindEnv("csp.credential-helper", "CSP_CREDENTIAL_HELPER")
+	rootCmd.PersistentFlags().String("csp-credential-helper", "", "Program that provides the VMware Cloud Service Platform API Token, used instead of an API Token [$CSP_CREDENTIAL_HELPER]")
+	bindFlag("csp.credential-helper", rootCmd.PersistentFlags().Lookup("csp-credential-helper"))
 
 	viper.SetDefault("csp.host", "console.cloud.vmware.com")
-	_ = viper.BindEnv("csp.host", "CSP_HOST")
+	bindEnv("csp.host", "CSP_HOST")
 	rootCmd.PersistentFlags().String("csp-host", "console.cloud.vmware.com", "Host for VMware Cloud Service Platform")
 	_ = rootCmd.PersistentFlags().MarkHidden("csp-host")
-	_ = viper.BindPFlag("csp.host", rootCmd.PersistentFlags().Lookup("csp-host"))
-
-	_ = viper.BindEnv("marketplace.host", "MKPCLI_HOST")
-	_ = viper.BindEnv("marketplace.api-host", "MKPCLI_API_HOST")
-	_ = viper.BindEnv("marketplace.ui-host", "MKPCLI_UI_HOST")
-	_ = viper.BindEnv("marketplace.storage.bucket", "MKPCLI_STORAGE_BUCKET")
-	_ = viper.BindEnv("marketplace.storage.region", "MKPCLI_STORAGE_REGION")
-
-	if os.Getenv("MARKETPLACE_ENV") == "staging" {
-		viper.SetDefault("marketplace.host", "gtwstg.market.csp.vmware.com")
-		viper.SetDefault("marketplace.api-host", "apistg.market.csp.vmware.com")
-		viper.SetDefault("marketplace.ui-host", "stg.market.csp.vmware.com")
-		viper.SetDefault("marketplace.storage.bucket", "cspmarketplacestage")
-		viper.SetDefault("marketplace.storage.region", "us-east-2")
-	} else {
-		viper.SetDefault("marketplace.host", "gtw.marketplace.cloud.vmware.com")
-		viper.SetDefault("marketplace.api-host", "api.marketplace.cloud.vmware.com")
-		viper.SetDefault("marketplace.ui-host", "marketplace.cloud.vmware.com")
-		viper.SetDefault("marketplace.storage.bucket", "cspmarketplaceprd")
-		viper.SetDefault("marketplace.storage.region", "us-west-2")
-	}
+	bindFlag("csp.host", rootCmd.PersistentFlags().Lookup("csp-host"))
+
+	viper.SetDefault("csp.public-key-file", "")
+	bindEnv("csp.public-key-file", "CSP_PUBLIC_KEY_FILE")
+	rootCmd.PersistentFlags().String("csp-public-key-file", "", "PEM file with the VMware Cloud Service Platform public key, used instead of fetching it [$CSP_PUBLIC_KEY_FILE]")
+	bindFlag("csp.public-key-file", rootCmd.PersistentFlags().Lookup("csp-public-key-file"))
+
+	viper.SetDefault("csp.check-public-key", false)
+	bindEnv("csp.check-public-key", "MKPCLI_CHECK_CSP_PUBLIC_KEY")
+	rootCmd.PersistentFlags().Bool("check-csp-public-key", false, "Fetch the CSP public key anyway, and fail if it does not match --csp-public-key-file [$MKPCLI_CHECK_CSP_PUBLIC_KEY]")
+	bindFlag("csp.check-public-key", rootCmd.PersistentFlags().Lookup("check-csp-public-key"))
+
+	viper.SetDefault("csp.token-issuer", "")
+	bindEnv("csp.token-issuer", "CSP_TOKEN_ISSUER")
+
+	viper.SetDefault("csp.no-token-cache", false)
+	bindEnv("csp.no-token-cache", "MKPCLI_NO_TOKEN_CACHE")
+	rootCmd.PersistentFlags().Bool("no-token-cache", false, "Do not reuse or store CSP access tokens on disk [$MKPCLI_NO_TOKEN_CACHE]")
+	bindFlag("csp.no-token-cache", rootCmd.PersistentFlags().Lookup("no-token-cache"))
+
+	viper.SetDefault("csp.token-cache-path", defaultTokenCachePath())
+	bindEnv("csp.token-cache-path", "MKPCLI_TOKEN_CACHE_PATH")
+
+	viper.SetDefault("csp.login-client-id", "")
+	bindEnv("csp.login-client-id", "CSP_LOGIN_CLIENT_ID")
+	viper.SetDefault("csp.login-callback-port", 0)
+
+	viper.SetDefault("csp.login-store-path", defaultLoginStorePath())
+	bindEnv("csp.login-store-path", "MKPCLI_LOGIN_STORE_PATH")
+
+	bindEnv("marketplace.host", "MKPCLI_HOST")
+	bindEnv("marketplace.api-host", "MKPCLI_API_HOST")
+	bindEnv("marketplace.ui-host", "MKPCLI_UI_HOST")
+	bindEnv("marketplace.storage.bucket", "MKPCLI_STORAGE_BUCKET")
+	bindEnv("marketplace.storage.region", "MKPCLI_STORAGE_REGION")
+
+	defaultProfile := config.BuiltinProfiles[defaultProfileName()]
+	viper.SetDefault("marketplace.host", defaultProfile.MarketplaceHost)
+	viper.SetDefault("marketplace.api-host", defaultProfile.APIHost)
+	viper.SetDefault("marketplace.ui-host", defaultProfile.UIHost)
+	viper.SetDefault("marketplace.storage.bucket", defaultProfile.StorageBucket)
+	viper.SetDefault("marketplace.storage.region", defaultProfile.StorageRegion)
+
+	viper.SetDefault("profile", "")
+	bindEnv("profile", "MKPCLI_PROFILE")
+	rootCmd.PersistentFlags().String("profile", "", "Name of the profile to use, overriding the current profile [$MKPCLI_PROFILE]")
+	bindFlag("profile", rootCmd.PersistentFlags().Lookup("profile"))
+
+	viper.SetDefault("profiles-path", defaultProfilesPath())
+	bindEnv("profiles-path", "MKPCLI_PROFILES_PATH")
 
 	viper.SetDefault("marketplace.strict-decoding", false)
-	_ = viper.BindEnv("marketplace.strict-decoding", "MKPCLI_STRICT_DECODING")
+	bindEnv("marketplace.strict-decoding", "MKPCLI_STRICT_DECODING")
 
 	viper.SetDefault("output_format", output.FormatHuman)
-	_ = viper.BindEnv("output_format", "MKPCLI_OUTPUT")
+	bindEnv("output_format", "MKPCLI_OUTPUT")
 	rootCmd.PersistentFlags().StringP("output", "o", output.FormatHuman, fmt.Sprintf("Output format. One of %s. [$MKPCLI_OUTPUT]", strings.Join(output.SupportedOutputs, "|")))
-	_ = viper.BindPFlag("output_format", rootCmd.PersistentFlags().Lookup("output"))
+	bindFlag("output_format", rootCmd.PersistentFlags().Lookup("output"))
 }
 
+// harRecorder keeps the session for --debug-har, to be saved once the command is done
+var harRecorder *pkg.HARRecorder
+
+// cancelTimeout releases the deadline set by --timeout, once the command is done
+var cancelTimeout context.CancelFunc = func() {}
+
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
+	// Cancel in-flight requests and uploads on Ctrl-C, so that they can clean up
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	err := rootCmd.ExecuteContext(ctx)
+	cancelTimeout()
+	stop()
+	if harRecorder != nil {
+		harErr := harRecorder.WriteFile(viper.GetString("debugging.har-file"))
+		if harErr != nil {
+			rootCmd.PrintErrln("Error:", harErr.Error())
+			err = harErr
+		}
+	}
+	if err != nil {
 		os.Exit(1)
 	}
 }
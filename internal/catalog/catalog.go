@@ -0,0 +1,224 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: BSD-2-Clause
+
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/vmware-labs/marketplace-cli/v2/internal"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/models"
+	"github.com/vmware-labs/marketplace-cli/v2/pkg"
+)
+
+// SyncConcurrency is how many product details are fetched at the same time
+const SyncConcurrency = 4
+
+// Catalog is a local copy of the products in one Marketplace, for searching offline
+type Catalog struct {
+	Host     string    `json:"host"`
+	SyncedAt time.Time `json:"synced_at"`
+	// UpdatedOn is the most recent update of any product, where the next incremental sync starts from
+	UpdatedOn int               `json:"updated_on"`
+	Products  []*models.Product `json:"products"`
+}
+
+type Store struct {
+	Dir string
+}
+
+func NewStore(dir string) *Store {
+	return &Store{Dir: dir}
+}
+
+func (s *Store) path(host string) string {
+	return filepath.Join(s.Dir, host+".json")
+}
+
+// Load returns the catalog for the host, or an empty one if it was never synced
+func (s *Store) Load(host string) (*Catalog, error) {
+	catalog := &Catalog{
+		Host:     host,
+		Products: []*models.Product{},
+	}
+
+	data, err := os.ReadFile(s.path(host))
+	if errors.Is(err, fs.ErrNotExist) {
+		return catalog, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read the catalog: %w", err)
+	}
+
+	err = json.Unmarshal(data, catalog)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the catalog %s: %w", s.path(host), err)
+	}
+	return catalog, nil
+}
+
+func (s *Store) Save(catalog *Catalog) error {
+	data, err := json.Marshal(catalog)
+	if err != nil {
+		return fmt.Errorf("failed to encode the catalog: %w", err)
+	}
+
+	err = os.MkdirAll(s.Dir, 0700)
+	if err != nil {
+		return fmt.Errorf("failed to create the catalog directory: %w", err)
+	}
+
+	// Write to a temporary file first, so an interrupted sync does not leave a broken catalog behind
+	temporary := s.path(catalog.Host) + ".tmp"
+	err = os.WriteFile(temporary, data, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to write the catalog: %w", err)
+	}
+	err = os.Rename(temporary, s.path(catalog.Host))
+	if err != nil {
+		return fmt.Errorf("failed to write the catalog: %w", err)
+	}
+	return nil
+}
+
+//go:generate counterfeiter . ProductSource
+type ProductSource interface {
+	EachProduct(ctx context.Context, options *pkg.ListProductsOptions, fn pkg.ProductFunc) error
+	GetProduct(ctx context.Context, slug string) (*models.Product, error)
+}
+
+type SyncResult struct {
+	Added   int `json:"added"`
+	Updated int `json:"updated"`
+	Removed int `json:"removed"`
+	Total   int `json:"total"`
+	// Warnings are products whose details could not be fetched. Their summary from the list is kept instead.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// Sync updates the catalog with the published products from every organization. An incremental sync only fetches the
+// products updated since the last sync, newest first. A full sync fetches everything, and removes products that are
+// no longer listed.
+func Sync(ctx context.Context, source ProductSource, catalog *Catalog, full bool) (*SyncResult, error) {
+	full = full || catalog.UpdatedOn == 0
+
+	options := &pkg.ListProductsOptions{
+		AllOrgs: true,
+		Sorting: &internal.Sorting{
+			Order:     1,
+			Key:       internal.SortKeyUpdateDate,
+			Direction: internal.SortDirectionDescending,
+		},
+		PageSize: 50,
+	}
+
+	var changed []*models.Product
+	err := source.EachProduct(ctx, options, func(product *models.Product) error {
+		// Products updated at the same moment as the last sync are fetched again, in case they were missed
+		if !full && product.UpdatedDate < catalog.UpdatedOn {
+			return pkg.ErrStopListing
+		}
+		changed = append(changed, product)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the products: %w", err)
+	}
+
+	result := &SyncResult{}
+	details, err := fetchDetails(ctx, source, changed, result)
+	if err != nil {
+		return nil, err
+	}
+
+	existing := map[string]*models.Product{}
+	for _, product := range catalog.Products {
+		existing[product.ProductId] = product
+	}
+
+	products := map[string]*models.Product{}
+	if !full {
+		for id, product := range existing {
+			products[id] = product
+		}
+	}
+	for _, product := range details {
+		if _, ok := existing[product.ProductId]; ok {
+			result.Updated++
+		} else {
+			result.Added++
+		}
+		products[product.ProductId] = product
+	}
+	if full {
+		for id := range existing {
+			if _, ok := products[id]; !ok {
+				result.Removed++
+			}
+		}
+	}
+
+	catalog.Products = make([]*models.Product, 0, len(products))
+	for _, product := range products {
+		catalog.Products = append(catalog.Products, product)
+		if product.UpdatedDate > catalog.UpdatedOn {
+			catalog.UpdatedOn = product.UpdatedDate
+		}
+	}
+	sort.Slice(catalog.Products, func(i, j int) bool {
+		return catalog.Products[i].Slug < catalog.Products[j].Slug
+	})
+	catalog.SyncedAt = time.Now().UTC()
+	result.Total = len(catalog.Products)
+	return result, nil
+}
+
+// fetchDetails gets the full details of each product, with up to SyncConcurrency requests at the same time
+func fetchDetails(ctx context.Context, source ProductSource, products []*models.Product, result *SyncResult) ([]*models.Product, error) {
+	details := make([]*models.Product, len(products))
+	warnings := make([]string, len(products))
+
+	var wait sync.WaitGroup
+	next := make(chan int)
+	for worker := 0; worker < SyncConcurrency; worker++ {
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+			for index := range next {
+				product, err := source.GetProduct(ctx, products[index].Slug)
+				if err != nil {
+					warnings[index] = fmt.Sprintf("failed to get the details for %s: %s", products[index].Slug, err.Error())
+					product = products[index]
+				}
+				details[index] = product
+			}
+		}()
+	}
+
+	for index := range products {
+		if ctx.Err() != nil {
+			break
+		}
+		next <- index
+	}
+	close(next)
+	wait.Wait()
+
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	for _, warning := range warnings {
+		if warning != "" {
+			result.Warnings = append(result.Warnings, warning)
+		}
+	}
+	return details, nil
+}
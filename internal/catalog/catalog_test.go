@@ -0,0 +1,189 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: BSD-2-Clause
+
+package catalog_test
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/vmware-labs/marketplace-cli/v2/internal"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/catalog"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/catalog/catalogfakes"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/models"
+	"github.com/vmware-labs/marketplace-cli/v2/pkg"
+	"github.com/vmware-labs/marketplace-cli/v2/test"
+)
+
+var _ = Describe("Catalog", func() {
+	Describe("Store", func() {
+		var (
+			dir   string
+			store *catalog.Store
+		)
+
+		BeforeEach(func() {
+			var err error
+			dir, err = os.MkdirTemp("", "mkpcli-catalog-test")
+			Expect(err).ToNot(HaveOccurred())
+			store = catalog.NewStore(dir)
+		})
+
+		AfterEach(func() {
+			Expect(os.RemoveAll(dir)).To(Succeed())
+		})
+
+		It("saves and loads the catalog", func() {
+			product := test.CreateFakeProduct("", "My Super Product", "my-super-product", models.SolutionTypeChart)
+			Expect(store.Save(&catalog.Catalog{
+				Host:      "marketplace.example.com",
+				UpdatedOn: 1000,
+				Products:  []*models.Product{product},
+			})).To(Succeed())
+
+			loaded, err := store.Load("marketplace.example.com")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(loaded.UpdatedOn).To(Equal(1000))
+			Expect(loaded.Products).To(HaveLen(1))
+			Expect(loaded.Products[0].Slug).To(Equal("my-super-product"))
+		})
+
+		When("the catalog was never synced", func() {
+			It("returns an empty catalog", func() {
+				loaded, err := store.Load("marketplace.example.com")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(loaded.Host).To(Equal("marketplace.example.com"))
+				Expect(loaded.Products).To(BeEmpty())
+			})
+		})
+	})
+
+	Describe("Sync", func() {
+		var (
+			listed  []*models.Product
+			source  *catalogfakes.FakeProductSource
+			current *catalog.Catalog
+		)
+
+		makeProduct := func(slug string, updatedOn int) *models.Product {
+			product := test.CreateFakeProduct("id-"+slug, slug, slug, models.SolutionTypeChart)
+			product.UpdatedDate = updatedOn
+			return product
+		}
+
+		BeforeEach(func() {
+			listed = []*models.Product{
+				makeProduct("newest", 300),
+				makeProduct("middle", 200),
+				makeProduct("oldest", 100),
+			}
+			source = &catalogfakes.FakeProductSource{}
+			source.EachProductStub = func(ctx context.Context, options *pkg.ListProductsOptions, fn pkg.ProductFunc) error {
+				for _, product := range listed {
+					err := fn(product)
+					if errors.Is(err, pkg.ErrStopListing) {
+						return nil
+					} else if err != nil {
+						return err
+					}
+				}
+				return nil
+			}
+			source.GetProductStub = func(ctx context.Context, slug string) (*models.Product, error) {
+				for _, product := range listed {
+					if product.Slug == slug {
+						detailed := *product
+						detailed.Description = &models.Description{Summary: "details for " + slug}
+						return &detailed, nil
+					}
+				}
+				return nil, errors.New("product not found")
+			}
+			current = &catalog.Catalog{Host: "marketplace.example.com"}
+		})
+
+		It("gets every product, with its details", func() {
+			result, err := catalog.Sync(context.Background(), source, current, false)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result.Added).To(Equal(3))
+			Expect(result.Total).To(Equal(3))
+
+			Expect(current.UpdatedOn).To(Equal(300))
+			Expect(current.SyncedAt).ToNot(BeZero())
+			Expect(current.Products).To(HaveLen(3))
+			Expect(current.Products[0].Slug).To(Equal("middle"))
+			Expect(current.Products[0].Description.Summary).To(Equal("details for middle"))
+
+			Expect(source.EachProductCallCount()).To(Equal(1))
+			_, options, _ := source.EachProductArgsForCall(0)
+			Expect(options.AllOrgs).To(BeTrue())
+			Expect(options.Sorting.Key).To(Equal(internal.SortKeyUpdateDate))
+			Expect(options.Sorting.Direction).To(Equal(internal.SortDirectionDescending))
+		})
+
+		When("the catalog was synced before", func() {
+			BeforeEach(func() {
+				_, err := catalog.Sync(context.Background(), source, current, false)
+				Expect(err).ToNot(HaveOccurred())
+
+				listed = []*models.Product{
+					makeProduct("brand-new", 400),
+					makeProduct("newest", 300),
+					makeProduct("middle", 200),
+					makeProduct("oldest", 100),
+				}
+			})
+
+			It("only gets the products updated since then", func() {
+				result, err := catalog.Sync(context.Background(), source, current, false)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.Added).To(Equal(1))
+				Expect(result.Updated).To(Equal(1))
+				Expect(result.Total).To(Equal(4))
+				Expect(current.UpdatedOn).To(Equal(400))
+
+				// 3 from the first sync, then the brand new one and the one at the last watermark
+				Expect(source.GetProductCallCount()).To(Equal(5))
+			})
+
+			When("doing a full sync", func() {
+				It("gets everything again and removes products that are gone", func() {
+					listed = listed[:2]
+					result, err := catalog.Sync(context.Background(), source, current, true)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(result.Added).To(Equal(1))
+					Expect(result.Updated).To(Equal(1))
+					Expect(result.Removed).To(Equal(2))
+					Expect(result.Total).To(Equal(2))
+				})
+			})
+		})
+
+		When("getting the details of a product fails", func() {
+			It("keeps the product from the list, with a warning", func() {
+				source.GetProductStub = nil
+				source.GetProductReturns(nil, errors.New("get product failed"))
+
+				result, err := catalog.Sync(context.Background(), source, current, false)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(result.Total).To(Equal(3))
+				Expect(result.Warnings).To(HaveLen(3))
+				Expect(result.Warnings[0]).To(Equal("failed to get the details for newest: get product failed"))
+			})
+		})
+
+		When("listing the products fails", func() {
+			It("returns an error and leaves the catalog alone", func() {
+				source.EachProductStub = nil
+				source.EachProductReturns(errors.New("list products failed"))
+
+				_, err := catalog.Sync(context.Background(), source, current, false)
+				Expect(err).To(MatchError("failed to list the products: list products failed"))
+				Expect(current.Products).To(BeEmpty())
+			})
+		})
+	})
+})
@@ -0,0 +1,199 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package catalogfakes
+
+import (
+	"context"
+	"sync"
+
+	"github.com/vmware-labs/marketplace-cli/v2/internal/catalog"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/models"
+	"github.com/vmware-labs/marketplace-cli/v2/pkg"
+)
+
+type FakeProductSource struct {
+	EachProductStub        func(context.Context, *pkg.ListProductsOptions, pkg.ProductFunc) error
+	eachProductMutex       sync.RWMutex
+	eachProductArgsForCall []struct {
+		arg1 context.Context
+		arg2 *pkg.ListProductsOptions
+		arg3 pkg.ProductFunc
+	}
+	eachProductReturns struct {
+		result1 error
+	}
+	eachProductReturnsOnCall map[int]struct {
+		result1 error
+	}
+	GetProductStub        func(context.Context, string) (*models.Product, error)
+	getProductMutex       sync.RWMutex
+	getProductArgsForCall []struct {
+		arg1 context.Context
+		arg2 string
+	}
+	getProductReturns struct {
+		result1 *models.Product
+		result2 error
+	}
+	getProductReturnsOnCall map[int]struct {
+		result1 *models.Product
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeProductSource) EachProduct(arg1 context.Context, arg2 *pkg.ListProductsOptions, arg3 pkg.ProductFunc) error {
+	fake.eachProductMutex.Lock()
+	ret, specificReturn := fake.eachProductReturnsOnCall[len(fake.eachProductArgsForCall)]
+	fake.eachProductArgsForCall = append(fake.eachProductArgsForCall, struct {
+		arg1 context.Context
+		arg2 *pkg.ListProductsOptions
+		arg3 pkg.ProductFunc
+	}{arg1, arg2, arg3})
+	stub := fake.EachProductStub
+	fakeReturns := fake.eachProductReturns
+	fake.recordInvocation("EachProduct", []interface{}{arg1, arg2, arg3})
+	fake.eachProductMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeProductSource) EachProductCallCount() int {
+	fake.eachProductMutex.RLock()
+	defer fake.eachProductMutex.RUnlock()
+	return len(fake.eachProductArgsForCall)
+}
+
+func (fake *FakeProductSource) EachProductCalls(stub func(context.Context, *pkg.ListProductsOptions, pkg.ProductFunc) error) {
+	fake.eachProductMutex.Lock()
+	defer fake.eachProductMutex.Unlock()
+	fake.EachProductStub = stub
+}
+
+func (fake *FakeProductSource) EachProductArgsForCall(i int) (context.Context, *pkg.ListProductsOptions, pkg.ProductFunc) {
+	fake.eachProductMutex.RLock()
+	defer fake.eachProductMutex.RUnlock()
+	argsForCall := fake.eachProductArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeProductSource) EachProductReturns(result1 error) {
+	fake.eachProductMutex.Lock()
+	defer fake.eachProductMutex.Unlock()
+	fake.EachProductStub = nil
+	fake.eachProductReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeProductSource) EachProductReturnsOnCall(i int, result1 error) {
+	fake.eachProductMutex.Lock()
+	defer fake.eachProductMutex.Unlock()
+	fake.EachProductStub = nil
+	if fake.eachProductReturnsOnCall == nil {
+		fake.eachProductReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.eachProductReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeProductSource) GetProduct(arg1 context.Context, arg2 string) (*models.Product, error) {
+	fake.getProductMutex.Lock()
+	ret, specificReturn := fake.getProductReturnsOnCall[len(fake.getProductArgsForCall)]
+	fake.getProductArgsForCall = append(fake.getProductArgsForCall, struct {
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
+	stub := fake.GetProductStub
+	fakeReturns := fake.getProductReturns
+	fake.recordInvocation("GetProduct", []interface{}{arg1, arg2})
+	fake.getProductMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeProductSource) GetProductCallCount() int {
+	fake.getProductMutex.RLock()
+	defer fake.getProductMutex.RUnlock()
+	return len(fake.getProductArgsForCall)
+}
+
+func (fake *FakeProductSource) GetProductCalls(stub func(context.Context, string) (*models.Product, error)) {
+	fake.getProductMutex.Lock()
+	defer fake.getProductMutex.Unlock()
+	fake.GetProductStub = stub
+}
+
+func (fake *FakeProductSource) GetProductArgsForCall(i int) (context.Context, string) {
+	fake.getProductMutex.RLock()
+	defer fake.getProductMutex.RUnlock()
+	argsForCall := fake.getProductArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeProductSource) GetProductReturns(result1 *models.Product, result2 error) {
+	fake.getProductMutex.Lock()
+	defer fake.getProductMutex.Unlock()
+	fake.GetProductStub = nil
+	fake.getProductReturns = struct {
+		result1 *models.Product
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeProductSource) GetProductReturnsOnCall(i int, result1 *models.Product, result2 error) {
+	fake.getProductMutex.Lock()
+	defer fake.getProductMutex.Unlock()
+	fake.GetProductStub = nil
+	if fake.getProductReturnsOnCall == nil {
+		fake.getProductReturnsOnCall = make(map[int]struct {
+			result1 *models.Product
+			result2 error
+		})
+	}
+	fake.getProductReturnsOnCall[i] = struct {
+		result1 *models.Product
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeProductSource) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.eachProductMutex.RLock()
+	defer fake.eachProductMutex.RUnlock()
+	fake.getProductMutex.RLock()
+	defer fake.getProductMutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeProductSource) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ catalog.ProductSource = new(FakeProductSource)
@@ -0,0 +1,285 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: BSD-2-Clause
+
+package catalog
+
+import (
+	"html"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/vmware-labs/marketplace-cli/v2/internal/models"
+)
+
+const (
+	FacetPublisher    = "publisher"
+	FacetSolutionType = "solution_type"
+	FacetCategory     = "category"
+	FacetTag          = "tag"
+
+	DefaultSearchLimit = 20
+)
+
+// How much a match in each field counts towards the score of a product
+const (
+	weightName         = 5.0
+	weightTag          = 3.0
+	weightCategory     = 3.0
+	weightPublisher    = 3.0
+	weightSolutionType = 2.0
+	weightSummary      = 2.0
+	weightDescription  = 1.0
+
+	// A word that only starts with a search term counts for less than the whole word
+	prefixMatch = 0.5
+)
+
+var htmlTags = regexp.MustCompile(`<[^>]*>`)
+
+type SearchQuery struct {
+	Text string
+
+	// Only products with these values are found. Matching ignores case.
+	Publisher    string
+	SolutionType string
+	Category     string
+	Tag          string
+
+	// Limit is how many results to return. Facets count every result.
+	Limit int
+}
+
+type SearchResult struct {
+	Slug         string  `json:"slug"`
+	DisplayName  string  `json:"displayname"`
+	Publisher    string  `json:"publisher"`
+	SolutionType string  `json:"solutiontype"`
+	Summary      string  `json:"summary"`
+	Score        float64 `json:"score"`
+
+	Product *models.Product `json:"-"`
+}
+
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+type SearchResults struct {
+	Query    string                   `json:"query"`
+	SyncedAt time.Time                `json:"synced_at"`
+	Total    int                      `json:"total"`
+	Results  []*SearchResult          `json:"results"`
+	Facets   map[string][]*FacetCount `json:"facets"`
+}
+
+type searchField struct {
+	weight float64
+	text   string
+}
+
+// Search finds the products that match every word of the query, ranked by where and how often the words appear
+func (c *Catalog) Search(query *SearchQuery) *SearchResults {
+	terms := tokenize(query.Text)
+	phrase := strings.ToLower(strings.TrimSpace(query.Text))
+
+	results := []*SearchResult{}
+	for _, product := range c.Products {
+		if !matchesFilters(product, query) {
+			continue
+		}
+
+		score, matched := scoreProduct(product, terms, phrase)
+		if !matched {
+			continue
+		}
+		results = append(results, &SearchResult{
+			Slug:         product.Slug,
+			DisplayName:  product.DisplayName,
+			Publisher:    publisherName(product),
+			SolutionType: product.SolutionType,
+			Summary:      summary(product),
+			Score:        score,
+			Product:      product,
+		})
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return strings.ToLower(results[i].DisplayName) < strings.ToLower(results[j].DisplayName)
+	})
+
+	searchResults := &SearchResults{
+		Query:    query.Text,
+		SyncedAt: c.SyncedAt,
+		Total:    len(results),
+		Facets:   countFacets(results),
+	}
+
+	limit := query.Limit
+	if limit <= 0 {
+		limit = DefaultSearchLimit
+	}
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	searchResults.Results = results
+	return searchResults
+}
+
+func scoreProduct(product *models.Product, terms []string, phrase string) (float64, bool) {
+	if len(terms) == 0 {
+		return 0, true
+	}
+
+	fields := productFields(product)
+	total := 0.0
+	for _, term := range terms {
+		termScore := 0.0
+		for _, field := range fields {
+			for _, word := range tokenize(field.text) {
+				if word == term {
+					termScore += field.weight
+				} else if len(term) > 1 && strings.HasPrefix(word, term) {
+					termScore += field.weight * prefixMatch
+				}
+			}
+		}
+		if termScore == 0 {
+			return 0, false
+		}
+		total += termScore
+	}
+
+	// The whole query appearing as written counts extra
+	if len(terms) > 1 {
+		for _, field := range fields {
+			if strings.Contains(strings.ToLower(field.text), phrase) {
+				total += field.weight
+			}
+		}
+	}
+	return total, true
+}
+
+func productFields(product *models.Product) []*searchField {
+	fields := []*searchField{
+		{weight: weightName, text: product.DisplayName},
+		{weight: weightPublisher, text: publisherName(product)},
+		{weight: weightSolutionType, text: product.SolutionType},
+		{weight: weightSummary, text: summary(product)},
+	}
+	if product.Description != nil {
+		fields = append(fields, &searchField{weight: weightDescription, text: stripHTML(product.Description.Description)})
+	}
+	for _, tag := range product.Tags {
+		fields = append(fields, &searchField{weight: weightTag, text: tag})
+	}
+	for _, category := range categories(product) {
+		fields = append(fields, &searchField{weight: weightCategory, text: category})
+	}
+	return fields
+}
+
+func matchesFilters(product *models.Product, query *SearchQuery) bool {
+	if query.Publisher != "" && !strings.EqualFold(publisherName(product), query.Publisher) {
+		return false
+	}
+	if query.SolutionType != "" && !strings.EqualFold(product.SolutionType, query.SolutionType) {
+		return false
+	}
+	if query.Category != "" && !containsFold(categories(product), query.Category) {
+		return false
+	}
+	if query.Tag != "" && !containsFold(product.Tags, query.Tag) {
+		return false
+	}
+	return true
+}
+
+func countFacets(results []*SearchResult) map[string][]*FacetCount {
+	counts := map[string]map[string]int{
+		FacetPublisher:    {},
+		FacetSolutionType: {},
+		FacetCategory:     {},
+		FacetTag:          {},
+	}
+	for _, result := range results {
+		if result.Publisher != "" {
+			counts[FacetPublisher][result.Publisher]++
+		}
+		if result.SolutionType != "" {
+			counts[FacetSolutionType][result.SolutionType]++
+		}
+		for _, category := range categories(result.Product) {
+			counts[FacetCategory][category]++
+		}
+		for _, tag := range result.Product.Tags {
+			counts[FacetTag][tag]++
+		}
+	}
+
+	facets := map[string][]*FacetCount{}
+	for facet, values := range counts {
+		facets[facet] = []*FacetCount{}
+		for value, count := range values {
+			facets[facet] = append(facets[facet], &FacetCount{Value: value, Count: count})
+		}
+		sort.Slice(facets[facet], func(i, j int) bool {
+			if facets[facet][i].Count != facets[facet][j].Count {
+				return facets[facet][i].Count > facets[facet][j].Count
+			}
+			return facets[facet][i].Value < facets[facet][j].Value
+		})
+	}
+	return facets
+}
+
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+func stripHTML(text string) string {
+	return html.UnescapeString(htmlTags.ReplaceAllString(text, " "))
+}
+
+func publisherName(product *models.Product) string {
+	if product.PublisherDetails != nil && product.PublisherDetails.OrgDisplayName != "" {
+		return product.PublisherDetails.OrgDisplayName
+	}
+	return product.PublisherOrgName
+}
+
+func summary(product *models.Product) string {
+	if product.Description == nil {
+		return ""
+	}
+	return product.Description.Summary
+}
+
+// categories combines the two lists of categories that the Marketplace uses
+func categories(product *models.Product) []string {
+	var all []string
+	for _, category := range append(append([]string{}, product.Categories...), product.Category...) {
+		if !containsFold(all, category) {
+			all = append(all, category)
+		}
+	}
+	return all
+}
+
+func containsFold(values []string, value string) bool {
+	for _, candidate := range values {
+		if strings.EqualFold(candidate, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: BSD-2-Clause
+
+package catalog_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/catalog"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/models"
+	"github.com/vmware-labs/marketplace-cli/v2/test"
+)
+
+var _ = Describe("Search", func() {
+	var products *catalog.Catalog
+
+	BeforeEach(func() {
+		database := test.CreateFakeProduct("", "Tanzu Postgres", "tanzu-postgres", models.SolutionTypeChart)
+		database.Description = &models.Description{
+			Summary:     "A relational database",
+			Description: "<p>Runs <b>PostgreSQL</b> &amp; friends</p>",
+		}
+		database.Categories = []string{"Databases"}
+		database.Tags = []string{"sql"}
+
+		cache := test.CreateFakeProduct("", "Redis Cache", "redis-cache", models.SolutionTypeImage)
+		cache.Description = &models.Description{Summary: "An in-memory database"}
+		cache.Category = []string{"Databases", "Caching"}
+		cache.PublisherDetails.OrgDisplayName = "Other Org"
+
+		monitoring := test.CreateFakeProduct("", "Database Monitor", "database-monitor", models.SolutionTypeOVA)
+		monitoring.Categories = []string{"Monitoring"}
+
+		products = &catalog.Catalog{
+			Products: []*models.Product{cache, database, monitoring},
+		}
+	})
+
+	It("ranks products by where the words appear", func() {
+		results := products.Search(&catalog.SearchQuery{Text: "database"})
+		Expect(results.Total).To(Equal(3))
+		Expect(results.Results[0].Slug).To(Equal("database-monitor"))
+	})
+
+	It("requires every word to match", func() {
+		results := products.Search(&catalog.SearchQuery{Text: "relational database"})
+		Expect(results.Total).To(Equal(1))
+		Expect(results.Results[0].Slug).To(Equal("tanzu-postgres"))
+	})
+
+	It("matches the start of words", func() {
+		results := products.Search(&catalog.SearchQuery{Text: "postgre"})
+		Expect(results.Total).To(Equal(1))
+		Expect(results.Results[0].Slug).To(Equal("tanzu-postgres"))
+	})
+
+	It("searches the description without its HTML", func() {
+		results := products.Search(&catalog.SearchQuery{Text: "friends"})
+		Expect(results.Total).To(Equal(1))
+
+		results = products.Search(&catalog.SearchQuery{Text: "amp"})
+		Expect(results.Total).To(Equal(0))
+	})
+
+	It("filters by publisher, solution type, category and tag", func() {
+		Expect(products.Search(&catalog.SearchQuery{Publisher: "other org"}).Total).To(Equal(1))
+		Expect(products.Search(&catalog.SearchQuery{SolutionType: models.SolutionTypeOVA}).Total).To(Equal(1))
+		Expect(products.Search(&catalog.SearchQuery{Category: "databases"}).Total).To(Equal(2))
+		Expect(products.Search(&catalog.SearchQuery{Tag: "SQL"}).Total).To(Equal(1))
+	})
+
+	It("counts the facets of every result", func() {
+		results := products.Search(&catalog.SearchQuery{Text: "database", Limit: 1})
+		Expect(results.Results).To(HaveLen(1))
+		Expect(results.Facets[catalog.FacetCategory]).To(Equal([]*catalog.FacetCount{
+			{Value: "Databases", Count: 2},
+			{Value: "Caching", Count: 1},
+			{Value: "Monitoring", Count: 1},
+		}))
+		Expect(results.Facets[catalog.FacetPublisher]).To(ContainElement(&catalog.FacetCount{Value: "my-org", Count: 2}))
+	})
+
+	When("there are no words to search for", func() {
+		It("returns every product, sorted by name", func() {
+			results := products.Search(&catalog.SearchQuery{})
+			Expect(results.Total).To(Equal(3))
+			Expect(results.Results[0].Slug).To(Equal("database-monitor"))
+			Expect(results.Results[2].Slug).To(Equal("tanzu-postgres"))
+		})
+	})
+})
@@ -0,0 +1,123 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: BSD-2-Clause
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// File is the persistent config file. Keys use the same dotted names as the settings, like "marketplace.host",
+// and are stored as nested YAML maps, which is how they are read back into the settings.
+type File struct {
+	Path string
+}
+
+func NewFile(path string) *File {
+	return &File{Path: path}
+}
+
+func (f *File) Load() (map[string]interface{}, error) {
+	values := map[string]interface{}{}
+	if f.Path == "" {
+		return values, nil
+	}
+
+	data, err := os.ReadFile(f.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return values, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read the config file: %w", err)
+	}
+
+	err = yaml.Unmarshal(data, &values)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the config file %s: %w", f.Path, err)
+	}
+	if values == nil {
+		values = map[string]interface{}{}
+	}
+	return values, nil
+}
+
+func (f *File) Set(key, value string) error {
+	values, err := f.Load()
+	if err != nil {
+		return err
+	}
+
+	parts := strings.Split(key, ".")
+	parent := values
+	for _, part := range parts[:len(parts)-1] {
+		child, ok := parent[part].(map[string]interface{})
+		if !ok {
+			child = map[string]interface{}{}
+			parent[part] = child
+		}
+		parent = child
+	}
+	parent[parts[len(parts)-1]] = value
+
+	return f.save(values)
+}
+
+func (f *File) Unset(key string) error {
+	values, err := f.Load()
+	if err != nil {
+		return err
+	}
+
+	if !unset(values, strings.Split(key, ".")) {
+		return nil
+	}
+	return f.save(values)
+}
+
+// unset removes the key, and any maps left empty by removing it. Returns false if the key was not set.
+func unset(values map[string]interface{}, parts []string) bool {
+	if len(parts) == 1 {
+		if _, ok := values[parts[0]]; !ok {
+			return false
+		}
+		delete(values, parts[0])
+		return true
+	}
+
+	child, ok := values[parts[0]].(map[string]interface{})
+	if !ok || !unset(child, parts[1:]) {
+		return false
+	}
+	if len(child) == 0 {
+		delete(values, parts[0])
+	}
+	return true
+}
+
+func (f *File) save(values map[string]interface{}) error {
+	if f.Path == "" {
+		return errors.New("no location is configured for the config file")
+	}
+
+	data, err := yaml.Marshal(values)
+	if err != nil {
+		return fmt.Errorf("failed to encode the config file: %w", err)
+	}
+
+	err = os.MkdirAll(filepath.Dir(f.Path), 0700)
+	if err != nil {
+		return fmt.Errorf("failed to create the config directory: %w", err)
+	}
+
+	err = os.WriteFile(f.Path, data, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to write the config file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: BSD-2-Clause
+
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vmware-labs/marketplace-cli/v2/internal/config"
+)
+
+var _ = Describe("File", func() {
+	var (
+		dir  string
+		file *config.File
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "mkpcli-config-test")
+		Expect(err).ToNot(HaveOccurred())
+		file = config.NewFile(filepath.Join(dir, "mkpcli", "config.yaml"))
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	It("is empty when the file does not exist", func() {
+		values, err := file.Load()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(values).To(BeEmpty())
+	})
+
+	It("stores settings as nested maps", func() {
+		Expect(file.Set("marketplace.storage.region", "us-east-2")).To(Succeed())
+		Expect(file.Set("output_format", "yaml")).To(Succeed())
+
+		data, err := os.ReadFile(file.Path)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(data)).To(Equal("marketplace:\n    storage:\n        region: us-east-2\noutput_format: yaml\n"))
+
+		info, err := os.Stat(file.Path)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+	})
+
+	It("removes settings, and the maps left empty", func() {
+		Expect(file.Set("marketplace.storage.region", "us-east-2")).To(Succeed())
+		Expect(file.Set("marketplace.host", "gtw.marketplace.example.com")).To(Succeed())
+
+		Expect(file.Unset("marketplace.storage.region")).To(Succeed())
+		values, err := file.Load()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(values).To(Equal(map[string]interface{}{
+			"marketplace": map[string]interface{}{
+				"host": "gtw.marketplace.example.com",
+			},
+		}))
+
+		Expect(file.Unset("marketplace.host")).To(Succeed())
+		values, err = file.Load()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(values).To(BeEmpty())
+	})
+
+	It("does nothing when unsetting a setting that is not in the file", func() {
+		Expect(file.Unset("marketplace.host")).To(Succeed())
+		_, err := os.Stat(file.Path)
+		Expect(os.IsNotExist(err)).To(BeTrue())
+	})
+
+	When("the file is not valid YAML", func() {
+		It("returns an error", func() {
+			Expect(os.MkdirAll(filepath.Dir(file.Path), 0700)).To(Succeed())
+			Expect(os.WriteFile(file.Path, []byte("not: [valid"), 0600)).To(Succeed())
+
+			_, err := file.Load()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(HavePrefix("failed to parse the config file " + file.Path))
+		})
+	})
+
+	When("no path is configured", func() {
+		It("cannot save settings", func() {
+			err := config.NewFile("").Set("output_format", "yaml")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("no location is configured for the config file"))
+		})
+	})
+})
+
+var _ = Describe("Redact", func() {
+	It("hides secret values", func() {
+		Expect(config.Redact("csp.api-token", "my-api-token")).To(Equal(config.RedactedValue))
+		Expect(config.Redact("csp.refresh-token", "my-access-token")).To(Equal(config.RedactedValue))
+		Expect(config.Redact("csp.client-secret", "my-client-secret")).To(Equal(config.RedactedValue))
+	})
+
+	It("shows empty secrets and other values", func() {
+		Expect(config.Redact("csp.api-token", "")).To(Equal(""))
+		Expect(config.Redact("csp.host", "console.cloud.vmware.com")).To(Equal("console.cloud.vmware.com"))
+	})
+})
@@ -0,0 +1,173 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: BSD-2-Clause
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	ProfileProduction = "production"
+	ProfileStaging    = "staging"
+)
+
+// Profile is a named environment: the Marketplace and CSP hosts to use, and where to find the credentials for them.
+// Credentials are referenced by the name of an environment variable, so secrets are never written to the profiles file.
+type Profile struct {
+	Name             string `json:"name" yaml:"name,omitempty"`
+	MarketplaceHost  string `json:"marketplace_host,omitempty" yaml:"marketplace_host,omitempty"`
+	APIHost          string `json:"api_host,omitempty" yaml:"api_host,omitempty"`
+	UIHost           string `json:"ui_host,omitempty" yaml:"ui_host,omitempty"`
+	StorageBucket    string `json:"storage_bucket,omitempty" yaml:"storage_bucket,omitempty"`
+	StorageRegion    string `json:"storage_region,omitempty" yaml:"storage_region,omitempty"`
+	CSPHost          string `json:"csp_host,omitempty" yaml:"csp_host,omitempty"`
+	APITokenEnv      string `json:"api_token_env,omitempty" yaml:"api_token_env,omitempty"`
+	ClientIDEnv      string `json:"client_id_env,omitempty" yaml:"client_id_env,omitempty"`
+	ClientSecretEnv  string `json:"client_secret_env,omitempty" yaml:"client_secret_env,omitempty"`
+	CredentialHelper string `json:"credential_helper,omitempty" yaml:"credential_helper,omitempty"`
+}
+
+var BuiltinProfiles = map[string]*Profile{
+	ProfileProduction: {
+		Name:            ProfileProduction,
+		MarketplaceHost: "gtw.marketplace.cloud.vmware.com",
+		APIHost:         "api.marketplace.cloud.vmware.com",
+		UIHost:          "marketplace.cloud.vmware.com",
+		StorageBucket:   "cspmarketplaceprd",
+		StorageRegion:   "us-west-2",
+		CSPHost:         "console.cloud.vmware.com",
+	},
+	ProfileStaging: {
+		Name:            ProfileStaging,
+		MarketplaceHost: "gtwstg.market.csp.vmware.com",
+		APIHost:         "apistg.market.csp.vmware.com",
+		UIHost:          "stg.market.csp.vmware.com",
+		StorageBucket:   "cspmarketplacestage",
+		StorageRegion:   "us-east-2",
+		CSPHost:         "console.cloud.vmware.com",
+	},
+}
+
+// Profiles is the content of the profiles file
+type Profiles struct {
+	Current  string              `yaml:"current,omitempty"`
+	Profiles map[string]*Profile `yaml:"profiles,omitempty"`
+}
+
+// Get returns the named profile, falling back to the built-in profiles. A profile in the file with the name of a
+// built-in profile only overrides the settings that it sets, and keeps the rest of the built-in profile.
+func (p *Profiles) Get(name string) (*Profile, bool) {
+	builtin, isBuiltin := BuiltinProfiles[name]
+	profile, inFile := p.Profiles[name]
+	if !inFile {
+		return builtin, isBuiltin
+	}
+
+	merged := &Profile{}
+	if isBuiltin {
+		*merged = *builtin
+	}
+	if profile != nil {
+		merged.override(profile)
+	}
+	merged.Name = name
+	return merged, true
+}
+
+// override replaces the settings of this profile with the ones set in the other profile
+func (p *Profile) override(other *Profile) {
+	setIfNotEmpty := func(setting *string, value string) {
+		if value != "" {
+			*setting = value
+		}
+	}
+	setIfNotEmpty(&p.MarketplaceHost, other.MarketplaceHost)
+	setIfNotEmpty(&p.APIHost, other.APIHost)
+	setIfNotEmpty(&p.UIHost, other.UIHost)
+	setIfNotEmpty(&p.StorageBucket, other.StorageBucket)
+	setIfNotEmpty(&p.StorageRegion, other.StorageRegion)
+	setIfNotEmpty(&p.CSPHost, other.CSPHost)
+	setIfNotEmpty(&p.APITokenEnv, other.APITokenEnv)
+	setIfNotEmpty(&p.ClientIDEnv, other.ClientIDEnv)
+	setIfNotEmpty(&p.ClientSecretEnv, other.ClientSecretEnv)
+	setIfNotEmpty(&p.CredentialHelper, other.CredentialHelper)
+}
+
+// All returns the profiles from the file and the built-in profiles, sorted by name
+func (p *Profiles) All() []*Profile {
+	var names []string
+	for name := range BuiltinProfiles {
+		if _, ok := p.Profiles[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	for name := range p.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var profiles []*Profile
+	for _, name := range names {
+		profile, _ := p.Get(name)
+		profiles = append(profiles, profile)
+	}
+	return profiles
+}
+
+type ProfileStore struct {
+	Path string
+}
+
+func NewProfileStore(path string) *ProfileStore {
+	return &ProfileStore{Path: path}
+}
+
+func (s *ProfileStore) Load() (*Profiles, error) {
+	profiles := &Profiles{}
+	if s.Path == "" {
+		return profiles, nil
+	}
+
+	data, err := os.ReadFile(s.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return profiles, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read the profiles file: %w", err)
+	}
+
+	err = yaml.Unmarshal(data, profiles)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the profiles file %s: %w", s.Path, err)
+	}
+	return profiles, nil
+}
+
+func (s *ProfileStore) Save(profiles *Profiles) error {
+	if s.Path == "" {
+		return errors.New("no location is configured for the profiles file")
+	}
+
+	data, err := yaml.Marshal(profiles)
+	if err != nil {
+		return fmt.Errorf("failed to encode the profiles: %w", err)
+	}
+
+	err = os.MkdirAll(filepath.Dir(s.Path), 0700)
+	if err != nil {
+		return fmt.Errorf("failed to create the profiles directory: %w", err)
+	}
+
+	err = os.WriteFile(s.Path, data, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to write the profiles file: %w", err)
+	}
+	return nil
+}
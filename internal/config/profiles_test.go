@@ -0,0 +1,107 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: BSD-2-Clause
+
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/config"
+)
+
+var _ = Describe("Profiles", func() {
+	var (
+		configDir string
+		store     *config.ProfileStore
+	)
+
+	BeforeEach(func() {
+		var err error
+		configDir, err = os.MkdirTemp("", "mkpcli-profiles-test")
+		Expect(err).ToNot(HaveOccurred())
+		store = config.NewProfileStore(filepath.Join(configDir, "mkpcli", "profiles.yaml"))
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(configDir)).To(Succeed())
+	})
+
+	It("has only the built-in profiles when there is no profiles file", func() {
+		profiles, err := store.Load()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(profiles.Current).To(BeEmpty())
+
+		all := profiles.All()
+		Expect(all).To(HaveLen(2))
+		Expect(all[0].Name).To(Equal("production"))
+		Expect(all[1].Name).To(Equal("staging"))
+	})
+
+	It("loads profiles from the profiles file", func() {
+		Expect(os.MkdirAll(filepath.Dir(store.Path), 0700)).To(Succeed())
+		Expect(os.WriteFile(store.Path, []byte(`current: partner
+profiles:
+  partner:
+    marketplace_host: gtw.partner.example.com
+    csp_host: console.partner.example.com
+    api_token_env: PARTNER_CSP_API_TOKEN
+  staging:
+    storage_region: eu-west-1
+`), 0600)).To(Succeed())
+
+		profiles, err := store.Load()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(profiles.Current).To(Equal("partner"))
+
+		partner, ok := profiles.Get("partner")
+		Expect(ok).To(BeTrue())
+		Expect(partner.Name).To(Equal("partner"))
+		Expect(partner.MarketplaceHost).To(Equal("gtw.partner.example.com"))
+		Expect(partner.CSPHost).To(Equal("console.partner.example.com"))
+		Expect(partner.APITokenEnv).To(Equal("PARTNER_CSP_API_TOKEN"))
+
+		By("letting the file override a built-in profile", func() {
+			staging, ok := profiles.Get("staging")
+			Expect(ok).To(BeTrue())
+			Expect(staging.Name).To(Equal("staging"))
+			Expect(staging.StorageRegion).To(Equal("eu-west-1"))
+
+			builtin := config.BuiltinProfiles[config.ProfileStaging]
+			Expect(staging.MarketplaceHost).To(Equal(builtin.MarketplaceHost))
+			Expect(staging.APIHost).To(Equal(builtin.APIHost))
+			Expect(staging.UIHost).To(Equal(builtin.UIHost))
+			Expect(staging.StorageBucket).To(Equal(builtin.StorageBucket))
+			Expect(staging.CSPHost).To(Equal(builtin.CSPHost))
+			Expect(builtin.StorageRegion).To(Equal("us-east-2"))
+			Expect(profiles.All()).To(HaveLen(3))
+		})
+
+		_, ok = profiles.Get("unknown")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("saves the profiles", func() {
+		profiles, err := store.Load()
+		Expect(err).ToNot(HaveOccurred())
+		profiles.Current = "staging"
+		Expect(store.Save(profiles)).To(Succeed())
+
+		profiles, err = store.Load()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(profiles.Current).To(Equal("staging"))
+	})
+
+	When("the profiles file is invalid", func() {
+		It("returns an error", func() {
+			Expect(os.MkdirAll(filepath.Dir(store.Path), 0700)).To(Succeed())
+			Expect(os.WriteFile(store.Path, []byte("profiles: [this is not a map"), 0600)).To(Succeed())
+
+			_, err := store.Load()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(HavePrefix("failed to parse the profiles file " + store.Path))
+		})
+	})
+})
@@ -0,0 +1,35 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: BSD-2-Clause
+
+package config
+
+const (
+	SourceFlag    = "flag"
+	SourceEnv     = "env"
+	SourceFile    = "file"
+	SourceProfile = "profile"
+	SourceDefault = "default"
+
+	RedactedValue = "[redacted]"
+)
+
+// Setting is the effective value of a setting, and where it came from
+type Setting struct {
+	Key    string      `json:"key" yaml:"key"`
+	Value  interface{} `json:"value" yaml:"value"`
+	Source string      `json:"source" yaml:"source"`
+}
+
+var SecretKeys = map[string]bool{
+	"csp.api-token":     true,
+	"csp.client-secret": true,
+	"csp.refresh-token": true,
+}
+
+// Redact hides the value of secret settings, so they are not printed
+func Redact(key string, value interface{}) interface{} {
+	if SecretKeys[key] && value != nil && value != "" {
+		return RedactedValue
+	}
+	return value
+}
@@ -28,6 +28,20 @@ type Claims struct {
 
 	// The token as a string, signed and ready to be put in an Authorization header
 	Token string `json:"-"`
+
+	// A new refresh token, if CSP issued one while redeeming a login
+	RefreshToken string `json:"-"`
+}
+
+// ParseClaims decodes an access token without verifying it
+func ParseClaims(token string) (*Claims, error) {
+	claims := &Claims{}
+	_, _, err := new(jwt.Parser).ParseUnverified(token, claims)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse access token: %w", err)
+	}
+	claims.Token = token
+	return claims, nil
 }
 
 func (claims *Claims) GetQualifiedUsername() string {
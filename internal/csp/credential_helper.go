@@ -0,0 +1,73 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: BSD-2-Clause
+
+package csp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+type CredentialHelperRequest struct {
+	Host string `json:"host"`
+}
+
+type CredentialHelperResponse struct {
+	Token     string     `json:"token"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// CredentialHelper gets the CSP API token from an external program, like a docker credential helper.
+// The program is run with the "get" argument, is sent a CredentialHelperRequest as JSON on stdin,
+// and must print a CredentialHelperResponse as JSON on stdout.
+type CredentialHelper struct {
+	Command string
+	Now     func() time.Time
+}
+
+func NewCredentialHelper(command string) *CredentialHelper {
+	return &CredentialHelper{
+		Command: command,
+		Now:     time.Now,
+	}
+}
+
+func (h *CredentialHelper) Get(ctx context.Context, cspHost string) (string, error) {
+	request, err := json.Marshal(&CredentialHelperRequest{Host: cspHost})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode the credential helper request: %w", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, h.Command, "get")
+	cmd.Stdin = bytes.NewReader(request)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err = cmd.Run()
+	if err != nil {
+		message := strings.TrimSpace(stderr.String())
+		if message == "" {
+			return "", fmt.Errorf("credential helper %s failed: %w", h.Command, err)
+		}
+		return "", fmt.Errorf("credential helper %s failed: %w: %s", h.Command, err, message)
+	}
+
+	var response CredentialHelperResponse
+	err = json.Unmarshal(stdout.Bytes(), &response)
+	if err != nil {
+		return "", fmt.Errorf("credential helper %s returned an invalid response: %w", h.Command, err)
+	}
+	if response.Token == "" {
+		return "", fmt.Errorf("credential helper %s did not return a token", h.Command)
+	}
+	if response.ExpiresAt != nil && !response.ExpiresAt.After(h.Now()) {
+		return "", fmt.Errorf("credential helper %s returned a token that expired at %s", h.Command, response.ExpiresAt.UTC().Format(time.RFC3339))
+	}
+	return response.Token, nil
+}
@@ -0,0 +1,106 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: BSD-2-Clause
+
+package csp_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/csp"
+)
+
+var _ = Describe("CredentialHelper", func() {
+	var (
+		helperDir  string
+		helperPath string
+		helper     *csp.CredentialHelper
+	)
+
+	writeHelper := func(script string) {
+		Expect(os.WriteFile(helperPath, []byte("#!/bin/sh\n"+script), 0700)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		var err error
+		helperDir, err = os.MkdirTemp("", "mkpcli-credential-helper-test")
+		Expect(err).ToNot(HaveOccurred())
+		helperPath = filepath.Join(helperDir, "mkpcli-credential-helper")
+		helper = csp.NewCredentialHelper(helperPath)
+		helper.Now = func() time.Time {
+			return time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+		}
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(helperDir)).To(Succeed())
+	})
+
+	It("sends the host on stdin and returns the token from stdout", func() {
+		writeHelper(`test "$1" = "get" || exit 1
+request=$(cat)
+test "$request" = '{"host":"console.example.com"}' || exit 1
+echo '{"token": "my-api-token", "expires_at": "2022-06-01T13:00:00Z"}'
+`)
+		token, err := helper.Get(context.Background(), "console.example.com")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(token).To(Equal("my-api-token"))
+	})
+
+	It("does not require an expiry", func() {
+		writeHelper(`echo '{"token": "my-api-token"}'`)
+		token, err := helper.Get(context.Background(), "console.example.com")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(token).To(Equal("my-api-token"))
+	})
+
+	When("the helper fails", func() {
+		It("returns an error with the helper's stderr", func() {
+			writeHelper(`echo "vault is sealed" >&2
+exit 3
+`)
+			_, err := helper.Get(context.Background(), "console.example.com")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("credential helper " + helperPath + " failed: exit status 3: vault is sealed"))
+		})
+	})
+
+	When("the helper does not exist", func() {
+		It("returns an error", func() {
+			_, err := helper.Get(context.Background(), "console.example.com")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(HavePrefix("credential helper " + helperPath + " failed: "))
+		})
+	})
+
+	When("the helper returns invalid JSON", func() {
+		It("returns an error", func() {
+			writeHelper(`echo "my-api-token"`)
+			_, err := helper.Get(context.Background(), "console.example.com")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(HavePrefix("credential helper " + helperPath + " returned an invalid response: "))
+		})
+	})
+
+	When("the helper does not return a token", func() {
+		It("returns an error", func() {
+			writeHelper(`echo '{}'`)
+			_, err := helper.Get(context.Background(), "console.example.com")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("credential helper " + helperPath + " did not return a token"))
+		})
+	})
+
+	When("the token has expired", func() {
+		It("returns an error", func() {
+			writeHelper(`echo '{"token": "my-api-token", "expires_at": "2022-06-01T11:00:00Z"}'`)
+			_, err := helper.Get(context.Background(), "console.example.com")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("credential helper " + helperPath + " returned a token that expired at 2022-06-01T11:00:00Z"))
+		})
+	})
+})
@@ -0,0 +1,68 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: BSD-2-Clause
+
+package csp
+
+import "errors"
+
+// Credentials are used to get an access token from CSP. Either an API token, the client ID and secret of an
+// OAuth app, or the refresh token from logging in with a web browser. If more than one is set, the login is used
+// first, then the OAuth app.
+type Credentials struct {
+	APIToken     string
+	ClientID     string
+	ClientSecret string
+	RefreshToken string
+}
+
+func (c *Credentials) IsLogin() bool {
+	return c.RefreshToken != ""
+}
+
+func (c *Credentials) IsClientCredentials() bool {
+	return !c.IsLogin() && (c.ClientID != "" || c.ClientSecret != "")
+}
+
+func (c *Credentials) Validate() error {
+	if c.IsLogin() {
+		if c.ClientID == "" {
+			return errors.New("missing CSP OAuth client ID for the login")
+		}
+		return nil
+	}
+
+	if c.IsClientCredentials() {
+		if c.ClientID == "" {
+			return errors.New("missing CSP OAuth client ID")
+		}
+		if c.ClientSecret == "" {
+			return errors.New("missing CSP OAuth client secret")
+		}
+		return nil
+	}
+
+	if c.APIToken == "" {
+		return errors.New("missing CSP API token or OAuth client credentials")
+	}
+	return nil
+}
+
+func (c *Credentials) Description() string {
+	if c.IsLogin() {
+		return "login refresh token"
+	}
+	if c.IsClientCredentials() {
+		return "client credentials"
+	}
+	return "api token"
+}
+
+func (c *Credentials) CacheKey(cspHost string) string {
+	if c.IsLogin() {
+		return TokenCacheKey(cspHost, c.RefreshToken)
+	}
+	if c.IsClientCredentials() {
+		return TokenCacheKey(cspHost, c.ClientID+":"+c.ClientSecret)
+	}
+	return TokenCacheKey(cspHost, c.APIToken)
+}
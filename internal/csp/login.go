@@ -0,0 +1,189 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: BSD-2-Clause
+
+package csp
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/vmware-labs/marketplace-cli/v2/pkg"
+)
+
+const (
+	LoginCallbackPath   = "/callback"
+	DefaultLoginTimeout = 5 * time.Minute
+)
+
+type AuthorizationCodeResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// LoginFlow logs in with a web browser, using the OAuth authorization code grant with PKCE.
+// The authorization code is received by a short-lived HTTP server on the loopback interface.
+type LoginFlow struct {
+	CSPHost      string
+	ClientID     string
+	CallbackPort int
+	Timeout      time.Duration
+	Client       pkg.HTTPClient
+	OpenBrowser  func(url string) error
+	Output       io.Writer
+}
+
+type callbackResult struct {
+	code string
+	err  error
+}
+
+func (l *LoginFlow) Login(ctx context.Context) (*Login, error) {
+	if l.ClientID == "" {
+		return nil, errors.New("missing CSP OAuth client ID for logging in")
+	}
+
+	verifier, err := randomString()
+	if err != nil {
+		return nil, err
+	}
+	state, err := randomString()
+	if err != nil {
+		return nil, err
+	}
+	challenge := sha256.Sum256([]byte(verifier))
+
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", l.CallbackPort))
+	if err != nil {
+		return nil, fmt.Errorf("failed to start the login callback server: %w", err)
+	}
+	redirectURI := fmt.Sprintf("http://%s%s", listener.Addr().String(), LoginCallbackPath)
+
+	results := make(chan *callbackResult, 1)
+	server := &http.Server{
+		Handler:           l.callbackHandler(state, results),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	defer server.Close()
+
+	authorizeURL := pkg.MakeURL(l.CSPHost, "/csp/gateway/discovery", url.Values{
+		"response_type":         []string{"code"},
+		"client_id":             []string{l.ClientID},
+		"redirect_uri":          []string{redirectURI},
+		"state":                 []string{state},
+		"code_challenge":        []string{base64.RawURLEncoding.EncodeToString(challenge[:])},
+		"code_challenge_method": []string{"S256"},
+	})
+
+	_, _ = fmt.Fprintf(l.Output, "Opening the login page in your browser. If it does not open, visit this URL:\n%s\n", authorizeURL.String())
+	err = l.OpenBrowser(authorizeURL.String())
+	if err != nil {
+		_, _ = fmt.Fprintf(l.Output, "Failed to open the browser: %s\n", err.Error())
+	}
+
+	timeout := l.Timeout
+	if timeout == 0 {
+		timeout = DefaultLoginTimeout
+	}
+
+	var result *callbackResult
+	select {
+	case result = <-results:
+	case <-time.After(timeout):
+		return nil, fmt.Errorf("timed out after %s waiting for the login to complete", timeout)
+	case <-ctx.Done():
+		return nil, fmt.Errorf("stopped waiting for the login to complete: %w", ctx.Err())
+	}
+	if result.err != nil {
+		return nil, result.err
+	}
+
+	return l.exchangeCode(ctx, result.code, redirectURI, verifier)
+}
+
+func (l *LoginFlow) callbackHandler(state string, results chan<- *callbackResult) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(LoginCallbackPath, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("state") != state {
+			// Not the callback for this login, so keep waiting for the real one
+			http.Error(w, "login callback had an unexpected state", http.StatusBadRequest)
+			return
+		}
+
+		result := &callbackResult{}
+		if query.Get("error") != "" {
+			result.err = fmt.Errorf("login failed: %s %s", query.Get("error"), query.Get("error_description"))
+		} else if query.Get("code") == "" {
+			result.err = errors.New("login callback did not include an authorization code")
+		} else {
+			result.code = query.Get("code")
+		}
+
+		if result.err != nil {
+			http.Error(w, result.err.Error(), http.StatusBadRequest)
+		} else {
+			_, _ = io.WriteString(w, "Login complete. You can close this window and return to the terminal.\n")
+		}
+
+		select {
+		case results <- result:
+		default:
+			// A result was already received, ignore any further callbacks
+		}
+	})
+	return mux
+}
+
+func (l *LoginFlow) exchangeCode(ctx context.Context, code, redirectURI, verifier string) (*Login, error) {
+	requestURL := pkg.MakeURL(l.CSPHost, "/csp/gateway/am/api/auth/authorize", nil)
+	resp, err := l.Client.PostForm(ctx, requestURL, url.Values{
+		"grant_type":    []string{"authorization_code"},
+		"code":          []string{code},
+		"redirect_uri":  []string{redirectURI},
+		"client_id":     []string{l.ClientID},
+		"code_verifier": []string{verifier},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange the authorization code: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to exchange the authorization code for a refresh token: %s", resp.Status)
+	}
+
+	var body AuthorizationCodeResponse
+	err = json.NewDecoder(resp.Body).Decode(&body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the authorization code response: %w", err)
+	}
+	if body.RefreshToken == "" {
+		return nil, errors.New("CSP did not return a refresh token")
+	}
+
+	return &Login{
+		ClientID:     l.ClientID,
+		RefreshToken: body.RefreshToken,
+	}, nil
+}
+
+func randomString() (string, error) {
+	data := make([]byte, 32)
+	_, err := rand.Read(data)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate random data: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
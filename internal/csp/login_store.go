@@ -0,0 +1,99 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: BSD-2-Clause
+
+package csp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Login is the result of logging in with a web browser
+type Login struct {
+	ClientID     string `json:"client_id"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// LoginStore keeps the refresh tokens from browser logins, one per CSP host
+type LoginStore struct {
+	Path string
+}
+
+func NewLoginStore(path string) *LoginStore {
+	return &LoginStore{Path: path}
+}
+
+func (s *LoginStore) Get(cspHost string) (*Login, error) {
+	logins, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	return logins[cspHost], nil
+}
+
+func (s *LoginStore) Set(cspHost string, login *Login) error {
+	logins, err := s.load()
+	if err != nil {
+		return err
+	}
+	logins[cspHost] = login
+	return s.save(logins)
+}
+
+func (s *LoginStore) Delete(cspHost string) error {
+	logins, err := s.load()
+	if err != nil {
+		return err
+	}
+	if _, ok := logins[cspHost]; !ok {
+		return nil
+	}
+	delete(logins, cspHost)
+	return s.save(logins)
+}
+
+func (s *LoginStore) load() (map[string]*Login, error) {
+	logins := map[string]*Login{}
+	if s.Path == "" {
+		return logins, nil
+	}
+
+	data, err := os.ReadFile(s.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return logins, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read the stored logins: %w", err)
+	}
+
+	err = json.Unmarshal(data, &logins)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the stored logins: %w", err)
+	}
+	return logins, nil
+}
+
+func (s *LoginStore) save(logins map[string]*Login) error {
+	if s.Path == "" {
+		return errors.New("no location is configured for storing logins")
+	}
+
+	data, err := json.Marshal(logins)
+	if err != nil {
+		return fmt.Errorf("failed to encode the stored logins: %w", err)
+	}
+
+	err = os.MkdirAll(filepath.Dir(s.Path), 0700)
+	if err != nil {
+		return fmt.Errorf("failed to create the login store directory: %w", err)
+	}
+
+	err = os.WriteFile(s.Path, data, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to write the stored logins: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: BSD-2-Clause
+
+package csp_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/csp"
+)
+
+var _ = Describe("LoginStore", func() {
+	var (
+		storeDir string
+		store    *csp.LoginStore
+	)
+
+	BeforeEach(func() {
+		var err error
+		storeDir, err = os.MkdirTemp("", "mkpcli-login-store-test")
+		Expect(err).ToNot(HaveOccurred())
+		store = csp.NewLoginStore(filepath.Join(storeDir, "mkpcli", "csp-logins.json"))
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(storeDir)).To(Succeed())
+	})
+
+	It("stores one login per CSP host", func() {
+		Expect(store.Set("console.example.com", &csp.Login{ClientID: "app", RefreshToken: "token-1"})).To(Succeed())
+		Expect(store.Set("console-stg.example.com", &csp.Login{ClientID: "app", RefreshToken: "token-2"})).To(Succeed())
+
+		login, err := store.Get("console.example.com")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(login.RefreshToken).To(Equal("token-1"))
+
+		By("only letting the user read the file", func() {
+			info, err := os.Stat(store.Path)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+		})
+
+		By("deleting a login", func() {
+			Expect(store.Delete("console.example.com")).To(Succeed())
+			login, err := store.Get("console.example.com")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(login).To(BeNil())
+
+			login, err = store.Get("console-stg.example.com")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(login.RefreshToken).To(Equal("token-2"))
+		})
+	})
+
+	It("has no logins before anything is stored", func() {
+		login, err := store.Get("console.example.com")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(login).To(BeNil())
+		Expect(store.Delete("console.example.com")).To(Succeed())
+	})
+})
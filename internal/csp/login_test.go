@@ -0,0 +1,207 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: BSD-2-Clause
+
+package csp_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/csp"
+	"github.com/vmware-labs/marketplace-cli/v2/pkg"
+)
+
+var _ = Describe("LoginFlow", func() {
+	var (
+		fakeCSP       *httptest.Server
+		loginFlow     *csp.LoginFlow
+		authorizeURL  *url.URL
+		tokenRequest  url.Values
+		callbackQuery func(authorizeURL *url.URL) url.Values
+	)
+
+	BeforeEach(func() {
+		tokenRequest = nil
+		fakeCSP = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.URL.Path).To(Equal("/csp/gateway/am/api/auth/authorize"))
+			Expect(r.ParseForm()).To(Succeed())
+			tokenRequest = r.PostForm
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(&csp.AuthorizationCodeResponse{
+				AccessToken:  "my-access-token",
+				RefreshToken: "my-refresh-token",
+			})
+		}))
+
+		client := pkg.NewClient(io.Discard, false, false, false)
+		client.PerformRequest = fakeCSP.Client().Do
+
+		callbackQuery = func(authorizeURL *url.URL) url.Values {
+			return url.Values{
+				"code":  []string{"my-authorization-code"},
+				"state": []string{authorizeURL.Query().Get("state")},
+			}
+		}
+
+		loginFlow = &csp.LoginFlow{
+			CSPHost:  strings.TrimPrefix(fakeCSP.URL, "https://"),
+			ClientID: "my-client-id",
+			Timeout:  10 * time.Second,
+			Client:   client,
+			Output:   io.Discard,
+			OpenBrowser: func(rawURL string) error {
+				var err error
+				authorizeURL, err = url.Parse(rawURL)
+				Expect(err).ToNot(HaveOccurred())
+
+				// Act as the browser, following the redirect back to the CLI after the user logs in
+				go func() {
+					defer GinkgoRecover()
+					redirectURI, err := url.Parse(authorizeURL.Query().Get("redirect_uri"))
+					Expect(err).ToNot(HaveOccurred())
+					redirectURI.RawQuery = callbackQuery(authorizeURL).Encode()
+					resp, err := http.Get(redirectURI.String())
+					Expect(err).ToNot(HaveOccurred())
+					_ = resp.Body.Close()
+				}()
+				return nil
+			},
+		}
+	})
+
+	AfterEach(func() {
+		fakeCSP.Close()
+	})
+
+	It("exchanges the authorization code for a refresh token", func() {
+		login, err := loginFlow.Login(context.Background())
+		Expect(err).ToNot(HaveOccurred())
+		Expect(login.ClientID).To(Equal("my-client-id"))
+		Expect(login.RefreshToken).To(Equal("my-refresh-token"))
+
+		By("sending the user to the authorize page with a PKCE challenge", func() {
+			Expect(authorizeURL.Path).To(Equal("/csp/gateway/discovery"))
+			query := authorizeURL.Query()
+			Expect(query.Get("response_type")).To(Equal("code"))
+			Expect(query.Get("client_id")).To(Equal("my-client-id"))
+			Expect(query.Get("redirect_uri")).To(MatchRegexp(`^http://127\.0\.0\.1:\d+/callback$`))
+			Expect(query.Get("code_challenge_method")).To(Equal("S256"))
+		})
+
+		By("sending the code verifier that matches the challenge", func() {
+			Expect(tokenRequest.Get("grant_type")).To(Equal("authorization_code"))
+			Expect(tokenRequest.Get("code")).To(Equal("my-authorization-code"))
+			Expect(tokenRequest.Get("client_id")).To(Equal("my-client-id"))
+			Expect(tokenRequest.Get("redirect_uri")).To(Equal(authorizeURL.Query().Get("redirect_uri")))
+
+			challenge := sha256.Sum256([]byte(tokenRequest.Get("code_verifier")))
+			Expect(authorizeURL.Query().Get("code_challenge")).To(Equal(base64.RawURLEncoding.EncodeToString(challenge[:])))
+		})
+	})
+
+	When("a callback has a different state", func() {
+		var strayResponses []int
+
+		BeforeEach(func() {
+			strayResponses = nil
+			openBrowser := loginFlow.OpenBrowser
+			loginFlow.OpenBrowser = func(rawURL string) error {
+				authorizeURL, err := url.Parse(rawURL)
+				Expect(err).ToNot(HaveOccurred())
+				redirectURI, err := url.Parse(authorizeURL.Query().Get("redirect_uri"))
+				Expect(err).ToNot(HaveOccurred())
+
+				for _, query := range []url.Values{
+					{"code": []string{"some-other-code"}, "state": []string{"some-other-state"}},
+					{"code": []string{"some-other-code"}},
+				} {
+					redirectURI.RawQuery = query.Encode()
+					resp, err := http.Get(redirectURI.String())
+					Expect(err).ToNot(HaveOccurred())
+					_ = resp.Body.Close()
+					strayResponses = append(strayResponses, resp.StatusCode)
+				}
+				return openBrowser(rawURL)
+			}
+		})
+
+		It("rejects it and keeps waiting for the real callback", func() {
+			login, err := loginFlow.Login(context.Background())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(login.RefreshToken).To(Equal("my-refresh-token"))
+			Expect(strayResponses).To(Equal([]int{http.StatusBadRequest, http.StatusBadRequest}))
+			Expect(tokenRequest.Get("code")).To(Equal("my-authorization-code"))
+		})
+
+		When("the real callback never comes", func() {
+			BeforeEach(func() {
+				loginFlow.Timeout = 100 * time.Millisecond
+				callbackQuery = func(authorizeURL *url.URL) url.Values {
+					return url.Values{
+						"code":  []string{"my-authorization-code"},
+						"state": []string{"some-other-state"},
+					}
+				}
+			})
+
+			It("times out without exchanging a code", func() {
+				_, err := loginFlow.Login(context.Background())
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("timed out after 100ms waiting for the login to complete"))
+				Expect(tokenRequest).To(BeNil())
+			})
+		})
+	})
+
+	When("the login is denied", func() {
+		BeforeEach(func() {
+			callbackQuery = func(authorizeURL *url.URL) url.Values {
+				return url.Values{
+					"error":             []string{"access_denied"},
+					"error_description": []string{"the user denied access"},
+					"state":             []string{authorizeURL.Query().Get("state")},
+				}
+			}
+		})
+
+		It("returns an error", func() {
+			_, err := loginFlow.Login(context.Background())
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("login failed: access_denied the user denied access"))
+		})
+	})
+
+	When("the login does not complete in time", func() {
+		BeforeEach(func() {
+			loginFlow.Timeout = 100 * time.Millisecond
+			loginFlow.OpenBrowser = func(string) error {
+				return nil
+			}
+		})
+
+		It("returns an error", func() {
+			_, err := loginFlow.Login(context.Background())
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("timed out after 100ms waiting for the login to complete"))
+		})
+	})
+
+	When("the client ID is missing", func() {
+		It("returns an error", func() {
+			loginFlow.ClientID = ""
+			_, err := loginFlow.Login(context.Background())
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("missing CSP OAuth client ID for logging in"))
+		})
+	})
+})
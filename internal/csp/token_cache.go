@@ -0,0 +1,151 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: BSD-2-Clause
+
+package csp
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// TokenExpiryMargin is how long before its expiry a cached access token stops being reused
+const TokenExpiryMargin = 5 * time.Minute
+
+type CachedToken struct {
+	AccessToken string `json:"access_token"`
+	ExpiresAt   int64  `json:"expires_at"`
+}
+
+type TokenCache struct {
+	Path string
+	Now  func() time.Time
+}
+
+func NewTokenCache(path string) *TokenCache {
+	return &TokenCache{
+		Path: path,
+		Now:  time.Now,
+	}
+}
+
+// TokenCacheKey identifies a cached token by the CSP host and a fingerprint of the credential used to redeem it,
+// so the credential itself is never written to disk
+func TokenCacheKey(cspHost, credential string) string {
+	return fmt.Sprintf("%s/%x", cspHost, sha256.Sum256([]byte(credential)))
+}
+
+func (c *TokenCache) Get(key string) (*Claims, bool) {
+	tokens, err := c.load()
+	if err != nil {
+		return nil, false
+	}
+
+	token, ok := tokens[key]
+	if !ok || c.isExpiring(token) {
+		return nil, false
+	}
+
+	claims, err := ParseClaims(token.AccessToken)
+	if err != nil {
+		return nil, false
+	}
+	return claims, true
+}
+
+func (c *TokenCache) Set(key string, claims *Claims) error {
+	if c.Path == "" || claims.ExpiresAt == 0 {
+		return nil
+	}
+
+	tokens, err := c.load()
+	if err != nil {
+		// A corrupt cache is simply replaced
+		tokens = map[string]*CachedToken{}
+	}
+
+	for existingKey, token := range tokens {
+		if c.isExpiring(token) {
+			delete(tokens, existingKey)
+		}
+	}
+
+	tokens[key] = &CachedToken{
+		AccessToken: claims.Token,
+		ExpiresAt:   claims.ExpiresAt,
+	}
+	return c.save(tokens)
+}
+
+func (c *TokenCache) Clear() error {
+	if c.Path == "" {
+		return nil
+	}
+
+	err := os.Remove(c.Path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to remove the token cache: %w", err)
+	}
+	return nil
+}
+
+func (c *TokenCache) isExpiring(token *CachedToken) bool {
+	return time.Unix(token.ExpiresAt, 0).Before(c.Now().Add(TokenExpiryMargin))
+}
+
+func (c *TokenCache) load() (map[string]*CachedToken, error) {
+	tokens := map[string]*CachedToken{}
+	if c.Path == "" {
+		return tokens, nil
+	}
+
+	data, err := os.ReadFile(c.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return tokens, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read the token cache: %w", err)
+	}
+
+	err = json.Unmarshal(data, &tokens)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the token cache: %w", err)
+	}
+	return tokens, nil
+}
+
+func (c *TokenCache) save(tokens map[string]*CachedToken) error {
+	data, err := json.Marshal(tokens)
+	if err != nil {
+		return fmt.Errorf("failed to encode the token cache: %w", err)
+	}
+
+	err = os.MkdirAll(filepath.Dir(c.Path), 0700)
+	if err != nil {
+		return fmt.Errorf("failed to create the token cache directory: %w", err)
+	}
+
+	// Write to a temporary file first, so another mkpcli process never reads a partly written cache
+	temporary, err := os.CreateTemp(filepath.Dir(c.Path), filepath.Base(c.Path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write the token cache: %w", err)
+	}
+	defer os.Remove(temporary.Name())
+
+	_, err = temporary.Write(data)
+	if closeErr := temporary.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write the token cache: %w", err)
+	}
+	err = os.Rename(temporary.Name(), c.Path)
+	if err != nil {
+		return fmt.Errorf("failed to write the token cache: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: BSD-2-Clause
+
+package csp_test
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/csp"
+)
+
+func makeToken(username string, expiresAt time.Time) *csp.Claims {
+	claims := &csp.Claims{
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: expiresAt.Unix(),
+		},
+		Username: username,
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("test-signing-key"))
+	Expect(err).ToNot(HaveOccurred())
+	claims.Token = token
+	return claims
+}
+
+var _ = Describe("TokenCache", func() {
+	var (
+		cacheDir string
+		cache    *csp.TokenCache
+		now      time.Time
+	)
+
+	BeforeEach(func() {
+		var err error
+		cacheDir, err = os.MkdirTemp("", "mkpcli-token-cache-test")
+		Expect(err).ToNot(HaveOccurred())
+
+		now = time.Now()
+		cache = csp.NewTokenCache(filepath.Join(cacheDir, "mkpcli", "csp-tokens.json"))
+		cache.Now = func() time.Time {
+			return now
+		}
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(cacheDir)).To(Succeed())
+	})
+
+	It("returns cached tokens until shortly before they expire", func() {
+		claims := makeToken("alice@example.com", now.Add(time.Hour))
+		Expect(cache.Set("console.example.com/abc", claims)).To(Succeed())
+
+		cachedClaims, ok := cache.Get("console.example.com/abc")
+		Expect(ok).To(BeTrue())
+		Expect(cachedClaims.Token).To(Equal(claims.Token))
+		Expect(cachedClaims.Username).To(Equal("alice@example.com"))
+
+		By("not returning tokens for other keys", func() {
+			_, ok = cache.Get("console.example.com/def")
+			Expect(ok).To(BeFalse())
+		})
+
+		By("not returning tokens about to expire", func() {
+			now = now.Add(time.Hour - csp.TokenExpiryMargin + time.Second)
+			_, ok = cache.Get("console.example.com/abc")
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	It("writes the cache file so only the user can read it", func() {
+		Expect(cache.Set("console.example.com/abc", makeToken("alice@example.com", now.Add(time.Hour)))).To(Succeed())
+
+		info, err := os.Stat(cache.Path)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+	})
+
+	It("does not leave temporary files behind", func() {
+		Expect(cache.Set("console.example.com/abc", makeToken("alice@example.com", now.Add(time.Hour)))).To(Succeed())
+		Expect(cache.Set("console.example.com/def", makeToken("bob@example.com", now.Add(time.Hour)))).To(Succeed())
+
+		files, err := os.ReadDir(filepath.Dir(cache.Path))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(files).To(HaveLen(1))
+		Expect(files[0].Name()).To(Equal("csp-tokens.json"))
+	})
+
+	It("prunes expired tokens when storing a new one", func() {
+		Expect(cache.Set("console.example.com/old", makeToken("alice@example.com", now.Add(time.Minute)))).To(Succeed())
+		Expect(cache.Set("console.example.com/new", makeToken("bob@example.com", now.Add(time.Hour)))).To(Succeed())
+
+		contents, err := os.ReadFile(cache.Path)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(contents)).ToNot(ContainSubstring("console.example.com/old"))
+		Expect(string(contents)).To(ContainSubstring("console.example.com/new"))
+	})
+
+	When("the cache file is corrupt", func() {
+		BeforeEach(func() {
+			Expect(os.MkdirAll(filepath.Dir(cache.Path), 0700)).To(Succeed())
+			Expect(os.WriteFile(cache.Path, []byte("this is not json"), 0600)).To(Succeed())
+		})
+
+		It("treats it as empty and replaces it", func() {
+			_, ok := cache.Get("console.example.com/abc")
+			Expect(ok).To(BeFalse())
+
+			Expect(cache.Set("console.example.com/abc", makeToken("alice@example.com", now.Add(time.Hour)))).To(Succeed())
+			_, ok = cache.Get("console.example.com/abc")
+			Expect(ok).To(BeTrue())
+		})
+	})
+
+	Describe("Clear", func() {
+		It("removes the cache file", func() {
+			Expect(cache.Set("console.example.com/abc", makeToken("alice@example.com", now.Add(time.Hour)))).To(Succeed())
+			Expect(cache.Clear()).To(Succeed())
+
+			_, err := os.Stat(cache.Path)
+			Expect(os.IsNotExist(err)).To(BeTrue())
+		})
+
+		It("succeeds if there is no cache file", func() {
+			Expect(cache.Clear()).To(Succeed())
+		})
+	})
+
+	Describe("TokenCacheKey", func() {
+		It("does not contain the credential", func() {
+			key := csp.TokenCacheKey("console.example.com", "my-secret-api-token")
+			Expect(key).To(HavePrefix("console.example.com/"))
+			Expect(key).ToNot(ContainSubstring("my-secret-api-token"))
+			Expect(csp.TokenCacheKey("console.example.com", "another-api-token")).ToNot(Equal(key))
+		})
+	})
+})
@@ -4,10 +4,15 @@
 package csp
 
 import (
+	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
+	"strings"
+	"time"
 
 	"github.com/golang-jwt/jwt"
 	"github.com/vmware-labs/marketplace-cli/v2/pkg"
@@ -17,28 +22,30 @@ type TokenServices struct {
 	keyfunc jwt.Keyfunc
 	keyPem  string
 	CSPHost string
+	Issuer  string
 	Client  pkg.HTTPClient
 }
 
 type RedeemResponse struct {
-	AccessToken string `json:"access_token"`
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token,omitempty"`
 }
 
-func (csp *TokenServices) Redeem(refreshToken string) (*Claims, error) {
+func (csp *TokenServices) Redeem(ctx context.Context, refreshToken string) (*Claims, error) {
 	requestURL := pkg.MakeURL(csp.CSPHost, "/csp/gateway/am/api/auth/api-tokens/authorize", nil)
 	formData := url.Values{
 		"refresh_token": []string{refreshToken},
 	}
 
 	retried := false
-	resp, err := csp.Client.PostForm(requestURL, formData)
+	resp, err := csp.Client.PostForm(ctx, requestURL, formData)
 	if err != nil {
 		return nil, fmt.Errorf("failed to redeem token: %w", err)
 	}
 
 	if resp.StatusCode == http.StatusServiceUnavailable {
 		retried = true
-		resp, err = csp.Client.PostForm(requestURL, formData)
+		resp, err = csp.Client.PostForm(ctx, requestURL, formData)
 		if err != nil {
 			return nil, fmt.Errorf("failed to redeem token on second attempt: %w", err)
 		}
@@ -51,45 +58,148 @@ func (csp *TokenServices) Redeem(refreshToken string) (*Claims, error) {
 		return nil, fmt.Errorf("failed twice to exchange refresh token for access token: %s", resp.Status)
 	}
 
-	var body RedeemResponse
-	err = json.NewDecoder(resp.Body).Decode(&body)
+	return csp.parseRedeemResponse(resp)
+}
+
+// Authenticate exchanges the given credentials for an access token, using the flow that matches them
+func (csp *TokenServices) Authenticate(ctx context.Context, credentials *Credentials) (*Claims, error) {
+	if credentials.IsLogin() {
+		return csp.RedeemLogin(ctx, credentials.ClientID, credentials.RefreshToken)
+	}
+	if credentials.IsClientCredentials() {
+		return csp.RedeemClientCredentials(ctx, credentials.ClientID, credentials.ClientSecret)
+	}
+	return csp.Redeem(ctx, credentials.APIToken)
+}
+
+// RedeemClientCredentials exchanges the ID and secret of a CSP OAuth app for an access token
+func (csp *TokenServices) RedeemClientCredentials(ctx context.Context, clientID, clientSecret string) (*Claims, error) {
+	requestURL := pkg.MakeURL(csp.CSPHost, "/csp/gateway/am/api/auth/authorize", nil)
+	formData := url.Values{
+		"grant_type": []string{"client_credentials"},
+	}
+	headers := map[string]string{
+		"Authorization": "Basic " + base64.StdEncoding.EncodeToString([]byte(clientID+":"+clientSecret)),
+		"Content-Type":  "application/x-www-form-urlencoded",
+	}
+
+	resp, err := csp.Client.SendRequest(ctx, "POST", requestURL, headers, strings.NewReader(formData.Encode()))
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse redeem response: %w", err)
+		return nil, fmt.Errorf("failed to redeem client credentials: %w", err)
 	}
 
-	claims := &Claims{}
-	_, _ = jwt.ParseWithClaims(body.AccessToken, claims, func(t *jwt.Token) (interface{}, error) {
-		// token was just retrieved, no need to validate
-		return "not a valid key anyway", nil
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to exchange client credentials for access token: %s", resp.Status)
+	}
+
+	return csp.parseRedeemResponse(resp)
+}
+
+// RedeemLogin exchanges the refresh token from logging in with a web browser for an access token
+func (csp *TokenServices) RedeemLogin(ctx context.Context, clientID, refreshToken string) (*Claims, error) {
+	requestURL := pkg.MakeURL(csp.CSPHost, "/csp/gateway/am/api/auth/authorize", nil)
+	resp, err := csp.Client.PostForm(ctx, requestURL, url.Values{
+		"grant_type":    []string{"refresh_token"},
+		"refresh_token": []string{refreshToken},
+		"client_id":     []string{clientID},
 	})
-	// err != nil here are the token validation has failed
+	if err != nil {
+		return nil, fmt.Errorf("failed to redeem login: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to exchange login refresh token for access token: %s", resp.Status)
+	}
+
+	return csp.parseRedeemResponse(resp)
+}
+
+func (csp *TokenServices) parseRedeemResponse(resp *http.Response) (*Claims, error) {
+	var body RedeemResponse
+	err := json.NewDecoder(resp.Body).Decode(&body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse redeem response: %w", err)
+	}
 
-	claims.Token = body.AccessToken
+	claims, err := csp.Validate(body.AccessToken)
+	if err != nil {
+		return nil, fmt.Errorf("the access token returned by CSP failed verification: %w", err)
+	}
+	claims.RefreshToken = body.RefreshToken
 	return claims, nil
 }
 
+// Validate checks the access token's signature against the CSP public key, and checks its expiry and issuer
 func (csp *TokenServices) Validate(jwtAccessToken string) (*Claims, error) {
 	claims := &Claims{}
 	_, err := jwt.ParseWithClaims(jwtAccessToken, claims, csp.keyfunc)
-	return claims, err
+	if err != nil {
+		return nil, err
+	}
+
+	if !claims.VerifyExpiresAt(time.Now().Unix(), true) {
+		return nil, errors.New("token does not have an expiry")
+	}
+
+	if csp.Issuer != "" {
+		if !claims.VerifyIssuer(csp.Issuer, true) {
+			return nil, fmt.Errorf("token was issued by \"%s\", expected \"%s\"", claims.Issuer, csp.Issuer)
+		}
+	} else if claims.Issuer == "" {
+		return nil, errors.New("token does not have an issuer")
+	}
+
+	claims.Token = jwtAccessToken
+	return claims, nil
 }
 
 func (csp *TokenServices) VerificationKey() string {
 	return csp.keyPem
 }
 
-func NewTokenServices(cspHost string, client pkg.HTTPClient) (*TokenServices, error) {
-	keyData, err := fetchPublicKey(cspHost, client)
+func NewTokenServices(ctx context.Context, cspHost string, client pkg.HTTPClient) (*TokenServices, error) {
+	keyData, err := fetchPublicKey(ctx, cspHost, client)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewTokenServicesWithKey(cspHost, client, keyData)
+}
+
+// NewTokenServicesWithPinnedKey fetches the CSP public key, and fails if it is not the same as the pinned key
+func NewTokenServicesWithPinnedKey(ctx context.Context, cspHost string, client pkg.HTTPClient, pinnedKeyData []byte) (*TokenServices, error) {
+	pinnedKey, err := jwt.ParseRSAPublicKeyFromPEM(pinnedKeyData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the pinned CSP public key: %w", err)
+	}
+
+	tokenServices, err := NewTokenServices(ctx, cspHost, client)
 	if err != nil {
 		return nil, err
 	}
 
+	fetchedKey, err := jwt.ParseRSAPublicKeyFromPEM([]byte(tokenServices.keyPem))
+	if err != nil {
+		return nil, fmt.Errorf("failed to make public key structure: %w", err)
+	}
+	if !pinnedKey.Equal(fetchedKey) {
+		return nil, fmt.Errorf("the public key from %s does not match the pinned CSP public key", cspHost)
+	}
+
+	return tokenServices, nil
+}
+
+// NewTokenServicesWithKey uses the given public key, rather than fetching it from CSP
+func NewTokenServicesWithKey(cspHost string, client pkg.HTTPClient, keyData []byte) (*TokenServices, error) {
 	publicKey, err := jwt.ParseRSAPublicKeyFromPEM(keyData)
 	if err != nil {
 		return nil, fmt.Errorf("failed to make public key structure: %w", err)
 	}
 
-	rsa := func(*jwt.Token) (interface{}, error) {
+	rsa := func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
 		return publicKey, nil
 	}
 
@@ -101,8 +211,8 @@ func NewTokenServices(cspHost string, client pkg.HTTPClient) (*TokenServices, er
 	}, nil
 }
 
-func fetchPublicKey(cspHost string, client pkg.HTTPClient) ([]byte, error) {
-	resp, err := client.Get(pkg.MakeURL(cspHost, "/csp/gateway/am/api/auth/token-public-key", nil))
+func fetchPublicKey(ctx context.Context, cspHost string, client pkg.HTTPClient) ([]byte, error) {
+	resp, err := client.Get(ctx, pkg.MakeURL(cspHost, "/csp/gateway/am/api/auth/token-public-key", nil))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get CSP Public key: %w", err)
 	}
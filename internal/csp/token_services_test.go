@@ -0,0 +1,277 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: BSD-2-Clause
+
+package csp_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/csp"
+	"github.com/vmware-labs/marketplace-cli/v2/pkg/pkgfakes"
+)
+
+func makeKey() (*rsa.PrivateKey, []byte) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	Expect(err).ToNot(HaveOccurred())
+
+	publicKeyBytes, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	Expect(err).ToNot(HaveOccurred())
+	return key, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKeyBytes})
+}
+
+func makeSignedToken(key *rsa.PrivateKey, issuer string, expiresAt time.Time) string {
+	claims := &csp.Claims{
+		StandardClaims: jwt.StandardClaims{
+			ExpiresAt: expiresAt.Unix(),
+			Issuer:    issuer,
+		},
+		Username: "alice@example.com",
+		Context:  "my-org-id",
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodRS256, claims).SignedString(key)
+	Expect(err).ToNot(HaveOccurred())
+	return token
+}
+
+func makeJSONResponse(body interface{}) *http.Response {
+	encoded, err := json.Marshal(body)
+	Expect(err).ToNot(HaveOccurred())
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       ioutil.NopCloser(bytes.NewReader(encoded)),
+	}
+}
+
+var _ = Describe("TokenServices", func() {
+	var (
+		httpClient *pkgfakes.FakeHTTPClient
+		key        *rsa.PrivateKey
+		keyPem     []byte
+	)
+
+	BeforeEach(func() {
+		key, keyPem = makeKey()
+		httpClient = &pkgfakes.FakeHTTPClient{}
+		httpClient.GetReturns(makeJSONResponse(map[string]interface{}{
+			"value": string(keyPem),
+		}), nil)
+	})
+
+	Describe("NewTokenServices", func() {
+		It("fetches the public key", func() {
+			tokenServices, err := csp.NewTokenServices(context.Background(), "console.cloud.vmware.example", httpClient)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(tokenServices.VerificationKey()).To(Equal(string(keyPem)))
+
+			Expect(httpClient.GetCallCount()).To(Equal(1))
+			_, url := httpClient.GetArgsForCall(0)
+			Expect(url.Host).To(Equal("console.cloud.vmware.example"))
+			Expect(url.Path).To(Equal("/csp/gateway/am/api/auth/token-public-key"))
+		})
+	})
+
+	Describe("NewTokenServicesWithKey", func() {
+		It("does not fetch the public key", func() {
+			tokenServices, err := csp.NewTokenServicesWithKey("console.cloud.vmware.example", httpClient, keyPem)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(tokenServices.VerificationKey()).To(Equal(string(keyPem)))
+			Expect(httpClient.GetCallCount()).To(Equal(0))
+		})
+	})
+
+	Describe("NewTokenServicesWithPinnedKey", func() {
+		It("fetches the public key and checks it against the pinned key", func() {
+			_, err := csp.NewTokenServicesWithPinnedKey(context.Background(), "console.cloud.vmware.example", httpClient, keyPem)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(httpClient.GetCallCount()).To(Equal(1))
+		})
+
+		When("the fetched key does not match", func() {
+			It("returns an error", func() {
+				_, otherKeyPem := makeKey()
+				_, err := csp.NewTokenServicesWithPinnedKey(context.Background(), "console.cloud.vmware.example", httpClient, otherKeyPem)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("the public key from console.cloud.vmware.example does not match the pinned CSP public key"))
+			})
+		})
+	})
+
+	Describe("Redeem", func() {
+		var tokenServices *csp.TokenServices
+
+		BeforeEach(func() {
+			var err error
+			tokenServices, err = csp.NewTokenServicesWithKey("console.cloud.vmware.example", httpClient, keyPem)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("exchanges the api token and verifies the access token", func() {
+			accessToken := makeSignedToken(key, "https://csp.example.com", time.Now().Add(30*time.Minute))
+			httpClient.PostFormReturns(makeJSONResponse(&csp.RedeemResponse{AccessToken: accessToken}), nil)
+
+			claims, err := tokenServices.Redeem(context.Background(), "my-api-token")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(claims.Token).To(Equal(accessToken))
+			Expect(claims.Username).To(Equal("alice@example.com"))
+			Expect(claims.Context).To(Equal("my-org-id"))
+
+			Expect(httpClient.PostFormCallCount()).To(Equal(1))
+			_, url, form := httpClient.PostFormArgsForCall(0)
+			Expect(url.Path).To(Equal("/csp/gateway/am/api/auth/api-tokens/authorize"))
+			Expect(form.Get("refresh_token")).To(Equal("my-api-token"))
+		})
+
+		When("the access token is signed by a different key", func() {
+			It("returns an error", func() {
+				otherKey, _ := makeKey()
+				accessToken := makeSignedToken(otherKey, "https://csp.example.com", time.Now().Add(30*time.Minute))
+				httpClient.PostFormReturns(makeJSONResponse(&csp.RedeemResponse{AccessToken: accessToken}), nil)
+
+				_, err := tokenServices.Redeem(context.Background(), "my-api-token")
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("the access token returned by CSP failed verification: crypto/rsa: verification error"))
+			})
+		})
+
+		When("the access token has expired", func() {
+			It("returns an error", func() {
+				accessToken := makeSignedToken(key, "https://csp.example.com", time.Now().Add(-time.Minute))
+				httpClient.PostFormReturns(makeJSONResponse(&csp.RedeemResponse{AccessToken: accessToken}), nil)
+
+				_, err := tokenServices.Redeem(context.Background(), "my-api-token")
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(HavePrefix("the access token returned by CSP failed verification: token is expired"))
+			})
+		})
+
+		When("the access token does not have an issuer", func() {
+			It("returns an error", func() {
+				accessToken := makeSignedToken(key, "", time.Now().Add(30*time.Minute))
+				httpClient.PostFormReturns(makeJSONResponse(&csp.RedeemResponse{AccessToken: accessToken}), nil)
+
+				_, err := tokenServices.Redeem(context.Background(), "my-api-token")
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("the access token returned by CSP failed verification: token does not have an issuer"))
+			})
+		})
+
+		When("an issuer is expected", func() {
+			BeforeEach(func() {
+				tokenServices.Issuer = "https://csp.example.com"
+			})
+
+			It("returns an error if the access token has a different issuer", func() {
+				accessToken := makeSignedToken(key, "https://evil.example.com", time.Now().Add(30*time.Minute))
+				httpClient.PostFormReturns(makeJSONResponse(&csp.RedeemResponse{AccessToken: accessToken}), nil)
+
+				_, err := tokenServices.Redeem(context.Background(), "my-api-token")
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("the access token returned by CSP failed verification: token was issued by \"https://evil.example.com\", expected \"https://csp.example.com\""))
+			})
+		})
+
+		When("CSP is temporarily unavailable", func() {
+			It("tries again", func() {
+				accessToken := makeSignedToken(key, "https://csp.example.com", time.Now().Add(30*time.Minute))
+				httpClient.PostFormReturnsOnCall(0, &http.Response{StatusCode: http.StatusServiceUnavailable, Status: "503 Service Unavailable"}, nil)
+				httpClient.PostFormReturnsOnCall(1, makeJSONResponse(&csp.RedeemResponse{AccessToken: accessToken}), nil)
+
+				claims, err := tokenServices.Redeem(context.Background(), "my-api-token")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(claims.Token).To(Equal(accessToken))
+				Expect(httpClient.PostFormCallCount()).To(Equal(2))
+			})
+		})
+	})
+
+	Describe("Authenticate", func() {
+		var (
+			tokenServices *csp.TokenServices
+			accessToken   string
+		)
+
+		BeforeEach(func() {
+			var err error
+			tokenServices, err = csp.NewTokenServicesWithKey("console.cloud.vmware.example", httpClient, keyPem)
+			Expect(err).ToNot(HaveOccurred())
+
+			accessToken = makeSignedToken(key, "https://csp.example.com", time.Now().Add(30*time.Minute))
+			httpClient.PostFormReturns(makeJSONResponse(&csp.RedeemResponse{AccessToken: accessToken}), nil)
+			httpClient.SendRequestReturns(makeJSONResponse(&csp.RedeemResponse{AccessToken: accessToken}), nil)
+		})
+
+		It("redeems an api token", func() {
+			claims, err := tokenServices.Authenticate(context.Background(), &csp.Credentials{APIToken: "my-api-token"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(claims.Token).To(Equal(accessToken))
+			Expect(httpClient.PostFormCallCount()).To(Equal(1))
+			Expect(httpClient.SendRequestCallCount()).To(Equal(0))
+		})
+
+		It("uses the client credentials grant for OAuth apps", func() {
+			claims, err := tokenServices.Authenticate(context.Background(), &csp.Credentials{
+				APIToken:     "my-api-token",
+				ClientID:     "my-client-id",
+				ClientSecret: "my-client-secret",
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(claims.Token).To(Equal(accessToken))
+			Expect(httpClient.PostFormCallCount()).To(Equal(0))
+
+			Expect(httpClient.SendRequestCallCount()).To(Equal(1))
+			_, method, url, headers, content := httpClient.SendRequestArgsForCall(0)
+			Expect(method).To(Equal("POST"))
+			Expect(url.Path).To(Equal("/csp/gateway/am/api/auth/authorize"))
+			Expect(headers["Authorization"]).To(Equal("Basic bXktY2xpZW50LWlkOm15LWNsaWVudC1zZWNyZXQ="))
+			Expect(headers["Content-Type"]).To(Equal("application/x-www-form-urlencoded"))
+			Expect(io.ReadAll(content)).To(Equal([]byte("grant_type=client_credentials")))
+		})
+
+		It("uses the refresh token grant for logins", func() {
+			httpClient.PostFormReturns(makeJSONResponse(&csp.RedeemResponse{
+				AccessToken:  accessToken,
+				RefreshToken: "my-new-refresh-token",
+			}), nil)
+
+			claims, err := tokenServices.Authenticate(context.Background(), &csp.Credentials{
+				ClientID:     "my-client-id",
+				RefreshToken: "my-refresh-token",
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(claims.Token).To(Equal(accessToken))
+			Expect(claims.RefreshToken).To(Equal("my-new-refresh-token"))
+
+			Expect(httpClient.PostFormCallCount()).To(Equal(1))
+			_, url, form := httpClient.PostFormArgsForCall(0)
+			Expect(url.Path).To(Equal("/csp/gateway/am/api/auth/authorize"))
+			Expect(form.Get("grant_type")).To(Equal("refresh_token"))
+			Expect(form.Get("refresh_token")).To(Equal("my-refresh-token"))
+			Expect(form.Get("client_id")).To(Equal("my-client-id"))
+		})
+
+		When("the client credentials are rejected", func() {
+			It("returns an error", func() {
+				httpClient.SendRequestReturns(&http.Response{StatusCode: http.StatusUnauthorized, Status: "401 Unauthorized"}, nil)
+				_, err := tokenServices.Authenticate(context.Background(), &csp.Credentials{
+					ClientID:     "my-client-id",
+					ClientSecret: "wrong-secret",
+				})
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("failed to exchange client credentials for access token: 401 Unauthorized"))
+			})
+		})
+	})
+})
@@ -0,0 +1,322 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: BSD-2-Clause
+
+package diff
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/vmware-labs/marketplace-cli/v2/internal/models"
+	"github.com/vmware-labs/marketplace-cli/v2/pkg"
+)
+
+const (
+	ChangeAdded   = "added"
+	ChangeRemoved = "removed"
+	ChangeChanged = "changed"
+)
+
+const (
+	FieldSolutionType           = "Solution type"
+	FieldEULAURL                = "EULA URL"
+	FieldEULAText               = "EULA text"
+	FieldEULAVersion            = "EULA version"
+	FieldOSLURL                 = "OSL URL"
+	FieldOSLSourceCodeURL       = "OSL source code URL"
+	FieldPCAURL                 = "PCA URL"
+	FieldPCAVersion             = "PCA version"
+	FieldExportECCN             = "Export compliance ECCN"
+	FieldExportHTSNumber        = "Export compliance HTS number"
+	FieldExportLicenseException = "Export compliance license exception"
+	FieldExportCCATSNumber      = "Export compliance CCATS number"
+	FieldExportCCATSDocument    = "Export compliance CCATS document"
+	FieldEncryption             = "Encryption"
+	FieldNonstandardEncryption  = "Nonstandard encryption"
+	FieldCompatibility          = "Compatibility"
+)
+
+// Side is one of the two product versions being compared
+type Side struct {
+	Host    string `json:"host,omitempty"`
+	Product string `json:"product"`
+	Version string `json:"version"`
+}
+
+func (s *Side) String() string {
+	if s.Host == "" {
+		return fmt.Sprintf("%s %s", s.Product, s.Version)
+	}
+	return fmt.Sprintf("%s %s on %s", s.Product, s.Version, s.Host)
+}
+
+// AssetChange is an asset that was added, removed or changed. Changed assets list what is different about them.
+type AssetChange struct {
+	Change      string     `json:"change"`
+	Type        string     `json:"type"`
+	Name        string     `json:"name"`
+	Differences []string   `json:"differences,omitempty"`
+	From        *pkg.Asset `json:"from,omitempty"`
+	To          *pkg.Asset `json:"to,omitempty"`
+}
+
+// DetailChange is a difference in the legal, compliance or compatibility details of a version
+type DetailChange struct {
+	Change string `json:"change"`
+	Field  string `json:"field"`
+	From   string `json:"from,omitempty"`
+	To     string `json:"to,omitempty"`
+}
+
+type ProductDiff struct {
+	From    *Side           `json:"from"`
+	To      *Side           `json:"to"`
+	Assets  []*AssetChange  `json:"assets"`
+	Details []*DetailChange `json:"details"`
+}
+
+func (d *ProductDiff) HasChanges() bool {
+	return len(d.Assets) > 0 || len(d.Details) > 0
+}
+
+// Compare finds what changed from one product version to another. Both products need their version specific details,
+// as returned by GetProductWithVersion.
+func Compare(from *Side, fromProduct *models.Product, to *Side, toProduct *models.Product) *ProductDiff {
+	return &ProductDiff{
+		From:    from,
+		To:      to,
+		Assets:  compareAssets(pkg.GetAssets(fromProduct, from.Version), pkg.GetAssets(toProduct, to.Version)),
+		Details: compareDetails(fromProduct, toProduct),
+	}
+}
+
+// assetKeys identifies the same asset in both versions. Charts and container images usually have a new version in
+// each product version, so they are matched by their chart name or repository instead of their version. Other assets
+// are matched by their name. Assets with the same key are matched in order.
+func assetKeys(assets []*pkg.Asset) []string {
+	counts := map[string]int{}
+	keys := make([]string, len(assets))
+	for index, asset := range assets {
+		name := asset.DisplayName
+		switch asset.Type {
+		case pkg.AssetTypeChart:
+			name = chartName(asset)
+		case pkg.AssetTypeContainerImage:
+			name = strings.TrimSuffix(asset.DisplayName, ":"+asset.Version)
+		}
+
+		key := asset.Type + "/" + name
+		counts[key]++
+		if counts[key] > 1 {
+			key += "#" + strconv.Itoa(counts[key])
+		}
+		keys[index] = key
+	}
+	return keys
+}
+
+// chartName is the name of a chart, taken from the file name of its URL. Helm packages charts as <name>-<version>.tgz.
+// Only the file name is used, because uploaded charts are stored in a different folder for each upload.
+func chartName(asset *pkg.Asset) string {
+	filename := asset.DisplayName
+	if chartURL, err := url.Parse(asset.DisplayName); err == nil {
+		filename = path.Base(chartURL.Path)
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(filename, ".tgz"), "-"+asset.Version)
+}
+
+func compareAssets(fromAssets, toAssets []*pkg.Asset) []*AssetChange {
+	changes := []*AssetChange{}
+
+	toByKey := map[string]*pkg.Asset{}
+	for index, key := range assetKeys(toAssets) {
+		toByKey[key] = toAssets[index]
+	}
+
+	matched := map[string]bool{}
+	for index, key := range assetKeys(fromAssets) {
+		from := fromAssets[index]
+		to, ok := toByKey[key]
+		if !ok {
+			changes = append(changes, &AssetChange{Change: ChangeRemoved, Type: from.Type, Name: from.DisplayName, From: from})
+			continue
+		}
+		matched[key] = true
+
+		if differences := assetDifferences(from, to); len(differences) > 0 {
+			changes = append(changes, &AssetChange{
+				Change:      ChangeChanged,
+				Type:        to.Type,
+				Name:        to.DisplayName,
+				Differences: differences,
+				From:        from,
+				To:          to,
+			})
+		}
+	}
+
+	for index, key := range assetKeys(toAssets) {
+		if !matched[key] {
+			to := toAssets[index]
+			changes = append(changes, &AssetChange{Change: ChangeAdded, Type: to.Type, Name: to.DisplayName, To: to})
+		}
+	}
+	return changes
+}
+
+func assetDifferences(from, to *pkg.Asset) []string {
+	var differences []string
+	compare := func(name, fromValue, toValue string) {
+		if fromValue != toValue {
+			differences = append(differences, fmt.Sprintf("%s: %s => %s", name, displayValue(fromValue), displayValue(toValue)))
+		}
+	}
+	compare("name", from.DisplayName, to.DisplayName)
+	// Other files and VM files take the version of the product, so only charts, images and metafiles have their own
+	if from.Type != pkg.AssetTypeOther && from.Type != pkg.AssetTypeVM {
+		compare("version", from.Version, to.Version)
+	}
+	compare("size", strconv.FormatInt(from.Size, 10), strconv.FormatInt(to.Size, 10))
+	compare("status", from.Status, to.Status)
+	compare("downloadable", strconv.FormatBool(from.Downloadable), strconv.FormatBool(to.Downloadable))
+	compare("error", from.Error, to.Error)
+	return differences
+}
+
+func displayValue(value string) string {
+	if value == "" {
+		return "(none)"
+	}
+	return value
+}
+
+type detail struct {
+	field string
+	value string
+}
+
+// details are the version specific details to compare, as text
+func details(product *models.Product) []*detail {
+	eula := product.EulaDetails
+	if eula == nil {
+		eula = &models.EULADetails{}
+	}
+	eulaURL := eula.Url
+	if eulaURL == "" {
+		eulaURL = product.EulaURL
+	}
+	osl := product.OpenSourceDisclosure
+	if osl == nil {
+		osl = &models.OpenSourceDisclosureURLS{}
+	}
+	pca := product.PCADetails
+	if pca == nil {
+		pca = &models.PCADetail{}
+	}
+	export := product.ExportCompliance
+	if export == nil {
+		export = &models.ProductExportCompliance{}
+	}
+
+	var encryption []string
+	nonstandardEncryption := ""
+	if product.EncryptionDetails != nil {
+		encryption = append(encryption, product.EncryptionDetails.List...)
+		sort.Strings(encryption)
+		if product.EncryptionDetails.NonstandardEncryption {
+			nonstandardEncryption = "yes"
+		}
+	}
+
+	return []*detail{
+		{field: FieldSolutionType, value: product.SolutionType},
+		{field: FieldEULAURL, value: eulaURL},
+		{field: FieldEULAText, value: eula.Text},
+		{field: FieldEULAVersion, value: eula.Version},
+		{field: FieldOSLURL, value: osl.LicenseDisclosureURL},
+		{field: FieldOSLSourceCodeURL, value: osl.SourceCodePackageURL},
+		{field: FieldPCAURL, value: pca.URL},
+		{field: FieldPCAVersion, value: pca.Version},
+		{field: FieldExportECCN, value: export.Eccn},
+		{field: FieldExportHTSNumber, value: export.HtsNumber},
+		{field: FieldExportLicenseException, value: export.LicenseException},
+		{field: FieldExportCCATSNumber, value: export.CcatsNumber},
+		{field: FieldExportCCATSDocument, value: export.CcatsDocumentUrl},
+		{field: FieldEncryption, value: strings.Join(encryption, ", ")},
+		{field: FieldNonstandardEncryption, value: nonstandardEncryption},
+	}
+}
+
+func detailChange(field, from, to string) *DetailChange {
+	change := &DetailChange{Change: ChangeChanged, Field: field, From: from, To: to}
+	if from == "" {
+		change.Change = ChangeAdded
+	} else if to == "" {
+		change.Change = ChangeRemoved
+	}
+	return change
+}
+
+func compareDetails(fromProduct, toProduct *models.Product) []*DetailChange {
+	changes := []*DetailChange{}
+	fromDetails := details(fromProduct)
+	toDetails := details(toProduct)
+	for index, from := range fromDetails {
+		if from.value != toDetails[index].value {
+			changes = append(changes, detailChange(from.field, from.value, toDetails[index].value))
+		}
+	}
+
+	// Each compatible product is added or removed on its own
+	fromCompatibility := compatibility(fromProduct)
+	toCompatibility := compatibility(toProduct)
+	for _, entry := range fromCompatibility {
+		if !contains(toCompatibility, entry) {
+			changes = append(changes, detailChange(FieldCompatibility, entry, ""))
+		}
+	}
+	for _, entry := range toCompatibility {
+		if !contains(fromCompatibility, entry) {
+			changes = append(changes, detailChange(FieldCompatibility, "", entry))
+		}
+	}
+	return changes
+}
+
+func compatibility(product *models.Product) []string {
+	var entries []string
+	for _, matrix := range product.CompatibilityMatrix {
+		var parts []string
+		for _, part := range []string{
+			matrix.VmwareProductName,
+			matrix.PartnerProd,
+			matrix.PartnerProdVer,
+			matrix.ThirdPartyCompany,
+			matrix.ThirdPartyProd,
+			matrix.ThirdPartyVer,
+			matrix.SupportStatement,
+		} {
+			if part != "" {
+				parts = append(parts, part)
+			}
+		}
+		if len(parts) > 0 {
+			entries = append(entries, strings.Join(parts, " / "))
+		}
+	}
+	sort.Strings(entries)
+	return entries
+}
+
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,179 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: BSD-2-Clause
+
+package diff_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/diff"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/models"
+	"github.com/vmware-labs/marketplace-cli/v2/pkg"
+	"github.com/vmware-labs/marketplace-cli/v2/test"
+)
+
+var _ = Describe("Compare", func() {
+	var (
+		fromProduct *models.Product
+		toProduct   *models.Product
+		from        *diff.Side
+		to          *diff.Side
+	)
+
+	BeforeEach(func() {
+		fromProduct = test.CreateFakeProduct("", "My Super Product", "my-super-product", models.SolutionTypeImage)
+		test.AddVerions(fromProduct, "1.0.0")
+		test.AddContainerImages(fromProduct, "1.0.0", "docker run it",
+			test.CreateFakeContainerImage("nginx", "1.0"),
+			test.CreateFakeContainerImage("redis", "6.2"),
+		)
+		fromProduct.AddOnFiles = []*models.AddOnFile{test.CreateFakeOtherFile("notes.txt", "1.0.0")}
+
+		toProduct = test.CreateFakeProduct(fromProduct.ProductId, "My Super Product", "my-super-product", models.SolutionTypeImage)
+		test.AddVerions(toProduct, "1.0.0", "1.1.0")
+		test.AddContainerImages(toProduct, "1.1.0", "docker run it",
+			test.CreateFakeContainerImage("nginx", "1.1"),
+			test.CreateFakeContainerImage("postgres", "14"),
+		)
+		toProduct.AddOnFiles = []*models.AddOnFile{test.CreateFakeOtherFile("notes.txt", "1.1.0")}
+
+		from = &diff.Side{Product: "my-super-product", Version: "1.0.0"}
+		to = &diff.Side{Product: "my-super-product", Version: "1.1.0"}
+	})
+
+	It("finds the added, removed and changed assets", func() {
+		productDiff := diff.Compare(from, fromProduct, to, toProduct)
+		Expect(productDiff.From).To(Equal(from))
+		Expect(productDiff.To).To(Equal(to))
+		Expect(productDiff.Details).To(BeEmpty())
+		Expect(productDiff.HasChanges()).To(BeTrue())
+
+		Expect(productDiff.Assets).To(HaveLen(3))
+		Expect(productDiff.Assets[0].Change).To(Equal(diff.ChangeChanged))
+		Expect(productDiff.Assets[0].Type).To(Equal(pkg.AssetTypeContainerImage))
+		Expect(productDiff.Assets[0].Name).To(Equal("nginx:1.1"))
+		Expect(productDiff.Assets[0].Differences).To(Equal([]string{"name: nginx:1.0 => nginx:1.1", "version: 1.0 => 1.1"}))
+		Expect(productDiff.Assets[0].From.DisplayName).To(Equal("nginx:1.0"))
+		Expect(productDiff.Assets[0].To.DisplayName).To(Equal("nginx:1.1"))
+
+		Expect(productDiff.Assets[1].Change).To(Equal(diff.ChangeRemoved))
+		Expect(productDiff.Assets[1].Name).To(Equal("redis:6.2"))
+		Expect(productDiff.Assets[1].To).To(BeNil())
+
+		Expect(productDiff.Assets[2].Change).To(Equal(diff.ChangeAdded))
+		Expect(productDiff.Assets[2].Name).To(Equal("postgres:14"))
+		Expect(productDiff.Assets[2].From).To(BeNil())
+	})
+
+	When("an asset is different in size or status", func() {
+		BeforeEach(func() {
+			toProduct.AddOnFiles[0].Size = 200
+			toProduct.AddOnFiles[0].Status = models.DeploymentStatusInactive
+		})
+
+		It("lists the differences", func() {
+			productDiff := diff.Compare(from, fromProduct, to, toProduct)
+			Expect(productDiff.Assets).To(ContainElement(&diff.AssetChange{
+				Change:      diff.ChangeChanged,
+				Type:        pkg.AssetTypeOther,
+				Name:        "notes.txt",
+				Differences: []string{"size: 140 => 200", "status: ACTIVE => INACTIVE", "downloadable: true => false"},
+				From:        pkg.GetAssets(fromProduct, "1.0.0")[0],
+				To:          pkg.GetAssets(toProduct, "1.1.0")[0],
+			}))
+		})
+	})
+
+	When("the charts are listed in a different order", func() {
+		BeforeEach(func() {
+			fromProduct.ChartVersions = []*models.ChartVersion{
+				{AppVersion: "1.0.0", Version: "1.2.0", HelmTarUrl: "https://charts.example.com/hdb-1.2.0.tgz"},
+				{AppVersion: "1.0.0", Version: "0.1.0", HelmTarUrl: "https://charts.example.com/hdb-operator-0.1.0.tgz"},
+			}
+			toProduct.ChartVersions = []*models.ChartVersion{
+				{AppVersion: "1.1.0", Version: "0.2.0", HelmTarUrl: "https://charts.example.com/hdb-operator-0.2.0.tgz"},
+				{AppVersion: "1.1.0", Version: "1.3.0", HelmTarUrl: "https://charts.example.com/hdb-1.3.0.tgz"},
+			}
+		})
+
+		It("matches each chart by its name", func() {
+			productDiff := diff.Compare(from, fromProduct, to, toProduct)
+			var charts []*diff.AssetChange
+			for _, asset := range productDiff.Assets {
+				if asset.Type == pkg.AssetTypeChart {
+					charts = append(charts, asset)
+				}
+			}
+
+			Expect(charts).To(HaveLen(2))
+			Expect(charts[0].Change).To(Equal(diff.ChangeChanged))
+			Expect(charts[0].Differences).To(Equal([]string{
+				"name: https://charts.example.com/hdb-1.2.0.tgz => https://charts.example.com/hdb-1.3.0.tgz",
+				"version: 1.2.0 => 1.3.0",
+			}))
+			Expect(charts[1].Change).To(Equal(diff.ChangeChanged))
+			Expect(charts[1].Differences).To(Equal([]string{
+				"name: https://charts.example.com/hdb-operator-0.1.0.tgz => https://charts.example.com/hdb-operator-0.2.0.tgz",
+				"version: 0.1.0 => 0.2.0",
+			}))
+		})
+	})
+
+	When("the version details are different", func() {
+		BeforeEach(func() {
+			fromProduct.EulaDetails = &models.EULADetails{Text: "The old EULA"}
+			toProduct.EulaDetails = &models.EULADetails{Text: "The new EULA", Url: "https://example.com/eula.txt"}
+			toProduct.OpenSourceDisclosure = &models.OpenSourceDisclosureURLS{LicenseDisclosureURL: "https://example.com/osl.txt"}
+			fromProduct.PCADetails = &models.PCADetail{URL: "https://example.com/pca.pdf", Version: "1"}
+			toProduct.PCADetails = &models.PCADetail{URL: "https://example.com/pca.pdf", Version: "2"}
+			fromProduct.ExportCompliance = &models.ProductExportCompliance{Eccn: "5D992"}
+			toProduct.ExportCompliance = &models.ProductExportCompliance{Eccn: "5D002", LicenseException: "ENC"}
+			toProduct.EncryptionDetails = &models.ProductEncryptionDetails{
+				List:                  []string{"userAuthEncryption", "dataAtRestEncryption"},
+				NonstandardEncryption: true,
+			}
+			fromProduct.CompatibilityMatrix = []*models.CompatibilityMatrix{
+				{VmwareProductName: "vSphere", PartnerProdVer: "7.0"},
+			}
+			toProduct.CompatibilityMatrix = []*models.CompatibilityMatrix{
+				{VmwareProductName: "vSphere", PartnerProdVer: "8.0"},
+			}
+		})
+
+		It("lists each change", func() {
+			productDiff := diff.Compare(from, fromProduct, to, toProduct)
+			Expect(productDiff.Details).To(Equal([]*diff.DetailChange{
+				{Change: diff.ChangeAdded, Field: diff.FieldEULAURL, To: "https://example.com/eula.txt"},
+				{Change: diff.ChangeChanged, Field: diff.FieldEULAText, From: "The old EULA", To: "The new EULA"},
+				{Change: diff.ChangeAdded, Field: diff.FieldOSLURL, To: "https://example.com/osl.txt"},
+				{Change: diff.ChangeChanged, Field: diff.FieldPCAVersion, From: "1", To: "2"},
+				{Change: diff.ChangeChanged, Field: diff.FieldExportECCN, From: "5D992", To: "5D002"},
+				{Change: diff.ChangeAdded, Field: diff.FieldExportLicenseException, To: "ENC"},
+				{Change: diff.ChangeChanged, Field: diff.FieldEncryption, From: "userAuthEncryption", To: "dataAtRestEncryption, userAuthEncryption"},
+				{Change: diff.ChangeAdded, Field: diff.FieldNonstandardEncryption, To: "yes"},
+				{Change: diff.ChangeRemoved, Field: diff.FieldCompatibility, From: "vSphere / 7.0"},
+				{Change: diff.ChangeAdded, Field: diff.FieldCompatibility, To: "vSphere / 8.0"},
+			}))
+		})
+	})
+
+	When("both sides are the same", func() {
+		It("has no changes", func() {
+			productDiff := diff.Compare(from, fromProduct, from, fromProduct)
+			Expect(productDiff.Assets).To(BeEmpty())
+			Expect(productDiff.Details).To(BeEmpty())
+			Expect(productDiff.HasChanges()).To(BeFalse())
+		})
+	})
+
+	When("a version has no assets", func() {
+		It("lists every asset as removed", func() {
+			to.Version = "1.0.0"
+			productDiff := diff.Compare(from, fromProduct, to, toProduct)
+			Expect(productDiff.Assets).To(HaveLen(3))
+			for _, asset := range productDiff.Assets {
+				Expect(asset.Change).To(Equal(diff.ChangeRemoved))
+			}
+		})
+	})
+})
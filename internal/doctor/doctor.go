@@ -0,0 +1,69 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: BSD-2-Clause
+
+package doctor
+
+import "fmt"
+
+const (
+	StatusPass = "pass"
+	StatusFail = "fail"
+	StatusSkip = "skip"
+)
+
+// Check is one step of diagnosing the environment. Run returns a short description of what it found.
+type Check struct {
+	Name string
+	Run  func() (string, error)
+
+	// Requires names earlier checks that must pass before this one can run
+	Requires []string
+}
+
+type Result struct {
+	Name    string `json:"name" yaml:"name"`
+	Status  string `json:"status" yaml:"status"`
+	Message string `json:"message" yaml:"message"`
+}
+
+// Run runs the checks in order. A check is skipped if any check it requires did not pass.
+func Run(checks []*Check) []*Result {
+	var results []*Result
+	statuses := map[string]string{}
+
+	for _, check := range checks {
+		result := &Result{Name: check.Name}
+		for _, required := range check.Requires {
+			if statuses[required] != StatusPass {
+				result.Status = StatusSkip
+				result.Message = fmt.Sprintf("requires %s", required)
+				break
+			}
+		}
+
+		if result.Status == "" {
+			message, err := check.Run()
+			if err != nil {
+				result.Status = StatusFail
+				result.Message = err.Error()
+			} else {
+				result.Status = StatusPass
+				result.Message = message
+			}
+		}
+
+		statuses[check.Name] = result.Status
+		results = append(results, result)
+	}
+	return results
+}
+
+// Passed is true if none of the checks failed
+func Passed(results []*Result) bool {
+	for _, result := range results {
+		if result.Status == StatusFail {
+			return false
+		}
+	}
+	return true
+}
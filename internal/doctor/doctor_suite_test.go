@@ -0,0 +1,16 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: BSD-2-Clause
+
+package doctor_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestDoctorSuite(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Doctor test suite")
+}
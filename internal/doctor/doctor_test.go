@@ -0,0 +1,61 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: BSD-2-Clause
+
+package doctor_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/vmware-labs/marketplace-cli/v2/internal/doctor"
+)
+
+var _ = Describe("Run", func() {
+	pass := func(message string) func() (string, error) {
+		return func() (string, error) { return message, nil }
+	}
+	fail := func(message string) func() (string, error) {
+		return func() (string, error) { return "", errors.New(message) }
+	}
+
+	It("runs the checks in order", func() {
+		results := doctor.Run([]*doctor.Check{
+			{Name: "first", Run: pass("first passed")},
+			{Name: "second", Run: fail("second failed")},
+			{Name: "third", Run: pass("third passed")},
+		})
+
+		Expect(results).To(Equal([]*doctor.Result{
+			{Name: "first", Status: doctor.StatusPass, Message: "first passed"},
+			{Name: "second", Status: doctor.StatusFail, Message: "second failed"},
+			{Name: "third", Status: doctor.StatusPass, Message: "third passed"},
+		}))
+		Expect(doctor.Passed(results)).To(BeFalse())
+	})
+
+	It("skips checks that require a check that did not pass", func() {
+		ran := false
+		results := doctor.Run([]*doctor.Check{
+			{Name: "host", Run: fail("host is down")},
+			{Name: "token", Requires: []string{"host"}, Run: func() (string, error) {
+				ran = true
+				return "", nil
+			}},
+			{Name: "upload", Requires: []string{"token"}, Run: pass("")},
+		})
+
+		Expect(ran).To(BeFalse())
+		Expect(results[1]).To(Equal(&doctor.Result{Name: "token", Status: doctor.StatusSkip, Message: "requires host"}))
+		Expect(results[2]).To(Equal(&doctor.Result{Name: "upload", Status: doctor.StatusSkip, Message: "requires token"}))
+	})
+
+	It("passes when no checks fail", func() {
+		results := doctor.Run([]*doctor.Check{
+			{Name: "first", Run: pass("")},
+			{Name: "second", Requires: []string{"unknown"}, Run: pass("")},
+		})
+		Expect(doctor.Passed(results)).To(BeTrue())
+	})
+})
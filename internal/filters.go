@@ -0,0 +1,41 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: BSD-2-Clause
+
+package internal
+
+import (
+	"encoding/json"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// Filters narrows a list of products to the ones with any of the given values for each key
+type Filters map[string][]string
+
+func (f Filters) Add(key, value string) {
+	f[key] = append(f[key], value)
+}
+
+func (f Filters) QueryString() string {
+	encoded, err := json.Marshal(f)
+	if err != nil {
+		return ""
+	}
+	return "filters=" + url.QueryEscape(string(encoded))
+}
+
+// String describes the filters, like "category=Networking,Security publisher=VMware"
+func (f Filters) String() string {
+	keys := make([]string, 0, len(f))
+	for key := range f {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		parts = append(parts, key+"="+strings.Join(f[key], ","))
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,37 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: BSD-2-Clause
+
+package internal_test
+
+import (
+	"net/url"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/vmware-labs/marketplace-cli/v2/internal"
+)
+
+var _ = Describe("Filters", func() {
+	var filters internal.Filters
+
+	BeforeEach(func() {
+		filters = internal.Filters{}
+		filters.Add("categories", "Networking")
+		filters.Add("categories", "Security & Compliance")
+		filters.Add("publishers", "VMware")
+	})
+
+	Describe("QueryString", func() {
+		It("returns the filters as an encoded JSON query parameter", func() {
+			query, err := url.ParseQuery(filters.QueryString())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(query.Get("filters")).To(MatchJSON(`{"categories":["Networking","Security & Compliance"],"publishers":["VMware"]}`))
+		})
+	})
+
+	Describe("String", func() {
+		It("describes the filters", func() {
+			Expect(filters.String()).To(Equal("categories=Networking,Security & Compliance publishers=VMware"))
+		})
+	})
+})
@@ -2,16 +2,18 @@
 package internalfakes
 
 import (
+	"context"
 	"sync"
 
 	"github.com/vmware-labs/marketplace-cli/v2/internal"
 )
 
 type FakeUploader struct {
-	UploadMediaFileStub        func(string) (string, string, error)
+	UploadMediaFileStub        func(context.Context, string) (string, string, error)
 	uploadMediaFileMutex       sync.RWMutex
 	uploadMediaFileArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
+		arg2 string
 	}
 	uploadMediaFileReturns struct {
 		result1 string
@@ -23,10 +25,11 @@ type FakeUploader struct {
 		result2 string
 		result3 error
 	}
-	UploadMetaFileStub        func(string) (string, string, error)
+	UploadMetaFileStub        func(context.Context, string) (string, string, error)
 	uploadMetaFileMutex       sync.RWMutex
 	uploadMetaFileArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
+		arg2 string
 	}
 	uploadMetaFileReturns struct {
 		result1 string
@@ -38,10 +41,11 @@ type FakeUploader struct {
 		result2 string
 		result3 error
 	}
-	UploadProductFileStub        func(string) (string, string, error)
+	UploadProductFileStub        func(context.Context, string) (string, string, error)
 	uploadProductFileMutex       sync.RWMutex
 	uploadProductFileArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
+		arg2 string
 	}
 	uploadProductFileReturns struct {
 		result1 string
@@ -57,18 +61,19 @@ type FakeUploader struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeUploader) UploadMediaFile(arg1 string) (string, string, error) {
+func (fake *FakeUploader) UploadMediaFile(arg1 context.Context, arg2 string) (string, string, error) {
 	fake.uploadMediaFileMutex.Lock()
 	ret, specificReturn := fake.uploadMediaFileReturnsOnCall[len(fake.uploadMediaFileArgsForCall)]
 	fake.uploadMediaFileArgsForCall = append(fake.uploadMediaFileArgsForCall, struct {
-		arg1 string
-	}{arg1})
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
 	stub := fake.UploadMediaFileStub
 	fakeReturns := fake.uploadMediaFileReturns
-	fake.recordInvocation("UploadMediaFile", []interface{}{arg1})
+	fake.recordInvocation("UploadMediaFile", []interface{}{arg1, arg2})
 	fake.uploadMediaFileMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2, ret.result3
@@ -82,17 +87,17 @@ func (fake *FakeUploader) UploadMediaFileCallCount() int {
 	return len(fake.uploadMediaFileArgsForCall)
 }
 
-func (fake *FakeUploader) UploadMediaFileCalls(stub func(string) (string, string, error)) {
+func (fake *FakeUploader) UploadMediaFileCalls(stub func(context.Context, string) (string, string, error)) {
 	fake.uploadMediaFileMutex.Lock()
 	defer fake.uploadMediaFileMutex.Unlock()
 	fake.UploadMediaFileStub = stub
 }
 
-func (fake *FakeUploader) UploadMediaFileArgsForCall(i int) string {
+func (fake *FakeUploader) UploadMediaFileArgsForCall(i int) (context.Context, string) {
 	fake.uploadMediaFileMutex.RLock()
 	defer fake.uploadMediaFileMutex.RUnlock()
 	argsForCall := fake.uploadMediaFileArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeUploader) UploadMediaFileReturns(result1 string, result2 string, result3 error) {
@@ -124,18 +129,19 @@ func (fake *FakeUploader) UploadMediaFileReturnsOnCall(i int, result1 string, re
 	}{result1, result2, result3}
 }
 
-func (fake *FakeUploader) UploadMetaFile(arg1 string) (string, string, error) {
+func (fake *FakeUploader) UploadMetaFile(arg1 context.Context, arg2 string) (string, string, error) {
 	fake.uploadMetaFileMutex.Lock()
 	ret, specificReturn := fake.uploadMetaFileReturnsOnCall[len(fake.uploadMetaFileArgsForCall)]
 	fake.uploadMetaFileArgsForCall = append(fake.uploadMetaFileArgsForCall, struct {
-		arg1 string
-	}{arg1})
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
 	stub := fake.UploadMetaFileStub
 	fakeReturns := fake.uploadMetaFileReturns
-	fake.recordInvocation("UploadMetaFile", []interface{}{arg1})
+	fake.recordInvocation("UploadMetaFile", []interface{}{arg1, arg2})
 	fake.uploadMetaFileMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2, ret.result3
@@ -149,17 +155,17 @@ func (fake *FakeUploader) UploadMetaFileCallCount() int {
 	return len(fake.uploadMetaFileArgsForCall)
 }
 
-func (fake *FakeUploader) UploadMetaFileCalls(stub func(string) (string, string, error)) {
+func (fake *FakeUploader) UploadMetaFileCalls(stub func(context.Context, string) (string, string, error)) {
 	fake.uploadMetaFileMutex.Lock()
 	defer fake.uploadMetaFileMutex.Unlock()
 	fake.UploadMetaFileStub = stub
 }
 
-func (fake *FakeUploader) UploadMetaFileArgsForCall(i int) string {
+func (fake *FakeUploader) UploadMetaFileArgsForCall(i int) (context.Context, string) {
 	fake.uploadMetaFileMutex.RLock()
 	defer fake.uploadMetaFileMutex.RUnlock()
 	argsForCall := fake.uploadMetaFileArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeUploader) UploadMetaFileReturns(result1 string, result2 string, result3 error) {
@@ -191,18 +197,19 @@ func (fake *FakeUploader) UploadMetaFileReturnsOnCall(i int, result1 string, res
 	}{result1, result2, result3}
 }
 
-func (fake *FakeUploader) UploadProductFile(arg1 string) (string, string, error) {
+func (fake *FakeUploader) UploadProductFile(arg1 context.Context, arg2 string) (string, string, error) {
 	fake.uploadProductFileMutex.Lock()
 	ret, specificReturn := fake.uploadProductFileReturnsOnCall[len(fake.uploadProductFileArgsForCall)]
 	fake.uploadProductFileArgsForCall = append(fake.uploadProductFileArgsForCall, struct {
-		arg1 string
-	}{arg1})
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
 	stub := fake.UploadProductFileStub
 	fakeReturns := fake.uploadProductFileReturns
-	fake.recordInvocation("UploadProductFile", []interface{}{arg1})
+	fake.recordInvocation("UploadProductFile", []interface{}{arg1, arg2})
 	fake.uploadProductFileMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2, ret.result3
@@ -216,17 +223,17 @@ func (fake *FakeUploader) UploadProductFileCallCount() int {
 	return len(fake.uploadProductFileArgsForCall)
 }
 
-func (fake *FakeUploader) UploadProductFileCalls(stub func(string) (string, string, error)) {
+func (fake *FakeUploader) UploadProductFileCalls(stub func(context.Context, string) (string, string, error)) {
 	fake.uploadProductFileMutex.Lock()
 	defer fake.uploadProductFileMutex.Unlock()
 	fake.UploadProductFileStub = stub
 }
 
-func (fake *FakeUploader) UploadProductFileArgsForCall(i int) string {
+func (fake *FakeUploader) UploadProductFileArgsForCall(i int) (context.Context, string) {
 	fake.uploadProductFileMutex.RLock()
 	defer fake.uploadProductFileMutex.RUnlock()
 	argsForCall := fake.uploadProductFileArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeUploader) UploadProductFileReturns(result1 string, result2 string, result3 error) {
@@ -0,0 +1,80 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: BSD-2-Clause
+
+package spec
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/vmware-labs/marketplace-cli/v2/internal/models"
+	"github.com/vmware-labs/marketplace-cli/v2/pkg"
+)
+
+// Apply makes the changes in the plan, and returns the updated product. The product details are updated first, then
+// the assets are attached one at a time, like the attach commands do. Each change is written to progress as it starts.
+func (p *Plan) Apply(ctx context.Context, marketplace pkg.MarketplaceInterface, progress io.Writer) (*models.Product, error) {
+	var product *models.Product
+	if changes := p.metadataChanges(); len(changes) > 0 {
+		for _, change := range changes {
+			_, _ = fmt.Fprintf(progress, "Applying: %s\n", change)
+		}
+
+		current, err := marketplace.GetProduct(ctx, p.Product)
+		if err != nil {
+			return nil, err
+		}
+		current.PrepForUpdate()
+		p.spec.applyMetadata(current)
+		product, err = marketplace.PutProduct(ctx, current, false)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update the details of %s: %w", p.Product, err)
+		}
+	}
+
+	for _, versionSpec := range p.spec.Versions {
+		changes := p.versionChanges(versionSpec.Number)
+		for _, change := range changes {
+			_, _ = fmt.Fprintf(progress, "Applying: %s\n", change)
+
+			// A new version is created along with the first asset attached to it, if there are any
+			if change.Action == ActionCreate && len(changes) > 1 {
+				continue
+			}
+
+			current, version, err := p.getVersion(ctx, marketplace, versionSpec.Number)
+			if err != nil {
+				return nil, err
+			}
+
+			if change.Action == ActionCreate {
+				current.PrepForUpdate()
+				product, err = marketplace.PutProduct(ctx, current, version.IsNewVersion)
+			} else {
+				product, err = change.attach(ctx, marketplace, current, version)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to %s: %w", change, err)
+			}
+		}
+	}
+
+	if product == nil {
+		return marketplace.GetProduct(ctx, p.Product)
+	}
+	return product, nil
+}
+
+// getVersion gets the product with the details for the version, or a new version if it does not exist yet
+func (p *Plan) getVersion(ctx context.Context, marketplace pkg.MarketplaceInterface, number string) (*models.Product, *models.Version, error) {
+	product, version, err := marketplace.GetProductWithVersion(ctx, p.Product, number)
+	if err != nil {
+		if errors.Is(err, &pkg.VersionDoesNotExistError{}) && product != nil {
+			return product, product.NewVersion(number), nil
+		}
+		return nil, nil, err
+	}
+	return product, version, nil
+}
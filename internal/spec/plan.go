@@ -0,0 +1,429 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: BSD-2-Clause
+
+package spec
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/vmware-labs/marketplace-cli/v2/internal/models"
+	"github.com/vmware-labs/marketplace-cli/v2/pkg"
+)
+
+const (
+	ActionUpdate = "update"
+	ActionCreate = "create"
+	ActionAttach = "attach"
+)
+
+type attachFunc func(ctx context.Context, marketplace pkg.MarketplaceInterface, product *models.Product, version *models.Version) (*models.Product, error)
+
+// Change is one difference between the spec and the product
+type Change struct {
+	Action  string `json:"action"`
+	Version string `json:"version,omitempty"`
+	// Field is the product detail that changes, or the type of asset that is attached
+	Field string `json:"field"`
+	From  string `json:"from,omitempty"`
+	To    string `json:"to"`
+
+	attach attachFunc
+}
+
+func (c *Change) String() string {
+	switch c.Action {
+	case ActionUpdate:
+		return fmt.Sprintf("update %s: %s => %s", c.Field, quote(c.From), quote(c.To))
+	case ActionCreate:
+		return fmt.Sprintf("create version %s", c.Version)
+	default:
+		return fmt.Sprintf("attach %s %s to version %s", c.Field, c.To, c.Version)
+	}
+}
+
+func quote(value string) string {
+	if len(value) > 60 {
+		value = value[:57] + "..."
+	}
+	return fmt.Sprintf("%q", value)
+}
+
+// Plan is what needs to change to make the product match the spec
+type Plan struct {
+	Product string    `json:"product"`
+	Changes []*Change `json:"changes"`
+	// Unchanged is how many assets in the spec are already attached
+	Unchanged int `json:"unchanged"`
+
+	// Current is the product as it was when the plan was made
+	Current *models.Product `json:"-"`
+
+	spec *Spec
+}
+
+func (p *Plan) HasChanges() bool {
+	return len(p.Changes) > 0
+}
+
+func (p *Plan) metadataChanges() []*Change {
+	var changes []*Change
+	for _, change := range p.Changes {
+		if change.Action == ActionUpdate {
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+func (p *Plan) versionChanges(version string) []*Change {
+	var changes []*Change
+	for _, change := range p.Changes {
+		if change.Version == version {
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+// MakePlan compares the spec with the product in the Marketplace
+func MakePlan(ctx context.Context, marketplace pkg.MarketplaceInterface, spec *Spec) (*Plan, error) {
+	product, err := marketplace.GetProduct(ctx, spec.Product)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &Plan{
+		Product: spec.Product,
+		Changes: []*Change{},
+		Current: product,
+		spec:    spec,
+	}
+	plan.Changes = append(plan.Changes, spec.metadataChanges(product)...)
+
+	for _, versionSpec := range spec.Versions {
+		versionProduct := product
+		if product.HasVersion(versionSpec.Number) {
+			versionProduct, _, err = marketplace.GetProductWithVersion(ctx, spec.Product, versionSpec.Number)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			plan.Changes = append(plan.Changes, &Change{
+				Action:  ActionCreate,
+				Version: versionSpec.Number,
+				Field:   "version",
+				To:      versionSpec.Number,
+			})
+		}
+
+		changes, unchanged, err := versionSpec.changes(versionProduct)
+		if err != nil {
+			return nil, err
+		}
+		plan.Changes = append(plan.Changes, changes...)
+		plan.Unchanged += unchanged
+	}
+	return plan, nil
+}
+
+// applyMetadata changes the product's details to the ones in the spec
+func (s *Spec) applyMetadata(product *models.Product) {
+	if product.Description == nil {
+		product.Description = &models.Description{}
+	}
+	if s.Summary != "" {
+		product.Description.Summary = s.Summary
+	}
+	if s.Description != "" {
+		product.Description.Description = s.Description
+	}
+	if s.VideoURLs != nil {
+		product.Description.VideoUrls = s.VideoURLs
+	}
+	if s.Highlights != nil {
+		product.Highlights = s.Highlights
+	}
+	if s.Tags != nil {
+		product.Tags = s.Tags
+	}
+	if s.Categories != nil {
+		product.Categories = s.Categories
+	}
+
+	if s.hasSupport() {
+		if product.SupportDetails == nil {
+			product.SupportDetails = &models.SupportDetails{}
+		}
+		product.SupportAvailable = true
+		if s.Support.URL != "" {
+			product.SupportDetails.Url = s.Support.URL
+		}
+		if s.Support.Email != "" {
+			product.SupportDetails.Email = []string{s.Support.Email}
+		}
+		if s.Support.Phone != "" {
+			product.SupportDetails.PhoneNumber = []string{s.Support.Phone}
+		}
+	}
+
+	if s.Website != "" {
+		if product.MetaDetails == nil {
+			product.MetaDetails = &models.AppProductMetaDetails{}
+		}
+		product.MetaDetails.WebsiteURL = s.Website
+	}
+	if s.LogoURL != "" {
+		product.Logo = s.LogoURL
+		product.ProductLogo = &models.Logo{URL: s.LogoURL}
+	}
+}
+
+type metadataField struct {
+	name  string
+	value string
+}
+
+// metadataFields are the product details that a spec can change, as text to compare and show in the plan
+func metadataFields(product *models.Product) []*metadataField {
+	description := product.Description
+	if description == nil {
+		description = &models.Description{}
+	}
+	support := product.SupportDetails
+	if support == nil {
+		support = &models.SupportDetails{}
+	}
+	website := ""
+	if product.MetaDetails != nil {
+		website = product.MetaDetails.WebsiteURL
+	}
+	logo := product.Logo
+	if product.ProductLogo != nil && product.ProductLogo.URL != "" {
+		logo = product.ProductLogo.URL
+	}
+
+	return []*metadataField{
+		{name: "summary", value: description.Summary},
+		{name: "description", value: description.Description},
+		{name: "highlights", value: strings.Join(product.Highlights, ", ")},
+		{name: "tags", value: strings.Join(product.Tags, ", ")},
+		{name: "categories", value: strings.Join(product.Categories, ", ")},
+		{name: "video urls", value: strings.Join(description.VideoUrls, ", ")},
+		{name: "support url", value: support.Url},
+		{name: "support email", value: strings.Join(support.Email, ", ")},
+		{name: "support phone", value: strings.Join(support.PhoneNumber, ", ")},
+		{name: "website", value: website},
+		{name: "logo url", value: logo},
+	}
+}
+
+// metadataChanges applies the spec to a copy of the product, and compares the details before and after
+func (s *Spec) metadataChanges(product *models.Product) []*Change {
+	data, _ := json.Marshal(product)
+	updated := &models.Product{}
+	_ = json.Unmarshal(data, updated)
+	s.applyMetadata(updated)
+
+	before := metadataFields(product)
+	after := metadataFields(updated)
+	var changes []*Change
+	for index, field := range before {
+		if field.value != after[index].value {
+			changes = append(changes, &Change{
+				Action: ActionUpdate,
+				Field:  field.name,
+				From:   field.value,
+				To:     after[index].value,
+			})
+		}
+	}
+	return changes
+}
+
+func checkSolutionType(product *models.Product, asset string, solutionTypes ...string) error {
+	for _, solutionType := range solutionTypes {
+		if product.SolutionType == solutionType {
+			return nil
+		}
+	}
+	return fmt.Errorf("cannot attach %s to %s which is of type %s", asset, product.Slug, product.SolutionType)
+}
+
+// changes finds the assets in the version spec that are not attached yet, either by their hash or their name and version
+func (v *VersionSpec) changes(product *models.Product) ([]*Change, int, error) {
+	var changes []*Change
+	unchanged := 0
+	add := func(attached bool, change *Change) {
+		if attached {
+			unchanged++
+			return
+		}
+		change.Action = ActionAttach
+		change.Version = v.Number
+		changes = append(changes, change)
+	}
+
+	if len(v.Charts) > 0 {
+		if err := checkSolutionType(product, "a chart", models.SolutionTypeChart); err != nil {
+			return nil, 0, err
+		}
+	}
+	for _, chart := range v.Charts {
+		attached, change, err := v.chartChange(product, chart)
+		if err != nil {
+			return nil, 0, err
+		}
+		add(attached, change)
+	}
+
+	if len(v.ContainerImages) > 0 {
+		if err := checkSolutionType(product, "an image", models.SolutionTypeImage); err != nil {
+			return nil, 0, err
+		}
+	}
+	for _, image := range v.ContainerImages {
+		image := image
+		add(product.HasContainerImage(v.Number, image.Image, image.Tag), &Change{
+			Field: "container image",
+			To:    image.Image + ":" + image.Tag,
+			attach: func(ctx context.Context, marketplace pkg.MarketplaceInterface, product *models.Product, version *models.Version) (*models.Product, error) {
+				if image.File != "" {
+					return marketplace.AttachLocalContainerImage(ctx, image.File, image.Image, image.Tag, image.TagType, image.Instructions, product, version)
+				}
+				return marketplace.AttachPublicContainerImage(ctx, image.Image, image.Tag, image.TagType, image.Instructions, product, version)
+			},
+		})
+	}
+
+	if len(v.VMFiles) > 0 {
+		if err := checkSolutionType(product, "a vm", models.SolutionTypeISO, models.SolutionTypeOVA); err != nil {
+			return nil, 0, err
+		}
+	}
+	for _, file := range v.VMFiles {
+		file := file
+		var attachedHashes []string
+		for _, attached := range product.GetFilesForVersion(v.Number) {
+			attachedHashes = append(attachedHashes, attached.HashDigest)
+		}
+		attached, err := isAttached(file.File, attachedHashes)
+		if err != nil {
+			return nil, 0, err
+		}
+		add(attached, &Change{
+			Field: "vm file",
+			To:    file.File,
+			attach: func(ctx context.Context, marketplace pkg.MarketplaceInterface, product *models.Product, version *models.Version) (*models.Product, error) {
+				return marketplace.UploadVM(ctx, file.File, product, version)
+			},
+		})
+	}
+
+	for _, metafile := range v.MetaFiles {
+		metafile := metafile
+		var attachedHashes []string
+		for _, attached := range product.GetMetaFilesForVersion(v.Number) {
+			for _, object := range attached.Objects {
+				attachedHashes = append(attachedHashes, object.HashDigest)
+			}
+		}
+		attached, err := isAttached(metafile.File, attachedHashes)
+		if err != nil {
+			return nil, 0, err
+		}
+		add(attached, &Change{
+			Field: "metafile",
+			To:    metafile.File,
+			attach: func(ctx context.Context, marketplace pkg.MarketplaceInterface, product *models.Product, version *models.Version) (*models.Product, error) {
+				return marketplace.AttachMetaFile(ctx, metafile.File, metafile.Type, metafile.Version, product, version)
+			},
+		})
+	}
+
+	if len(v.OtherFiles) > 0 {
+		if err := checkSolutionType(product, "an other file", models.SolutionTypeOthers); err != nil {
+			return nil, 0, err
+		}
+	}
+	for _, file := range v.OtherFiles {
+		file := file
+		var attachedHashes []string
+		for _, attached := range product.AddOnFiles {
+			if attached.AppVersion == v.Number {
+				attachedHashes = append(attachedHashes, attached.HashDigest)
+			}
+		}
+		attached, err := isAttached(file.File, attachedHashes)
+		if err != nil {
+			return nil, 0, err
+		}
+		add(attached, &Change{
+			Field: "other file",
+			To:    file.File,
+			attach: func(ctx context.Context, marketplace pkg.MarketplaceInterface, product *models.Product, version *models.Version) (*models.Product, error) {
+				return marketplace.AttachOtherFile(ctx, file.File, product, version)
+			},
+		})
+	}
+	return changes, unchanged, nil
+}
+
+// chartChange matches public charts by their URL, and local charts by their name and chart version
+func (v *VersionSpec) chartChange(product *models.Product, chart *ChartSpec) (bool, *Change, error) {
+	attachedCharts := product.GetChartsForVersion(v.Number)
+
+	if isRemoteChart(chart.Chart) {
+		chartURL, err := url.Parse(chart.Chart)
+		if err != nil {
+			return false, nil, fmt.Errorf("failed to parse chart URL: %w", err)
+		}
+		attached := false
+		for _, attachedChart := range attachedCharts {
+			attached = attached || attachedChart.HelmTarUrl == chart.Chart
+		}
+		return attached, &Change{
+			Field: "chart",
+			To:    chart.Chart,
+			attach: func(ctx context.Context, marketplace pkg.MarketplaceInterface, product *models.Product, version *models.Version) (*models.Product, error) {
+				return marketplace.AttachPublicChart(ctx, chartURL, chart.Instructions, product, version)
+			},
+		}, nil
+	}
+
+	localChart, err := pkg.LoadChart(chart.Chart)
+	if err != nil {
+		return false, nil, err
+	}
+	attached := false
+	for _, attachedChart := range attachedCharts {
+		attached = attached || (attachedChart.Repo != nil &&
+			attachedChart.Repo.Name == localChart.Repo.Name &&
+			attachedChart.Version == localChart.Version)
+	}
+	return attached, &Change{
+		Field: "chart",
+		To:    chart.Chart,
+		attach: func(ctx context.Context, marketplace pkg.MarketplaceInterface, product *models.Product, version *models.Version) (*models.Product, error) {
+			return marketplace.AttachLocalChart(ctx, chart.Chart, chart.Instructions, product, version)
+		},
+	}, nil
+}
+
+// isAttached checks if a file with the same content is already attached
+func isAttached(file string, attachedHashes []string) (bool, error) {
+	hash, err := pkg.Hash(file, models.HashAlgoSHA1)
+	if err != nil {
+		return false, fmt.Errorf("failed to read %s: %w", filepath.Base(file), err)
+	}
+	for _, attachedHash := range attachedHashes {
+		if strings.EqualFold(attachedHash, hash) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,246 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: BSD-2-Clause
+
+package spec_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gbytes"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/models"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/spec"
+	"github.com/vmware-labs/marketplace-cli/v2/pkg"
+	"github.com/vmware-labs/marketplace-cli/v2/pkg/pkgfakes"
+	"github.com/vmware-labs/marketplace-cli/v2/test"
+)
+
+var _ = Describe("Plan", func() {
+	var (
+		dir         string
+		product     *models.Product
+		marketplace *pkgfakes.FakeMarketplaceInterface
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "mkpcli-plan-test")
+		Expect(err).ToNot(HaveOccurred())
+
+		product = test.CreateFakeProduct("", "My Super Product", "my-super-product", models.SolutionTypeImage)
+		product.Description = &models.Description{Summary: "The old summary"}
+		product.Tags = []string{"database"}
+		test.AddVerions(product, "1.2.3")
+		test.AddContainerImages(product, "1.2.3", "docker run it", test.CreateFakeContainerImage("my-image", "1.2.3"))
+
+		marketplace = &pkgfakes.FakeMarketplaceInterface{}
+		marketplace.GetProductReturns(product, nil)
+		marketplace.GetProductWithVersionStub = func(ctx context.Context, slug string, version string) (*models.Product, *models.Version, error) {
+			if product.HasVersion(version) {
+				return product, product.GetVersion(version), nil
+			}
+			return product, nil, &pkg.VersionDoesNotExistError{Product: slug, Version: version}
+		}
+		marketplace.PutProductStub = func(ctx context.Context, product *models.Product, versionUpdate bool) (*models.Product, error) {
+			return product, nil
+		}
+		marketplace.AttachPublicContainerImageStub = func(ctx context.Context, image, tag, tagType, instructions string, product *models.Product, version *models.Version) (*models.Product, error) {
+			return product, nil
+		}
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	loadSpec := func(content string) *spec.Spec {
+		path := filepath.Join(dir, "product.yaml")
+		Expect(os.WriteFile(path, []byte(content), 0600)).To(Succeed())
+		productSpec, err := spec.Load(path)
+		Expect(err).ToNot(HaveOccurred())
+		return productSpec
+	}
+
+	It("lists the changed details, new versions and missing assets", func() {
+		plan, err := spec.MakePlan(context.Background(), marketplace, loadSpec(`
+product: my-super-product
+summary: The new summary
+tags: [database]
+versions:
+  - number: 1.2.3
+    container_images:
+      - image: my-image
+        tag: 1.2.3
+        instructions: docker run it
+      - image: my-image
+        tag: 1.2.3-debian
+        instructions: docker run it
+  - number: 1.3.0
+    container_images:
+      - image: my-image
+        tag: 1.3.0
+        instructions: docker run it
+`))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(plan.HasChanges()).To(BeTrue())
+		Expect(plan.Unchanged).To(Equal(1))
+
+		var changes []string
+		for _, change := range plan.Changes {
+			changes = append(changes, change.String())
+		}
+		Expect(changes).To(Equal([]string{
+			`update summary: "The old summary" => "The new summary"`,
+			"attach container image my-image:1.2.3-debian to version 1.2.3",
+			"create version 1.3.0",
+			"attach container image my-image:1.3.0 to version 1.3.0",
+		}))
+	})
+
+	It("finds files that are already attached by their content", func() {
+		product.SolutionType = models.SolutionTypeOthers
+		otherFile := filepath.Join(dir, "addon.zip")
+		Expect(os.WriteFile(otherFile, []byte("some content"), 0600)).To(Succeed())
+		hash, err := pkg.Hash(otherFile, models.HashAlgoSHA1)
+		Expect(err).ToNot(HaveOccurred())
+		product.AddOnFiles = []*models.AddOnFile{{Name: "addon.zip", AppVersion: "1.2.3", HashDigest: hash}}
+
+		plan, err := spec.MakePlan(context.Background(), marketplace, loadSpec(`
+product: my-super-product
+versions:
+  - number: 1.2.3
+    other_files:
+      - file: addon.zip
+`))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(plan.HasChanges()).To(BeFalse())
+		Expect(plan.Unchanged).To(Equal(1))
+	})
+
+	It("finds local charts that are already attached by their name and version", func() {
+		product.SolutionType = models.SolutionTypeChart
+		chart, chartPath, chartDir := test.CreateFakeChart("my-chart")
+		defer os.RemoveAll(chartDir)
+		product.ChartVersions = []*models.ChartVersion{{
+			AppVersion: "1.2.3",
+			Version:    chart.Metadata.Version,
+			Repo:       &models.Repo{Name: "my-chart"},
+		}}
+
+		plan, err := spec.MakePlan(context.Background(), marketplace, loadSpec(`
+product: my-super-product
+versions:
+  - number: 1.2.3
+    charts:
+      - chart: `+chartPath+`
+        instructions: helm install it
+  - number: 1.3.0
+    charts:
+      - chart: `+chartPath+`
+        instructions: helm install it
+`))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(plan.Unchanged).To(Equal(1))
+		Expect(plan.Changes).To(HaveLen(2))
+		Expect(plan.Changes[1].String()).To(Equal("attach chart " + chartPath + " to version 1.3.0"))
+	})
+
+	When("an asset does not fit the type of product", func() {
+		It("returns an error", func() {
+			_, err := spec.MakePlan(context.Background(), marketplace, loadSpec(`
+product: my-super-product
+versions:
+  - number: 1.2.3
+    vm_files:
+      - file: my-vm.ova
+`))
+			Expect(err).To(MatchError("cannot attach a vm to my-super-product which is of type CONTAINER"))
+		})
+	})
+
+	When("getting the product fails", func() {
+		It("returns an error", func() {
+			marketplace.GetProductReturns(nil, errors.New("get product failed"))
+			_, err := spec.MakePlan(context.Background(), marketplace, loadSpec("product: my-super-product\n"))
+			Expect(err).To(MatchError("get product failed"))
+		})
+	})
+
+	Describe("Apply", func() {
+		It("updates the details, then attaches the missing assets", func() {
+			plan, err := spec.MakePlan(context.Background(), marketplace, loadSpec(`
+product: my-super-product
+summary: The new summary
+versions:
+  - number: 1.3.0
+    container_images:
+      - image: my-image
+        tag: 1.3.0
+        instructions: docker run it
+`))
+			Expect(err).ToNot(HaveOccurred())
+
+			progress := NewBuffer()
+			_, err = plan.Apply(context.Background(), marketplace, progress)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(progress).To(Say(`Applying: update summary: "The old summary" => "The new summary"`))
+			Expect(progress).To(Say("Applying: create version 1.3.0"))
+			Expect(progress).To(Say("Applying: attach container image my-image:1.3.0 to version 1.3.0"))
+
+			By("updating the details once", func() {
+				Expect(marketplace.PutProductCallCount()).To(Equal(1))
+				_, updated, versionUpdate := marketplace.PutProductArgsForCall(0)
+				Expect(versionUpdate).To(BeFalse())
+				Expect(updated.Description.Summary).To(Equal("The new summary"))
+			})
+
+			By("attaching the image to the new version", func() {
+				Expect(marketplace.AttachPublicContainerImageCallCount()).To(Equal(1))
+				_, image, tag, tagType, _, _, version := marketplace.AttachPublicContainerImageArgsForCall(0)
+				Expect(image).To(Equal("my-image"))
+				Expect(tag).To(Equal("1.3.0"))
+				Expect(tagType).To(Equal(models.ImageTagTypeFixed))
+				Expect(version.Number).To(Equal("1.3.0"))
+				Expect(version.IsNewVersion).To(BeTrue())
+			})
+		})
+
+		When("a new version has no assets", func() {
+			It("creates the version", func() {
+				plan, err := spec.MakePlan(context.Background(), marketplace, loadSpec("product: my-super-product\nversions:\n  - number: 2.0.0\n"))
+				Expect(err).ToNot(HaveOccurred())
+
+				_, err = plan.Apply(context.Background(), marketplace, NewBuffer())
+				Expect(err).ToNot(HaveOccurred())
+				Expect(marketplace.PutProductCallCount()).To(Equal(1))
+				_, updated, versionUpdate := marketplace.PutProductArgsForCall(0)
+				Expect(versionUpdate).To(BeTrue())
+				Expect(updated.HasVersion("2.0.0")).To(BeTrue())
+			})
+		})
+
+		When("attaching an asset fails", func() {
+			It("returns an error", func() {
+				marketplace.AttachPublicContainerImageStub = nil
+				marketplace.AttachPublicContainerImageReturns(nil, errors.New("attach failed"))
+				plan, err := spec.MakePlan(context.Background(), marketplace, loadSpec(`
+product: my-super-product
+versions:
+  - number: 1.2.3
+    container_images:
+      - image: my-image
+        tag: 1.2.4
+        instructions: docker run it
+`))
+				Expect(err).ToNot(HaveOccurred())
+
+				_, err = plan.Apply(context.Background(), marketplace, NewBuffer())
+				Expect(err).To(MatchError("failed to attach container image my-image:1.2.4 to version 1.2.3: attach failed"))
+			})
+		})
+	})
+})
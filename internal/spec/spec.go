@@ -0,0 +1,213 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: BSD-2-Clause
+
+package spec
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/vmware-labs/marketplace-cli/v2/internal/models"
+	"github.com/vmware-labs/marketplace-cli/v2/pkg"
+	"gopkg.in/yaml.v3"
+)
+
+var metaFileTypes = map[string]string{
+	"cli":    pkg.MetaFileTypeCLI,
+	"config": pkg.MetaFileTypeConfig,
+	"other":  pkg.MetaFileTypeOther,
+	"misc":   pkg.MetaFileTypeOther,
+}
+
+// Spec describes how a product should look. Anything left out of the spec is not changed. An empty list, like
+// "tags: []", removes everything from that list.
+type Spec struct {
+	Product         string         `yaml:"product"`
+	Summary         string         `yaml:"summary,omitempty"`
+	Description     string         `yaml:"description,omitempty"`
+	DescriptionFile string         `yaml:"description_file,omitempty"`
+	Highlights      []string       `yaml:"highlights,omitempty"`
+	Tags            []string       `yaml:"tags,omitempty"`
+	Categories      []string       `yaml:"categories,omitempty"`
+	VideoURLs       []string       `yaml:"video_urls,omitempty"`
+	Support         *SupportSpec   `yaml:"support,omitempty"`
+	Website         string         `yaml:"website,omitempty"`
+	LogoURL         string         `yaml:"logo_url,omitempty"`
+	Versions        []*VersionSpec `yaml:"versions,omitempty"`
+}
+
+type SupportSpec struct {
+	URL   string `yaml:"url,omitempty"`
+	Email string `yaml:"email,omitempty"`
+	Phone string `yaml:"phone,omitempty"`
+}
+
+// VersionSpec lists the assets that should be attached to a version. Assets that are attached, but not in the spec,
+// are left alone.
+type VersionSpec struct {
+	Number          string                `yaml:"number"`
+	Charts          []*ChartSpec          `yaml:"charts,omitempty"`
+	ContainerImages []*ContainerImageSpec `yaml:"container_images,omitempty"`
+	VMFiles         []*FileSpec           `yaml:"vm_files,omitempty"`
+	MetaFiles       []*MetaFileSpec       `yaml:"metafiles,omitempty"`
+	OtherFiles      []*FileSpec           `yaml:"other_files,omitempty"`
+}
+
+type ChartSpec struct {
+	// Chart is the path to a chart tgz, or a public URL
+	Chart        string `yaml:"chart"`
+	Instructions string `yaml:"instructions"`
+}
+
+type ContainerImageSpec struct {
+	Image   string `yaml:"image"`
+	Tag     string `yaml:"tag"`
+	TagType string `yaml:"tag_type"`
+	// File is a local tar of the image to upload. Without it, the image is pulled from the image repository.
+	File         string `yaml:"file,omitempty"`
+	Instructions string `yaml:"instructions"`
+}
+
+type FileSpec struct {
+	File string `yaml:"file"`
+}
+
+type MetaFileSpec struct {
+	File string `yaml:"file"`
+	Type string `yaml:"type"`
+	// Version is the version of the metafile itself. Defaults to the product version.
+	Version string `yaml:"version,omitempty"`
+}
+
+// Load reads the spec file. Relative file paths in it are relative to the spec file.
+func Load(path string) (*Spec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the product spec: %w", err)
+	}
+
+	spec := &Spec{}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	err = decoder.Decode(spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the product spec %s: %w", path, err)
+	}
+
+	spec.resolvePaths(filepath.Dir(path))
+	err = spec.Validate()
+	if err != nil {
+		return nil, fmt.Errorf("invalid product spec %s: %w", path, err)
+	}
+
+	if spec.DescriptionFile != "" {
+		description, err := os.ReadFile(spec.DescriptionFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the description file: %w", err)
+		}
+		spec.Description = string(description)
+	}
+	return spec, nil
+}
+
+func resolvePath(dir, path string) string {
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
+
+func (s *Spec) resolvePaths(dir string) {
+	s.DescriptionFile = resolvePath(dir, s.DescriptionFile)
+	for _, version := range s.Versions {
+		for _, chart := range version.Charts {
+			if !isRemoteChart(chart.Chart) {
+				chart.Chart = resolvePath(dir, strings.TrimPrefix(chart.Chart, "file://"))
+			}
+		}
+		for _, image := range version.ContainerImages {
+			image.File = resolvePath(dir, image.File)
+		}
+		for _, file := range version.VMFiles {
+			file.File = resolvePath(dir, file.File)
+		}
+		for _, metafile := range version.MetaFiles {
+			metafile.File = resolvePath(dir, metafile.File)
+		}
+		for _, file := range version.OtherFiles {
+			file.File = resolvePath(dir, file.File)
+		}
+	}
+}
+
+func isRemoteChart(chart string) bool {
+	chartURL, err := url.Parse(chart)
+	return err == nil && (chartURL.Scheme == "http" || chartURL.Scheme == "https")
+}
+
+// Validate checks that everything needed is in the spec, and normalizes the image tag types and metafile types
+func (s *Spec) Validate() error {
+	if s.Product == "" {
+		return errors.New("the product slug is required")
+	}
+	if s.Description != "" && s.DescriptionFile != "" {
+		return errors.New("only one of description and description_file can be set")
+	}
+
+	versions := map[string]bool{}
+	for _, version := range s.Versions {
+		if version.Number == "" {
+			return errors.New("every version needs a number")
+		}
+		if versions[version.Number] {
+			return fmt.Errorf("version %s is listed more than once", version.Number)
+		}
+		versions[version.Number] = true
+
+		for _, chart := range version.Charts {
+			if chart.Chart == "" || chart.Instructions == "" {
+				return fmt.Errorf("every chart in version %s needs a chart and instructions", version.Number)
+			}
+		}
+		for _, image := range version.ContainerImages {
+			if image.Image == "" || image.Tag == "" || image.Instructions == "" {
+				return fmt.Errorf("every container image in version %s needs an image, tag and instructions", version.Number)
+			}
+			image.TagType = strings.ToUpper(image.TagType)
+			if image.TagType == "" {
+				image.TagType = models.ImageTagTypeFixed
+			}
+			if image.TagType != models.ImageTagTypeFixed && image.TagType != models.ImageTagTypeFloating {
+				return fmt.Errorf("invalid image tag type for %s:%s: %s. must be either \"%s\" or \"%s\"", image.Image, image.Tag, image.TagType, models.ImageTagTypeFixed, models.ImageTagTypeFloating)
+			}
+		}
+		for _, file := range append(append([]*FileSpec{}, version.VMFiles...), version.OtherFiles...) {
+			if file.File == "" {
+				return fmt.Errorf("every file in version %s needs a file", version.Number)
+			}
+		}
+		for _, metafile := range version.MetaFiles {
+			if metafile.File == "" {
+				return fmt.Errorf("every metafile in version %s needs a file", version.Number)
+			}
+			metaFileType, ok := metaFileTypes[strings.ToLower(metafile.Type)]
+			if !ok {
+				return fmt.Errorf("invalid metafile type for %s: \"%s\". must be one of cli, config or other", metafile.File, metafile.Type)
+			}
+			metafile.Type = metaFileType
+			if metafile.Version == "" {
+				metafile.Version = version.Number
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Spec) hasSupport() bool {
+	return s.Support != nil && (s.Support.URL != "" || s.Support.Email != "" || s.Support.Phone != "")
+}
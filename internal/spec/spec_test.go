@@ -0,0 +1,123 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: BSD-2-Clause
+
+package spec_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/models"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/spec"
+	"github.com/vmware-labs/marketplace-cli/v2/pkg"
+)
+
+var _ = Describe("Load", func() {
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "mkpcli-spec-test")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	writeSpec := func(content string) string {
+		path := filepath.Join(dir, "product.yaml")
+		Expect(os.WriteFile(path, []byte(content), 0600)).To(Succeed())
+		return path
+	}
+
+	It("reads the spec", func() {
+		Expect(os.WriteFile(filepath.Join(dir, "description.html"), []byte("<p>My description</p>"), 0600)).To(Succeed())
+		path := writeSpec(`
+product: my-super-product
+summary: My summary
+description_file: description.html
+tags: []
+support:
+  email: support@example.com
+versions:
+  - number: 1.2.3
+    charts:
+      - chart: charts/my-chart-1.2.3.tgz
+        instructions: helm install it
+      - chart: https://charts.example.com/my-chart-1.2.3.tgz
+        instructions: helm install it
+    container_images:
+      - image: registry.example.com/my-image
+        tag: latest
+        tag_type: floating
+        instructions: docker run it
+    metafiles:
+      - file: /path/to/cli
+        type: cli
+`)
+
+		productSpec, err := spec.Load(path)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(productSpec.Product).To(Equal("my-super-product"))
+		Expect(productSpec.Description).To(Equal("<p>My description</p>"))
+		Expect(productSpec.Tags).To(BeEmpty())
+		Expect(productSpec.Tags).ToNot(BeNil())
+		Expect(productSpec.Highlights).To(BeNil())
+		Expect(productSpec.Support.Email).To(Equal("support@example.com"))
+
+		version := productSpec.Versions[0]
+		Expect(version.Charts[0].Chart).To(Equal(filepath.Join(dir, "charts/my-chart-1.2.3.tgz")))
+		Expect(version.Charts[1].Chart).To(Equal("https://charts.example.com/my-chart-1.2.3.tgz"))
+		Expect(version.ContainerImages[0].TagType).To(Equal(models.ImageTagTypeFloating))
+		Expect(version.MetaFiles[0].File).To(Equal("/path/to/cli"))
+		Expect(version.MetaFiles[0].Type).To(Equal(pkg.MetaFileTypeCLI))
+		Expect(version.MetaFiles[0].Version).To(Equal("1.2.3"))
+	})
+
+	When("the spec has an unknown field", func() {
+		It("returns an error", func() {
+			_, err := spec.Load(writeSpec("product: my-super-product\nsumary: a typo\n"))
+			Expect(err).To(MatchError(ContainSubstring("field sumary not found")))
+		})
+	})
+
+	When("the spec is missing the product", func() {
+		It("returns an error", func() {
+			_, err := spec.Load(writeSpec("summary: My summary\n"))
+			Expect(err).To(MatchError(ContainSubstring("the product slug is required")))
+		})
+	})
+
+	When("a version is listed twice", func() {
+		It("returns an error", func() {
+			_, err := spec.Load(writeSpec("product: my-super-product\nversions:\n  - number: 1.2.3\n  - number: 1.2.3\n"))
+			Expect(err).To(MatchError(ContainSubstring("version 1.2.3 is listed more than once")))
+		})
+	})
+
+	When("a container image has an invalid tag type", func() {
+		It("returns an error", func() {
+			_, err := spec.Load(writeSpec(`
+product: my-super-product
+versions:
+  - number: 1.2.3
+    container_images:
+      - image: my-image
+        tag: 1.2.3
+        tag_type: sometimes
+        instructions: docker run it
+`))
+			Expect(err).To(MatchError(ContainSubstring("invalid image tag type for my-image:1.2.3: SOMETIMES")))
+		})
+	})
+
+	When("the spec file does not exist", func() {
+		It("returns an error", func() {
+			_, err := spec.Load(filepath.Join(dir, "missing.yaml"))
+			Expect(err).To(MatchError(ContainSubstring("failed to read the product spec")))
+		})
+	})
+})
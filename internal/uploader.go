@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
 	"path"
 	"path/filepath"
@@ -15,7 +16,6 @@ import (
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
-	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 )
@@ -32,9 +32,9 @@ func now() string {
 
 //go:generate counterfeiter . Uploader
 type Uploader interface {
-	UploadMediaFile(filePath string) (string, string, error)
-	UploadMetaFile(filePath string) (string, string, error)
-	UploadProductFile(filePath string) (string, string, error)
+	UploadMediaFile(ctx context.Context, filePath string) (string, string, error)
+	UploadMetaFile(ctx context.Context, filePath string) (string, string, error)
+	UploadProductFile(ctx context.Context, filePath string) (string, string, error)
 }
 
 type S3Uploader struct {
@@ -50,13 +50,22 @@ type S3Client interface {
 	PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error)
 }
 
-func NewS3Client(region string, creds aws.Credentials) S3Client {
-	s3Config, err := config.LoadDefaultConfig(context.Background(),
-		config.WithCredentialsProvider(credentials.StaticCredentialsProvider{
-			Value: creds,
-		}),
+// S3HTTPClient sends the requests to the storage bucket
+type S3HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// NewS3Client makes a client for the storage bucket. If httpClient is nil, the AWS SDK default is used.
+func NewS3Client(region string, creds aws.CredentialsProvider, httpClient S3HTTPClient) S3Client {
+	options := []func(*config.LoadOptions) error{
+		config.WithCredentialsProvider(creds),
 		config.WithRegion(region),
-	)
+	}
+	if httpClient != nil {
+		options = append(options, config.WithHTTPClient(httpClient))
+	}
+
+	s3Config, err := config.LoadDefaultConfig(context.Background(), options...)
 	if err != nil {
 		return nil
 	}
@@ -74,44 +83,46 @@ func NewS3Uploader(bucket, region, orgID string, client S3Client, output io.Writ
 	}
 }
 
-func (u *S3Uploader) UploadMediaFile(filePath string) (string, string, error) {
+func (u *S3Uploader) UploadMediaFile(ctx context.Context, filePath string) (string, string, error) {
 	filename := filepath.Base(filePath)
 	key := path.Join(u.orgID, FolderMediaFiles, now(), filename)
 	url := fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", u.bucket, u.region, key)
-	err := u.upload(filePath, key, types.ObjectCannedACLPublicRead)
+	err := u.upload(ctx, filePath, key, types.ObjectCannedACLPublicRead)
 	return filename, url, err
 }
 
-func (u *S3Uploader) UploadMetaFile(filePath string) (string, string, error) {
+func (u *S3Uploader) UploadMetaFile(ctx context.Context, filePath string) (string, string, error) {
 	filename := filepath.Base(filePath)
 	datestamp := now()
 	key := path.Join(u.orgID, FolderMetaFiles, datestamp, filename)
 	url := fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", u.bucket, u.region, key)
-	err := u.upload(filePath, key, types.ObjectCannedACLPrivate)
+	err := u.upload(ctx, filePath, key, types.ObjectCannedACLPrivate)
 	return filename, url, err
 }
 
-func (u *S3Uploader) UploadProductFile(filePath string) (string, string, error) {
+func (u *S3Uploader) UploadProductFile(ctx context.Context, filePath string) (string, string, error) {
 	filename := filepath.Base(filePath)
 	datestamp := now()
 	key := path.Join(u.orgID, FolderProductFiles, datestamp, filename)
 	url := fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", u.bucket, u.region, key)
-	err := u.upload(filePath, key, types.ObjectCannedACLPrivate)
+	err := u.upload(ctx, filePath, key, types.ObjectCannedACLPrivate)
 	return filename, url, err
 }
 
-func (u *S3Uploader) upload(filePath, key string, acl types.ObjectCannedACL) error {
+func (u *S3Uploader) upload(ctx context.Context, filePath, key string, acl types.ObjectCannedACL) error {
 	file, err := os.Open(filePath)
 	if err != nil {
 		return fmt.Errorf("failed to open %s: %w", filePath, err)
 	}
+	defer file.Close()
+
 	stat, err := file.Stat()
 	if err != nil {
 		return fmt.Errorf("failed to get info for %s: %w", filePath, err)
 	}
 
 	progressBar := MakeProgressBar(fmt.Sprintf("Uploading %s", path.Base(file.Name())), stat.Size(), u.output)
-	_, err = u.client.PutObject(context.Background(), &s3.PutObjectInput{
+	_, err = u.client.PutObject(ctx, &s3.PutObjectInput{
 		ACL:           acl,
 		Bucket:        aws.String(u.bucket),
 		Key:           aws.String(key),
@@ -121,11 +132,5 @@ func (u *S3Uploader) upload(filePath, key string, acl types.ObjectCannedACL) err
 	if err != nil {
 		return fmt.Errorf("failed to upload file: %w", err)
 	}
-
-	err = file.Close()
-	if err != nil {
-		return fmt.Errorf("failed to close file: %w", err)
-	}
-
 	return nil
 }
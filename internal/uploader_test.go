@@ -4,6 +4,7 @@
 package internal_test
 
 import (
+	"context"
 	"errors"
 	"io"
 	"os"
@@ -48,7 +49,7 @@ var _ = Describe("Uploader", func() {
 		It("properly uploads a media file", func() {
 			output := NewBuffer()
 			uploader := internal.NewS3Uploader("my-bucket", "my-region", "my-org", client, output)
-			filename, fileUrl, err := uploader.UploadMediaFile(file.Name())
+			filename, fileUrl, err := uploader.UploadMediaFile(context.Background(), file.Name())
 			Expect(err).ToNot(HaveOccurred())
 
 			By("sending the object to S3", func() {
@@ -82,7 +83,7 @@ var _ = Describe("Uploader", func() {
 			It("returns an error", func() {
 				output := NewBuffer()
 				uploader := internal.NewS3Uploader("my-bucket", "my-region", "my-org", client, output)
-				_, _, err := uploader.UploadMediaFile(file.Name())
+				_, _, err := uploader.UploadMediaFile(context.Background(), file.Name())
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(Equal("failed to upload file: put object failed"))
 			})
@@ -93,7 +94,7 @@ var _ = Describe("Uploader", func() {
 		It("properly uploads a meta file", func() {
 			output := NewBuffer()
 			uploader := internal.NewS3Uploader("my-bucket", "my-region", "my-org", client, output)
-			filename, fileUrl, err := uploader.UploadMetaFile(file.Name())
+			filename, fileUrl, err := uploader.UploadMetaFile(context.Background(), file.Name())
 			Expect(err).ToNot(HaveOccurred())
 
 			By("sending the object to S3", func() {
@@ -127,7 +128,7 @@ var _ = Describe("Uploader", func() {
 			It("returns an error", func() {
 				output := NewBuffer()
 				uploader := internal.NewS3Uploader("my-bucket", "my-region", "my-org", client, output)
-				_, _, err := uploader.UploadMediaFile(file.Name())
+				_, _, err := uploader.UploadMediaFile(context.Background(), file.Name())
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(Equal("failed to upload file: put object failed"))
 			})
@@ -138,7 +139,7 @@ var _ = Describe("Uploader", func() {
 		It("properly uploads a product file", func() {
 			output := NewBuffer()
 			uploader := internal.NewS3Uploader("my-bucket", "my-region", "my-org", client, output)
-			filename, fileUrl, err := uploader.UploadProductFile(file.Name())
+			filename, fileUrl, err := uploader.UploadProductFile(context.Background(), file.Name())
 			Expect(err).ToNot(HaveOccurred())
 
 			By("sending the object to S3", func() {
@@ -172,7 +173,7 @@ var _ = Describe("Uploader", func() {
 			It("returns an error", func() {
 				output := NewBuffer()
 				uploader := internal.NewS3Uploader("my-bucket", "my-region", "my-org", client, output)
-				_, _, err := uploader.UploadProductFile(file.Name())
+				_, _, err := uploader.UploadProductFile(context.Background(), file.Name())
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(Equal("failed to upload file: put object failed"))
 			})
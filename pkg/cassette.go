@@ -0,0 +1,266 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: BSD-2-Clause
+
+package pkg
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// A cassette is a directory of recorded requests and responses, one JSON file per interaction, numbered in the order
+// they were sent. Credentials and signatures are scrubbed before anything is written.
+
+const cassetteFilePattern = "interaction-*.json"
+
+// maxRecordedBodySize is the largest response body that is saved. Larger or binary bodies, like downloads, are passed
+// on without being read or saved.
+const maxRecordedBodySize = 1024 * 1024
+
+// ErrNotRecorded is returned when replaying a request that is not in the cassette
+var ErrNotRecorded = errors.New("no recorded response")
+
+type Interaction struct {
+	Request  *RecordedRequest  `json:"request"`
+	Response *RecordedResponse `json:"response"`
+}
+
+type RecordedRequest struct {
+	Method  string      `json:"method"`
+	URL     string      `json:"url"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    string      `json:"body,omitempty"`
+}
+
+type RecordedResponse struct {
+	Status       string      `json:"status"`
+	StatusCode   int         `json:"status_code"`
+	Headers      http.Header `json:"headers,omitempty"`
+	Body         string      `json:"body,omitempty"`
+	BodyEncoding string      `json:"body_encoding,omitempty"`
+	// BodyOmitted is true if the body was too large, or not text, so it was not saved. It is replayed as empty.
+	BodyOmitted bool `json:"body_omitted,omitempty"`
+}
+
+// Recorder sends requests, and saves each request and response to the cassette
+type Recorder struct {
+	Dir            string
+	PerformRequest PerformRequestFunc
+
+	lock  sync.Mutex
+	count int
+}
+
+func NewRecorder(dir string, performRequest PerformRequestFunc) (*Recorder, error) {
+	err := os.MkdirAll(dir, 0700)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the cassette directory %s: %w", dir, err)
+	}
+	return &Recorder{
+		Dir:            dir,
+		PerformRequest: performRequest,
+	}, nil
+}
+
+func (r *Recorder) Do(req *http.Request) (*http.Response, error) {
+	interaction := &Interaction{
+		Request: &RecordedRequest{
+			Method:  req.Method,
+			URL:     RedactURL(req.URL),
+			Headers: RedactHeaders(req.Header),
+		},
+	}
+
+	if req.Body != nil && req.Body != http.NoBody && isTextual(req.Header.Get("Content-Type")) {
+		content, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the %s request body: %w", req.URL.String(), err)
+		}
+		_ = req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(content))
+		interaction.Request.Body = string(RedactPayload(req.Header.Get("Content-Type"), content))
+	}
+
+	resp, err := r.PerformRequest(req)
+	if err != nil {
+		return nil, err
+	}
+
+	interaction.Response = &RecordedResponse{
+		Status:     resp.Status,
+		StatusCode: resp.StatusCode,
+		Headers:    RedactHeaders(resp.Header),
+	}
+	err = recordResponseBody(resp, interaction.Response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the %s response body: %w", req.URL.String(), err)
+	}
+
+	err = r.save(interaction)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// recordResponseBody saves the body of a textual response, and replaces it with a copy for the caller.
+// Other bodies are left alone, so that downloads are streamed instead of kept in memory.
+func recordResponseBody(resp *http.Response, recorded *RecordedResponse) error {
+	if resp.Body == nil || resp.Body == http.NoBody {
+		return nil
+	}
+	contentType := resp.Header.Get("Content-Type")
+	if !isTextual(contentType) || resp.ContentLength > maxRecordedBodySize {
+		recorded.BodyOmitted = resp.ContentLength != 0
+		return nil
+	}
+
+	content, err := io.ReadAll(io.LimitReader(resp.Body, maxRecordedBodySize+1))
+	if err != nil {
+		_ = resp.Body.Close()
+		return err
+	}
+	if len(content) > maxRecordedBodySize {
+		// The length was not known up front, so pass on what was read, followed by the rest
+		resp.Body = &partlyReadBody{Reader: io.MultiReader(bytes.NewReader(content), resp.Body), Closer: resp.Body}
+		recorded.BodyOmitted = true
+		return nil
+	}
+	_ = resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(content))
+
+	if utf8.Valid(content) {
+		recorded.Body = string(RedactPayload(contentType, content))
+	} else {
+		recorded.Body = base64.StdEncoding.EncodeToString(content)
+		recorded.BodyEncoding = "base64"
+	}
+	return nil
+}
+
+type partlyReadBody struct {
+	io.Reader
+	io.Closer
+}
+
+func (r *Recorder) save(interaction *Interaction) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.count++
+	path := filepath.Join(r.Dir, strings.Replace(cassetteFilePattern, "*", fmt.Sprintf("%04d", r.count), 1))
+	encoded, err := json.MarshalIndent(interaction, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode the recorded request: %w", err)
+	}
+	err = os.WriteFile(path, encoded, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to save the recorded request: %w", err)
+	}
+	return nil
+}
+
+// Replayer answers requests from a cassette, without using the network. Requests are matched by method and URL.
+// Interactions are used in the order they were recorded, and the last match is used again once all are used.
+type Replayer struct {
+	Dir          string
+	interactions []*Interaction
+
+	lock sync.Mutex
+	used []bool
+}
+
+func LoadCassette(dir string) (*Replayer, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, cassetteFilePattern))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the cassette %s: %w", dir, err)
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no recorded requests found in %s", dir)
+	}
+	sort.Strings(paths)
+
+	replayer := &Replayer{Dir: dir}
+	for _, path := range paths {
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the cassette %s: %w", dir, err)
+		}
+		interaction := &Interaction{}
+		err = json.Unmarshal(contents, interaction)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the recorded request %s: %w", path, err)
+		}
+		if interaction.Request == nil || interaction.Response == nil {
+			return nil, fmt.Errorf("the recorded request %s is incomplete", path)
+		}
+		replayer.interactions = append(replayer.interactions, interaction)
+	}
+	replayer.used = make([]bool, len(replayer.interactions))
+	return replayer, nil
+}
+
+func (r *Replayer) Do(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		_, _ = io.Copy(io.Discard, req.Body)
+		_ = req.Body.Close()
+	}
+
+	interaction := r.find(req.Method, RedactURL(req.URL))
+	if interaction == nil {
+		return nil, fmt.Errorf("%w for %s %s in %s", ErrNotRecorded, req.Method, RedactURL(req.URL), r.Dir)
+	}
+
+	body := []byte(interaction.Response.Body)
+	if interaction.Response.BodyEncoding == "base64" {
+		var err error
+		body, err = base64.StdEncoding.DecodeString(interaction.Response.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode the recorded response for %s %s: %w", req.Method, RedactURL(req.URL), err)
+		}
+	}
+
+	return &http.Response{
+		Status:        interaction.Response.Status,
+		StatusCode:    interaction.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        interaction.Response.Headers.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+func (r *Replayer) find(method, url string) *Interaction {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	last := -1
+	for i, interaction := range r.interactions {
+		if interaction.Request.Method != method || interaction.Request.URL != url {
+			continue
+		}
+		if !r.used[i] {
+			r.used[i] = true
+			return interaction
+		}
+		last = i
+	}
+	if last < 0 {
+		return nil
+	}
+	return r.interactions[last]
+}
@@ -0,0 +1,239 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: BSD-2-Clause
+
+package pkg_test
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/vmware-labs/marketplace-cli/v2/pkg"
+	"github.com/vmware-labs/marketplace-cli/v2/pkg/pkgfakes"
+)
+
+var _ = Describe("Cassettes", func() {
+	var (
+		dir            string
+		performRequest *pkgfakes.FakePerformRequestFunc
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "mkpcli-cassette-test")
+		Expect(err).ToNot(HaveOccurred())
+
+		performRequest = &pkgfakes.FakePerformRequestFunc{}
+		performRequest.Returns(&http.Response{
+			Status:     "200 OK",
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       ioutil.NopCloser(strings.NewReader(`{"access_token":"my-access-token","expires_in":1799}`)),
+		}, nil)
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	record := func(method, rawURL, contentType, body string) *http.Response {
+		recorder, err := pkg.NewRecorder(dir, performRequest.Spy)
+		Expect(err).ToNot(HaveOccurred())
+
+		request, err := http.NewRequest(method, rawURL, strings.NewReader(body))
+		Expect(err).ToNot(HaveOccurred())
+		request.Header.Set("Content-Type", contentType)
+		request.Header.Set("csp-auth-token", "my-access-token")
+
+		response, err := recorder.Do(request)
+		Expect(err).ToNot(HaveOccurred())
+		return response
+	}
+
+	Describe("Recorder", func() {
+		It("saves the request and response without credentials", func() {
+			response := record(
+				"POST",
+				"https://console.cloud.vmware.example/csp/gateway/am/api/auth/api-tokens/authorize?signature=abc123",
+				"application/x-www-form-urlencoded",
+				"refresh_token=my-api-token",
+			)
+
+			By("returning the real response", func() {
+				Expect(ioutil.ReadAll(response.Body)).To(MatchJSON(`{"access_token":"my-access-token","expires_in":1799}`))
+			})
+
+			By("sending the real request", func() {
+				request := performRequest.ArgsForCall(0)
+				Expect(ioutil.ReadAll(request.Body)).To(Equal([]byte("refresh_token=my-api-token")))
+			})
+
+			By("saving the interaction", func() {
+				contents, err := os.ReadFile(filepath.Join(dir, "interaction-0001.json"))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(string(contents)).ToNot(ContainSubstring("my-access-token"))
+				Expect(string(contents)).ToNot(ContainSubstring("my-api-token"))
+				Expect(string(contents)).ToNot(ContainSubstring("abc123"))
+				Expect(string(contents)).To(ContainSubstring(`"url": "https://console.cloud.vmware.example/csp/gateway/am/api/auth/api-tokens/authorize?signature=%5Bredacted%5D"`))
+			})
+		})
+
+		When("the response is a download", func() {
+			var body io.ReadCloser
+
+			BeforeEach(func() {
+				body = ioutil.NopCloser(strings.NewReader("binary file contents"))
+				performRequest.Returns(&http.Response{
+					Status:        "200 OK",
+					StatusCode:    http.StatusOK,
+					Header:        http.Header{"Content-Type": []string{"application/octet-stream"}},
+					Body:          body,
+					ContentLength: 20,
+				}, nil)
+			})
+
+			It("passes on the body without saving it", func() {
+				response := record("GET", "https://bucket.s3.example.com/my-file.ova?X-Amz-Signature=abc123", "", "")
+				Expect(response.Body).To(BeIdenticalTo(body))
+				Expect(ioutil.ReadAll(response.Body)).To(Equal([]byte("binary file contents")))
+
+				contents, err := os.ReadFile(filepath.Join(dir, "interaction-0001.json"))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(string(contents)).To(ContainSubstring(`"body_omitted": true`))
+				Expect(string(contents)).ToNot(ContainSubstring("binary file contents"))
+
+				By("replaying it as empty", func() {
+					replayer, err := pkg.LoadCassette(dir)
+					Expect(err).ToNot(HaveOccurred())
+					request, err := http.NewRequest("GET", "https://bucket.s3.example.com/my-file.ova?X-Amz-Signature=def456", nil)
+					Expect(err).ToNot(HaveOccurred())
+					response, err := replayer.Do(request)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(ioutil.ReadAll(response.Body)).To(BeEmpty())
+				})
+			})
+		})
+
+		When("the response is larger than can be saved", func() {
+			var large string
+
+			BeforeEach(func() {
+				large = `{"data":"` + strings.Repeat("a", 2*1024*1024) + `"}`
+				performRequest.Returns(&http.Response{
+					Status:        "200 OK",
+					StatusCode:    http.StatusOK,
+					Header:        http.Header{"Content-Type": []string{"application/json"}},
+					Body:          ioutil.NopCloser(strings.NewReader(large)),
+					ContentLength: -1,
+				}, nil)
+			})
+
+			It("passes on the whole body without saving it", func() {
+				response := record("GET", "https://marketplace.vmware.example/api/v1/products", "", "")
+				Expect(ioutil.ReadAll(response.Body)).To(Equal([]byte(large)))
+
+				contents, err := os.ReadFile(filepath.Join(dir, "interaction-0001.json"))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(string(contents)).To(ContainSubstring(`"body_omitted": true`))
+				Expect(len(contents)).To(BeNumerically("<", 1024))
+			})
+		})
+
+		When("the request fails", func() {
+			It("does not save anything", func() {
+				performRequest.Returns(nil, &url.Error{Op: "Get", URL: "https://example.com", Err: os.ErrDeadlineExceeded})
+				recorder, err := pkg.NewRecorder(dir, performRequest.Spy)
+				Expect(err).ToNot(HaveOccurred())
+
+				request, err := http.NewRequest("GET", "https://example.com", nil)
+				Expect(err).ToNot(HaveOccurred())
+				_, err = recorder.Do(request)
+				Expect(err).To(HaveOccurred())
+
+				files, err := os.ReadDir(dir)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(files).To(BeEmpty())
+			})
+		})
+	})
+
+	Describe("Replayer", func() {
+		It("answers requests from the cassette", func() {
+			performRequest.ReturnsOnCall(1, &http.Response{
+				Status:     "200 OK",
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"text/plain; charset=iso-8859-1"}},
+				Body:       ioutil.NopCloser(strings.NewReader("\xff\xfe latin-1 text")),
+			}, nil)
+			recorder, err := pkg.NewRecorder(dir, performRequest.Spy)
+			Expect(err).ToNot(HaveOccurred())
+			for _, rawURL := range []string{"https://marketplace.example.com/api/v1/products", "https://example.com/file.tgz?X-Amz-Signature=abc123"} {
+				request, err := http.NewRequest("GET", rawURL, nil)
+				Expect(err).ToNot(HaveOccurred())
+				_, err = recorder.Do(request)
+				Expect(err).ToNot(HaveOccurred())
+			}
+
+			replayer, err := pkg.LoadCassette(dir)
+			Expect(err).ToNot(HaveOccurred())
+
+			By("matching the method and URL", func() {
+				request, err := http.NewRequest("GET", "https://marketplace.example.com/api/v1/products", nil)
+				Expect(err).ToNot(HaveOccurred())
+				response, err := replayer.Do(request)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(response.StatusCode).To(Equal(http.StatusOK))
+				Expect(response.Header.Get("Content-Type")).To(Equal("application/json"))
+				Expect(ioutil.ReadAll(response.Body)).To(MatchJSON(`{"access_token":"[redacted]","expires_in":1799}`))
+			})
+
+			By("matching URLs with a different signature", func() {
+				request, err := http.NewRequest("GET", "https://example.com/file.tgz?X-Amz-Signature=def456", nil)
+				Expect(err).ToNot(HaveOccurred())
+				response, err := replayer.Do(request)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(ioutil.ReadAll(response.Body)).To(Equal([]byte("\xff\xfe latin-1 text")))
+			})
+
+			By("using the last match again", func() {
+				request, err := http.NewRequest("GET", "https://marketplace.example.com/api/v1/products", nil)
+				Expect(err).ToNot(HaveOccurred())
+				response, err := replayer.Do(request)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(response.StatusCode).To(Equal(http.StatusOK))
+			})
+
+			By("not using the network", func() {
+				Expect(performRequest.CallCount()).To(Equal(2))
+			})
+		})
+
+		When("there is no recorded response", func() {
+			It("returns an error", func() {
+				record("GET", "https://marketplace.example.com/api/v1/products", "", "")
+				replayer, err := pkg.LoadCassette(dir)
+				Expect(err).ToNot(HaveOccurred())
+
+				request, err := http.NewRequest("DELETE", "https://marketplace.example.com/api/v1/products", nil)
+				Expect(err).ToNot(HaveOccurred())
+				_, err = replayer.Do(request)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("no recorded response for DELETE https://marketplace.example.com/api/v1/products in " + dir))
+			})
+		})
+
+		When("the cassette is empty", func() {
+			It("returns an error", func() {
+				_, err := pkg.LoadCassette(dir)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("no recorded requests found in " + dir))
+			})
+		})
+	})
+})
@@ -4,6 +4,7 @@
 package pkg
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"net/http"
@@ -35,8 +36,8 @@ func LoadChart(chartPath string) (*models.ChartVersion, error) {
 	}, nil
 }
 
-func (m *Marketplace) DownloadChart(chartURL *url.URL) (*models.ChartVersion, error) {
-	req, err := http.NewRequest("GET", chartURL.String(), nil)
+func (m *Marketplace) DownloadChart(ctx context.Context, chartURL *url.URL) (*models.ChartVersion, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", chartURL.String(), nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to make request to download chart: %w", err)
 	}
@@ -53,6 +54,8 @@ func (m *Marketplace) DownloadChart(chartURL *url.URL) (*models.ChartVersion, er
 
 	_, err = io.Copy(chartFile, resp.Body)
 	if err != nil {
+		_ = chartFile.Close()
+		_ = os.Remove(chartFile.Name())
 		return nil, fmt.Errorf("failed to save local chart: %w", err)
 	}
 
@@ -68,17 +71,17 @@ func (m *Marketplace) DownloadChart(chartURL *url.URL) (*models.ChartVersion, er
 	return chart, nil
 }
 
-func (m *Marketplace) AttachLocalChart(chartPath, instructions string, product *models.Product, version *models.Version) (*models.Product, error) {
+func (m *Marketplace) AttachLocalChart(ctx context.Context, chartPath, instructions string, product *models.Product, version *models.Version) (*models.Product, error) {
 	chart, err := LoadChart(chartPath)
 	if err != nil {
 		return nil, err
 	}
 
-	uploader, err := m.GetUploader(product.PublisherDetails.OrgId)
+	uploader, err := m.GetUploader(ctx, product.PublisherDetails.OrgId)
 	if err != nil {
 		return nil, err
 	}
-	_, uploadedChartUrl, err := uploader.UploadProductFile(chartPath)
+	_, uploadedChartUrl, err := uploader.UploadProductFile(ctx, chartPath)
 	if err != nil {
 		return nil, err
 	}
@@ -89,11 +92,11 @@ func (m *Marketplace) AttachLocalChart(chartPath, instructions string, product *
 
 	product.PrepForUpdate()
 	product.ChartVersions = []*models.ChartVersion{chart}
-	return m.PutProduct(product, version.IsNewVersion)
+	return m.PutProduct(ctx, product, version.IsNewVersion)
 }
 
-func (m *Marketplace) AttachPublicChart(chartPath *url.URL, instructions string, product *models.Product, version *models.Version) (*models.Product, error) {
-	chart, err := m.DownloadChart(chartPath)
+func (m *Marketplace) AttachPublicChart(ctx context.Context, chartPath *url.URL, instructions string, product *models.Product, version *models.Version) (*models.Product, error) {
+	chart, err := m.DownloadChart(ctx, chartPath)
 	if err != nil {
 		return nil, err
 	}
@@ -103,5 +106,5 @@ func (m *Marketplace) AttachPublicChart(chartPath *url.URL, instructions string,
 
 	product.PrepForUpdate()
 	product.ChartVersions = []*models.ChartVersion{chart}
-	return m.PutProduct(product, version.IsNewVersion)
+	return m.PutProduct(ctx, product, version.IsNewVersion)
 }
@@ -5,6 +5,7 @@ package pkg_test
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"io/ioutil"
 	"net/http"
@@ -87,7 +88,7 @@ var _ = Describe("Charts", func() {
 		It("downloads a chart", func() {
 			chartUrl, err := url.Parse("https://charts.example.com/my-chart.tgz")
 			Expect(err).ToNot(HaveOccurred())
-			chart, err := marketplace.DownloadChart(chartUrl)
+			chart, err := marketplace.DownloadChart(context.Background(), chartUrl)
 			Expect(err).ToNot(HaveOccurred())
 
 			By("requesting the chart", func() {
@@ -105,7 +106,7 @@ var _ = Describe("Charts", func() {
 			It("returns an error", func() {
 				chartUrl, err := url.Parse("https://charts.example.com/my-chart.tgz")
 				Expect(err).ToNot(HaveOccurred())
-				_, err = marketplace.DownloadChart(chartUrl)
+				_, err = marketplace.DownloadChart(context.Background(), chartUrl)
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(Equal("failed to download chart: http request failed"))
 			})
@@ -118,7 +119,7 @@ var _ = Describe("Charts", func() {
 			It("returns an error", func() {
 				chartUrl, err := url.Parse("https://charts.example.com/my-chart.tgz")
 				Expect(err).ToNot(HaveOccurred())
-				_, err = marketplace.DownloadChart(chartUrl)
+				_, err = marketplace.DownloadChart(context.Background(), chartUrl)
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(ContainSubstring("failed to save local chart: read fail"))
 			})
@@ -131,7 +132,7 @@ var _ = Describe("Charts", func() {
 			It("returns an error", func() {
 				chartUrl, err := url.Parse("https://charts.example.com/my-chart.tgz")
 				Expect(err).ToNot(HaveOccurred())
-				_, err = marketplace.DownloadChart(chartUrl)
+				_, err = marketplace.DownloadChart(context.Background(), chartUrl)
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(Equal("failed to read chart: chart loading failed"))
 			})
@@ -151,7 +152,7 @@ var _ = Describe("Charts", func() {
 			product := test.CreateFakeProduct("", "Hyperspace Database", "hyperspace-database", models.SolutionTypeChart)
 			version := &models.Version{Number: "1.2.3"}
 			test.AddVerions(product, "1.2.3")
-			updatedProduct, err := marketplace.AttachLocalChart(chartPath, "helm install it", product, version)
+			updatedProduct, err := marketplace.AttachLocalChart(context.Background(), chartPath, "helm install it", product, version)
 			Expect(err).ToNot(HaveOccurred())
 
 			By("loading the local chart", func() {
@@ -161,7 +162,8 @@ var _ = Describe("Charts", func() {
 
 			By("uploading the chart", func() {
 				Expect(uploader.UploadProductFileCallCount()).To(Equal(1))
-				Expect(uploader.UploadProductFileArgsForCall(0)).To(Equal(chartPath))
+				_, uploadedFile := uploader.UploadProductFileArgsForCall(0)
+				Expect(uploadedFile).To(Equal(chartPath))
 			})
 
 			By("updating the product", func() {
@@ -186,7 +188,7 @@ var _ = Describe("Charts", func() {
 				product := test.CreateFakeProduct("", "Hyperspace Database", "hyperspace-database", models.SolutionTypeChart)
 				version := &models.Version{Number: "1.2.3"}
 				test.AddVerions(product, "1.2.3")
-				_, err := marketplace.AttachLocalChart(chartPath, "helm install it", product, version)
+				_, err := marketplace.AttachLocalChart(context.Background(), chartPath, "helm install it", product, version)
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(Equal("failed to read chart: load chart failed"))
 			})
@@ -201,7 +203,7 @@ var _ = Describe("Charts", func() {
 				product := test.CreateFakeProduct("", "Hyperspace Database", "hyperspace-database", models.SolutionTypeChart)
 				version := &models.Version{Number: "1.2.3"}
 				test.AddVerions(product, "1.2.3")
-				_, err := marketplace.AttachLocalChart(chartPath, "helm install it", product, version)
+				_, err := marketplace.AttachLocalChart(context.Background(), chartPath, "helm install it", product, version)
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(Equal("failed to get upload credentials: get uploader failed"))
 			})
@@ -215,7 +217,7 @@ var _ = Describe("Charts", func() {
 				product := test.CreateFakeProduct("", "Hyperspace Database", "hyperspace-database", models.SolutionTypeChart)
 				version := &models.Version{Number: "1.2.3"}
 				test.AddVerions(product, "1.2.3")
-				_, err := marketplace.AttachLocalChart(chartPath, "helm install it", product, version)
+				_, err := marketplace.AttachLocalChart(context.Background(), chartPath, "helm install it", product, version)
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(Equal("upload product file failed"))
 			})
@@ -229,7 +231,7 @@ var _ = Describe("Charts", func() {
 				product := test.CreateFakeProduct("", "Hyperspace Database", "hyperspace-database", models.SolutionTypeChart)
 				version := &models.Version{Number: "1.2.3"}
 				test.AddVerions(product, "1.2.3")
-				_, err := marketplace.AttachLocalChart(chartPath, "helm install it", product, version)
+				_, err := marketplace.AttachLocalChart(context.Background(), chartPath, "helm install it", product, version)
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(Equal("sending the update for product \"hyperspace-database\" failed: update product failed"))
 			})
@@ -262,7 +264,7 @@ var _ = Describe("Charts", func() {
 			product := test.CreateFakeProduct("", "Hyperspace Database", "hyperspace-database", models.SolutionTypeChart)
 			version := &models.Version{Number: "1.2.3"}
 			test.AddVerions(product, "1.2.3")
-			updatedProduct, err := marketplace.AttachPublicChart(chartUrl, "helm install it", product, version)
+			updatedProduct, err := marketplace.AttachPublicChart(context.Background(), chartUrl, "helm install it", product, version)
 			Expect(err).ToNot(HaveOccurred())
 
 			By("downloading the chart", func() {
@@ -274,7 +276,7 @@ var _ = Describe("Charts", func() {
 
 			By("updating the product", func() {
 				Expect(httpClient.PutCallCount()).To(Equal(1))
-				url, _, contentType := httpClient.PutArgsForCall(0)
+				_, url, _, contentType := httpClient.PutArgsForCall(0)
 				Expect(url.String()).To(ContainSubstring("https://marketplace.vmware.example/api/v1/products"))
 				Expect(contentType).To(Equal("application/json"))
 			})
@@ -296,7 +298,7 @@ var _ = Describe("Charts", func() {
 				product := test.CreateFakeProduct("", "Hyperspace Database", "hyperspace-database", models.SolutionTypeChart)
 				version := &models.Version{Number: "1.2.3"}
 				test.AddVerions(product, "1.2.3")
-				_, err := marketplace.AttachPublicChart(chartUrl, "helm install it", product, version)
+				_, err := marketplace.AttachPublicChart(context.Background(), chartUrl, "helm install it", product, version)
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(Equal("failed to download chart: download chart failed"))
 			})
@@ -310,7 +312,7 @@ var _ = Describe("Charts", func() {
 				product := test.CreateFakeProduct("", "Hyperspace Database", "hyperspace-database", models.SolutionTypeChart)
 				version := &models.Version{Number: "1.2.3"}
 				test.AddVerions(product, "1.2.3")
-				_, err := marketplace.AttachPublicChart(chartUrl, "helm install it", product, version)
+				_, err := marketplace.AttachPublicChart(context.Background(), chartUrl, "helm install it", product, version)
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(Equal("sending the update for product \"hyperspace-database\" failed: update product failed"))
 			})
@@ -4,21 +4,22 @@
 package pkg
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/vmware-labs/marketplace-cli/v2/internal/models"
 )
 
-func (m *Marketplace) AttachLocalContainerImage(imageFile, image, tag, tagType, instructions string, product *models.Product, version *models.Version) (*models.Product, error) {
+func (m *Marketplace) AttachLocalContainerImage(ctx context.Context, imageFile, image, tag, tagType, instructions string, product *models.Product, version *models.Version) (*models.Product, error) {
 	if product.HasContainerImage(version.Number, image, tag) {
 		return nil, fmt.Errorf("%s %s already has the image %s:%s", product.Slug, version.Number, image, tag)
 	}
 
-	uploader, err := m.GetUploader(product.PublisherDetails.OrgId)
+	uploader, err := m.GetUploader(ctx, product.PublisherDetails.OrgId)
 	if err != nil {
 		return nil, err
 	}
-	_, fileUrl, err := uploader.UploadProductFile(imageFile)
+	_, fileUrl, err := uploader.UploadProductFile(ctx, imageFile)
 	if err != nil {
 		return nil, err
 	}
@@ -42,10 +43,10 @@ func (m *Marketplace) AttachLocalContainerImage(imageFile, image, tag, tagType,
 		},
 	})
 
-	return m.PutProduct(product, version.IsNewVersion)
+	return m.PutProduct(ctx, product, version.IsNewVersion)
 }
 
-func (m *Marketplace) AttachPublicContainerImage(image, tag, tagType, instructions string, product *models.Product, version *models.Version) (*models.Product, error) {
+func (m *Marketplace) AttachPublicContainerImage(ctx context.Context, image, tag, tagType, instructions string, product *models.Product, version *models.Version) (*models.Product, error) {
 	if product.HasContainerImage(version.Number, image, tag) {
 		return nil, fmt.Errorf("%s %s already has the image %s:%s", product.Slug, version.Number, image, tag)
 	}
@@ -68,5 +69,5 @@ func (m *Marketplace) AttachPublicContainerImage(image, tag, tagType, instructio
 		},
 	})
 
-	return m.PutProduct(product, version.IsNewVersion)
+	return m.PutProduct(ctx, product, version.IsNewVersion)
 }
@@ -4,6 +4,7 @@
 package pkg_test
 
 import (
+	"context"
 	"errors"
 
 	. "github.com/onsi/ginkgo"
@@ -44,12 +45,13 @@ var _ = Describe("Container image", func() {
 			product := test.CreateFakeProduct("", "Hyperspace Database", "hyperspace-database", models.SolutionTypeImage)
 			test.AddVerions(product, "1.2.3")
 
-			updatedProduct, err := marketplace.AttachLocalContainerImage("image.tar", "nginx", "latest", "FLOATING", "docker run it", product, &models.Version{Number: "1.2.3"})
+			updatedProduct, err := marketplace.AttachLocalContainerImage(context.Background(), "image.tar", "nginx", "latest", "FLOATING", "docker run it", product, &models.Version{Number: "1.2.3"})
 			Expect(err).ToNot(HaveOccurred())
 
 			By("uploading the image file", func() {
 				Expect(uploader.UploadProductFileCallCount()).To(Equal(1))
-				Expect(uploader.UploadProductFileArgsForCall(0)).To(Equal("image.tar"))
+				_, uploadedFile := uploader.UploadProductFileArgsForCall(0)
+				Expect(uploadedFile).To(Equal("image.tar"))
 			})
 
 			By("updating the product in the marketplace", func() {
@@ -81,7 +83,7 @@ var _ = Describe("Container image", func() {
 					},
 				})
 
-				_, err := marketplace.AttachLocalContainerImage("image.tar", "nginx", "latest", "FLOATING", "docker run it", product, &models.Version{Number: "1.2.3"})
+				_, err := marketplace.AttachLocalContainerImage(context.Background(), "image.tar", "nginx", "latest", "FLOATING", "docker run it", product, &models.Version{Number: "1.2.3"})
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(Equal("hyperspace-database 1.2.3 already has the image nginx:latest"))
 			})
@@ -96,7 +98,7 @@ var _ = Describe("Container image", func() {
 				product := test.CreateFakeProduct("", "Hyperspace Database", "hyperspace-database", models.SolutionTypeImage)
 				test.AddVerions(product, "1.2.3")
 
-				_, err := marketplace.AttachLocalContainerImage("image.tar", "nginx", "latest", "FLOATING", "docker run it", product, &models.Version{Number: "1.2.3"})
+				_, err := marketplace.AttachLocalContainerImage(context.Background(), "image.tar", "nginx", "latest", "FLOATING", "docker run it", product, &models.Version{Number: "1.2.3"})
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(Equal("failed to get upload credentials: get uploader failed"))
 			})
@@ -110,7 +112,7 @@ var _ = Describe("Container image", func() {
 				product := test.CreateFakeProduct("", "Hyperspace Database", "hyperspace-database", models.SolutionTypeImage)
 				test.AddVerions(product, "1.2.3")
 
-				_, err := marketplace.AttachLocalContainerImage("image.tar", "nginx", "latest", "FLOATING", "docker run it", product, &models.Version{Number: "1.2.3"})
+				_, err := marketplace.AttachLocalContainerImage(context.Background(), "image.tar", "nginx", "latest", "FLOATING", "docker run it", product, &models.Version{Number: "1.2.3"})
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(Equal("upload failed"))
 			})
@@ -124,7 +126,7 @@ var _ = Describe("Container image", func() {
 				product := test.CreateFakeProduct("", "Hyperspace Database", "hyperspace-database", models.SolutionTypeImage)
 				test.AddVerions(product, "1.2.3")
 
-				_, err := marketplace.AttachLocalContainerImage("image.tar", "nginx", "latest", "FLOATING", "docker run it", product, &models.Version{Number: "1.2.3"})
+				_, err := marketplace.AttachLocalContainerImage(context.Background(), "image.tar", "nginx", "latest", "FLOATING", "docker run it", product, &models.Version{Number: "1.2.3"})
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(Equal("sending the update for product \"hyperspace-database\" failed: put product failed"))
 			})
@@ -140,7 +142,7 @@ var _ = Describe("Container image", func() {
 			product := test.CreateFakeProduct("", "Hyperspace Database", "hyperspace-database", models.SolutionTypeImage)
 			test.AddVerions(product, "1.2.3")
 
-			updatedProduct, err := marketplace.AttachPublicContainerImage("nginx", "latest", "FLOATING", "docker run it", product, &models.Version{Number: "1.2.3"})
+			updatedProduct, err := marketplace.AttachPublicContainerImage(context.Background(), "nginx", "latest", "FLOATING", "docker run it", product, &models.Version{Number: "1.2.3"})
 			Expect(err).ToNot(HaveOccurred())
 
 			By("updating the product in the marketplace", func() {
@@ -173,7 +175,7 @@ var _ = Describe("Container image", func() {
 					DockerType:            models.DockerTypeRegistry,
 				})
 
-				_, err := marketplace.AttachPublicContainerImage("nginx", "latest", "FLOATING", "docker run it", product, &models.Version{Number: "1.2.3"})
+				_, err := marketplace.AttachPublicContainerImage(context.Background(), "nginx", "latest", "FLOATING", "docker run it", product, &models.Version{Number: "1.2.3"})
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(Equal("hyperspace-database 1.2.3 already has the image nginx:latest"))
 			})
@@ -187,7 +189,7 @@ var _ = Describe("Container image", func() {
 				product := test.CreateFakeProduct("", "Hyperspace Database", "hyperspace-database", models.SolutionTypeImage)
 				test.AddVerions(product, "1.2.3")
 
-				_, err := marketplace.AttachPublicContainerImage("nginx", "latest", "FLOATING", "docker run it", product, &models.Version{Number: "1.2.3"})
+				_, err := marketplace.AttachPublicContainerImage(context.Background(), "nginx", "latest", "FLOATING", "docker run it", product, &models.Version{Number: "1.2.3"})
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(Equal("sending the update for product \"hyperspace-database\" failed: put product failed"))
 			})
@@ -4,6 +4,7 @@
 package pkg
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -37,9 +38,9 @@ type DownloadResponse struct {
 	Response *DownloadResponseBody `json:"response"`
 }
 
-func (m *Marketplace) Download(filename string, payload *DownloadRequestPayload) error {
+func (m *Marketplace) Download(ctx context.Context, filename string, payload *DownloadRequestPayload) error {
 	requestURL := MakeURL(m.GetHost(), fmt.Sprintf("/api/v1/products/%s/download", payload.ProductId), nil)
-	resp, err := m.Client.PostJSON(requestURL, payload)
+	resp, err := m.Client.PostJSON(ctx, requestURL, payload)
 	if err != nil {
 		return fmt.Errorf("failed to get download link: %w", err)
 	}
@@ -58,17 +59,30 @@ func (m *Marketplace) Download(filename string, payload *DownloadRequestPayload)
 		return fmt.Errorf("failed to parse response: %w", err)
 	}
 
-	return m.downloadFile(filename, downloadResponse.Response.PreSignedURL)
+	return m.downloadFile(ctx, filename, downloadResponse.Response.PreSignedURL)
 }
 
-func (m *Marketplace) downloadFile(filename string, fileDownloadURL string) error {
+func (m *Marketplace) downloadFile(ctx context.Context, filename string, fileDownloadURL string) error {
 	file, err := os.Create(filename)
 	if err != nil {
 		return fmt.Errorf("failed to create file for download: %w", err)
 	}
-	defer file.Close()
 
-	req, err := http.NewRequest("GET", fileDownloadURL, nil)
+	err = m.writeDownload(ctx, file, filename, fileDownloadURL)
+	closeErr := file.Close()
+	if err == nil && closeErr != nil {
+		err = fmt.Errorf("failed to download file to disk: %w", closeErr)
+	}
+	if err != nil {
+		// Do not leave a partial file behind, for example when the download was interrupted
+		_ = os.Remove(filename)
+		return err
+	}
+	return nil
+}
+
+func (m *Marketplace) writeDownload(ctx context.Context, file io.Writer, filename string, fileDownloadURL string) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", fileDownloadURL, nil)
 	if err != nil {
 		return fmt.Errorf("failed to create download file request: %w", err)
 	}
@@ -4,6 +4,7 @@
 package pkg_test
 
 import (
+	"context"
 	"errors"
 	"io"
 	"io/ioutil"
@@ -56,7 +57,7 @@ var _ = Describe("Download", func() {
 
 	AfterEach(func() {
 		if filename != "" {
-			Expect(os.Remove(filename)).To(Succeed())
+			_ = os.Remove(filename)
 		}
 	})
 
@@ -66,12 +67,12 @@ var _ = Describe("Download", func() {
 			ProductId:  "my-product-id",
 			AppVersion: "1.2.3",
 		}
-		err := marketplace.Download(filename, requestPayload)
+		err := marketplace.Download(context.Background(), filename, requestPayload)
 		Expect(err).ToNot(HaveOccurred())
 
 		By("requesting the download link", func() {
 			Expect(httpClient.PostJSONCallCount()).To(Equal(1))
-			url, requestPayload := httpClient.PostJSONArgsForCall(0)
+			_, url, requestPayload := httpClient.PostJSONArgsForCall(0)
 			Expect(url.String()).To(Equal("https://marketplace.example.com/api/v1/products/my-product-id/download"))
 			payload := requestPayload.(*pkg.DownloadRequestPayload)
 			Expect(payload.ProductId).To(Equal("my-product-id"))
@@ -110,7 +111,7 @@ var _ = Describe("Download", func() {
 				ProductId:  "my-product-id",
 				AppVersion: "1.2.3",
 			}
-			err := marketplace.Download("", requestPayload)
+			err := marketplace.Download(context.Background(), "", requestPayload)
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(Equal("failed to get download link: download link request failed"))
 		})
@@ -128,7 +129,7 @@ var _ = Describe("Download", func() {
 				ProductId:  "my-product-id",
 				AppVersion: "1.2.3",
 			}
-			err := marketplace.Download("", requestPayload)
+			err := marketplace.Download(context.Background(), "", requestPayload)
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(Equal("failed to fetch download link: I'm a teapot\ndownload link request failed"))
 		})
@@ -148,7 +149,7 @@ var _ = Describe("Download", func() {
 					ProductId:  "my-product-id",
 					AppVersion: "1.2.3",
 				}
-				err := marketplace.Download("", requestPayload)
+				err := marketplace.Download(context.Background(), "", requestPayload)
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(Equal("failed to fetch download link: I'm a teapot"))
 			})
@@ -165,7 +166,7 @@ var _ = Describe("Download", func() {
 				ProductId:  "my-product-id",
 				AppVersion: "1.2.3",
 			}
-			err := marketplace.Download("", requestPayload)
+			err := marketplace.Download(context.Background(), "", requestPayload)
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(Equal("failed to parse response: invalid character 'h' in literal true (expecting 'r')"))
 		})
@@ -177,7 +178,7 @@ var _ = Describe("Download", func() {
 				ProductId:  "my-product-id",
 				AppVersion: "1.2.3",
 			}
-			err := marketplace.Download("/this/path/does/not/exist", requestPayload)
+			err := marketplace.Download(context.Background(), "/this/path/does/not/exist", requestPayload)
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(Equal("failed to create file for download: open /this/path/does/not/exist: no such file or directory"))
 		})
@@ -197,9 +198,13 @@ var _ = Describe("Download", func() {
 				ProductId:  "my-product-id",
 				AppVersion: "1.2.3",
 			}
-			err := marketplace.Download(filename, requestPayload)
+			err := marketplace.Download(context.Background(), filename, requestPayload)
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(Equal("failed to create download file request: parse \": : this is a bad url\": missing protocol scheme"))
+
+			By("removing the partial file", func() {
+				Expect(filename).ToNot(BeAnExistingFile())
+			})
 		})
 	})
 
@@ -213,9 +218,13 @@ var _ = Describe("Download", func() {
 				ProductId:  "my-product-id",
 				AppVersion: "1.2.3",
 			}
-			err := marketplace.Download(filename, requestPayload)
+			err := marketplace.Download(context.Background(), filename, requestPayload)
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(Equal("failed to download file: download failed"))
+
+			By("removing the partial file", func() {
+				Expect(filename).ToNot(BeAnExistingFile())
+			})
 		})
 	})
 
@@ -229,9 +238,13 @@ var _ = Describe("Download", func() {
 				ProductId:  "my-product-id",
 				AppVersion: "1.2.3",
 			}
-			err := marketplace.Download(filename, requestPayload)
+			err := marketplace.Download(context.Background(), filename, requestPayload)
 			Expect(err).To(HaveOccurred())
 			Expect(err.Error()).To(Equal("failed to download file to disk: writing failed"))
+
+			By("removing the partial file", func() {
+				Expect(filename).ToNot(BeAnExistingFile())
+			})
 		})
 	})
 })
@@ -0,0 +1,102 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: BSD-2-Clause
+
+package pkg
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ProductFacet is one way that the list of products can be filtered, with the values that can be used for it
+type ProductFacet struct {
+	Key    string               `json:"key"`
+	Values []*ProductFacetValue `json:"values"`
+}
+
+type ProductFacetValue struct {
+	Value string `json:"value"`
+	Count int    `json:"count,omitempty"`
+}
+
+var (
+	facetValueFields = []string{"value", "key", "name", "displayname", "displayName", "id"}
+	facetCountFields = []string{"count", "doc_count", "total"}
+)
+
+// ParseProductFacets reads the available filters from the list of products response. The filters are keyed by name,
+// and each has a list of values, which are either plain values or objects with a value and a count of products.
+func ParseProductFacets(availableFilters interface{}) []*ProductFacet {
+	filters, ok := availableFilters.(map[string]interface{})
+	if !ok {
+		return []*ProductFacet{}
+	}
+
+	facets := []*ProductFacet{}
+	for key, values := range filters {
+		facet := &ProductFacet{
+			Key:    key,
+			Values: []*ProductFacetValue{},
+		}
+
+		switch values := values.(type) {
+		case []interface{}:
+			for _, value := range values {
+				if facetValue := parseFacetValue(value); facetValue != nil {
+					facet.Values = append(facet.Values, facetValue)
+				}
+			}
+		case map[string]interface{}:
+			// Values with their counts, like {"Networking": 12}
+			for value, count := range values {
+				facetValue := &ProductFacetValue{Value: value}
+				if number, ok := count.(float64); ok {
+					facetValue.Count = int(number)
+				}
+				facet.Values = append(facet.Values, facetValue)
+			}
+			sort.Slice(facet.Values, func(i, j int) bool {
+				return facet.Values[i].Value < facet.Values[j].Value
+			})
+		default:
+			if facetValue := parseFacetValue(values); facetValue != nil {
+				facet.Values = append(facet.Values, facetValue)
+			}
+		}
+		facets = append(facets, facet)
+	}
+
+	sort.Slice(facets, func(i, j int) bool {
+		return facets[i].Key < facets[j].Key
+	})
+	return facets
+}
+
+func parseFacetValue(value interface{}) *ProductFacetValue {
+	switch value := value.(type) {
+	case nil:
+		return nil
+	case string:
+		return &ProductFacetValue{Value: value}
+	case map[string]interface{}:
+		facetValue := &ProductFacetValue{}
+		for _, field := range facetValueFields {
+			if fieldValue, ok := value[field]; ok && fieldValue != nil {
+				facetValue.Value = fmt.Sprint(fieldValue)
+				break
+			}
+		}
+		for _, field := range facetCountFields {
+			if count, ok := value[field].(float64); ok {
+				facetValue.Count = int(count)
+				break
+			}
+		}
+		if facetValue.Value == "" {
+			return nil
+		}
+		return facetValue
+	default:
+		return &ProductFacetValue{Value: fmt.Sprint(value)}
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: BSD-2-Clause
+
+package pkg_test
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/vmware-labs/marketplace-cli/v2/pkg"
+)
+
+var _ = Describe("ParseProductFacets", func() {
+	parse := func(availableFilters string) []*pkg.ProductFacet {
+		var decoded interface{}
+		Expect(json.Unmarshal([]byte(availableFilters), &decoded)).To(Succeed())
+		return pkg.ParseProductFacets(decoded)
+	}
+
+	It("reads lists of values", func() {
+		facets := parse(`{"solutiontype": ["HELMCHARTS", "VM"], "categories": [{"displayname": "Networking", "count": 12}, {"id": 7}]}`)
+		Expect(facets).To(HaveLen(2))
+		Expect(facets[0].Key).To(Equal("categories"))
+		Expect(facets[0].Values).To(Equal([]*pkg.ProductFacetValue{
+			{Value: "Networking", Count: 12},
+			{Value: "7"},
+		}))
+		Expect(facets[1].Key).To(Equal("solutiontype"))
+		Expect(facets[1].Values).To(Equal([]*pkg.ProductFacetValue{{Value: "HELMCHARTS"}, {Value: "VM"}}))
+	})
+
+	It("reads values with counts", func() {
+		facets := parse(`{"publishers": {"VMware": 40, "Bitnami": 120}}`)
+		Expect(facets).To(HaveLen(1))
+		Expect(facets[0].Values).To(Equal([]*pkg.ProductFacetValue{
+			{Value: "Bitnami", Count: 120},
+			{Value: "VMware", Count: 40},
+		}))
+	})
+
+	When("there are no available filters", func() {
+		It("returns an empty list", func() {
+			Expect(pkg.ParseProductFacets(nil)).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,327 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: BSD-2-Clause
+
+package pkg
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MaxHARContentSize limits how much of each request and response body is kept in the HAR archive
+const MaxHARContentSize = 1024 * 1024
+
+// The types below are the parts of the HAR 1.2 format that the recorder fills in.
+// See http://www.softwareishard.com/blog/har-12-spec/
+
+type HAR struct {
+	Log *HARLog `json:"log"`
+}
+
+type HARLog struct {
+	Version string      `json:"version"`
+	Creator *HARCreator `json:"creator"`
+	Entries []*HAREntry `json:"entries"`
+}
+
+type HARCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type HAREntry struct {
+	StartedDateTime string       `json:"startedDateTime"`
+	Time            float64      `json:"time"`
+	Request         *HARRequest  `json:"request"`
+	Response        *HARResponse `json:"response"`
+	Cache           struct{}     `json:"cache"`
+	Timings         *HARTimings  `json:"timings"`
+	Comment         string       `json:"comment,omitempty"`
+}
+
+type HARNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type HARRequest struct {
+	Method      string          `json:"method"`
+	URL         string          `json:"url"`
+	HTTPVersion string          `json:"httpVersion"`
+	Cookies     []*HARNameValue `json:"cookies"`
+	Headers     []*HARNameValue `json:"headers"`
+	QueryString []*HARNameValue `json:"queryString"`
+	PostData    *HARPostData    `json:"postData,omitempty"`
+	HeadersSize int64           `json:"headersSize"`
+	BodySize    int64           `json:"bodySize"`
+}
+
+type HARPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type HARResponse struct {
+	Status      int             `json:"status"`
+	StatusText  string          `json:"statusText"`
+	HTTPVersion string          `json:"httpVersion"`
+	Cookies     []*HARNameValue `json:"cookies"`
+	Headers     []*HARNameValue `json:"headers"`
+	Content     *HARContent     `json:"content"`
+	RedirectURL string          `json:"redirectURL"`
+	HeadersSize int64           `json:"headersSize"`
+	BodySize    int64           `json:"bodySize"`
+}
+
+type HARContent struct {
+	Size     int64  `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+}
+
+type HARTimings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+// HARRecorder keeps every request and response that goes through the client, so that the session can be saved as a
+// HAR archive. Credentials are redacted, and only textual bodies are kept.
+type HARRecorder struct {
+	Creator *HARCreator
+
+	lock    sync.Mutex
+	records []*harRecord
+}
+
+type harRecord struct {
+	entry        *HAREntry
+	requestBody  *bodyCapture
+	responseBody *bodyCapture
+}
+
+func NewHARRecorder(name, version string) *HARRecorder {
+	return &HARRecorder{
+		Creator: &HARCreator{Name: name, Version: version},
+	}
+}
+
+// start records the request, and captures its body as it is sent
+func (r *HARRecorder) start(req *http.Request, started time.Time) *harRecord {
+	record := &harRecord{
+		entry: &HAREntry{
+			StartedDateTime: started.UTC().Format("2006-01-02T15:04:05.000Z"),
+			Request: &HARRequest{
+				Method:      req.Method,
+				URL:         RedactURL(req.URL),
+				HTTPVersion: req.Proto,
+				Cookies:     []*HARNameValue{},
+				Headers:     harHeaders(req.Header),
+				QueryString: harQueryString(req),
+				HeadersSize: -1,
+				BodySize:    req.ContentLength,
+			},
+			Timings: &HARTimings{},
+		},
+	}
+
+	if req.Body != nil && req.Body != http.NoBody {
+		record.requestBody = newBodyCapture(req.Body, req.Header.Get("Content-Type"))
+		req.Body = record.requestBody
+	}
+
+	r.lock.Lock()
+	r.records = append(r.records, record)
+	r.lock.Unlock()
+	return record
+}
+
+// finish records the response, and captures its body as it is read
+func (r *HARRecorder) finish(record *harRecord, resp *http.Response, err error, elapsed time.Duration) {
+	record.entry.Time = milliseconds(elapsed)
+	record.entry.Timings.Wait = milliseconds(elapsed)
+	record.entry.Response = &HARResponse{
+		Cookies:     []*HARNameValue{},
+		Headers:     []*HARNameValue{},
+		Content:     &HARContent{},
+		HeadersSize: -1,
+		BodySize:    -1,
+	}
+	if err != nil {
+		record.entry.Comment = RedactError(err)
+	}
+	if resp == nil {
+		return
+	}
+
+	record.entry.Response.Status = resp.StatusCode
+	record.entry.Response.StatusText = http.StatusText(resp.StatusCode)
+	record.entry.Response.HTTPVersion = resp.Proto
+	record.entry.Response.Headers = harHeaders(resp.Header)
+	record.entry.Response.RedirectURL = resp.Header.Get("Location")
+	record.entry.Response.Content.MimeType = resp.Header.Get("Content-Type")
+	if resp.Body != nil && resp.Body != http.NoBody {
+		record.responseBody = newBodyCapture(resp.Body, resp.Header.Get("Content-Type"))
+		resp.Body = record.responseBody
+	}
+}
+
+// HAR builds the archive from everything recorded so far
+func (r *HARRecorder) HAR() *HAR {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	har := &HAR{
+		Log: &HARLog{
+			Version: "1.2",
+			Creator: r.Creator,
+			Entries: []*HAREntry{},
+		},
+	}
+	for _, record := range r.records {
+		entry := *record.entry
+		request := *entry.Request
+		entry.Request = &request
+		if record.requestBody != nil {
+			size, text := record.requestBody.contents()
+			request.BodySize = size
+			request.PostData = &HARPostData{
+				MimeType: record.requestBody.contentType,
+				Text:     text,
+			}
+		}
+
+		if entry.Response != nil {
+			response := *entry.Response
+			content := *response.Content
+			response.Content = &content
+			entry.Response = &response
+			if record.responseBody != nil {
+				response.BodySize, content.Text = record.responseBody.contents()
+				content.Size = response.BodySize
+			}
+		}
+		har.Log.Entries = append(har.Log.Entries, &entry)
+	}
+	return har
+}
+
+func (r *HARRecorder) Write(writer io.Writer) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r.HAR())
+}
+
+// WriteFile saves the archive. It can contain product details, so only the current user can read it.
+func (r *HARRecorder) WriteFile(path string) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create the HAR file %s: %w", path, err)
+	}
+	defer file.Close()
+
+	err = r.Write(file)
+	if err != nil {
+		return fmt.Errorf("failed to write the HAR file %s: %w", path, err)
+	}
+	return nil
+}
+
+func harHeaders(headers http.Header) []*HARNameValue {
+	redacted := RedactHeaders(headers)
+	var names []string
+	for name := range redacted {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	values := []*HARNameValue{}
+	for _, name := range names {
+		for _, value := range redacted[name] {
+			values = append(values, &HARNameValue{Name: name, Value: value})
+		}
+	}
+	return values
+}
+
+func harQueryString(req *http.Request) []*HARNameValue {
+	query := req.URL.Query()
+	var names []string
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	values := []*HARNameValue{}
+	for _, name := range names {
+		for _, value := range query[name] {
+			if sensitiveFields[strings.ToLower(name)] {
+				value = RedactedValue
+			}
+			values = append(values, &HARNameValue{Name: name, Value: value})
+		}
+	}
+	return values
+}
+
+func milliseconds(duration time.Duration) float64 {
+	return float64(duration) / float64(time.Millisecond)
+}
+
+// isTextual is true for content that is worth keeping in the archive, unlike downloaded files
+func isTextual(contentType string) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	return strings.HasPrefix(mediaType, "text/") ||
+		mediaType == "application/json" ||
+		strings.HasSuffix(mediaType, "+json") ||
+		mediaType == "application/xml" ||
+		mediaType == "application/x-www-form-urlencoded"
+}
+
+// bodyCapture counts the bytes read from a body, and keeps a copy if it is textual
+type bodyCapture struct {
+	body        io.ReadCloser
+	contentType string
+
+	lock sync.Mutex
+	size int64
+	kept bytes.Buffer
+}
+
+func newBodyCapture(body io.ReadCloser, contentType string) *bodyCapture {
+	return &bodyCapture{body: body, contentType: contentType}
+}
+
+func (c *bodyCapture) Read(p []byte) (int, error) {
+	n, err := c.body.Read(p)
+	c.lock.Lock()
+	c.size += int64(n)
+	if isTextual(c.contentType) && c.kept.Len() < MaxHARContentSize {
+		keep := n
+		if c.kept.Len()+keep > MaxHARContentSize {
+			keep = MaxHARContentSize - c.kept.Len()
+		}
+		c.kept.Write(p[:keep])
+	}
+	c.lock.Unlock()
+	return n, err
+}
+
+func (c *bodyCapture) Close() error {
+	return c.body.Close()
+}
+
+func (c *bodyCapture) contents() (int64, string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.size, string(RedactPayload(c.contentType, c.kept.Bytes()))
+}
@@ -0,0 +1,154 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: BSD-2-Clause
+
+package pkg_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/vmware-labs/marketplace-cli/v2/pkg"
+	"github.com/vmware-labs/marketplace-cli/v2/pkg/pkgfakes"
+)
+
+var _ = Describe("HARRecorder", func() {
+	var (
+		httpClient     *pkg.DebuggingClient
+		performRequest *pkgfakes.FakePerformRequestFunc
+		recorder       *pkg.HARRecorder
+	)
+
+	BeforeEach(func() {
+		performRequest = &pkgfakes.FakePerformRequestFunc{}
+		performRequest.Returns(&http.Response{
+			Status:     "200 OK",
+			StatusCode: http.StatusOK,
+			Proto:      "HTTP/1.1",
+			Header:     http.Header{"Content-Type": []string{"application/json"}},
+			Body:       ioutil.NopCloser(strings.NewReader(`{"access_token":"my-new-access-token","expires_in":1799}`)),
+		}, nil)
+
+		recorder = pkg.NewHARRecorder("mkpcli", "1.2.3")
+		httpClient = pkg.NewClient(nil, false, false, false)
+		httpClient.PerformRequest = performRequest.Spy
+		httpClient.HAR = recorder
+		httpClient.SetAccessToken("my-access-token")
+	})
+
+	It("records requests and responses without credentials", func() {
+		requestURL := pkg.MakeURL("console.cloud.vmware.example", "/csp/gateway/am/api/auth/api-tokens/authorize", url.Values{"signature": []string{"abc123"}})
+		response, err := httpClient.PostForm(context.Background(), requestURL, url.Values{"refresh_token": []string{"my-api-token"}})
+		Expect(err).ToNot(HaveOccurred())
+
+		By("recording the request body as it is sent", func() {
+			request := performRequest.ArgsForCall(0)
+			_, err = ioutil.ReadAll(request.Body)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		By("recording the response body as it is read", func() {
+			_, err = ioutil.ReadAll(response.Body)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		har := recorder.HAR()
+		Expect(har.Log.Version).To(Equal("1.2"))
+		Expect(har.Log.Creator.Name).To(Equal("mkpcli"))
+		Expect(har.Log.Entries).To(HaveLen(1))
+
+		entry := har.Log.Entries[0]
+		Expect(entry.Request.Method).To(Equal("POST"))
+		Expect(entry.Request.URL).To(Equal("https://console.cloud.vmware.example/csp/gateway/am/api/auth/api-tokens/authorize?signature=%5Bredacted%5D"))
+		Expect(entry.Request.QueryString).To(ContainElement(&pkg.HARNameValue{Name: "signature", Value: pkg.RedactedValue}))
+		Expect(entry.Request.Headers).To(ContainElement(&pkg.HARNameValue{Name: "Csp-Auth-Token", Value: pkg.RedactedValue}))
+		Expect(entry.Request.PostData.MimeType).To(Equal("application/x-www-form-urlencoded"))
+		Expect(entry.Request.PostData.Text).To(Equal("refresh_token=%5Bredacted%5D"))
+
+		Expect(entry.Response.Status).To(Equal(http.StatusOK))
+		Expect(entry.Response.Content.MimeType).To(Equal("application/json"))
+		Expect(entry.Response.Content.Size).To(Equal(int64(56)))
+		Expect(entry.Response.Content.Text).To(MatchJSON(`{"access_token":"[redacted]","expires_in":1799}`))
+	})
+
+	It("does not keep binary content", func() {
+		performRequest.Returns(&http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"application/octet-stream"}},
+			Body:       ioutil.NopCloser(strings.NewReader("binary file contents")),
+		}, nil)
+
+		response, err := httpClient.Get(context.Background(), pkg.MakeURL("example.com", "/download/file.tgz", nil))
+		Expect(err).ToNot(HaveOccurred())
+		_, err = ioutil.ReadAll(response.Body)
+		Expect(err).ToNot(HaveOccurred())
+
+		content := recorder.HAR().Log.Entries[0].Response.Content
+		Expect(content.Size).To(Equal(int64(20)))
+		Expect(content.Text).To(BeEmpty())
+	})
+
+	It("records failed requests", func() {
+		performRequest.Returns(nil, &url.Error{
+			Op:  "Get",
+			URL: "https://example.com/download/file.tgz?X-Amz-Signature=abc123",
+			Err: errors.New("connection reset by peer"),
+		})
+
+		_, err := httpClient.Get(context.Background(), pkg.MakeURL("example.com", "/download/file.tgz", url.Values{"X-Amz-Signature": []string{"abc123"}}))
+		Expect(err).To(HaveOccurred())
+
+		entry := recorder.HAR().Log.Entries[0]
+		Expect(entry.Response.Status).To(Equal(0))
+		Expect(entry.Comment).To(Equal(`Get "https://example.com/download/file.tgz?X-Amz-Signature=%5Bredacted%5D": connection reset by peer`))
+	})
+
+	Describe("WriteFile", func() {
+		var dir string
+
+		BeforeEach(func() {
+			var err error
+			dir, err = os.MkdirTemp("", "mkpcli-har-test")
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			Expect(os.RemoveAll(dir)).To(Succeed())
+		})
+
+		It("saves the archive so only the current user can read it", func() {
+			_, err := httpClient.Get(context.Background(), pkg.MakeURL("marketplace.vmware.example", "/api/v1/products", nil))
+			Expect(err).ToNot(HaveOccurred())
+
+			path := filepath.Join(dir, "session.har")
+			Expect(recorder.WriteFile(path)).To(Succeed())
+
+			info, err := os.Stat(path)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+
+			contents, err := os.ReadFile(path)
+			Expect(err).ToNot(HaveOccurred())
+			har := &pkg.HAR{}
+			Expect(json.Unmarshal(contents, har)).To(Succeed())
+			Expect(har.Log.Entries).To(HaveLen(1))
+			Expect(har.Log.Entries[0].Request.URL).To(Equal("https://marketplace.vmware.example/api/v1/products"))
+		})
+
+		When("the file cannot be created", func() {
+			It("returns an error", func() {
+				err := recorder.WriteFile(filepath.Join(dir, "missing", "session.har"))
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(HavePrefix("failed to create the HAR file " + filepath.Join(dir, "missing", "session.har")))
+			})
+		})
+	})
+})
@@ -5,6 +5,7 @@ package pkg
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -13,31 +14,65 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
-
-	"github.com/spf13/viper"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 //go:generate counterfeiter . HTTPClient
 type HTTPClient interface {
-	Get(requestURL *url.URL) (*http.Response, error)
-	Post(requestURL *url.URL, content io.Reader, contentType string) (*http.Response, error)
-	PostForm(requestURL *url.URL, content url.Values) (resp *http.Response, err error)
-	PostJSON(requestURL *url.URL, content interface{}) (*http.Response, error)
-	Put(requestURL *url.URL, content io.Reader, contentType string) (*http.Response, error)
-	SendRequest(method string, requestURL *url.URL, headers map[string]string, content io.Reader) (*http.Response, error)
+	Get(ctx context.Context, requestURL *url.URL) (*http.Response, error)
+	Post(ctx context.Context, requestURL *url.URL, content io.Reader, contentType string) (*http.Response, error)
+	PostForm(ctx context.Context, requestURL *url.URL, content url.Values) (resp *http.Response, err error)
+	PostJSON(ctx context.Context, requestURL *url.URL, content interface{}) (*http.Response, error)
+	Put(ctx context.Context, requestURL *url.URL, content io.Reader, contentType string) (*http.Response, error)
+	SendRequest(ctx context.Context, method string, requestURL *url.URL, headers map[string]string, content io.Reader) (*http.Response, error)
 	Do(req *http.Request) (*http.Response, error)
+	SetAccessToken(token string)
 }
 
 //go:generate counterfeiter . PerformRequestFunc
 type PerformRequestFunc func(req *http.Request) (*http.Response, error)
 
+// Do lets a PerformRequestFunc be used wherever an HTTP client is expected, like for uploads to the storage bucket
+func (f PerformRequestFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+//go:generate counterfeiter . ReauthenticateFunc
+type ReauthenticateFunc func(ctx context.Context) (string, error)
+
+const (
+	LogFormatText = "text"
+	LogFormatJSON = "json"
+)
+
 type DebuggingClient struct {
 	Logger               *log.Logger
 	PrintRequests        bool
 	PrintRequestPayloads bool
 	PrintResposePayloads bool
-	requestID            int
+	requestID            int64
 	PerformRequest       PerformRequestFunc
+
+	// LogFormat is LogFormatText for human-readable debug output, or LogFormatJSON for one JSON object per line
+	LogFormat string
+	// HAR records every request and response, if set
+	HAR *HARRecorder
+
+	// Reauthenticate gets a new access token when a request to one of the ReauthenticateHosts is rejected with 401
+	Reauthenticate      ReauthenticateFunc
+	ReauthenticateHosts []string
+
+	// The access token is sent with every request, and might be replaced by concurrent requests.
+	// reauthenticateLock makes sure that only one of them gets a new token.
+	accessToken        string
+	accessTokenLock    sync.RWMutex
+	reauthenticateLock sync.Mutex
+
+	// Retry sends failed requests again. If nil, requests are only sent once.
+	Retry *RetryPolicy
+	Sleep SleepFunc
 }
 
 func NewClient(output io.Writer, printRequests, printRequestPayloads, printResponsePayloads bool) *DebuggingClient {
@@ -48,74 +83,164 @@ func NewClient(output io.Writer, printRequests, printRequestPayloads, printRespo
 		PrintResposePayloads: printResponsePayloads,
 		requestID:            0,
 		PerformRequest:       http.DefaultClient.Do,
+		LogFormat:            LogFormatText,
 	}
 }
 
-func (c *DebuggingClient) printRequest(req *http.Request) int {
-	requestID := c.requestID
-	c.requestID++
-	if c.PrintRequests {
-		c.Logger.Printf("Request #%d: %s %s\n", requestID, req.Method, req.URL.String())
+// RequestLog is a line of JSON debug output
+type RequestLog struct {
+	Time            time.Time `json:"time"`
+	RequestID       int64     `json:"request_id"`
+	Message         string    `json:"message,omitempty"`
+	Method          string    `json:"method"`
+	URL             string    `json:"url"`
+	Status          int       `json:"status,omitempty"`
+	LatencyMS       int64     `json:"latency_ms"`
+	RequestBytes    int64     `json:"request_bytes"`
+	ResponseBytes   int64     `json:"response_bytes"`
+	RequestPayload  string    `json:"request_payload,omitempty"`
+	ResponsePayload string    `json:"response_payload,omitempty"`
+	Error           string    `json:"error,omitempty"`
+	Attempt         int       `json:"attempt,omitempty"`
+	WaitMS          int64     `json:"wait_ms,omitempty"`
+}
+
+func (c *DebuggingClient) jsonLogs() bool {
+	return c.LogFormat == LogFormatJSON
+}
+
+func (c *DebuggingClient) printJSON(entry *RequestLog) {
+	encoded, err := json.Marshal(entry)
+	if err != nil {
+		return
 	}
-	if c.PrintRequestPayloads && req.ContentLength > 0 {
-		req.Body = c.printPayload(fmt.Sprintf("request #%d body", requestID), req.Body)
+	_, _ = fmt.Fprintln(c.Logger.Writer(), string(encoded))
+}
+
+func (c *DebuggingClient) printRequest(requestID int64, req *http.Request) string {
+	if c.PrintRequests && !c.jsonLogs() {
+		c.Logger.Printf("Request #%d: %s %s\n", requestID, req.Method, RedactURL(req.URL))
 	}
 
-	return requestID
+	var payload string
+	if c.PrintRequestPayloads && req.ContentLength > 0 {
+		req.Body, payload = c.readPayload(req.Header.Get("Content-Type"), req.Body)
+		c.printPayload(fmt.Sprintf("request #%d body", requestID), payload)
+	}
+	return payload
 }
 
-func (c *DebuggingClient) printResponse(requestID int, resp *http.Response) {
-	if c.PrintRequests && resp != nil {
-		c.Logger.Printf("Request #%d Response: %s", requestID, resp.Status)
+func (c *DebuggingClient) printResponse(requestID int64, req *http.Request, resp *http.Response, err error, latency time.Duration, requestPayload string) {
+	if !c.PrintRequests {
+		return
+	}
+
+	var payload string
+	if resp != nil && c.PrintResposePayloads {
+		resp.Body, payload = c.readPayload(resp.Header.Get("Content-Type"), resp.Body)
+	}
+
+	if c.jsonLogs() {
+		entry := &RequestLog{
+			Time:            time.Now().UTC(),
+			RequestID:       requestID,
+			Method:          req.Method,
+			URL:             RedactURL(req.URL),
+			LatencyMS:       latency.Milliseconds(),
+			RequestBytes:    req.ContentLength,
+			ResponseBytes:   -1,
+			RequestPayload:  requestPayload,
+			ResponsePayload: payload,
+		}
+		if resp != nil {
+			entry.Status = resp.StatusCode
+			entry.ResponseBytes = resp.ContentLength
+		}
+		if err != nil {
+			entry.Error = RedactError(err)
+		}
+		c.printJSON(entry)
+		return
+	}
+
+	if resp != nil {
+		c.Logger.Printf("Request #%d Response: %s (%s)", requestID, resp.Status, latency.Round(time.Millisecond))
 		if c.PrintResposePayloads {
-			resp.Body = c.printPayload(fmt.Sprintf("request #%d response body", requestID), resp.Body)
+			c.printPayload(fmt.Sprintf("request #%d response body", requestID), payload)
 		}
 	}
 }
 
-func (c *DebuggingClient) printPayload(name string, payload io.ReadCloser) io.ReadCloser {
-	c.Logger.Printf("--- Start of %s payload ---", name)
+// readPayload reads the whole payload, and returns a replacement for it along with a redacted copy for printing
+func (c *DebuggingClient) readPayload(contentType string, payload io.ReadCloser) (io.ReadCloser, string) {
 	content, _ := ioutil.ReadAll(payload)
-	c.Logger.Println(string(content))
+	return io.NopCloser(bytes.NewReader(content)), string(RedactPayload(contentType, content))
+}
+
+func (c *DebuggingClient) printPayload(name, payload string) {
+	if c.jsonLogs() {
+		return
+	}
+	c.Logger.Printf("--- Start of %s payload ---", name)
+	c.Logger.Println(payload)
 	c.Logger.Printf("--- End of %s payload ---", name)
+}
 
-	return io.NopCloser(bytes.NewReader(content))
+func (c *DebuggingClient) printRetry(requestID int64, req *http.Request, wait time.Duration, attempt int) {
+	if !c.PrintRequests {
+		return
+	}
+	if c.jsonLogs() {
+		c.printJSON(&RequestLog{
+			Time:          time.Now().UTC(),
+			RequestID:     requestID,
+			Message:       "retrying",
+			Method:        req.Method,
+			URL:           RedactURL(req.URL),
+			RequestBytes:  req.ContentLength,
+			ResponseBytes: -1,
+			Attempt:       attempt,
+			WaitMS:        wait.Milliseconds(),
+		})
+		return
+	}
+	c.Logger.Printf("Retrying %s %s in %s (attempt %d of %d)", req.Method, RedactURL(req.URL), wait, attempt, c.Retry.MaxAttempts)
 }
 
-func (c *DebuggingClient) Get(requestURL *url.URL) (*http.Response, error) {
-	return c.SendRequest("GET", requestURL, map[string]string{}, nil)
+func (c *DebuggingClient) Get(ctx context.Context, requestURL *url.URL) (*http.Response, error) {
+	return c.SendRequest(ctx, "GET", requestURL, map[string]string{}, nil)
 }
 
-func (c *DebuggingClient) Post(requestURL *url.URL, content io.Reader, contentType string) (*http.Response, error) {
+func (c *DebuggingClient) Post(ctx context.Context, requestURL *url.URL, content io.Reader, contentType string) (*http.Response, error) {
 	headers := map[string]string{
 		"Content-Type": contentType,
 	}
-	return c.SendRequest("POST", requestURL, headers, content)
+	return c.SendRequest(ctx, "POST", requestURL, headers, content)
 }
 
-func (c *DebuggingClient) PostForm(requestURL *url.URL, content url.Values) (resp *http.Response, err error) {
-	return c.Post(requestURL, strings.NewReader(content.Encode()), "application/x-www-form-urlencoded")
+func (c *DebuggingClient) PostForm(ctx context.Context, requestURL *url.URL, content url.Values) (resp *http.Response, err error) {
+	return c.Post(ctx, requestURL, strings.NewReader(content.Encode()), "application/x-www-form-urlencoded")
 }
 
-func (c *DebuggingClient) PostJSON(requestURL *url.URL, content interface{}) (*http.Response, error) {
+func (c *DebuggingClient) PostJSON(ctx context.Context, requestURL *url.URL, content interface{}) (*http.Response, error) {
 	encoded, err := json.Marshal(content)
 	if err != nil {
 		return nil, fmt.Errorf("failed to encode request payload: %w", err)
 	}
 
-	return c.Post(requestURL, bytes.NewReader(encoded), "application/json")
+	return c.Post(ctx, requestURL, bytes.NewReader(encoded), "application/json")
 }
 
-func (c *DebuggingClient) Put(requestURL *url.URL, content io.Reader, contentType string) (*http.Response, error) {
+func (c *DebuggingClient) Put(ctx context.Context, requestURL *url.URL, content io.Reader, contentType string) (*http.Response, error) {
 	headers := map[string]string{}
 	if contentType != "" {
 		headers["Content-Type"] = contentType
 	}
-	return c.SendRequest("PUT", requestURL, headers, content)
+	return c.SendRequest(ctx, "PUT", requestURL, headers, content)
 }
 
-func (c *DebuggingClient) SendRequest(method string, requestURL *url.URL, headers map[string]string, content io.Reader) (*http.Response, error) {
-	req, err := http.NewRequest(method, requestURL.String(), content)
+func (c *DebuggingClient) SendRequest(ctx context.Context, method string, requestURL *url.URL, headers map[string]string, content io.Reader) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, requestURL.String(), content)
 	if err != nil {
 		return nil, fmt.Errorf("failed to build %s request: %w", requestURL.String(), err)
 	}
@@ -125,9 +250,8 @@ func (c *DebuggingClient) SendRequest(method string, requestURL *url.URL, header
 	}
 
 	req.Header.Add("Accept", "application/json")
-	CSPAPIToken := viper.GetString("csp.refresh-token")
-	if CSPAPIToken != "" {
-		req.Header.Add("csp-auth-token", viper.GetString("csp.refresh-token"))
+	if accessToken := c.getAccessToken(); accessToken != "" {
+		req.Header.Add("csp-auth-token", accessToken)
 	}
 
 	resp, err := c.Do(req)
@@ -135,16 +259,186 @@ func (c *DebuggingClient) SendRequest(method string, requestURL *url.URL, header
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
 
+	if resp.StatusCode == http.StatusUnauthorized && c.canReauthenticate(req) {
+		return c.reauthenticateAndReplay(req, resp)
+	}
+
+	return resp, nil
+}
+
+// SetAccessToken sets the CSP access token sent with each request
+func (c *DebuggingClient) SetAccessToken(token string) {
+	c.accessTokenLock.Lock()
+	defer c.accessTokenLock.Unlock()
+	c.accessToken = token
+}
+
+func (c *DebuggingClient) getAccessToken() string {
+	c.accessTokenLock.RLock()
+	defer c.accessTokenLock.RUnlock()
+	return c.accessToken
+}
+
+// refreshAccessToken replaces the rejected access token. If another request already replaced it in the meantime,
+// that new token is used instead of getting yet another one.
+func (c *DebuggingClient) refreshAccessToken(ctx context.Context, rejected string) (string, error) {
+	c.reauthenticateLock.Lock()
+	defer c.reauthenticateLock.Unlock()
+
+	if current := c.getAccessToken(); current != rejected {
+		return current, nil
+	}
+
+	token, err := c.Reauthenticate(ctx)
+	if err != nil {
+		return "", err
+	}
+	c.SetAccessToken(token)
+	return token, nil
+}
+
+func (c *DebuggingClient) canReauthenticate(req *http.Request) bool {
+	if c.Reauthenticate == nil || req.Header.Get("csp-auth-token") == "" {
+		return false
+	}
+	for _, host := range c.ReauthenticateHosts {
+		if req.URL.Host == host {
+			return true
+		}
+	}
+	return false
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// reauthenticateAndReplay gets a new access token after the current one was rejected,
+// and sends the request again if it is safe to do so. Otherwise, the original response is returned.
+func (c *DebuggingClient) reauthenticateAndReplay(req *http.Request, resp *http.Response) (*http.Response, error) {
+	token, err := c.refreshAccessToken(req.Context(), req.Header.Get("csp-auth-token"))
+	if err != nil {
+		return nil, fmt.Errorf("the access token was rejected, and re-authenticating failed: %w", err)
+	}
+
+	if !isIdempotent(req.Method) || (req.Body != nil && req.GetBody == nil) {
+		return resp, nil
+	}
+
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		retry.Body, err = req.GetBody()
+		if err != nil {
+			return nil, fmt.Errorf("failed to replay %s request: %w", req.URL.String(), err)
+		}
+	}
+	retry.Header.Set("csp-auth-token", token)
+	if resp.Body != nil {
+		_ = resp.Body.Close()
+	}
+
+	resp, err = c.Do(retry)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
 	return resp, nil
 }
 
 func (c *DebuggingClient) Do(req *http.Request) (*http.Response, error) {
-	requestID := c.printRequest(req)
+	if !c.Retry.CanRetry(req.Method) {
+		return c.do(c.nextRequestID(), req)
+	}
+
+	err := rewindableBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	for attempt := 1; ; attempt++ {
+		requestID := c.nextRequestID()
+		resp, err := c.do(requestID, req)
+		if attempt >= c.Retry.MaxAttempts || !ShouldRetry(resp, err) {
+			return resp, err
+		}
+
+		wait, ok := c.Retry.Wait(attempt, resp)
+		if !ok {
+			return resp, err
+		}
+		if resp != nil && resp.Body != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+		}
+		c.printRetry(requestID, req, wait, attempt+1)
+		err = c.sleep(req.Context(), wait)
+		if err != nil {
+			return nil, err
+		}
+
+		if req.GetBody != nil {
+			req.Body, err = req.GetBody()
+			if err != nil {
+				return nil, fmt.Errorf("failed to replay %s request: %w", req.URL.String(), err)
+			}
+		}
+	}
+}
+
+// nextRequestID numbers each request in the debug output. Requests can be sent concurrently.
+func (c *DebuggingClient) nextRequestID() int64 {
+	return atomic.AddInt64(&c.requestID, 1) - 1
+}
+
+func (c *DebuggingClient) do(requestID int64, req *http.Request) (*http.Response, error) {
+	requestPayload := c.printRequest(requestID, req)
+
+	started := time.Now()
+	var record *harRecord
+	if c.HAR != nil {
+		record = c.HAR.start(req, started)
+	}
+
 	resp, err := c.PerformRequest(req)
-	c.printResponse(requestID, resp)
+	latency := time.Since(started)
+
+	if record != nil {
+		c.HAR.finish(record, resp, err, latency)
+	}
+	c.printResponse(requestID, req, resp, err, latency, requestPayload)
 	return resp, err
 }
 
+func (c *DebuggingClient) sleep(ctx context.Context, duration time.Duration) error {
+	if c.Sleep != nil {
+		return c.Sleep(ctx, duration)
+	}
+	return Sleep(ctx, duration)
+}
+
+// rewindableBody buffers the request body, if it cannot already be read again, so that the request can be retried
+func rewindableBody(req *http.Request) error {
+	if req.Body == nil || req.Body == http.NoBody || req.GetBody != nil {
+		return nil
+	}
+
+	content, err := io.ReadAll(req.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read the %s request body: %w", req.URL.String(), err)
+	}
+	_ = req.Body.Close()
+
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(content)), nil
+	}
+	req.Body, _ = req.GetBody()
+	req.ContentLength = int64(len(content))
+	return nil
+}
+
 type QueryStringParameter interface {
 	QueryString() string
 }
@@ -4,14 +4,21 @@
 package pkg_test
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"regexp"
 	"strings"
+	"sync"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
-	"github.com/spf13/viper"
+	. "github.com/onsi/gomega/gbytes"
 	"github.com/vmware-labs/marketplace-cli/v2/internal"
 	"github.com/vmware-labs/marketplace-cli/v2/pkg"
 	"github.com/vmware-labs/marketplace-cli/v2/pkg/pkgfakes"
@@ -24,18 +31,18 @@ var _ = Describe("HTTP Client", func() {
 	)
 
 	BeforeEach(func() {
-		viper.Set("csp.refresh-token", "secrets")
 		performRequest = &pkgfakes.FakePerformRequestFunc{}
 		performRequest.Returns(&http.Response{
 			StatusCode: http.StatusTeapot,
 		}, nil)
 		httpClient = pkg.NewClient(nil, false, false, false)
 		httpClient.PerformRequest = performRequest.Spy
+		httpClient.SetAccessToken("secrets")
 	})
 
 	var _ = Describe("Get", func() {
 		It("sends a valid request", func() {
-			response, err := httpClient.Get(pkg.MakeURL(
+			response, err := httpClient.Get(context.Background(), pkg.MakeURL(
 				"marketplace.vmware.example",
 				"/api/v1/unit-tests",
 				url.Values{
@@ -67,7 +74,7 @@ var _ = Describe("HTTP Client", func() {
 	Describe("Put", func() {
 		It("sends a valid request", func() {
 			content := strings.NewReader("everything totally passed")
-			response, err := httpClient.Put(
+			response, err := httpClient.Put(context.Background(),
 				pkg.MakeURL(
 					"marketplace.vmware.example",
 					"/api/v1/unit-tests",
@@ -104,6 +111,386 @@ var _ = Describe("HTTP Client", func() {
 			})
 		})
 	})
+
+	Describe("Debug output", func() {
+		var output *Buffer
+
+		BeforeEach(func() {
+			output = NewBuffer()
+			httpClient = pkg.NewClient(output, true, false, false)
+			httpClient.PerformRequest = performRequest.Spy
+			performRequest.Returns(&http.Response{
+				Status:        "200 OK",
+				StatusCode:    http.StatusOK,
+				ContentLength: 42,
+			}, nil)
+		})
+
+		It("prints each request without signatures", func() {
+			requestURL := pkg.MakeURL("marketplace.vmware.example", "/download", url.Values{"X-Amz-Signature": []string{"signed"}})
+			_, err := httpClient.Get(context.Background(), requestURL)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(output).To(Say(regexp.QuoteMeta("Request #0: GET https://marketplace.vmware.example/download?X-Amz-Signature=%5Bredacted%5D")))
+			Expect(output).To(Say(regexp.QuoteMeta("Request #0 Response: 200 OK")))
+			Expect(string(output.Contents())).ToNot(ContainSubstring("signed"))
+		})
+
+		When("the format is JSON", func() {
+			BeforeEach(func() {
+				httpClient.LogFormat = pkg.LogFormatJSON
+				httpClient.PrintRequestPayloads = true
+			})
+
+			It("prints one JSON object per request", func() {
+				requestURL := pkg.MakeURL("console.cloud.vmware.example", "/csp/gateway/am/api/auth/api-tokens/authorize", nil)
+				_, err := httpClient.PostForm(context.Background(), requestURL, url.Values{"refresh_token": []string{"my-api-token"}})
+				Expect(err).ToNot(HaveOccurred())
+
+				lines := strings.Split(strings.TrimSpace(string(output.Contents())), "\n")
+				Expect(lines).To(HaveLen(1))
+
+				entry := &pkg.RequestLog{}
+				Expect(json.Unmarshal([]byte(lines[0]), entry)).To(Succeed())
+				Expect(entry.RequestID).To(Equal(int64(0)))
+				Expect(entry.Method).To(Equal("POST"))
+				Expect(entry.URL).To(Equal("https://console.cloud.vmware.example/csp/gateway/am/api/auth/api-tokens/authorize"))
+				Expect(entry.Status).To(Equal(http.StatusOK))
+				Expect(entry.LatencyMS).To(BeNumerically(">=", 0))
+				Expect(entry.RequestBytes).To(Equal(int64(len("refresh_token=my-api-token"))))
+				Expect(entry.ResponseBytes).To(Equal(int64(42)))
+				Expect(entry.RequestPayload).To(Equal("refresh_token=%5Bredacted%5D"))
+			})
+		})
+
+		It("numbers concurrent requests uniquely", func() {
+			var wait sync.WaitGroup
+			for i := 0; i < 10; i++ {
+				wait.Add(1)
+				go func() {
+					defer GinkgoRecover()
+					defer wait.Done()
+					_, err := httpClient.Get(context.Background(), pkg.MakeURL("marketplace.vmware.example", "/api/v1/unit-tests", nil))
+					Expect(err).ToNot(HaveOccurred())
+				}()
+			}
+			wait.Wait()
+
+			for i := 0; i < 10; i++ {
+				Expect(string(output.Contents())).To(ContainSubstring(fmt.Sprintf("Request #%d: GET", i)))
+			}
+		})
+	})
+
+	Describe("Re-authenticating", func() {
+		var reauthenticate *pkgfakes.FakeReauthenticateFunc
+
+		BeforeEach(func() {
+			reauthenticate = &pkgfakes.FakeReauthenticateFunc{}
+			reauthenticate.Returns("new-secrets", nil)
+			httpClient.Reauthenticate = reauthenticate.Spy
+			httpClient.ReauthenticateHosts = []string{"marketplace.vmware.example"}
+
+			performRequest.ReturnsOnCall(0, &http.Response{
+				StatusCode: http.StatusUnauthorized,
+				Body:       ioutil.NopCloser(strings.NewReader("")),
+			}, nil)
+		})
+
+		It("gets a new access token and replays the request", func() {
+			response, err := httpClient.Put(context.Background(),
+				pkg.MakeURL("marketplace.vmware.example", "/api/v1/unit-tests", nil),
+				strings.NewReader("everything totally passed"),
+				"text/plain",
+			)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.StatusCode).To(Equal(http.StatusTeapot))
+
+			Expect(reauthenticate.CallCount()).To(Equal(1))
+			Expect(performRequest.CallCount()).To(Equal(2))
+			request := performRequest.ArgsForCall(1)
+			Expect(request.Method).To(Equal("PUT"))
+			Expect(request.Header.Get("csp-auth-token")).To(Equal("new-secrets"))
+			Expect(request.Header.Get("Content-Type")).To(Equal("text/plain"))
+			Expect(ioutil.ReadAll(request.Body)).To(Equal([]byte("everything totally passed")))
+		})
+
+		It("only replays the request once", func() {
+			performRequest.Returns(&http.Response{StatusCode: http.StatusUnauthorized}, nil)
+
+			response, err := httpClient.Get(context.Background(), pkg.MakeURL("marketplace.vmware.example", "/api/v1/unit-tests", nil))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+			Expect(reauthenticate.CallCount()).To(Equal(1))
+			Expect(performRequest.CallCount()).To(Equal(2))
+		})
+
+		When("the request is not idempotent", func() {
+			It("gets a new access token, but does not replay the request", func() {
+				response, err := httpClient.PostJSON(context.Background(), pkg.MakeURL("marketplace.vmware.example", "/api/v1/unit-tests", nil), map[string]string{})
+				Expect(err).ToNot(HaveOccurred())
+				Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+				Expect(reauthenticate.CallCount()).To(Equal(1))
+				Expect(performRequest.CallCount()).To(Equal(1))
+			})
+		})
+
+		When("the request is not for a marketplace host", func() {
+			It("does not re-authenticate", func() {
+				response, err := httpClient.Get(context.Background(), pkg.MakeURL("other.vmware.example", "/api/v1/unit-tests", nil))
+				Expect(err).ToNot(HaveOccurred())
+				Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+				Expect(reauthenticate.CallCount()).To(Equal(0))
+			})
+		})
+
+		When("concurrent requests are rejected", func() {
+			It("only gets one new access token", func() {
+				const pages = 4
+				var rejected sync.WaitGroup
+				rejected.Add(pages)
+				performRequest.Calls(func(req *http.Request) (*http.Response, error) {
+					if req.Header.Get("csp-auth-token") == "secrets" {
+						// Reject every page before any of them gets a new token
+						rejected.Done()
+						rejected.Wait()
+						return &http.Response{StatusCode: http.StatusUnauthorized}, nil
+					}
+					return &http.Response{StatusCode: http.StatusOK}, nil
+				})
+
+				var wg sync.WaitGroup
+				for page := 1; page <= pages; page++ {
+					wg.Add(1)
+					go func(page int) {
+						defer GinkgoRecover()
+						defer wg.Done()
+						response, err := httpClient.Get(context.Background(), pkg.MakeURL("marketplace.vmware.example", "/api/v1/products", url.Values{
+							"pagination": []string{fmt.Sprintf(`{"page":%d}`, page)},
+						}))
+						Expect(err).ToNot(HaveOccurred())
+						Expect(response.StatusCode).To(Equal(http.StatusOK))
+					}(page)
+				}
+				wg.Wait()
+
+				Expect(reauthenticate.CallCount()).To(Equal(1))
+				Expect(performRequest.CallCount()).To(Equal(2 * pages))
+				for index := pages; index < 2*pages; index++ {
+					Expect(performRequest.ArgsForCall(index).Header.Get("csp-auth-token")).To(Equal("new-secrets"))
+				}
+			})
+		})
+
+		When("re-authenticating fails", func() {
+			BeforeEach(func() {
+				reauthenticate.Returns("", errors.New("redeem failed"))
+			})
+
+			It("returns an error", func() {
+				_, err := httpClient.Get(context.Background(), pkg.MakeURL("marketplace.vmware.example", "/api/v1/unit-tests", nil))
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("the access token was rejected, and re-authenticating failed: redeem failed"))
+				Expect(performRequest.CallCount()).To(Equal(1))
+			})
+		})
+	})
+
+	Describe("Retrying", func() {
+		var sleep *pkgfakes.FakeSleepFunc
+
+		BeforeEach(func() {
+			sleep = &pkgfakes.FakeSleepFunc{}
+			httpClient.Sleep = sleep.Spy
+			httpClient.Retry = &pkg.RetryPolicy{
+				MaxAttempts:    3,
+				InitialBackoff: time.Second,
+				MaxBackoff:     10 * time.Second,
+				MaxRetryAfter:  time.Minute,
+			}
+		})
+
+		It("retries server errors with backoff", func() {
+			performRequest.ReturnsOnCall(0, &http.Response{StatusCode: http.StatusBadGateway}, nil)
+			performRequest.ReturnsOnCall(1, nil, errors.New("connection reset by peer"))
+
+			response, err := httpClient.Get(context.Background(), pkg.MakeURL("marketplace.vmware.example", "/api/v1/unit-tests", nil))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.StatusCode).To(Equal(http.StatusTeapot))
+			Expect(performRequest.CallCount()).To(Equal(3))
+
+			Expect(sleep.CallCount()).To(Equal(2))
+			_, firstWait := sleep.ArgsForCall(0)
+			Expect(firstWait).To(BeNumerically("<=", time.Second))
+			_, secondWait := sleep.ArgsForCall(1)
+			Expect(secondWait).To(BeNumerically(">=", time.Second))
+		})
+
+		It("waits as long as the Retry-After header asks", func() {
+			performRequest.ReturnsOnCall(0, &http.Response{
+				StatusCode: http.StatusTooManyRequests,
+				Header:     http.Header{"Retry-After": []string{"7"}},
+			}, nil)
+
+			_, err := httpClient.Get(context.Background(), pkg.MakeURL("marketplace.vmware.example", "/api/v1/unit-tests", nil))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(sleep.CallCount()).To(Equal(1))
+			_, wait := sleep.ArgsForCall(0)
+			Expect(wait).To(Equal(7 * time.Second))
+		})
+
+		It("waits longer than the maximum backoff if Retry-After asks for it", func() {
+			performRequest.ReturnsOnCall(0, &http.Response{
+				StatusCode: http.StatusTooManyRequests,
+				Header:     http.Header{"Retry-After": []string{"30"}},
+			}, nil)
+
+			response, err := httpClient.Get(context.Background(), pkg.MakeURL("marketplace.vmware.example", "/api/v1/unit-tests", nil))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.StatusCode).To(Equal(http.StatusTeapot))
+			Expect(performRequest.CallCount()).To(Equal(2))
+			_, wait := sleep.ArgsForCall(0)
+			Expect(wait).To(Equal(30 * time.Second))
+		})
+
+		It("gives up if Retry-After asks to wait longer than the maximum Retry-After", func() {
+			performRequest.ReturnsOnCall(0, &http.Response{
+				StatusCode: http.StatusTooManyRequests,
+				Header:     http.Header{"Retry-After": []string{"3600"}},
+			}, nil)
+
+			response, err := httpClient.Get(context.Background(), pkg.MakeURL("marketplace.vmware.example", "/api/v1/unit-tests", nil))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.StatusCode).To(Equal(http.StatusTooManyRequests))
+			Expect(performRequest.CallCount()).To(Equal(1))
+			Expect(sleep.CallCount()).To(Equal(0))
+		})
+
+		It("stops after the maximum attempts", func() {
+			performRequest.Returns(&http.Response{StatusCode: http.StatusServiceUnavailable}, nil)
+
+			response, err := httpClient.Get(context.Background(), pkg.MakeURL("marketplace.vmware.example", "/api/v1/unit-tests", nil))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.StatusCode).To(Equal(http.StatusServiceUnavailable))
+			Expect(performRequest.CallCount()).To(Equal(3))
+		})
+
+		It("returns the last error", func() {
+			performRequest.Returns(nil, errors.New("connection refused"))
+
+			_, err := httpClient.Get(context.Background(), pkg.MakeURL("marketplace.vmware.example", "/api/v1/unit-tests", nil))
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(Equal("request failed: connection refused"))
+			Expect(performRequest.CallCount()).To(Equal(3))
+		})
+
+		It("stops retrying when the request is cancelled", func() {
+			performRequest.Returns(&http.Response{StatusCode: http.StatusServiceUnavailable}, nil)
+			sleep.Returns(context.Canceled)
+
+			_, err := httpClient.Get(context.Background(), pkg.MakeURL("marketplace.vmware.example", "/api/v1/unit-tests", nil))
+			Expect(err).To(MatchError(context.Canceled))
+			Expect(performRequest.CallCount()).To(Equal(1))
+		})
+
+		It("does not retry writes", func() {
+			performRequest.Returns(&http.Response{StatusCode: http.StatusServiceUnavailable}, nil)
+
+			response, err := httpClient.PostJSON(context.Background(), pkg.MakeURL("marketplace.vmware.example", "/api/v1/unit-tests", nil), map[string]string{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.StatusCode).To(Equal(http.StatusServiceUnavailable))
+			Expect(performRequest.CallCount()).To(Equal(1))
+		})
+
+		When("retrying writes is allowed", func() {
+			BeforeEach(func() {
+				httpClient.Retry.RetryWrites = true
+			})
+
+			It("sends the same body again", func() {
+				performRequest.ReturnsOnCall(0, &http.Response{StatusCode: http.StatusServiceUnavailable}, nil)
+
+				content := ioutil.NopCloser(strings.NewReader("everything totally passed"))
+				_, err := httpClient.Put(context.Background(), pkg.MakeURL("marketplace.vmware.example", "/api/v1/unit-tests", nil), content, "text/plain")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(performRequest.CallCount()).To(Equal(2))
+
+				request := performRequest.ArgsForCall(1)
+				Expect(request.Method).To(Equal("PUT"))
+				Expect(ioutil.ReadAll(request.Body)).To(Equal([]byte("everything totally passed")))
+			})
+		})
+	})
+
+	Describe("Debug output", func() {
+		var output *Buffer
+
+		BeforeEach(func() {
+			output = NewBuffer()
+			httpClient = pkg.NewClient(output, true, false, false)
+			httpClient.PerformRequest = performRequest.Spy
+			performRequest.Returns(&http.Response{
+				Status:        "200 OK",
+				StatusCode:    http.StatusOK,
+				ContentLength: 42,
+			}, nil)
+		})
+
+		It("prints each request without signatures", func() {
+			requestURL := pkg.MakeURL("marketplace.vmware.example", "/download", url.Values{"X-Amz-Signature": []string{"signed"}})
+			_, err := httpClient.Get(context.Background(), requestURL)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(output).To(Say(regexp.QuoteMeta("Request #0: GET https://marketplace.vmware.example/download?X-Amz-Signature=%5Bredacted%5D")))
+			Expect(output).To(Say(regexp.QuoteMeta("Request #0 Response: 200 OK")))
+			Expect(string(output.Contents())).ToNot(ContainSubstring("signed"))
+		})
+
+		When("the format is JSON", func() {
+			BeforeEach(func() {
+				httpClient.LogFormat = pkg.LogFormatJSON
+				httpClient.PrintRequestPayloads = true
+			})
+
+			It("prints one JSON object per request", func() {
+				requestURL := pkg.MakeURL("console.cloud.vmware.example", "/csp/gateway/am/api/auth/api-tokens/authorize", nil)
+				_, err := httpClient.PostForm(context.Background(), requestURL, url.Values{"refresh_token": []string{"my-api-token"}})
+				Expect(err).ToNot(HaveOccurred())
+
+				lines := strings.Split(strings.TrimSpace(string(output.Contents())), "\n")
+				Expect(lines).To(HaveLen(1))
+
+				entry := &pkg.RequestLog{}
+				Expect(json.Unmarshal([]byte(lines[0]), entry)).To(Succeed())
+				Expect(entry.RequestID).To(Equal(int64(0)))
+				Expect(entry.Method).To(Equal("POST"))
+				Expect(entry.URL).To(Equal("https://console.cloud.vmware.example/csp/gateway/am/api/auth/api-tokens/authorize"))
+				Expect(entry.Status).To(Equal(http.StatusOK))
+				Expect(entry.LatencyMS).To(BeNumerically(">=", 0))
+				Expect(entry.RequestBytes).To(Equal(int64(len("refresh_token=my-api-token"))))
+				Expect(entry.ResponseBytes).To(Equal(int64(42)))
+				Expect(entry.RequestPayload).To(Equal("refresh_token=%5Bredacted%5D"))
+			})
+		})
+
+		It("numbers concurrent requests uniquely", func() {
+			var wait sync.WaitGroup
+			for i := 0; i < 10; i++ {
+				wait.Add(1)
+				go func() {
+					defer GinkgoRecover()
+					defer wait.Done()
+					_, err := httpClient.Get(context.Background(), pkg.MakeURL("marketplace.vmware.example", "/api/v1/unit-tests", nil))
+					Expect(err).ToNot(HaveOccurred())
+				}()
+			}
+			wait.Wait()
+
+			for i := 0; i < 10; i++ {
+				Expect(string(output.Contents())).To(ContainSubstring(fmt.Sprintf("Request #%d: GET", i)))
+			}
+		})
+	})
 })
 
 var _ = Describe("MakeURL", func() {
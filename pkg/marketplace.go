@@ -4,6 +4,7 @@
 package pkg
 
 import (
+	"context"
 	"encoding/json"
 	"io"
 	"net/url"
@@ -21,28 +22,33 @@ type MarketplaceInterface interface {
 	GetAPIHost() string
 	GetUIHost() string
 
-	ListProducts(allOrgs bool, searchTerm string) ([]*models.Product, error)
-	GetProduct(slug string) (*models.Product, error)
-	GetProductWithVersion(slug, version string) (*models.Product, *models.Version, error)
-	PutProduct(product *models.Product, versionUpdate bool) (*models.Product, error)
-
-	GetUploader(orgID string) (internal.Uploader, error)
+	ListProducts(ctx context.Context, options *ListProductsOptions) ([]*models.Product, error)
+	EachProduct(ctx context.Context, options *ListProductsOptions, fn ProductFunc) error
+	EachProductPage(ctx context.Context, options *ListProductsOptions, fn ProductPageFunc) error
+	ListProductFilters(ctx context.Context, options *ListProductsOptions) ([]*ProductFacet, error)
+	GetProduct(ctx context.Context, slug string) (*models.Product, error)
+	GetProductWithVersion(ctx context.Context, slug, version string) (*models.Product, *models.Version, error)
+	PutProduct(ctx context.Context, product *models.Product, versionUpdate bool) (*models.Product, error)
+
+	GetUploadCredentials(ctx context.Context) (*CredentialsResponse, error)
+	GetStorageBucketRegion(ctx context.Context) (string, error)
+	GetUploader(ctx context.Context, orgID string) (internal.Uploader, error)
 	SetUploader(uploader internal.Uploader)
 
-	Download(filename string, payload *DownloadRequestPayload) error
+	Download(ctx context.Context, filename string, payload *DownloadRequestPayload) error
 
-	DownloadChart(chartURL *url.URL) (*models.ChartVersion, error)
-	AttachLocalChart(chartPath, instructions string, product *models.Product, version *models.Version) (*models.Product, error)
-	AttachPublicChart(chartPath *url.URL, instructions string, product *models.Product, version *models.Version) (*models.Product, error)
+	DownloadChart(ctx context.Context, chartURL *url.URL) (*models.ChartVersion, error)
+	AttachLocalChart(ctx context.Context, chartPath, instructions string, product *models.Product, version *models.Version) (*models.Product, error)
+	AttachPublicChart(ctx context.Context, chartPath *url.URL, instructions string, product *models.Product, version *models.Version) (*models.Product, error)
 
-	AttachLocalContainerImage(imageFile, image, tag, tagType, instructions string, product *models.Product, version *models.Version) (*models.Product, error)
-	AttachPublicContainerImage(image, tag, tagType, instructions string, product *models.Product, version *models.Version) (*models.Product, error)
+	AttachLocalContainerImage(ctx context.Context, imageFile, image, tag, tagType, instructions string, product *models.Product, version *models.Version) (*models.Product, error)
+	AttachPublicContainerImage(ctx context.Context, image, tag, tagType, instructions string, product *models.Product, version *models.Version) (*models.Product, error)
 
-	AttachMetaFile(metafile, metafileType, metafileVersion string, product *models.Product, version *models.Version) (*models.Product, error)
+	AttachMetaFile(ctx context.Context, metafile, metafileType, metafileVersion string, product *models.Product, version *models.Version) (*models.Product, error)
 
-	AttachOtherFile(file string, product *models.Product, version *models.Version) (*models.Product, error)
+	AttachOtherFile(ctx context.Context, file string, product *models.Product, version *models.Version) (*models.Product, error)
 
-	UploadVM(vmFile string, product *models.Product, version *models.Version) (*models.Product, error)
+	UploadVM(ctx context.Context, vmFile string, product *models.Product, version *models.Version) (*models.Product, error)
 }
 
 type Marketplace struct {
@@ -52,7 +58,9 @@ type Marketplace struct {
 	StorageBucket  string
 	StorageRegion  string
 	Client         HTTPClient
+	StorageClient  internal.S3HTTPClient
 	Output         io.Writer
+	Cache          *ResponseCache
 	uploader       internal.Uploader
 	strictDecoding bool
 }
@@ -4,6 +4,7 @@
 package pkg
 
 import (
+	"context"
 	"github.com/vmware-labs/marketplace-cli/v2/internal/models"
 )
 
@@ -13,17 +14,17 @@ const (
 	MetaFileTypeOther  = "MISC"
 )
 
-func (m *Marketplace) AttachMetaFile(metafile, metafileType, metafileVersion string, product *models.Product, version *models.Version) (*models.Product, error) {
+func (m *Marketplace) AttachMetaFile(ctx context.Context, metafile, metafileType, metafileVersion string, product *models.Product, version *models.Version) (*models.Product, error) {
 	hashString, err := Hash(metafile, models.HashAlgoSHA1)
 	if err != nil {
 		return nil, err
 	}
 
-	uploader, err := m.GetUploader(product.PublisherDetails.OrgId)
+	uploader, err := m.GetUploader(ctx, product.PublisherDetails.OrgId)
 	if err != nil {
 		return nil, err
 	}
-	filename, fileUrl, err := uploader.UploadMetaFile(metafile)
+	filename, fileUrl, err := uploader.UploadMetaFile(ctx, metafile)
 	if err != nil {
 		return nil, err
 	}
@@ -43,5 +44,5 @@ func (m *Marketplace) AttachMetaFile(metafile, metafileType, metafileVersion str
 		},
 	})
 
-	return m.PutProduct(product, version.IsNewVersion)
+	return m.PutProduct(ctx, product, version.IsNewVersion)
 }
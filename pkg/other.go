@@ -4,20 +4,21 @@
 package pkg
 
 import (
+	"context"
 	"github.com/vmware-labs/marketplace-cli/v2/internal/models"
 )
 
-func (m *Marketplace) AttachOtherFile(file string, product *models.Product, version *models.Version) (*models.Product, error) {
+func (m *Marketplace) AttachOtherFile(ctx context.Context, file string, product *models.Product, version *models.Version) (*models.Product, error) {
 	hashString, err := Hash(file, models.HashAlgoSHA1)
 	if err != nil {
 		return nil, err
 	}
 
-	uploader, err := m.GetUploader(product.PublisherDetails.OrgId)
+	uploader, err := m.GetUploader(ctx, product.PublisherDetails.OrgId)
 	if err != nil {
 		return nil, err
 	}
-	filename, fileUrl, err := uploader.UploadProductFile(file)
+	filename, fileUrl, err := uploader.UploadProductFile(ctx, file)
 	if err != nil {
 		return nil, err
 	}
@@ -31,5 +32,5 @@ func (m *Marketplace) AttachOtherFile(file string, product *models.Product, vers
 		HashAlgorithm: models.HashAlgoSHA1,
 	}}
 
-	return m.PutProduct(product, version.IsNewVersion)
+	return m.PutProduct(ctx, product, version.IsNewVersion)
 }
@@ -4,6 +4,7 @@
 package pkg_test
 
 import (
+	"context"
 	"errors"
 	"io/ioutil"
 	"os"
@@ -56,12 +57,12 @@ var _ = Describe("Other", func() {
 			product := test.CreateFakeProduct("", "Hyperspace Database", "hyperspace-database", "PENDING")
 			test.AddVerions(product, "1.2.3")
 
-			updatedProduct, err := marketplace.AttachOtherFile(filePath, product, &models.Version{Number: "1.2.3"})
+			updatedProduct, err := marketplace.AttachOtherFile(context.Background(), filePath, product, &models.Version{Number: "1.2.3"})
 			Expect(err).ToNot(HaveOccurred())
 
 			By("uploading the file", func() {
 				Expect(uploader.UploadProductFileCallCount()).To(Equal(1))
-				uploadedFilePath := uploader.UploadProductFileArgsForCall(0)
+				_, uploadedFilePath := uploader.UploadProductFileArgsForCall(0)
 				Expect(uploadedFilePath).To(Equal(filePath))
 			})
 
@@ -81,7 +82,7 @@ var _ = Describe("Other", func() {
 				product := test.CreateFakeProduct("", "Hyperspace Database", "hyperspace-database", "PENDING")
 				test.AddVerions(product, "1.2.3")
 
-				_, err := marketplace.AttachOtherFile("this/file/does/not/exist", product, &models.Version{Number: "1.2.3"})
+				_, err := marketplace.AttachOtherFile(context.Background(), "this/file/does/not/exist", product, &models.Version{Number: "1.2.3"})
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(Equal("failed to open this/file/does/not/exist: open this/file/does/not/exist: no such file or directory"))
 			})
@@ -97,7 +98,7 @@ var _ = Describe("Other", func() {
 				product := test.CreateFakeProduct("", "Hyperspace Database", "hyperspace-database", "PENDING")
 				test.AddVerions(product, "1.2.3")
 
-				_, err := marketplace.AttachOtherFile(filePath, product, &models.Version{Number: "1.2.3"})
+				_, err := marketplace.AttachOtherFile(context.Background(), filePath, product, &models.Version{Number: "1.2.3"})
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(Equal("failed to get upload credentials: get uploader failed"))
 			})
@@ -111,7 +112,7 @@ var _ = Describe("Other", func() {
 				product := test.CreateFakeProduct("", "Hyperspace Database", "hyperspace-database", "PENDING")
 				test.AddVerions(product, "1.2.3")
 
-				_, err := marketplace.AttachOtherFile(filePath, product, &models.Version{Number: "1.2.3"})
+				_, err := marketplace.AttachOtherFile(context.Background(), filePath, product, &models.Version{Number: "1.2.3"})
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(Equal("upload product file failed"))
 			})
@@ -125,7 +126,7 @@ var _ = Describe("Other", func() {
 				product := test.CreateFakeProduct("", "Hyperspace Database", "hyperspace-database", "PENDING")
 				test.AddVerions(product, "1.2.3")
 
-				_, err := marketplace.AttachOtherFile(filePath, product, &models.Version{Number: "1.2.3"})
+				_, err := marketplace.AttachOtherFile(context.Background(), filePath, product, &models.Version{Number: "1.2.3"})
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(Equal("sending the update for product \"hyperspace-database\" failed: put product failed"))
 			})
@@ -5,6 +5,7 @@ package pkg_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"io"
 	"io/ioutil"
@@ -25,7 +26,7 @@ func TestPkg(t *testing.T) {
 	RunSpecs(t, "Pkg test suite")
 }
 
-func PutProductEchoResponse(requestURL *url.URL, content io.Reader, contentType string) (*http.Response, error) {
+func PutProductEchoResponse(ctx context.Context, requestURL *url.URL, content io.Reader, contentType string) (*http.Response, error) {
 	Expect(contentType).To(Equal("application/json"))
 	var product *models.Product
 	productBytes, err := ioutil.ReadAll(content)
@@ -2,6 +2,7 @@
 package pkgfakes
 
 import (
+	"context"
 	"io"
 	"net/http"
 	"net/url"
@@ -24,10 +25,11 @@ type FakeHTTPClient struct {
 		result1 *http.Response
 		result2 error
 	}
-	GetStub        func(*url.URL) (*http.Response, error)
+	GetStub        func(context.Context, *url.URL) (*http.Response, error)
 	getMutex       sync.RWMutex
 	getArgsForCall []struct {
-		arg1 *url.URL
+		arg1 context.Context
+		arg2 *url.URL
 	}
 	getReturns struct {
 		result1 *http.Response
@@ -37,12 +39,13 @@ type FakeHTTPClient struct {
 		result1 *http.Response
 		result2 error
 	}
-	PostStub        func(*url.URL, io.Reader, string) (*http.Response, error)
+	PostStub        func(context.Context, *url.URL, io.Reader, string) (*http.Response, error)
 	postMutex       sync.RWMutex
 	postArgsForCall []struct {
-		arg1 *url.URL
-		arg2 io.Reader
-		arg3 string
+		arg1 context.Context
+		arg2 *url.URL
+		arg3 io.Reader
+		arg4 string
 	}
 	postReturns struct {
 		result1 *http.Response
@@ -52,11 +55,12 @@ type FakeHTTPClient struct {
 		result1 *http.Response
 		result2 error
 	}
-	PostFormStub        func(*url.URL, url.Values) (*http.Response, error)
+	PostFormStub        func(context.Context, *url.URL, url.Values) (*http.Response, error)
 	postFormMutex       sync.RWMutex
 	postFormArgsForCall []struct {
-		arg1 *url.URL
-		arg2 url.Values
+		arg1 context.Context
+		arg2 *url.URL
+		arg3 url.Values
 	}
 	postFormReturns struct {
 		result1 *http.Response
@@ -66,11 +70,12 @@ type FakeHTTPClient struct {
 		result1 *http.Response
 		result2 error
 	}
-	PostJSONStub        func(*url.URL, interface{}) (*http.Response, error)
+	PostJSONStub        func(context.Context, *url.URL, interface{}) (*http.Response, error)
 	postJSONMutex       sync.RWMutex
 	postJSONArgsForCall []struct {
-		arg1 *url.URL
-		arg2 interface{}
+		arg1 context.Context
+		arg2 *url.URL
+		arg3 interface{}
 	}
 	postJSONReturns struct {
 		result1 *http.Response
@@ -80,12 +85,13 @@ type FakeHTTPClient struct {
 		result1 *http.Response
 		result2 error
 	}
-	PutStub        func(*url.URL, io.Reader, string) (*http.Response, error)
+	PutStub        func(context.Context, *url.URL, io.Reader, string) (*http.Response, error)
 	putMutex       sync.RWMutex
 	putArgsForCall []struct {
-		arg1 *url.URL
-		arg2 io.Reader
-		arg3 string
+		arg1 context.Context
+		arg2 *url.URL
+		arg3 io.Reader
+		arg4 string
 	}
 	putReturns struct {
 		result1 *http.Response
@@ -95,13 +101,14 @@ type FakeHTTPClient struct {
 		result1 *http.Response
 		result2 error
 	}
-	SendRequestStub        func(string, *url.URL, map[string]string, io.Reader) (*http.Response, error)
+	SendRequestStub        func(context.Context, string, *url.URL, map[string]string, io.Reader) (*http.Response, error)
 	sendRequestMutex       sync.RWMutex
 	sendRequestArgsForCall []struct {
-		arg1 string
-		arg2 *url.URL
-		arg3 map[string]string
-		arg4 io.Reader
+		arg1 context.Context
+		arg2 string
+		arg3 *url.URL
+		arg4 map[string]string
+		arg5 io.Reader
 	}
 	sendRequestReturns struct {
 		result1 *http.Response
@@ -111,6 +118,11 @@ type FakeHTTPClient struct {
 		result1 *http.Response
 		result2 error
 	}
+	SetAccessTokenStub        func(string)
+	setAccessTokenMutex       sync.RWMutex
+	setAccessTokenArgsForCall []struct {
+		arg1 string
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -179,18 +191,19 @@ func (fake *FakeHTTPClient) DoReturnsOnCall(i int, result1 *http.Response, resul
 	}{result1, result2}
 }
 
-func (fake *FakeHTTPClient) Get(arg1 *url.URL) (*http.Response, error) {
+func (fake *FakeHTTPClient) Get(arg1 context.Context, arg2 *url.URL) (*http.Response, error) {
 	fake.getMutex.Lock()
 	ret, specificReturn := fake.getReturnsOnCall[len(fake.getArgsForCall)]
 	fake.getArgsForCall = append(fake.getArgsForCall, struct {
-		arg1 *url.URL
-	}{arg1})
+		arg1 context.Context
+		arg2 *url.URL
+	}{arg1, arg2})
 	stub := fake.GetStub
 	fakeReturns := fake.getReturns
-	fake.recordInvocation("Get", []interface{}{arg1})
+	fake.recordInvocation("Get", []interface{}{arg1, arg2})
 	fake.getMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -204,17 +217,17 @@ func (fake *FakeHTTPClient) GetCallCount() int {
 	return len(fake.getArgsForCall)
 }
 
-func (fake *FakeHTTPClient) GetCalls(stub func(*url.URL) (*http.Response, error)) {
+func (fake *FakeHTTPClient) GetCalls(stub func(context.Context, *url.URL) (*http.Response, error)) {
 	fake.getMutex.Lock()
 	defer fake.getMutex.Unlock()
 	fake.GetStub = stub
 }
 
-func (fake *FakeHTTPClient) GetArgsForCall(i int) *url.URL {
+func (fake *FakeHTTPClient) GetArgsForCall(i int) (context.Context, *url.URL) {
 	fake.getMutex.RLock()
 	defer fake.getMutex.RUnlock()
 	argsForCall := fake.getArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeHTTPClient) GetReturns(result1 *http.Response, result2 error) {
@@ -243,20 +256,21 @@ func (fake *FakeHTTPClient) GetReturnsOnCall(i int, result1 *http.Response, resu
 	}{result1, result2}
 }
 
-func (fake *FakeHTTPClient) Post(arg1 *url.URL, arg2 io.Reader, arg3 string) (*http.Response, error) {
+func (fake *FakeHTTPClient) Post(arg1 context.Context, arg2 *url.URL, arg3 io.Reader, arg4 string) (*http.Response, error) {
 	fake.postMutex.Lock()
 	ret, specificReturn := fake.postReturnsOnCall[len(fake.postArgsForCall)]
 	fake.postArgsForCall = append(fake.postArgsForCall, struct {
-		arg1 *url.URL
-		arg2 io.Reader
-		arg3 string
-	}{arg1, arg2, arg3})
+		arg1 context.Context
+		arg2 *url.URL
+		arg3 io.Reader
+		arg4 string
+	}{arg1, arg2, arg3, arg4})
 	stub := fake.PostStub
 	fakeReturns := fake.postReturns
-	fake.recordInvocation("Post", []interface{}{arg1, arg2, arg3})
+	fake.recordInvocation("Post", []interface{}{arg1, arg2, arg3, arg4})
 	fake.postMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2, arg3)
+		return stub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -270,17 +284,17 @@ func (fake *FakeHTTPClient) PostCallCount() int {
 	return len(fake.postArgsForCall)
 }
 
-func (fake *FakeHTTPClient) PostCalls(stub func(*url.URL, io.Reader, string) (*http.Response, error)) {
+func (fake *FakeHTTPClient) PostCalls(stub func(context.Context, *url.URL, io.Reader, string) (*http.Response, error)) {
 	fake.postMutex.Lock()
 	defer fake.postMutex.Unlock()
 	fake.PostStub = stub
 }
 
-func (fake *FakeHTTPClient) PostArgsForCall(i int) (*url.URL, io.Reader, string) {
+func (fake *FakeHTTPClient) PostArgsForCall(i int) (context.Context, *url.URL, io.Reader, string) {
 	fake.postMutex.RLock()
 	defer fake.postMutex.RUnlock()
 	argsForCall := fake.postArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
 }
 
 func (fake *FakeHTTPClient) PostReturns(result1 *http.Response, result2 error) {
@@ -309,19 +323,20 @@ func (fake *FakeHTTPClient) PostReturnsOnCall(i int, result1 *http.Response, res
 	}{result1, result2}
 }
 
-func (fake *FakeHTTPClient) PostForm(arg1 *url.URL, arg2 url.Values) (*http.Response, error) {
+func (fake *FakeHTTPClient) PostForm(arg1 context.Context, arg2 *url.URL, arg3 url.Values) (*http.Response, error) {
 	fake.postFormMutex.Lock()
 	ret, specificReturn := fake.postFormReturnsOnCall[len(fake.postFormArgsForCall)]
 	fake.postFormArgsForCall = append(fake.postFormArgsForCall, struct {
-		arg1 *url.URL
-		arg2 url.Values
-	}{arg1, arg2})
+		arg1 context.Context
+		arg2 *url.URL
+		arg3 url.Values
+	}{arg1, arg2, arg3})
 	stub := fake.PostFormStub
 	fakeReturns := fake.postFormReturns
-	fake.recordInvocation("PostForm", []interface{}{arg1, arg2})
+	fake.recordInvocation("PostForm", []interface{}{arg1, arg2, arg3})
 	fake.postFormMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -335,17 +350,17 @@ func (fake *FakeHTTPClient) PostFormCallCount() int {
 	return len(fake.postFormArgsForCall)
 }
 
-func (fake *FakeHTTPClient) PostFormCalls(stub func(*url.URL, url.Values) (*http.Response, error)) {
+func (fake *FakeHTTPClient) PostFormCalls(stub func(context.Context, *url.URL, url.Values) (*http.Response, error)) {
 	fake.postFormMutex.Lock()
 	defer fake.postFormMutex.Unlock()
 	fake.PostFormStub = stub
 }
 
-func (fake *FakeHTTPClient) PostFormArgsForCall(i int) (*url.URL, url.Values) {
+func (fake *FakeHTTPClient) PostFormArgsForCall(i int) (context.Context, *url.URL, url.Values) {
 	fake.postFormMutex.RLock()
 	defer fake.postFormMutex.RUnlock()
 	argsForCall := fake.postFormArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeHTTPClient) PostFormReturns(result1 *http.Response, result2 error) {
@@ -374,19 +389,20 @@ func (fake *FakeHTTPClient) PostFormReturnsOnCall(i int, result1 *http.Response,
 	}{result1, result2}
 }
 
-func (fake *FakeHTTPClient) PostJSON(arg1 *url.URL, arg2 interface{}) (*http.Response, error) {
+func (fake *FakeHTTPClient) PostJSON(arg1 context.Context, arg2 *url.URL, arg3 interface{}) (*http.Response, error) {
 	fake.postJSONMutex.Lock()
 	ret, specificReturn := fake.postJSONReturnsOnCall[len(fake.postJSONArgsForCall)]
 	fake.postJSONArgsForCall = append(fake.postJSONArgsForCall, struct {
-		arg1 *url.URL
-		arg2 interface{}
-	}{arg1, arg2})
+		arg1 context.Context
+		arg2 *url.URL
+		arg3 interface{}
+	}{arg1, arg2, arg3})
 	stub := fake.PostJSONStub
 	fakeReturns := fake.postJSONReturns
-	fake.recordInvocation("PostJSON", []interface{}{arg1, arg2})
+	fake.recordInvocation("PostJSON", []interface{}{arg1, arg2, arg3})
 	fake.postJSONMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -400,17 +416,17 @@ func (fake *FakeHTTPClient) PostJSONCallCount() int {
 	return len(fake.postJSONArgsForCall)
 }
 
-func (fake *FakeHTTPClient) PostJSONCalls(stub func(*url.URL, interface{}) (*http.Response, error)) {
+func (fake *FakeHTTPClient) PostJSONCalls(stub func(context.Context, *url.URL, interface{}) (*http.Response, error)) {
 	fake.postJSONMutex.Lock()
 	defer fake.postJSONMutex.Unlock()
 	fake.PostJSONStub = stub
 }
 
-func (fake *FakeHTTPClient) PostJSONArgsForCall(i int) (*url.URL, interface{}) {
+func (fake *FakeHTTPClient) PostJSONArgsForCall(i int) (context.Context, *url.URL, interface{}) {
 	fake.postJSONMutex.RLock()
 	defer fake.postJSONMutex.RUnlock()
 	argsForCall := fake.postJSONArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeHTTPClient) PostJSONReturns(result1 *http.Response, result2 error) {
@@ -439,20 +455,21 @@ func (fake *FakeHTTPClient) PostJSONReturnsOnCall(i int, result1 *http.Response,
 	}{result1, result2}
 }
 
-func (fake *FakeHTTPClient) Put(arg1 *url.URL, arg2 io.Reader, arg3 string) (*http.Response, error) {
+func (fake *FakeHTTPClient) Put(arg1 context.Context, arg2 *url.URL, arg3 io.Reader, arg4 string) (*http.Response, error) {
 	fake.putMutex.Lock()
 	ret, specificReturn := fake.putReturnsOnCall[len(fake.putArgsForCall)]
 	fake.putArgsForCall = append(fake.putArgsForCall, struct {
-		arg1 *url.URL
-		arg2 io.Reader
-		arg3 string
-	}{arg1, arg2, arg3})
+		arg1 context.Context
+		arg2 *url.URL
+		arg3 io.Reader
+		arg4 string
+	}{arg1, arg2, arg3, arg4})
 	stub := fake.PutStub
 	fakeReturns := fake.putReturns
-	fake.recordInvocation("Put", []interface{}{arg1, arg2, arg3})
+	fake.recordInvocation("Put", []interface{}{arg1, arg2, arg3, arg4})
 	fake.putMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2, arg3)
+		return stub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -466,17 +483,17 @@ func (fake *FakeHTTPClient) PutCallCount() int {
 	return len(fake.putArgsForCall)
 }
 
-func (fake *FakeHTTPClient) PutCalls(stub func(*url.URL, io.Reader, string) (*http.Response, error)) {
+func (fake *FakeHTTPClient) PutCalls(stub func(context.Context, *url.URL, io.Reader, string) (*http.Response, error)) {
 	fake.putMutex.Lock()
 	defer fake.putMutex.Unlock()
 	fake.PutStub = stub
 }
 
-func (fake *FakeHTTPClient) PutArgsForCall(i int) (*url.URL, io.Reader, string) {
+func (fake *FakeHTTPClient) PutArgsForCall(i int) (context.Context, *url.URL, io.Reader, string) {
 	fake.putMutex.RLock()
 	defer fake.putMutex.RUnlock()
 	argsForCall := fake.putArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
 }
 
 func (fake *FakeHTTPClient) PutReturns(result1 *http.Response, result2 error) {
@@ -505,21 +522,22 @@ func (fake *FakeHTTPClient) PutReturnsOnCall(i int, result1 *http.Response, resu
 	}{result1, result2}
 }
 
-func (fake *FakeHTTPClient) SendRequest(arg1 string, arg2 *url.URL, arg3 map[string]string, arg4 io.Reader) (*http.Response, error) {
+func (fake *FakeHTTPClient) SendRequest(arg1 context.Context, arg2 string, arg3 *url.URL, arg4 map[string]string, arg5 io.Reader) (*http.Response, error) {
 	fake.sendRequestMutex.Lock()
 	ret, specificReturn := fake.sendRequestReturnsOnCall[len(fake.sendRequestArgsForCall)]
 	fake.sendRequestArgsForCall = append(fake.sendRequestArgsForCall, struct {
-		arg1 string
-		arg2 *url.URL
-		arg3 map[string]string
-		arg4 io.Reader
-	}{arg1, arg2, arg3, arg4})
+		arg1 context.Context
+		arg2 string
+		arg3 *url.URL
+		arg4 map[string]string
+		arg5 io.Reader
+	}{arg1, arg2, arg3, arg4, arg5})
 	stub := fake.SendRequestStub
 	fakeReturns := fake.sendRequestReturns
-	fake.recordInvocation("SendRequest", []interface{}{arg1, arg2, arg3, arg4})
+	fake.recordInvocation("SendRequest", []interface{}{arg1, arg2, arg3, arg4, arg5})
 	fake.sendRequestMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2, arg3, arg4)
+		return stub(arg1, arg2, arg3, arg4, arg5)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -533,17 +551,17 @@ func (fake *FakeHTTPClient) SendRequestCallCount() int {
 	return len(fake.sendRequestArgsForCall)
 }
 
-func (fake *FakeHTTPClient) SendRequestCalls(stub func(string, *url.URL, map[string]string, io.Reader) (*http.Response, error)) {
+func (fake *FakeHTTPClient) SendRequestCalls(stub func(context.Context, string, *url.URL, map[string]string, io.Reader) (*http.Response, error)) {
 	fake.sendRequestMutex.Lock()
 	defer fake.sendRequestMutex.Unlock()
 	fake.SendRequestStub = stub
 }
 
-func (fake *FakeHTTPClient) SendRequestArgsForCall(i int) (string, *url.URL, map[string]string, io.Reader) {
+func (fake *FakeHTTPClient) SendRequestArgsForCall(i int) (context.Context, string, *url.URL, map[string]string, io.Reader) {
 	fake.sendRequestMutex.RLock()
 	defer fake.sendRequestMutex.RUnlock()
 	argsForCall := fake.sendRequestArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
 }
 
 func (fake *FakeHTTPClient) SendRequestReturns(result1 *http.Response, result2 error) {
@@ -572,6 +590,38 @@ func (fake *FakeHTTPClient) SendRequestReturnsOnCall(i int, result1 *http.Respon
 	}{result1, result2}
 }
 
+func (fake *FakeHTTPClient) SetAccessToken(arg1 string) {
+	fake.setAccessTokenMutex.Lock()
+	fake.setAccessTokenArgsForCall = append(fake.setAccessTokenArgsForCall, struct {
+		arg1 string
+	}{arg1})
+	stub := fake.SetAccessTokenStub
+	fake.recordInvocation("SetAccessToken", []interface{}{arg1})
+	fake.setAccessTokenMutex.Unlock()
+	if stub != nil {
+		fake.SetAccessTokenStub(arg1)
+	}
+}
+
+func (fake *FakeHTTPClient) SetAccessTokenCallCount() int {
+	fake.setAccessTokenMutex.RLock()
+	defer fake.setAccessTokenMutex.RUnlock()
+	return len(fake.setAccessTokenArgsForCall)
+}
+
+func (fake *FakeHTTPClient) SetAccessTokenCalls(stub func(string)) {
+	fake.setAccessTokenMutex.Lock()
+	defer fake.setAccessTokenMutex.Unlock()
+	fake.SetAccessTokenStub = stub
+}
+
+func (fake *FakeHTTPClient) SetAccessTokenArgsForCall(i int) string {
+	fake.setAccessTokenMutex.RLock()
+	defer fake.setAccessTokenMutex.RUnlock()
+	argsForCall := fake.setAccessTokenArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FakeHTTPClient) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -589,6 +639,8 @@ func (fake *FakeHTTPClient) Invocations() map[string][][]interface{} {
 	defer fake.putMutex.RUnlock()
 	fake.sendRequestMutex.RLock()
 	defer fake.sendRequestMutex.RUnlock()
+	fake.setAccessTokenMutex.RLock()
+	defer fake.setAccessTokenMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
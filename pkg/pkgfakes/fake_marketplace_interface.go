@@ -2,6 +2,7 @@
 package pkgfakes
 
 import (
+	"context"
 	"io"
 	"net/url"
 	"sync"
@@ -12,13 +13,14 @@ import (
 )
 
 type FakeMarketplaceInterface struct {
-	AttachLocalChartStub        func(string, string, *models.Product, *models.Version) (*models.Product, error)
+	AttachLocalChartStub        func(context.Context, string, string, *models.Product, *models.Version) (*models.Product, error)
 	attachLocalChartMutex       sync.RWMutex
 	attachLocalChartArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
-		arg3 *models.Product
-		arg4 *models.Version
+		arg3 string
+		arg4 *models.Product
+		arg5 *models.Version
 	}
 	attachLocalChartReturns struct {
 		result1 *models.Product
@@ -28,16 +30,17 @@ type FakeMarketplaceInterface struct {
 		result1 *models.Product
 		result2 error
 	}
-	AttachLocalContainerImageStub        func(string, string, string, string, string, *models.Product, *models.Version) (*models.Product, error)
+	AttachLocalContainerImageStub        func(context.Context, string, string, string, string, string, *models.Product, *models.Version) (*models.Product, error)
 	attachLocalContainerImageMutex       sync.RWMutex
 	attachLocalContainerImageArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
 		arg3 string
 		arg4 string
 		arg5 string
-		arg6 *models.Product
-		arg7 *models.Version
+		arg6 string
+		arg7 *models.Product
+		arg8 *models.Version
 	}
 	attachLocalContainerImageReturns struct {
 		result1 *models.Product
@@ -47,14 +50,15 @@ type FakeMarketplaceInterface struct {
 		result1 *models.Product
 		result2 error
 	}
-	AttachMetaFileStub        func(string, string, string, *models.Product, *models.Version) (*models.Product, error)
+	AttachMetaFileStub        func(context.Context, string, string, string, *models.Product, *models.Version) (*models.Product, error)
 	attachMetaFileMutex       sync.RWMutex
 	attachMetaFileArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
 		arg3 string
-		arg4 *models.Product
-		arg5 *models.Version
+		arg4 string
+		arg5 *models.Product
+		arg6 *models.Version
 	}
 	attachMetaFileReturns struct {
 		result1 *models.Product
@@ -64,12 +68,13 @@ type FakeMarketplaceInterface struct {
 		result1 *models.Product
 		result2 error
 	}
-	AttachOtherFileStub        func(string, *models.Product, *models.Version) (*models.Product, error)
+	AttachOtherFileStub        func(context.Context, string, *models.Product, *models.Version) (*models.Product, error)
 	attachOtherFileMutex       sync.RWMutex
 	attachOtherFileArgsForCall []struct {
-		arg1 string
-		arg2 *models.Product
-		arg3 *models.Version
+		arg1 context.Context
+		arg2 string
+		arg3 *models.Product
+		arg4 *models.Version
 	}
 	attachOtherFileReturns struct {
 		result1 *models.Product
@@ -79,13 +84,14 @@ type FakeMarketplaceInterface struct {
 		result1 *models.Product
 		result2 error
 	}
-	AttachPublicChartStub        func(*url.URL, string, *models.Product, *models.Version) (*models.Product, error)
+	AttachPublicChartStub        func(context.Context, *url.URL, string, *models.Product, *models.Version) (*models.Product, error)
 	attachPublicChartMutex       sync.RWMutex
 	attachPublicChartArgsForCall []struct {
-		arg1 *url.URL
-		arg2 string
-		arg3 *models.Product
-		arg4 *models.Version
+		arg1 context.Context
+		arg2 *url.URL
+		arg3 string
+		arg4 *models.Product
+		arg5 *models.Version
 	}
 	attachPublicChartReturns struct {
 		result1 *models.Product
@@ -95,15 +101,16 @@ type FakeMarketplaceInterface struct {
 		result1 *models.Product
 		result2 error
 	}
-	AttachPublicContainerImageStub        func(string, string, string, string, *models.Product, *models.Version) (*models.Product, error)
+	AttachPublicContainerImageStub        func(context.Context, string, string, string, string, *models.Product, *models.Version) (*models.Product, error)
 	attachPublicContainerImageMutex       sync.RWMutex
 	attachPublicContainerImageArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
 		arg3 string
 		arg4 string
-		arg5 *models.Product
-		arg6 *models.Version
+		arg5 string
+		arg6 *models.Product
+		arg7 *models.Version
 	}
 	attachPublicContainerImageReturns struct {
 		result1 *models.Product
@@ -125,11 +132,12 @@ type FakeMarketplaceInterface struct {
 	decodeJsonReturnsOnCall map[int]struct {
 		result1 error
 	}
-	DownloadStub        func(string, *pkg.DownloadRequestPayload) error
+	DownloadStub        func(context.Context, string, *pkg.DownloadRequestPayload) error
 	downloadMutex       sync.RWMutex
 	downloadArgsForCall []struct {
-		arg1 string
-		arg2 *pkg.DownloadRequestPayload
+		arg1 context.Context
+		arg2 string
+		arg3 *pkg.DownloadRequestPayload
 	}
 	downloadReturns struct {
 		result1 error
@@ -137,10 +145,11 @@ type FakeMarketplaceInterface struct {
 	downloadReturnsOnCall map[int]struct {
 		result1 error
 	}
-	DownloadChartStub        func(*url.URL) (*models.ChartVersion, error)
+	DownloadChartStub        func(context.Context, *url.URL) (*models.ChartVersion, error)
 	downloadChartMutex       sync.RWMutex
 	downloadChartArgsForCall []struct {
-		arg1 *url.URL
+		arg1 context.Context
+		arg2 *url.URL
 	}
 	downloadChartReturns struct {
 		result1 *models.ChartVersion
@@ -150,6 +159,32 @@ type FakeMarketplaceInterface struct {
 		result1 *models.ChartVersion
 		result2 error
 	}
+	EachProductStub        func(context.Context, *pkg.ListProductsOptions, pkg.ProductFunc) error
+	eachProductMutex       sync.RWMutex
+	eachProductArgsForCall []struct {
+		arg1 context.Context
+		arg2 *pkg.ListProductsOptions
+		arg3 pkg.ProductFunc
+	}
+	eachProductReturns struct {
+		result1 error
+	}
+	eachProductReturnsOnCall map[int]struct {
+		result1 error
+	}
+	EachProductPageStub        func(context.Context, *pkg.ListProductsOptions, pkg.ProductPageFunc) error
+	eachProductPageMutex       sync.RWMutex
+	eachProductPageArgsForCall []struct {
+		arg1 context.Context
+		arg2 *pkg.ListProductsOptions
+		arg3 pkg.ProductPageFunc
+	}
+	eachProductPageReturns struct {
+		result1 error
+	}
+	eachProductPageReturnsOnCall map[int]struct {
+		result1 error
+	}
 	EnableStrictDecodingStub        func()
 	enableStrictDecodingMutex       sync.RWMutex
 	enableStrictDecodingArgsForCall []struct {
@@ -174,10 +209,11 @@ type FakeMarketplaceInterface struct {
 	getHostReturnsOnCall map[int]struct {
 		result1 string
 	}
-	GetProductStub        func(string) (*models.Product, error)
+	GetProductStub        func(context.Context, string) (*models.Product, error)
 	getProductMutex       sync.RWMutex
 	getProductArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
+		arg2 string
 	}
 	getProductReturns struct {
 		result1 *models.Product
@@ -187,11 +223,12 @@ type FakeMarketplaceInterface struct {
 		result1 *models.Product
 		result2 error
 	}
-	GetProductWithVersionStub        func(string, string) (*models.Product, *models.Version, error)
+	GetProductWithVersionStub        func(context.Context, string, string) (*models.Product, *models.Version, error)
 	getProductWithVersionMutex       sync.RWMutex
 	getProductWithVersionArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
+		arg3 string
 	}
 	getProductWithVersionReturns struct {
 		result1 *models.Product
@@ -203,6 +240,19 @@ type FakeMarketplaceInterface struct {
 		result2 *models.Version
 		result3 error
 	}
+	GetStorageBucketRegionStub        func(context.Context) (string, error)
+	getStorageBucketRegionMutex       sync.RWMutex
+	getStorageBucketRegionArgsForCall []struct {
+		arg1 context.Context
+	}
+	getStorageBucketRegionReturns struct {
+		result1 string
+		result2 error
+	}
+	getStorageBucketRegionReturnsOnCall map[int]struct {
+		result1 string
+		result2 error
+	}
 	GetUIHostStub        func() string
 	getUIHostMutex       sync.RWMutex
 	getUIHostArgsForCall []struct {
@@ -213,10 +263,24 @@ type FakeMarketplaceInterface struct {
 	getUIHostReturnsOnCall map[int]struct {
 		result1 string
 	}
-	GetUploaderStub        func(string) (internal.Uploader, error)
+	GetUploadCredentialsStub        func(context.Context) (*pkg.CredentialsResponse, error)
+	getUploadCredentialsMutex       sync.RWMutex
+	getUploadCredentialsArgsForCall []struct {
+		arg1 context.Context
+	}
+	getUploadCredentialsReturns struct {
+		result1 *pkg.CredentialsResponse
+		result2 error
+	}
+	getUploadCredentialsReturnsOnCall map[int]struct {
+		result1 *pkg.CredentialsResponse
+		result2 error
+	}
+	GetUploaderStub        func(context.Context, string) (internal.Uploader, error)
 	getUploaderMutex       sync.RWMutex
 	getUploaderArgsForCall []struct {
-		arg1 string
+		arg1 context.Context
+		arg2 string
 	}
 	getUploaderReturns struct {
 		result1 internal.Uploader
@@ -226,11 +290,25 @@ type FakeMarketplaceInterface struct {
 		result1 internal.Uploader
 		result2 error
 	}
-	ListProductsStub        func(bool, string) ([]*models.Product, error)
+	ListProductFiltersStub        func(context.Context, *pkg.ListProductsOptions) ([]*pkg.ProductFacet, error)
+	listProductFiltersMutex       sync.RWMutex
+	listProductFiltersArgsForCall []struct {
+		arg1 context.Context
+		arg2 *pkg.ListProductsOptions
+	}
+	listProductFiltersReturns struct {
+		result1 []*pkg.ProductFacet
+		result2 error
+	}
+	listProductFiltersReturnsOnCall map[int]struct {
+		result1 []*pkg.ProductFacet
+		result2 error
+	}
+	ListProductsStub        func(context.Context, *pkg.ListProductsOptions) ([]*models.Product, error)
 	listProductsMutex       sync.RWMutex
 	listProductsArgsForCall []struct {
-		arg1 bool
-		arg2 string
+		arg1 context.Context
+		arg2 *pkg.ListProductsOptions
 	}
 	listProductsReturns struct {
 		result1 []*models.Product
@@ -240,11 +318,12 @@ type FakeMarketplaceInterface struct {
 		result1 []*models.Product
 		result2 error
 	}
-	PutProductStub        func(*models.Product, bool) (*models.Product, error)
+	PutProductStub        func(context.Context, *models.Product, bool) (*models.Product, error)
 	putProductMutex       sync.RWMutex
 	putProductArgsForCall []struct {
-		arg1 *models.Product
-		arg2 bool
+		arg1 context.Context
+		arg2 *models.Product
+		arg3 bool
 	}
 	putProductReturns struct {
 		result1 *models.Product
@@ -259,12 +338,13 @@ type FakeMarketplaceInterface struct {
 	setUploaderArgsForCall []struct {
 		arg1 internal.Uploader
 	}
-	UploadVMStub        func(string, *models.Product, *models.Version) (*models.Product, error)
+	UploadVMStub        func(context.Context, string, *models.Product, *models.Version) (*models.Product, error)
 	uploadVMMutex       sync.RWMutex
 	uploadVMArgsForCall []struct {
-		arg1 string
-		arg2 *models.Product
-		arg3 *models.Version
+		arg1 context.Context
+		arg2 string
+		arg3 *models.Product
+		arg4 *models.Version
 	}
 	uploadVMReturns struct {
 		result1 *models.Product
@@ -278,21 +358,22 @@ type FakeMarketplaceInterface struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeMarketplaceInterface) AttachLocalChart(arg1 string, arg2 string, arg3 *models.Product, arg4 *models.Version) (*models.Product, error) {
+func (fake *FakeMarketplaceInterface) AttachLocalChart(arg1 context.Context, arg2 string, arg3 string, arg4 *models.Product, arg5 *models.Version) (*models.Product, error) {
 	fake.attachLocalChartMutex.Lock()
 	ret, specificReturn := fake.attachLocalChartReturnsOnCall[len(fake.attachLocalChartArgsForCall)]
 	fake.attachLocalChartArgsForCall = append(fake.attachLocalChartArgsForCall, struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
-		arg3 *models.Product
-		arg4 *models.Version
-	}{arg1, arg2, arg3, arg4})
+		arg3 string
+		arg4 *models.Product
+		arg5 *models.Version
+	}{arg1, arg2, arg3, arg4, arg5})
 	stub := fake.AttachLocalChartStub
 	fakeReturns := fake.attachLocalChartReturns
-	fake.recordInvocation("AttachLocalChart", []interface{}{arg1, arg2, arg3, arg4})
+	fake.recordInvocation("AttachLocalChart", []interface{}{arg1, arg2, arg3, arg4, arg5})
 	fake.attachLocalChartMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2, arg3, arg4)
+		return stub(arg1, arg2, arg3, arg4, arg5)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -306,17 +387,17 @@ func (fake *FakeMarketplaceInterface) AttachLocalChartCallCount() int {
 	return len(fake.attachLocalChartArgsForCall)
 }
 
-func (fake *FakeMarketplaceInterface) AttachLocalChartCalls(stub func(string, string, *models.Product, *models.Version) (*models.Product, error)) {
+func (fake *FakeMarketplaceInterface) AttachLocalChartCalls(stub func(context.Context, string, string, *models.Product, *models.Version) (*models.Product, error)) {
 	fake.attachLocalChartMutex.Lock()
 	defer fake.attachLocalChartMutex.Unlock()
 	fake.AttachLocalChartStub = stub
 }
 
-func (fake *FakeMarketplaceInterface) AttachLocalChartArgsForCall(i int) (string, string, *models.Product, *models.Version) {
+func (fake *FakeMarketplaceInterface) AttachLocalChartArgsForCall(i int) (context.Context, string, string, *models.Product, *models.Version) {
 	fake.attachLocalChartMutex.RLock()
 	defer fake.attachLocalChartMutex.RUnlock()
 	argsForCall := fake.attachLocalChartArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
 }
 
 func (fake *FakeMarketplaceInterface) AttachLocalChartReturns(result1 *models.Product, result2 error) {
@@ -345,24 +426,25 @@ func (fake *FakeMarketplaceInterface) AttachLocalChartReturnsOnCall(i int, resul
 	}{result1, result2}
 }
 
-func (fake *FakeMarketplaceInterface) AttachLocalContainerImage(arg1 string, arg2 string, arg3 string, arg4 string, arg5 string, arg6 *models.Product, arg7 *models.Version) (*models.Product, error) {
+func (fake *FakeMarketplaceInterface) AttachLocalContainerImage(arg1 context.Context, arg2 string, arg3 string, arg4 string, arg5 string, arg6 string, arg7 *models.Product, arg8 *models.Version) (*models.Product, error) {
 	fake.attachLocalContainerImageMutex.Lock()
 	ret, specificReturn := fake.attachLocalContainerImageReturnsOnCall[len(fake.attachLocalContainerImageArgsForCall)]
 	fake.attachLocalContainerImageArgsForCall = append(fake.attachLocalContainerImageArgsForCall, struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
 		arg3 string
 		arg4 string
 		arg5 string
-		arg6 *models.Product
-		arg7 *models.Version
-	}{arg1, arg2, arg3, arg4, arg5, arg6, arg7})
+		arg6 string
+		arg7 *models.Product
+		arg8 *models.Version
+	}{arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8})
 	stub := fake.AttachLocalContainerImageStub
 	fakeReturns := fake.attachLocalContainerImageReturns
-	fake.recordInvocation("AttachLocalContainerImage", []interface{}{arg1, arg2, arg3, arg4, arg5, arg6, arg7})
+	fake.recordInvocation("AttachLocalContainerImage", []interface{}{arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8})
 	fake.attachLocalContainerImageMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2, arg3, arg4, arg5, arg6, arg7)
+		return stub(arg1, arg2, arg3, arg4, arg5, arg6, arg7, arg8)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -376,17 +458,17 @@ func (fake *FakeMarketplaceInterface) AttachLocalContainerImageCallCount() int {
 	return len(fake.attachLocalContainerImageArgsForCall)
 }
 
-func (fake *FakeMarketplaceInterface) AttachLocalContainerImageCalls(stub func(string, string, string, string, string, *models.Product, *models.Version) (*models.Product, error)) {
+func (fake *FakeMarketplaceInterface) AttachLocalContainerImageCalls(stub func(context.Context, string, string, string, string, string, *models.Product, *models.Version) (*models.Product, error)) {
 	fake.attachLocalContainerImageMutex.Lock()
 	defer fake.attachLocalContainerImageMutex.Unlock()
 	fake.AttachLocalContainerImageStub = stub
 }
 
-func (fake *FakeMarketplaceInterface) AttachLocalContainerImageArgsForCall(i int) (string, string, string, string, string, *models.Product, *models.Version) {
+func (fake *FakeMarketplaceInterface) AttachLocalContainerImageArgsForCall(i int) (context.Context, string, string, string, string, string, *models.Product, *models.Version) {
 	fake.attachLocalContainerImageMutex.RLock()
 	defer fake.attachLocalContainerImageMutex.RUnlock()
 	argsForCall := fake.attachLocalContainerImageArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6, argsForCall.arg7
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6, argsForCall.arg7, argsForCall.arg8
 }
 
 func (fake *FakeMarketplaceInterface) AttachLocalContainerImageReturns(result1 *models.Product, result2 error) {
@@ -415,22 +497,23 @@ func (fake *FakeMarketplaceInterface) AttachLocalContainerImageReturnsOnCall(i i
 	}{result1, result2}
 }
 
-func (fake *FakeMarketplaceInterface) AttachMetaFile(arg1 string, arg2 string, arg3 string, arg4 *models.Product, arg5 *models.Version) (*models.Product, error) {
+func (fake *FakeMarketplaceInterface) AttachMetaFile(arg1 context.Context, arg2 string, arg3 string, arg4 string, arg5 *models.Product, arg6 *models.Version) (*models.Product, error) {
 	fake.attachMetaFileMutex.Lock()
 	ret, specificReturn := fake.attachMetaFileReturnsOnCall[len(fake.attachMetaFileArgsForCall)]
 	fake.attachMetaFileArgsForCall = append(fake.attachMetaFileArgsForCall, struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
 		arg3 string
-		arg4 *models.Product
-		arg5 *models.Version
-	}{arg1, arg2, arg3, arg4, arg5})
+		arg4 string
+		arg5 *models.Product
+		arg6 *models.Version
+	}{arg1, arg2, arg3, arg4, arg5, arg6})
 	stub := fake.AttachMetaFileStub
 	fakeReturns := fake.attachMetaFileReturns
-	fake.recordInvocation("AttachMetaFile", []interface{}{arg1, arg2, arg3, arg4, arg5})
+	fake.recordInvocation("AttachMetaFile", []interface{}{arg1, arg2, arg3, arg4, arg5, arg6})
 	fake.attachMetaFileMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2, arg3, arg4, arg5)
+		return stub(arg1, arg2, arg3, arg4, arg5, arg6)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -444,17 +527,17 @@ func (fake *FakeMarketplaceInterface) AttachMetaFileCallCount() int {
 	return len(fake.attachMetaFileArgsForCall)
 }
 
-func (fake *FakeMarketplaceInterface) AttachMetaFileCalls(stub func(string, string, string, *models.Product, *models.Version) (*models.Product, error)) {
+func (fake *FakeMarketplaceInterface) AttachMetaFileCalls(stub func(context.Context, string, string, string, *models.Product, *models.Version) (*models.Product, error)) {
 	fake.attachMetaFileMutex.Lock()
 	defer fake.attachMetaFileMutex.Unlock()
 	fake.AttachMetaFileStub = stub
 }
 
-func (fake *FakeMarketplaceInterface) AttachMetaFileArgsForCall(i int) (string, string, string, *models.Product, *models.Version) {
+func (fake *FakeMarketplaceInterface) AttachMetaFileArgsForCall(i int) (context.Context, string, string, string, *models.Product, *models.Version) {
 	fake.attachMetaFileMutex.RLock()
 	defer fake.attachMetaFileMutex.RUnlock()
 	argsForCall := fake.attachMetaFileArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6
 }
 
 func (fake *FakeMarketplaceInterface) AttachMetaFileReturns(result1 *models.Product, result2 error) {
@@ -483,20 +566,21 @@ func (fake *FakeMarketplaceInterface) AttachMetaFileReturnsOnCall(i int, result1
 	}{result1, result2}
 }
 
-func (fake *FakeMarketplaceInterface) AttachOtherFile(arg1 string, arg2 *models.Product, arg3 *models.Version) (*models.Product, error) {
+func (fake *FakeMarketplaceInterface) AttachOtherFile(arg1 context.Context, arg2 string, arg3 *models.Product, arg4 *models.Version) (*models.Product, error) {
 	fake.attachOtherFileMutex.Lock()
 	ret, specificReturn := fake.attachOtherFileReturnsOnCall[len(fake.attachOtherFileArgsForCall)]
 	fake.attachOtherFileArgsForCall = append(fake.attachOtherFileArgsForCall, struct {
-		arg1 string
-		arg2 *models.Product
-		arg3 *models.Version
-	}{arg1, arg2, arg3})
+		arg1 context.Context
+		arg2 string
+		arg3 *models.Product
+		arg4 *models.Version
+	}{arg1, arg2, arg3, arg4})
 	stub := fake.AttachOtherFileStub
 	fakeReturns := fake.attachOtherFileReturns
-	fake.recordInvocation("AttachOtherFile", []interface{}{arg1, arg2, arg3})
+	fake.recordInvocation("AttachOtherFile", []interface{}{arg1, arg2, arg3, arg4})
 	fake.attachOtherFileMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2, arg3)
+		return stub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -510,17 +594,17 @@ func (fake *FakeMarketplaceInterface) AttachOtherFileCallCount() int {
 	return len(fake.attachOtherFileArgsForCall)
 }
 
-func (fake *FakeMarketplaceInterface) AttachOtherFileCalls(stub func(string, *models.Product, *models.Version) (*models.Product, error)) {
+func (fake *FakeMarketplaceInterface) AttachOtherFileCalls(stub func(context.Context, string, *models.Product, *models.Version) (*models.Product, error)) {
 	fake.attachOtherFileMutex.Lock()
 	defer fake.attachOtherFileMutex.Unlock()
 	fake.AttachOtherFileStub = stub
 }
 
-func (fake *FakeMarketplaceInterface) AttachOtherFileArgsForCall(i int) (string, *models.Product, *models.Version) {
+func (fake *FakeMarketplaceInterface) AttachOtherFileArgsForCall(i int) (context.Context, string, *models.Product, *models.Version) {
 	fake.attachOtherFileMutex.RLock()
 	defer fake.attachOtherFileMutex.RUnlock()
 	argsForCall := fake.attachOtherFileArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
 }
 
 func (fake *FakeMarketplaceInterface) AttachOtherFileReturns(result1 *models.Product, result2 error) {
@@ -549,21 +633,22 @@ func (fake *FakeMarketplaceInterface) AttachOtherFileReturnsOnCall(i int, result
 	}{result1, result2}
 }
 
-func (fake *FakeMarketplaceInterface) AttachPublicChart(arg1 *url.URL, arg2 string, arg3 *models.Product, arg4 *models.Version) (*models.Product, error) {
+func (fake *FakeMarketplaceInterface) AttachPublicChart(arg1 context.Context, arg2 *url.URL, arg3 string, arg4 *models.Product, arg5 *models.Version) (*models.Product, error) {
 	fake.attachPublicChartMutex.Lock()
 	ret, specificReturn := fake.attachPublicChartReturnsOnCall[len(fake.attachPublicChartArgsForCall)]
 	fake.attachPublicChartArgsForCall = append(fake.attachPublicChartArgsForCall, struct {
-		arg1 *url.URL
-		arg2 string
-		arg3 *models.Product
-		arg4 *models.Version
-	}{arg1, arg2, arg3, arg4})
+		arg1 context.Context
+		arg2 *url.URL
+		arg3 string
+		arg4 *models.Product
+		arg5 *models.Version
+	}{arg1, arg2, arg3, arg4, arg5})
 	stub := fake.AttachPublicChartStub
 	fakeReturns := fake.attachPublicChartReturns
-	fake.recordInvocation("AttachPublicChart", []interface{}{arg1, arg2, arg3, arg4})
+	fake.recordInvocation("AttachPublicChart", []interface{}{arg1, arg2, arg3, arg4, arg5})
 	fake.attachPublicChartMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2, arg3, arg4)
+		return stub(arg1, arg2, arg3, arg4, arg5)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -577,17 +662,17 @@ func (fake *FakeMarketplaceInterface) AttachPublicChartCallCount() int {
 	return len(fake.attachPublicChartArgsForCall)
 }
 
-func (fake *FakeMarketplaceInterface) AttachPublicChartCalls(stub func(*url.URL, string, *models.Product, *models.Version) (*models.Product, error)) {
+func (fake *FakeMarketplaceInterface) AttachPublicChartCalls(stub func(context.Context, *url.URL, string, *models.Product, *models.Version) (*models.Product, error)) {
 	fake.attachPublicChartMutex.Lock()
 	defer fake.attachPublicChartMutex.Unlock()
 	fake.AttachPublicChartStub = stub
 }
 
-func (fake *FakeMarketplaceInterface) AttachPublicChartArgsForCall(i int) (*url.URL, string, *models.Product, *models.Version) {
+func (fake *FakeMarketplaceInterface) AttachPublicChartArgsForCall(i int) (context.Context, *url.URL, string, *models.Product, *models.Version) {
 	fake.attachPublicChartMutex.RLock()
 	defer fake.attachPublicChartMutex.RUnlock()
 	argsForCall := fake.attachPublicChartArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5
 }
 
 func (fake *FakeMarketplaceInterface) AttachPublicChartReturns(result1 *models.Product, result2 error) {
@@ -616,23 +701,24 @@ func (fake *FakeMarketplaceInterface) AttachPublicChartReturnsOnCall(i int, resu
 	}{result1, result2}
 }
 
-func (fake *FakeMarketplaceInterface) AttachPublicContainerImage(arg1 string, arg2 string, arg3 string, arg4 string, arg5 *models.Product, arg6 *models.Version) (*models.Product, error) {
+func (fake *FakeMarketplaceInterface) AttachPublicContainerImage(arg1 context.Context, arg2 string, arg3 string, arg4 string, arg5 string, arg6 *models.Product, arg7 *models.Version) (*models.Product, error) {
 	fake.attachPublicContainerImageMutex.Lock()
 	ret, specificReturn := fake.attachPublicContainerImageReturnsOnCall[len(fake.attachPublicContainerImageArgsForCall)]
 	fake.attachPublicContainerImageArgsForCall = append(fake.attachPublicContainerImageArgsForCall, struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
 		arg3 string
 		arg4 string
-		arg5 *models.Product
-		arg6 *models.Version
-	}{arg1, arg2, arg3, arg4, arg5, arg6})
+		arg5 string
+		arg6 *models.Product
+		arg7 *models.Version
+	}{arg1, arg2, arg3, arg4, arg5, arg6, arg7})
 	stub := fake.AttachPublicContainerImageStub
 	fakeReturns := fake.attachPublicContainerImageReturns
-	fake.recordInvocation("AttachPublicContainerImage", []interface{}{arg1, arg2, arg3, arg4, arg5, arg6})
+	fake.recordInvocation("AttachPublicContainerImage", []interface{}{arg1, arg2, arg3, arg4, arg5, arg6, arg7})
 	fake.attachPublicContainerImageMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2, arg3, arg4, arg5, arg6)
+		return stub(arg1, arg2, arg3, arg4, arg5, arg6, arg7)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -646,17 +732,17 @@ func (fake *FakeMarketplaceInterface) AttachPublicContainerImageCallCount() int
 	return len(fake.attachPublicContainerImageArgsForCall)
 }
 
-func (fake *FakeMarketplaceInterface) AttachPublicContainerImageCalls(stub func(string, string, string, string, *models.Product, *models.Version) (*models.Product, error)) {
+func (fake *FakeMarketplaceInterface) AttachPublicContainerImageCalls(stub func(context.Context, string, string, string, string, *models.Product, *models.Version) (*models.Product, error)) {
 	fake.attachPublicContainerImageMutex.Lock()
 	defer fake.attachPublicContainerImageMutex.Unlock()
 	fake.AttachPublicContainerImageStub = stub
 }
 
-func (fake *FakeMarketplaceInterface) AttachPublicContainerImageArgsForCall(i int) (string, string, string, string, *models.Product, *models.Version) {
+func (fake *FakeMarketplaceInterface) AttachPublicContainerImageArgsForCall(i int) (context.Context, string, string, string, string, *models.Product, *models.Version) {
 	fake.attachPublicContainerImageMutex.RLock()
 	defer fake.attachPublicContainerImageMutex.RUnlock()
 	argsForCall := fake.attachPublicContainerImageArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4, argsForCall.arg5, argsForCall.arg6, argsForCall.arg7
 }
 
 func (fake *FakeMarketplaceInterface) AttachPublicContainerImageReturns(result1 *models.Product, result2 error) {
@@ -747,19 +833,20 @@ func (fake *FakeMarketplaceInterface) DecodeJsonReturnsOnCall(i int, result1 err
 	}{result1}
 }
 
-func (fake *FakeMarketplaceInterface) Download(arg1 string, arg2 *pkg.DownloadRequestPayload) error {
+func (fake *FakeMarketplaceInterface) Download(arg1 context.Context, arg2 string, arg3 *pkg.DownloadRequestPayload) error {
 	fake.downloadMutex.Lock()
 	ret, specificReturn := fake.downloadReturnsOnCall[len(fake.downloadArgsForCall)]
 	fake.downloadArgsForCall = append(fake.downloadArgsForCall, struct {
-		arg1 string
-		arg2 *pkg.DownloadRequestPayload
-	}{arg1, arg2})
+		arg1 context.Context
+		arg2 string
+		arg3 *pkg.DownloadRequestPayload
+	}{arg1, arg2, arg3})
 	stub := fake.DownloadStub
 	fakeReturns := fake.downloadReturns
-	fake.recordInvocation("Download", []interface{}{arg1, arg2})
+	fake.recordInvocation("Download", []interface{}{arg1, arg2, arg3})
 	fake.downloadMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1
@@ -773,17 +860,17 @@ func (fake *FakeMarketplaceInterface) DownloadCallCount() int {
 	return len(fake.downloadArgsForCall)
 }
 
-func (fake *FakeMarketplaceInterface) DownloadCalls(stub func(string, *pkg.DownloadRequestPayload) error) {
+func (fake *FakeMarketplaceInterface) DownloadCalls(stub func(context.Context, string, *pkg.DownloadRequestPayload) error) {
 	fake.downloadMutex.Lock()
 	defer fake.downloadMutex.Unlock()
 	fake.DownloadStub = stub
 }
 
-func (fake *FakeMarketplaceInterface) DownloadArgsForCall(i int) (string, *pkg.DownloadRequestPayload) {
+func (fake *FakeMarketplaceInterface) DownloadArgsForCall(i int) (context.Context, string, *pkg.DownloadRequestPayload) {
 	fake.downloadMutex.RLock()
 	defer fake.downloadMutex.RUnlock()
 	argsForCall := fake.downloadArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeMarketplaceInterface) DownloadReturns(result1 error) {
@@ -809,18 +896,19 @@ func (fake *FakeMarketplaceInterface) DownloadReturnsOnCall(i int, result1 error
 	}{result1}
 }
 
-func (fake *FakeMarketplaceInterface) DownloadChart(arg1 *url.URL) (*models.ChartVersion, error) {
+func (fake *FakeMarketplaceInterface) DownloadChart(arg1 context.Context, arg2 *url.URL) (*models.ChartVersion, error) {
 	fake.downloadChartMutex.Lock()
 	ret, specificReturn := fake.downloadChartReturnsOnCall[len(fake.downloadChartArgsForCall)]
 	fake.downloadChartArgsForCall = append(fake.downloadChartArgsForCall, struct {
-		arg1 *url.URL
-	}{arg1})
+		arg1 context.Context
+		arg2 *url.URL
+	}{arg1, arg2})
 	stub := fake.DownloadChartStub
 	fakeReturns := fake.downloadChartReturns
-	fake.recordInvocation("DownloadChart", []interface{}{arg1})
+	fake.recordInvocation("DownloadChart", []interface{}{arg1, arg2})
 	fake.downloadChartMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -834,17 +922,17 @@ func (fake *FakeMarketplaceInterface) DownloadChartCallCount() int {
 	return len(fake.downloadChartArgsForCall)
 }
 
-func (fake *FakeMarketplaceInterface) DownloadChartCalls(stub func(*url.URL) (*models.ChartVersion, error)) {
+func (fake *FakeMarketplaceInterface) DownloadChartCalls(stub func(context.Context, *url.URL) (*models.ChartVersion, error)) {
 	fake.downloadChartMutex.Lock()
 	defer fake.downloadChartMutex.Unlock()
 	fake.DownloadChartStub = stub
 }
 
-func (fake *FakeMarketplaceInterface) DownloadChartArgsForCall(i int) *url.URL {
+func (fake *FakeMarketplaceInterface) DownloadChartArgsForCall(i int) (context.Context, *url.URL) {
 	fake.downloadChartMutex.RLock()
 	defer fake.downloadChartMutex.RUnlock()
 	argsForCall := fake.downloadChartArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeMarketplaceInterface) DownloadChartReturns(result1 *models.ChartVersion, result2 error) {
@@ -873,6 +961,132 @@ func (fake *FakeMarketplaceInterface) DownloadChartReturnsOnCall(i int, result1
 	}{result1, result2}
 }
 
+func (fake *FakeMarketplaceInterface) EachProduct(arg1 context.Context, arg2 *pkg.ListProductsOptions, arg3 pkg.ProductFunc) error {
+	fake.eachProductMutex.Lock()
+	ret, specificReturn := fake.eachProductReturnsOnCall[len(fake.eachProductArgsForCall)]
+	fake.eachProductArgsForCall = append(fake.eachProductArgsForCall, struct {
+		arg1 context.Context
+		arg2 *pkg.ListProductsOptions
+		arg3 pkg.ProductFunc
+	}{arg1, arg2, arg3})
+	stub := fake.EachProductStub
+	fakeReturns := fake.eachProductReturns
+	fake.recordInvocation("EachProduct", []interface{}{arg1, arg2, arg3})
+	fake.eachProductMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeMarketplaceInterface) EachProductCallCount() int {
+	fake.eachProductMutex.RLock()
+	defer fake.eachProductMutex.RUnlock()
+	return len(fake.eachProductArgsForCall)
+}
+
+func (fake *FakeMarketplaceInterface) EachProductCalls(stub func(context.Context, *pkg.ListProductsOptions, pkg.ProductFunc) error) {
+	fake.eachProductMutex.Lock()
+	defer fake.eachProductMutex.Unlock()
+	fake.EachProductStub = stub
+}
+
+func (fake *FakeMarketplaceInterface) EachProductArgsForCall(i int) (context.Context, *pkg.ListProductsOptions, pkg.ProductFunc) {
+	fake.eachProductMutex.RLock()
+	defer fake.eachProductMutex.RUnlock()
+	argsForCall := fake.eachProductArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeMarketplaceInterface) EachProductReturns(result1 error) {
+	fake.eachProductMutex.Lock()
+	defer fake.eachProductMutex.Unlock()
+	fake.EachProductStub = nil
+	fake.eachProductReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeMarketplaceInterface) EachProductReturnsOnCall(i int, result1 error) {
+	fake.eachProductMutex.Lock()
+	defer fake.eachProductMutex.Unlock()
+	fake.EachProductStub = nil
+	if fake.eachProductReturnsOnCall == nil {
+		fake.eachProductReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.eachProductReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeMarketplaceInterface) EachProductPage(arg1 context.Context, arg2 *pkg.ListProductsOptions, arg3 pkg.ProductPageFunc) error {
+	fake.eachProductPageMutex.Lock()
+	ret, specificReturn := fake.eachProductPageReturnsOnCall[len(fake.eachProductPageArgsForCall)]
+	fake.eachProductPageArgsForCall = append(fake.eachProductPageArgsForCall, struct {
+		arg1 context.Context
+		arg2 *pkg.ListProductsOptions
+		arg3 pkg.ProductPageFunc
+	}{arg1, arg2, arg3})
+	stub := fake.EachProductPageStub
+	fakeReturns := fake.eachProductPageReturns
+	fake.recordInvocation("EachProductPage", []interface{}{arg1, arg2, arg3})
+	fake.eachProductPageMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2, arg3)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fakeReturns.result1
+}
+
+func (fake *FakeMarketplaceInterface) EachProductPageCallCount() int {
+	fake.eachProductPageMutex.RLock()
+	defer fake.eachProductPageMutex.RUnlock()
+	return len(fake.eachProductPageArgsForCall)
+}
+
+func (fake *FakeMarketplaceInterface) EachProductPageCalls(stub func(context.Context, *pkg.ListProductsOptions, pkg.ProductPageFunc) error) {
+	fake.eachProductPageMutex.Lock()
+	defer fake.eachProductPageMutex.Unlock()
+	fake.EachProductPageStub = stub
+}
+
+func (fake *FakeMarketplaceInterface) EachProductPageArgsForCall(i int) (context.Context, *pkg.ListProductsOptions, pkg.ProductPageFunc) {
+	fake.eachProductPageMutex.RLock()
+	defer fake.eachProductPageMutex.RUnlock()
+	argsForCall := fake.eachProductPageArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+}
+
+func (fake *FakeMarketplaceInterface) EachProductPageReturns(result1 error) {
+	fake.eachProductPageMutex.Lock()
+	defer fake.eachProductPageMutex.Unlock()
+	fake.EachProductPageStub = nil
+	fake.eachProductPageReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeMarketplaceInterface) EachProductPageReturnsOnCall(i int, result1 error) {
+	fake.eachProductPageMutex.Lock()
+	defer fake.eachProductPageMutex.Unlock()
+	fake.EachProductPageStub = nil
+	if fake.eachProductPageReturnsOnCall == nil {
+		fake.eachProductPageReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.eachProductPageReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeMarketplaceInterface) EnableStrictDecoding() {
 	fake.enableStrictDecodingMutex.Lock()
 	fake.enableStrictDecodingArgsForCall = append(fake.enableStrictDecodingArgsForCall, struct {
@@ -1003,18 +1217,19 @@ func (fake *FakeMarketplaceInterface) GetHostReturnsOnCall(i int, result1 string
 	}{result1}
 }
 
-func (fake *FakeMarketplaceInterface) GetProduct(arg1 string) (*models.Product, error) {
+func (fake *FakeMarketplaceInterface) GetProduct(arg1 context.Context, arg2 string) (*models.Product, error) {
 	fake.getProductMutex.Lock()
 	ret, specificReturn := fake.getProductReturnsOnCall[len(fake.getProductArgsForCall)]
 	fake.getProductArgsForCall = append(fake.getProductArgsForCall, struct {
-		arg1 string
-	}{arg1})
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
 	stub := fake.GetProductStub
 	fakeReturns := fake.getProductReturns
-	fake.recordInvocation("GetProduct", []interface{}{arg1})
+	fake.recordInvocation("GetProduct", []interface{}{arg1, arg2})
 	fake.getProductMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -1028,17 +1243,17 @@ func (fake *FakeMarketplaceInterface) GetProductCallCount() int {
 	return len(fake.getProductArgsForCall)
 }
 
-func (fake *FakeMarketplaceInterface) GetProductCalls(stub func(string) (*models.Product, error)) {
+func (fake *FakeMarketplaceInterface) GetProductCalls(stub func(context.Context, string) (*models.Product, error)) {
 	fake.getProductMutex.Lock()
 	defer fake.getProductMutex.Unlock()
 	fake.GetProductStub = stub
 }
 
-func (fake *FakeMarketplaceInterface) GetProductArgsForCall(i int) string {
+func (fake *FakeMarketplaceInterface) GetProductArgsForCall(i int) (context.Context, string) {
 	fake.getProductMutex.RLock()
 	defer fake.getProductMutex.RUnlock()
 	argsForCall := fake.getProductArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeMarketplaceInterface) GetProductReturns(result1 *models.Product, result2 error) {
@@ -1067,19 +1282,20 @@ func (fake *FakeMarketplaceInterface) GetProductReturnsOnCall(i int, result1 *mo
 	}{result1, result2}
 }
 
-func (fake *FakeMarketplaceInterface) GetProductWithVersion(arg1 string, arg2 string) (*models.Product, *models.Version, error) {
+func (fake *FakeMarketplaceInterface) GetProductWithVersion(arg1 context.Context, arg2 string, arg3 string) (*models.Product, *models.Version, error) {
 	fake.getProductWithVersionMutex.Lock()
 	ret, specificReturn := fake.getProductWithVersionReturnsOnCall[len(fake.getProductWithVersionArgsForCall)]
 	fake.getProductWithVersionArgsForCall = append(fake.getProductWithVersionArgsForCall, struct {
-		arg1 string
+		arg1 context.Context
 		arg2 string
-	}{arg1, arg2})
+		arg3 string
+	}{arg1, arg2, arg3})
 	stub := fake.GetProductWithVersionStub
 	fakeReturns := fake.getProductWithVersionReturns
-	fake.recordInvocation("GetProductWithVersion", []interface{}{arg1, arg2})
+	fake.recordInvocation("GetProductWithVersion", []interface{}{arg1, arg2, arg3})
 	fake.getProductWithVersionMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2, ret.result3
@@ -1093,17 +1309,17 @@ func (fake *FakeMarketplaceInterface) GetProductWithVersionCallCount() int {
 	return len(fake.getProductWithVersionArgsForCall)
 }
 
-func (fake *FakeMarketplaceInterface) GetProductWithVersionCalls(stub func(string, string) (*models.Product, *models.Version, error)) {
+func (fake *FakeMarketplaceInterface) GetProductWithVersionCalls(stub func(context.Context, string, string) (*models.Product, *models.Version, error)) {
 	fake.getProductWithVersionMutex.Lock()
 	defer fake.getProductWithVersionMutex.Unlock()
 	fake.GetProductWithVersionStub = stub
 }
 
-func (fake *FakeMarketplaceInterface) GetProductWithVersionArgsForCall(i int) (string, string) {
+func (fake *FakeMarketplaceInterface) GetProductWithVersionArgsForCall(i int) (context.Context, string, string) {
 	fake.getProductWithVersionMutex.RLock()
 	defer fake.getProductWithVersionMutex.RUnlock()
 	argsForCall := fake.getProductWithVersionArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeMarketplaceInterface) GetProductWithVersionReturns(result1 *models.Product, result2 *models.Version, result3 error) {
@@ -1135,6 +1351,70 @@ func (fake *FakeMarketplaceInterface) GetProductWithVersionReturnsOnCall(i int,
 	}{result1, result2, result3}
 }
 
+func (fake *FakeMarketplaceInterface) GetStorageBucketRegion(arg1 context.Context) (string, error) {
+	fake.getStorageBucketRegionMutex.Lock()
+	ret, specificReturn := fake.getStorageBucketRegionReturnsOnCall[len(fake.getStorageBucketRegionArgsForCall)]
+	fake.getStorageBucketRegionArgsForCall = append(fake.getStorageBucketRegionArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.GetStorageBucketRegionStub
+	fakeReturns := fake.getStorageBucketRegionReturns
+	fake.recordInvocation("GetStorageBucketRegion", []interface{}{arg1})
+	fake.getStorageBucketRegionMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeMarketplaceInterface) GetStorageBucketRegionCallCount() int {
+	fake.getStorageBucketRegionMutex.RLock()
+	defer fake.getStorageBucketRegionMutex.RUnlock()
+	return len(fake.getStorageBucketRegionArgsForCall)
+}
+
+func (fake *FakeMarketplaceInterface) GetStorageBucketRegionCalls(stub func(context.Context) (string, error)) {
+	fake.getStorageBucketRegionMutex.Lock()
+	defer fake.getStorageBucketRegionMutex.Unlock()
+	fake.GetStorageBucketRegionStub = stub
+}
+
+func (fake *FakeMarketplaceInterface) GetStorageBucketRegionArgsForCall(i int) context.Context {
+	fake.getStorageBucketRegionMutex.RLock()
+	defer fake.getStorageBucketRegionMutex.RUnlock()
+	argsForCall := fake.getStorageBucketRegionArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeMarketplaceInterface) GetStorageBucketRegionReturns(result1 string, result2 error) {
+	fake.getStorageBucketRegionMutex.Lock()
+	defer fake.getStorageBucketRegionMutex.Unlock()
+	fake.GetStorageBucketRegionStub = nil
+	fake.getStorageBucketRegionReturns = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeMarketplaceInterface) GetStorageBucketRegionReturnsOnCall(i int, result1 string, result2 error) {
+	fake.getStorageBucketRegionMutex.Lock()
+	defer fake.getStorageBucketRegionMutex.Unlock()
+	fake.GetStorageBucketRegionStub = nil
+	if fake.getStorageBucketRegionReturnsOnCall == nil {
+		fake.getStorageBucketRegionReturnsOnCall = make(map[int]struct {
+			result1 string
+			result2 error
+		})
+	}
+	fake.getStorageBucketRegionReturnsOnCall[i] = struct {
+		result1 string
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeMarketplaceInterface) GetUIHost() string {
 	fake.getUIHostMutex.Lock()
 	ret, specificReturn := fake.getUIHostReturnsOnCall[len(fake.getUIHostArgsForCall)]
@@ -1188,18 +1468,83 @@ func (fake *FakeMarketplaceInterface) GetUIHostReturnsOnCall(i int, result1 stri
 	}{result1}
 }
 
-func (fake *FakeMarketplaceInterface) GetUploader(arg1 string) (internal.Uploader, error) {
+func (fake *FakeMarketplaceInterface) GetUploadCredentials(arg1 context.Context) (*pkg.CredentialsResponse, error) {
+	fake.getUploadCredentialsMutex.Lock()
+	ret, specificReturn := fake.getUploadCredentialsReturnsOnCall[len(fake.getUploadCredentialsArgsForCall)]
+	fake.getUploadCredentialsArgsForCall = append(fake.getUploadCredentialsArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	stub := fake.GetUploadCredentialsStub
+	fakeReturns := fake.getUploadCredentialsReturns
+	fake.recordInvocation("GetUploadCredentials", []interface{}{arg1})
+	fake.getUploadCredentialsMutex.Unlock()
+	if stub != nil {
+		return stub(arg1)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeMarketplaceInterface) GetUploadCredentialsCallCount() int {
+	fake.getUploadCredentialsMutex.RLock()
+	defer fake.getUploadCredentialsMutex.RUnlock()
+	return len(fake.getUploadCredentialsArgsForCall)
+}
+
+func (fake *FakeMarketplaceInterface) GetUploadCredentialsCalls(stub func(context.Context) (*pkg.CredentialsResponse, error)) {
+	fake.getUploadCredentialsMutex.Lock()
+	defer fake.getUploadCredentialsMutex.Unlock()
+	fake.GetUploadCredentialsStub = stub
+}
+
+func (fake *FakeMarketplaceInterface) GetUploadCredentialsArgsForCall(i int) context.Context {
+	fake.getUploadCredentialsMutex.RLock()
+	defer fake.getUploadCredentialsMutex.RUnlock()
+	argsForCall := fake.getUploadCredentialsArgsForCall[i]
+	return argsForCall.arg1
+}
+
+func (fake *FakeMarketplaceInterface) GetUploadCredentialsReturns(result1 *pkg.CredentialsResponse, result2 error) {
+	fake.getUploadCredentialsMutex.Lock()
+	defer fake.getUploadCredentialsMutex.Unlock()
+	fake.GetUploadCredentialsStub = nil
+	fake.getUploadCredentialsReturns = struct {
+		result1 *pkg.CredentialsResponse
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeMarketplaceInterface) GetUploadCredentialsReturnsOnCall(i int, result1 *pkg.CredentialsResponse, result2 error) {
+	fake.getUploadCredentialsMutex.Lock()
+	defer fake.getUploadCredentialsMutex.Unlock()
+	fake.GetUploadCredentialsStub = nil
+	if fake.getUploadCredentialsReturnsOnCall == nil {
+		fake.getUploadCredentialsReturnsOnCall = make(map[int]struct {
+			result1 *pkg.CredentialsResponse
+			result2 error
+		})
+	}
+	fake.getUploadCredentialsReturnsOnCall[i] = struct {
+		result1 *pkg.CredentialsResponse
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeMarketplaceInterface) GetUploader(arg1 context.Context, arg2 string) (internal.Uploader, error) {
 	fake.getUploaderMutex.Lock()
 	ret, specificReturn := fake.getUploaderReturnsOnCall[len(fake.getUploaderArgsForCall)]
 	fake.getUploaderArgsForCall = append(fake.getUploaderArgsForCall, struct {
-		arg1 string
-	}{arg1})
+		arg1 context.Context
+		arg2 string
+	}{arg1, arg2})
 	stub := fake.GetUploaderStub
 	fakeReturns := fake.getUploaderReturns
-	fake.recordInvocation("GetUploader", []interface{}{arg1})
+	fake.recordInvocation("GetUploader", []interface{}{arg1, arg2})
 	fake.getUploaderMutex.Unlock()
 	if stub != nil {
-		return stub(arg1)
+		return stub(arg1, arg2)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -1213,17 +1558,17 @@ func (fake *FakeMarketplaceInterface) GetUploaderCallCount() int {
 	return len(fake.getUploaderArgsForCall)
 }
 
-func (fake *FakeMarketplaceInterface) GetUploaderCalls(stub func(string) (internal.Uploader, error)) {
+func (fake *FakeMarketplaceInterface) GetUploaderCalls(stub func(context.Context, string) (internal.Uploader, error)) {
 	fake.getUploaderMutex.Lock()
 	defer fake.getUploaderMutex.Unlock()
 	fake.GetUploaderStub = stub
 }
 
-func (fake *FakeMarketplaceInterface) GetUploaderArgsForCall(i int) string {
+func (fake *FakeMarketplaceInterface) GetUploaderArgsForCall(i int) (context.Context, string) {
 	fake.getUploaderMutex.RLock()
 	defer fake.getUploaderMutex.RUnlock()
 	argsForCall := fake.getUploaderArgsForCall[i]
-	return argsForCall.arg1
+	return argsForCall.arg1, argsForCall.arg2
 }
 
 func (fake *FakeMarketplaceInterface) GetUploaderReturns(result1 internal.Uploader, result2 error) {
@@ -1252,12 +1597,77 @@ func (fake *FakeMarketplaceInterface) GetUploaderReturnsOnCall(i int, result1 in
 	}{result1, result2}
 }
 
-func (fake *FakeMarketplaceInterface) ListProducts(arg1 bool, arg2 string) ([]*models.Product, error) {
+func (fake *FakeMarketplaceInterface) ListProductFilters(arg1 context.Context, arg2 *pkg.ListProductsOptions) ([]*pkg.ProductFacet, error) {
+	fake.listProductFiltersMutex.Lock()
+	ret, specificReturn := fake.listProductFiltersReturnsOnCall[len(fake.listProductFiltersArgsForCall)]
+	fake.listProductFiltersArgsForCall = append(fake.listProductFiltersArgsForCall, struct {
+		arg1 context.Context
+		arg2 *pkg.ListProductsOptions
+	}{arg1, arg2})
+	stub := fake.ListProductFiltersStub
+	fakeReturns := fake.listProductFiltersReturns
+	fake.recordInvocation("ListProductFilters", []interface{}{arg1, arg2})
+	fake.listProductFiltersMutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fakeReturns.result1, fakeReturns.result2
+}
+
+func (fake *FakeMarketplaceInterface) ListProductFiltersCallCount() int {
+	fake.listProductFiltersMutex.RLock()
+	defer fake.listProductFiltersMutex.RUnlock()
+	return len(fake.listProductFiltersArgsForCall)
+}
+
+func (fake *FakeMarketplaceInterface) ListProductFiltersCalls(stub func(context.Context, *pkg.ListProductsOptions) ([]*pkg.ProductFacet, error)) {
+	fake.listProductFiltersMutex.Lock()
+	defer fake.listProductFiltersMutex.Unlock()
+	fake.ListProductFiltersStub = stub
+}
+
+func (fake *FakeMarketplaceInterface) ListProductFiltersArgsForCall(i int) (context.Context, *pkg.ListProductsOptions) {
+	fake.listProductFiltersMutex.RLock()
+	defer fake.listProductFiltersMutex.RUnlock()
+	argsForCall := fake.listProductFiltersArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeMarketplaceInterface) ListProductFiltersReturns(result1 []*pkg.ProductFacet, result2 error) {
+	fake.listProductFiltersMutex.Lock()
+	defer fake.listProductFiltersMutex.Unlock()
+	fake.ListProductFiltersStub = nil
+	fake.listProductFiltersReturns = struct {
+		result1 []*pkg.ProductFacet
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeMarketplaceInterface) ListProductFiltersReturnsOnCall(i int, result1 []*pkg.ProductFacet, result2 error) {
+	fake.listProductFiltersMutex.Lock()
+	defer fake.listProductFiltersMutex.Unlock()
+	fake.ListProductFiltersStub = nil
+	if fake.listProductFiltersReturnsOnCall == nil {
+		fake.listProductFiltersReturnsOnCall = make(map[int]struct {
+			result1 []*pkg.ProductFacet
+			result2 error
+		})
+	}
+	fake.listProductFiltersReturnsOnCall[i] = struct {
+		result1 []*pkg.ProductFacet
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeMarketplaceInterface) ListProducts(arg1 context.Context, arg2 *pkg.ListProductsOptions) ([]*models.Product, error) {
 	fake.listProductsMutex.Lock()
 	ret, specificReturn := fake.listProductsReturnsOnCall[len(fake.listProductsArgsForCall)]
 	fake.listProductsArgsForCall = append(fake.listProductsArgsForCall, struct {
-		arg1 bool
-		arg2 string
+		arg1 context.Context
+		arg2 *pkg.ListProductsOptions
 	}{arg1, arg2})
 	stub := fake.ListProductsStub
 	fakeReturns := fake.listProductsReturns
@@ -1278,13 +1688,13 @@ func (fake *FakeMarketplaceInterface) ListProductsCallCount() int {
 	return len(fake.listProductsArgsForCall)
 }
 
-func (fake *FakeMarketplaceInterface) ListProductsCalls(stub func(bool, string) ([]*models.Product, error)) {
+func (fake *FakeMarketplaceInterface) ListProductsCalls(stub func(context.Context, *pkg.ListProductsOptions) ([]*models.Product, error)) {
 	fake.listProductsMutex.Lock()
 	defer fake.listProductsMutex.Unlock()
 	fake.ListProductsStub = stub
 }
 
-func (fake *FakeMarketplaceInterface) ListProductsArgsForCall(i int) (bool, string) {
+func (fake *FakeMarketplaceInterface) ListProductsArgsForCall(i int) (context.Context, *pkg.ListProductsOptions) {
 	fake.listProductsMutex.RLock()
 	defer fake.listProductsMutex.RUnlock()
 	argsForCall := fake.listProductsArgsForCall[i]
@@ -1317,19 +1727,20 @@ func (fake *FakeMarketplaceInterface) ListProductsReturnsOnCall(i int, result1 [
 	}{result1, result2}
 }
 
-func (fake *FakeMarketplaceInterface) PutProduct(arg1 *models.Product, arg2 bool) (*models.Product, error) {
+func (fake *FakeMarketplaceInterface) PutProduct(arg1 context.Context, arg2 *models.Product, arg3 bool) (*models.Product, error) {
 	fake.putProductMutex.Lock()
 	ret, specificReturn := fake.putProductReturnsOnCall[len(fake.putProductArgsForCall)]
 	fake.putProductArgsForCall = append(fake.putProductArgsForCall, struct {
-		arg1 *models.Product
-		arg2 bool
-	}{arg1, arg2})
+		arg1 context.Context
+		arg2 *models.Product
+		arg3 bool
+	}{arg1, arg2, arg3})
 	stub := fake.PutProductStub
 	fakeReturns := fake.putProductReturns
-	fake.recordInvocation("PutProduct", []interface{}{arg1, arg2})
+	fake.recordInvocation("PutProduct", []interface{}{arg1, arg2, arg3})
 	fake.putProductMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2)
+		return stub(arg1, arg2, arg3)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -1343,17 +1754,17 @@ func (fake *FakeMarketplaceInterface) PutProductCallCount() int {
 	return len(fake.putProductArgsForCall)
 }
 
-func (fake *FakeMarketplaceInterface) PutProductCalls(stub func(*models.Product, bool) (*models.Product, error)) {
+func (fake *FakeMarketplaceInterface) PutProductCalls(stub func(context.Context, *models.Product, bool) (*models.Product, error)) {
 	fake.putProductMutex.Lock()
 	defer fake.putProductMutex.Unlock()
 	fake.PutProductStub = stub
 }
 
-func (fake *FakeMarketplaceInterface) PutProductArgsForCall(i int) (*models.Product, bool) {
+func (fake *FakeMarketplaceInterface) PutProductArgsForCall(i int) (context.Context, *models.Product, bool) {
 	fake.putProductMutex.RLock()
 	defer fake.putProductMutex.RUnlock()
 	argsForCall := fake.putProductArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
 }
 
 func (fake *FakeMarketplaceInterface) PutProductReturns(result1 *models.Product, result2 error) {
@@ -1414,20 +1825,21 @@ func (fake *FakeMarketplaceInterface) SetUploaderArgsForCall(i int) internal.Upl
 	return argsForCall.arg1
 }
 
-func (fake *FakeMarketplaceInterface) UploadVM(arg1 string, arg2 *models.Product, arg3 *models.Version) (*models.Product, error) {
+func (fake *FakeMarketplaceInterface) UploadVM(arg1 context.Context, arg2 string, arg3 *models.Product, arg4 *models.Version) (*models.Product, error) {
 	fake.uploadVMMutex.Lock()
 	ret, specificReturn := fake.uploadVMReturnsOnCall[len(fake.uploadVMArgsForCall)]
 	fake.uploadVMArgsForCall = append(fake.uploadVMArgsForCall, struct {
-		arg1 string
-		arg2 *models.Product
-		arg3 *models.Version
-	}{arg1, arg2, arg3})
+		arg1 context.Context
+		arg2 string
+		arg3 *models.Product
+		arg4 *models.Version
+	}{arg1, arg2, arg3, arg4})
 	stub := fake.UploadVMStub
 	fakeReturns := fake.uploadVMReturns
-	fake.recordInvocation("UploadVM", []interface{}{arg1, arg2, arg3})
+	fake.recordInvocation("UploadVM", []interface{}{arg1, arg2, arg3, arg4})
 	fake.uploadVMMutex.Unlock()
 	if stub != nil {
-		return stub(arg1, arg2, arg3)
+		return stub(arg1, arg2, arg3, arg4)
 	}
 	if specificReturn {
 		return ret.result1, ret.result2
@@ -1441,17 +1853,17 @@ func (fake *FakeMarketplaceInterface) UploadVMCallCount() int {
 	return len(fake.uploadVMArgsForCall)
 }
 
-func (fake *FakeMarketplaceInterface) UploadVMCalls(stub func(string, *models.Product, *models.Version) (*models.Product, error)) {
+func (fake *FakeMarketplaceInterface) UploadVMCalls(stub func(context.Context, string, *models.Product, *models.Version) (*models.Product, error)) {
 	fake.uploadVMMutex.Lock()
 	defer fake.uploadVMMutex.Unlock()
 	fake.UploadVMStub = stub
 }
 
-func (fake *FakeMarketplaceInterface) UploadVMArgsForCall(i int) (string, *models.Product, *models.Version) {
+func (fake *FakeMarketplaceInterface) UploadVMArgsForCall(i int) (context.Context, string, *models.Product, *models.Version) {
 	fake.uploadVMMutex.RLock()
 	defer fake.uploadVMMutex.RUnlock()
 	argsForCall := fake.uploadVMArgsForCall[i]
-	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3
+	return argsForCall.arg1, argsForCall.arg2, argsForCall.arg3, argsForCall.arg4
 }
 
 func (fake *FakeMarketplaceInterface) UploadVMReturns(result1 *models.Product, result2 error) {
@@ -1501,6 +1913,10 @@ func (fake *FakeMarketplaceInterface) Invocations() map[string][][]interface{} {
 	defer fake.downloadMutex.RUnlock()
 	fake.downloadChartMutex.RLock()
 	defer fake.downloadChartMutex.RUnlock()
+	fake.eachProductMutex.RLock()
+	defer fake.eachProductMutex.RUnlock()
+	fake.eachProductPageMutex.RLock()
+	defer fake.eachProductPageMutex.RUnlock()
 	fake.enableStrictDecodingMutex.RLock()
 	defer fake.enableStrictDecodingMutex.RUnlock()
 	fake.getAPIHostMutex.RLock()
@@ -1511,10 +1927,16 @@ func (fake *FakeMarketplaceInterface) Invocations() map[string][][]interface{} {
 	defer fake.getProductMutex.RUnlock()
 	fake.getProductWithVersionMutex.RLock()
 	defer fake.getProductWithVersionMutex.RUnlock()
+	fake.getStorageBucketRegionMutex.RLock()
+	defer fake.getStorageBucketRegionMutex.RUnlock()
 	fake.getUIHostMutex.RLock()
 	defer fake.getUIHostMutex.RUnlock()
+	fake.getUploadCredentialsMutex.RLock()
+	defer fake.getUploadCredentialsMutex.RUnlock()
 	fake.getUploaderMutex.RLock()
 	defer fake.getUploaderMutex.RUnlock()
+	fake.listProductFiltersMutex.RLock()
+	defer fake.listProductFiltersMutex.RUnlock()
 	fake.listProductsMutex.RLock()
 	defer fake.listProductsMutex.RUnlock()
 	fake.putProductMutex.RLock()
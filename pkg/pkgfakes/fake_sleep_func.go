@@ -0,0 +1,114 @@
+// Code generated by counterfeiter. DO NOT EDIT.
+package pkgfakes
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/vmware-labs/marketplace-cli/v2/pkg"
+)
+
+type FakeSleepFunc struct {
+	Stub        func(context.Context, time.Duration) error
+	mutex       sync.RWMutex
+	argsForCall []struct {
+		arg1 context.Context
+		arg2 time.Duration
+	}
+	returns struct {
+		result1 error
+	}
+	returnsOnCall map[int]struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeSleepFunc) Spy(arg1 context.Context, arg2 time.Duration) error {
+	fake.mutex.Lock()
+	ret, specificReturn := fake.returnsOnCall[len(fake.argsForCall)]
+	fake.argsForCall = append(fake.argsForCall, struct {
+		arg1 context.Context
+		arg2 time.Duration
+	}{arg1, arg2})
+	stub := fake.Stub
+	returns := fake.returns
+	fake.recordInvocation("SleepFunc", []interface{}{arg1, arg2})
+	fake.mutex.Unlock()
+	if stub != nil {
+		return stub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return returns.result1
+}
+
+func (fake *FakeSleepFunc) CallCount() int {
+	fake.mutex.RLock()
+	defer fake.mutex.RUnlock()
+	return len(fake.argsForCall)
+}
+
+func (fake *FakeSleepFunc) Calls(stub func(context.Context, time.Duration) error) {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+	fake.Stub = stub
+}
+
+func (fake *FakeSleepFunc) ArgsForCall(i int) (context.Context, time.Duration) {
+	fake.mutex.RLock()
+	defer fake.mutex.RUnlock()
+	return fake.argsForCall[i].arg1, fake.argsForCall[i].arg2
+}
+
+func (fake *FakeSleepFunc) Returns(result1 error) {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+	fake.Stub = nil
+	fake.returns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeSleepFunc) ReturnsOnCall(i int, result1 error) {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+	fake.Stub = nil
+	if fake.returnsOnCall == nil {
+		fake.returnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.returnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeSleepFunc) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.mutex.RLock()
+	defer fake.mutex.RUnlock()
+	copiedInvocations := map[string][][]interface{}{}
+	for key, value := range fake.invocations {
+		copiedInvocations[key] = value
+	}
+	return copiedInvocations
+}
+
+func (fake *FakeSleepFunc) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ pkg.SleepFunc = new(FakeSleepFunc).Spy
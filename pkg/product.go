@@ -5,16 +5,15 @@ package pkg
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/url"
 	"strconv"
-	"time"
 
 	"github.com/google/uuid"
-	"github.com/schollz/progressbar/v3"
 	"github.com/vmware-labs/marketplace-cli/v2/internal"
 	"github.com/vmware-labs/marketplace-cli/v2/internal/models"
 )
@@ -54,88 +53,6 @@ type ListProductResponsePayload struct {
 	Params           *ListProductResponseParams `json:"params"`
 }
 
-func (m *Marketplace) ListProducts(allOrgs bool, searchTerm string) ([]*models.Product, error) {
-	values := url.Values{
-		"managed": []string{strconv.FormatBool(!allOrgs)},
-	}
-	if searchTerm != "" {
-		values.Set("search", searchTerm)
-	}
-
-	var products []*models.Product
-	firstTime := true
-	totalProducts := 0
-	pagination := &internal.Pagination{
-		Page:     1,
-		PageSize: 20,
-	}
-	sorting := &internal.Sorting{
-		Order:     1,
-		Key:       internal.SortKeyDisplayName,
-		Direction: internal.SortDirectionAscending,
-	}
-
-	var progressBar *progressbar.ProgressBar
-	for ; firstTime || len(products) < totalProducts; pagination.Page++ {
-		requestURL := MakeURL(m.GetHost(), "/api/v1/products", values)
-		ApplyParameters(requestURL, pagination, sorting)
-		resp, err := m.Client.Get(requestURL)
-		if err != nil {
-			return nil, fmt.Errorf("sending the request for the list of products failed: %w", err)
-		}
-
-		if resp.StatusCode != http.StatusOK {
-			body, err := ioutil.ReadAll(resp.Body)
-			if err != nil {
-				body = []byte{}
-			}
-			return nil, fmt.Errorf("getting the list of products failed: (%d) %s: %s", resp.StatusCode, resp.Status, body)
-		}
-
-		response := &ListProductResponse{}
-		err = m.DecodeJson(resp.Body, response)
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse the list of products: %w", err)
-		}
-
-		// Return immediately if we get an empty list.
-		// On empty lists, we cannot necessarily trust response.Response.Params.ProductCount
-		// See: https://github.com/vmware-labs/marketplace-cli/issues/62
-		if len(response.Response.Products) == 0 {
-			return products, nil
-		}
-
-		products = append(products, response.Response.Products...)
-
-		if firstTime {
-			totalProducts = response.Response.Params.ProductCount
-			progressBar = m.makeRequestProgressBar(totalProducts)
-			firstTime = false
-		}
-		_ = progressBar.Add(len(response.Response.Products))
-	}
-
-	return products, nil
-}
-
-func (m *Marketplace) makeRequestProgressBar(max int) *progressbar.ProgressBar {
-	progressBar := progressbar.NewOptions(
-		max,
-		progressbar.OptionSetDescription("Getting the list of products"),
-		progressbar.OptionSetWriter(m.Output),
-		progressbar.OptionSetWidth(10),
-		progressbar.OptionThrottle(65*time.Millisecond),
-		progressbar.OptionShowCount(),
-		progressbar.OptionShowIts(),
-		progressbar.OptionSetItsString("products"),
-		progressbar.OptionClearOnFinish(),
-		progressbar.OptionSpinnerType(14),
-		progressbar.OptionFullWidth(),
-	)
-	_ = progressBar.RenderBlank()
-	return progressBar
-}
-
 type GetProductResponse struct {
 	Response *GetProductResponsePayload `json:"response"`
 }
@@ -160,7 +77,7 @@ type VersionSpecificDetailsPayload struct {
 	Data       *models.VersionSpecificProductDetails `json:"data"`
 }
 
-func (m *Marketplace) GetProduct(slug string) (*models.Product, error) {
+func (m *Marketplace) GetProduct(ctx context.Context, slug string) (*models.Product, error) {
 	isSlug := true
 	_, err := uuid.Parse(slug)
 	if err == nil {
@@ -176,7 +93,7 @@ func (m *Marketplace) GetProduct(slug string) (*models.Product, error) {
 		},
 	)
 
-	resp, err := m.Client.Get(requestURL)
+	resp, err := m.cachedRequest(ctx, http.MethodGet, requestURL, nil, slug)
 	if err != nil {
 		return nil, fmt.Errorf("sending the request for product %s failed: %w", slug, err)
 	}
@@ -206,7 +123,7 @@ func (m *Marketplace) GetProduct(slug string) (*models.Product, error) {
 	return product, nil
 }
 
-func (m *Marketplace) getVersionDetails(product *models.Product, version string) (*models.VersionSpecificProductDetails, error) {
+func (m *Marketplace) getVersionDetails(ctx context.Context, product *models.Product, version string) (*models.VersionSpecificProductDetails, error) {
 	requestURL := MakeURL(
 		m.GetHost(),
 		fmt.Sprintf("/api/v1/products/%s/version-details", product.ProductId),
@@ -220,7 +137,7 @@ func (m *Marketplace) getVersionDetails(product *models.Product, version string)
 		VersionNumber: version,
 	}
 
-	resp, err := m.Client.PostJSON(requestURL, payload)
+	resp, err := m.cachedRequest(ctx, http.MethodPost, requestURL, payload, product.ProductId, product.Slug)
 	if err != nil {
 		return nil, fmt.Errorf("sending the product version details request for %s %s failed: %w", product.Slug, version, err)
 	}
@@ -251,8 +168,8 @@ func (m *Marketplace) getVersionDetails(product *models.Product, version string)
 	return response.Response.Data, nil
 }
 
-func (m *Marketplace) GetProductWithVersion(slug, version string) (*models.Product, *models.Version, error) {
-	product, err := m.GetProduct(slug)
+func (m *Marketplace) GetProductWithVersion(ctx context.Context, slug, version string) (*models.Product, *models.Version, error) {
+	product, err := m.GetProduct(ctx, slug)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -262,7 +179,7 @@ func (m *Marketplace) GetProductWithVersion(slug, version string) (*models.Produ
 	}
 	versionObject := product.GetVersion(version)
 
-	versionDetails, err := m.getVersionDetails(product, versionObject.Number)
+	versionDetails, err := m.getVersionDetails(ctx, product, versionObject.Number)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -275,7 +192,7 @@ func (m *Marketplace) GetProductWithVersion(slug, version string) (*models.Produ
 	return product, versionObject, nil
 }
 
-func (m *Marketplace) PutProduct(product *models.Product, versionUpdate bool) (*models.Product, error) {
+func (m *Marketplace) PutProduct(ctx context.Context, product *models.Product, versionUpdate bool) (*models.Product, error) {
 	encoded, err := json.Marshal(product)
 	if err != nil {
 		return nil, err
@@ -290,10 +207,11 @@ func (m *Marketplace) PutProduct(product *models.Product, versionUpdate bool) (*
 		},
 	)
 
-	resp, err := m.Client.Put(requestURL, bytes.NewReader(encoded), "application/json")
+	resp, err := m.Client.Put(ctx, requestURL, bytes.NewReader(encoded), "application/json")
 	if err != nil {
 		return nil, fmt.Errorf("sending the update for product \"%s\" failed: %w", product.Slug, err)
 	}
+	m.invalidateCache(product)
 
 	if resp.StatusCode == http.StatusForbidden {
 		return nil, fmt.Errorf("you do not have permission to modify the product \"%s\"", product.Slug)
@@ -0,0 +1,405 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: BSD-2-Clause
+
+package pkg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/schollz/progressbar/v3"
+	"github.com/vmware-labs/marketplace-cli/v2/internal"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/models"
+)
+
+const (
+	DefaultListPageSize    = 20
+	DefaultListConcurrency = 4
+)
+
+// ErrStopListing can be returned from a ProductPageFunc or ProductFunc to stop listing products, without an error
+var ErrStopListing = errors.New("stop listing products")
+
+// ProductPageFunc is called with each page of products, in order
+type ProductPageFunc func(products []*models.Product) error
+
+// ProductFunc is called with each product, in order
+type ProductFunc func(product *models.Product) error
+
+// ListProductsOptions changes which products are listed, and how they are sorted and paged.
+// Any unset field uses the default.
+type ListProductsOptions struct {
+	// AllOrgs lists the published products from every organization, instead of every product from your organization
+	AllOrgs    bool
+	SearchTerm string
+
+	// Sorting defaults to display name, ascending
+	Sorting  *internal.Sorting
+	PageSize int32
+	Filters  internal.Filters
+
+	// MaxResults stops listing once this many products are found. No limit if 0.
+	MaxResults int
+	// Concurrency is how many pages are fetched at the same time, once the first page says how many there are
+	Concurrency int
+
+	// ShowProgress draws a progress bar on the marketplace output while listing
+	ShowProgress bool
+}
+
+func (o *ListProductsOptions) allOrgs() bool {
+	return o != nil && o.AllOrgs
+}
+
+func (o *ListProductsOptions) searchTerm() string {
+	if o == nil {
+		return ""
+	}
+	return o.SearchTerm
+}
+
+func (o *ListProductsOptions) sorting() *internal.Sorting {
+	if o != nil && o.Sorting != nil {
+		return o.Sorting
+	}
+	return &internal.Sorting{
+		Order:     1,
+		Key:       internal.SortKeyDisplayName,
+		Direction: internal.SortDirectionAscending,
+	}
+}
+
+func (o *ListProductsOptions) pageSize() int32 {
+	if o != nil && o.PageSize > 0 {
+		return o.PageSize
+	}
+	return DefaultListPageSize
+}
+
+func (o *ListProductsOptions) maxResults() int {
+	if o != nil && o.MaxResults > 0 {
+		return o.MaxResults
+	}
+	return 0
+}
+
+func (o *ListProductsOptions) concurrency() int {
+	if o != nil && o.Concurrency > 0 {
+		return o.Concurrency
+	}
+	return DefaultListConcurrency
+}
+
+func (o *ListProductsOptions) showProgress() bool {
+	return o != nil && o.ShowProgress
+}
+
+func (o *ListProductsOptions) parameters() []QueryStringParameter {
+	parameters := []QueryStringParameter{o.sorting()}
+	if o != nil && len(o.Filters) > 0 {
+		parameters = append(parameters, o.Filters)
+	}
+	return parameters
+}
+
+// ListProducts returns every product, after fetching all of them
+func (m *Marketplace) ListProducts(ctx context.Context, options *ListProductsOptions) ([]*models.Product, error) {
+	var products []*models.Product
+	err := m.EachProductPage(ctx, options, func(page []*models.Product) error {
+		products = append(products, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+// EachProduct calls fn with each product as soon as its page arrives.
+// Return ErrStopListing from fn to stop early.
+func (m *Marketplace) EachProduct(ctx context.Context, options *ListProductsOptions, fn ProductFunc) error {
+	return m.EachProductPage(ctx, options, func(page []*models.Product) error {
+		for _, product := range page {
+			err := fn(product)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// EachProductPage calls fn with each page of products, in order. Once the first page says how many products there
+// are, the rest of the pages are fetched at the same time, but still passed to fn in order. Products that already
+// appeared on an earlier page are left out, because they can move between pages while the list is being fetched.
+// Return ErrStopListing from fn to stop early.
+func (m *Marketplace) EachProductPage(ctx context.Context, options *ListProductsOptions, fn ProductPageFunc) error {
+	lister := &productLister{
+		marketplace: m,
+		options:     options,
+		fn:          fn,
+		seen:        map[string]bool{},
+	}
+	err := lister.list(ctx)
+	if errors.Is(err, ErrStopListing) {
+		return nil
+	}
+	return err
+}
+
+type productLister struct {
+	marketplace *Marketplace
+	options     *ListProductsOptions
+	fn          ProductPageFunc
+	progressBar *progressbar.ProgressBar
+	seen        map[string]bool
+	found       int
+	total       int
+}
+
+func (l *productLister) list(ctx context.Context) error {
+	pageSize := l.options.pageSize()
+	response, err := l.marketplace.listProductsPage(ctx, l.options, &internal.Pagination{Page: 1, PageSize: pageSize})
+	if err != nil {
+		return err
+	}
+
+	// Return immediately if we get an empty list.
+	// On empty lists, we cannot necessarily trust response.Response.Params.ProductCount
+	// See: https://github.com/vmware-labs/marketplace-cli/issues/62
+	if len(response.Products) == 0 {
+		return nil
+	}
+
+	l.total = response.Params.ProductCount
+	if maxResults := l.options.maxResults(); maxResults > 0 && maxResults < l.total {
+		l.total = maxResults
+	}
+	if l.options.showProgress() {
+		l.progressBar = l.marketplace.makeRequestProgressBar(l.total)
+	}
+	err = l.yield(response.Products)
+	if err != nil {
+		return err
+	}
+
+	// Now that the total is known, fetch the rest of the pages at the same time
+	lastPage := int32((l.total + int(pageSize) - 1) / int(pageSize))
+	err = l.listPages(ctx, 2, lastPage)
+	if err != nil {
+		return err
+	}
+
+	// Keep going if the pages were shorter than expected, until a page without any new products. The server might
+	// return the last page again for pages past the end, or the total might be out of date.
+	for page := lastPage + 1; l.found < l.total; page++ {
+		response, err = l.marketplace.listProductsPage(ctx, l.options, &internal.Pagination{Page: page, PageSize: pageSize})
+		if err != nil {
+			return err
+		}
+		found := l.found
+		err = l.yield(response.Products)
+		if err != nil {
+			return err
+		}
+		if l.found == found {
+			break
+		}
+	}
+	return nil
+}
+
+// yield passes on the products that were not seen before, stopping at the maximum number of results
+func (l *productLister) yield(products []*models.Product) error {
+	if l.progressBar != nil {
+		_ = l.progressBar.Add(len(products))
+	}
+
+	var page []*models.Product
+	for _, product := range products {
+		key := product.ProductId
+		if key == "" {
+			key = product.Slug
+		}
+		if l.seen[key] {
+			continue
+		}
+		l.seen[key] = true
+		page = append(page, product)
+	}
+
+	maxResults := l.options.maxResults()
+	if maxResults > 0 && l.found+len(page) > maxResults {
+		page = page[:maxResults-l.found]
+	}
+	l.found += len(page)
+
+	if len(page) > 0 {
+		err := l.fn(page)
+		if err != nil {
+			return err
+		}
+	}
+	if maxResults > 0 && l.found >= maxResults {
+		return ErrStopListing
+	}
+	return nil
+}
+
+type productPageResult struct {
+	products []*models.Product
+	err      error
+}
+
+// listPages fetches the pages from first to last, with up to options.Concurrency requests at the same time, and
+// yields them in order. Only that many pages are fetched ahead of the one being yielded.
+func (l *productLister) listPages(ctx context.Context, first, last int32) error {
+	if last < first {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	var wait sync.WaitGroup
+	defer wait.Wait()
+	defer cancel()
+
+	results := make([]chan *productPageResult, last-first+1)
+	for index := range results {
+		results[index] = make(chan *productPageResult, 1)
+	}
+
+	workers := l.options.concurrency()
+	if workers > len(results) {
+		workers = len(results)
+	}
+	slots := make(chan struct{}, workers)
+	next := make(chan int)
+	go func() {
+		defer close(next)
+		for index := range results {
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case next <- index:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	for worker := 0; worker < workers; worker++ {
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+			for index := range next {
+				pagination := &internal.Pagination{
+					Page:     first + int32(index),
+					PageSize: l.options.pageSize(),
+				}
+				response, err := l.marketplace.listProductsPage(ctx, l.options, pagination)
+				result := &productPageResult{err: err}
+				if err == nil {
+					result.products = response.Products
+				}
+				results[index] <- result
+			}
+		}()
+	}
+
+	for index := range results {
+		var result *productPageResult
+		select {
+		case result = <-results[index]:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		<-slots
+
+		if result.err != nil {
+			return result.err
+		}
+		err := l.yield(result.products)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListProductFilters returns the ways the list of products can be filtered, as advertised by the first page of it
+func (m *Marketplace) ListProductFilters(ctx context.Context, options *ListProductsOptions) ([]*ProductFacet, error) {
+	pagination := &internal.Pagination{
+		Page:     1,
+		PageSize: options.pageSize(),
+	}
+	response, err := m.listProductsPage(ctx, options, pagination)
+	if err != nil {
+		return nil, err
+	}
+	return ParseProductFacets(response.AvailableFilters), nil
+}
+
+func (m *Marketplace) listProductsPage(ctx context.Context, options *ListProductsOptions, pagination *internal.Pagination) (*ListProductResponsePayload, error) {
+	values := url.Values{
+		"managed": []string{strconv.FormatBool(!options.allOrgs())},
+	}
+	if searchTerm := options.searchTerm(); searchTerm != "" {
+		values.Set("search", searchTerm)
+	}
+
+	requestURL := MakeURL(m.GetHost(), "/api/v1/products", values)
+	ApplyParameters(requestURL, append([]QueryStringParameter{pagination}, options.parameters()...)...)
+	resp, err := m.Client.Get(ctx, requestURL)
+	if err != nil {
+		return nil, fmt.Errorf("sending the request for the list of products failed: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		body, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			body = []byte{}
+		}
+		return nil, fmt.Errorf("getting the list of products failed: (%d) %s: %s", resp.StatusCode, resp.Status, body)
+	}
+
+	response := &ListProductResponse{}
+	err = m.DecodeJson(resp.Body, response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the list of products: %w", err)
+	}
+	if response.Response == nil {
+		return nil, errors.New("failed to parse the list of products: the response is empty")
+	}
+	if response.Response.Params == nil {
+		response.Response.Params = &ListProductResponseParams{}
+	}
+	return response.Response, nil
+}
+
+func (m *Marketplace) makeRequestProgressBar(max int) *progressbar.ProgressBar {
+	progressBar := progressbar.NewOptions(
+		max,
+		progressbar.OptionSetDescription("Getting the list of products"),
+		progressbar.OptionSetWriter(m.Output),
+		progressbar.OptionSetWidth(10),
+		progressbar.OptionThrottle(65*time.Millisecond),
+		progressbar.OptionShowCount(),
+		progressbar.OptionShowIts(),
+		progressbar.OptionSetItsString("products"),
+		progressbar.OptionClearOnFinish(),
+		progressbar.OptionSpinnerType(14),
+		progressbar.OptionFullWidth(),
+	)
+	_ = progressBar.RenderBlank()
+	return progressBar
+}
@@ -5,11 +5,16 @@ package pkg_test
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"strings"
+	"sync"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -70,12 +75,12 @@ var _ = Describe("Product", func() {
 		})
 
 		It("gets the list of products", func() {
-			products, err := marketplace.ListProducts(false, "")
+			products, err := marketplace.ListProducts(context.Background(), nil)
 			Expect(err).ToNot(HaveOccurred())
 
 			By("sending the right request", func() {
 				Expect(httpClient.GetCallCount()).To(Equal(1))
-				url := httpClient.GetArgsForCall(0)
+				_, url := httpClient.GetArgsForCall(0)
 				Expect(url.Path).To(Equal("/api/v1/products"))
 				Expect(url.Query().Get("pagination")).To(Equal("{\"page\":1,\"pageSize\":20}"))
 			})
@@ -87,17 +92,39 @@ var _ = Describe("Product", func() {
 
 		Context("with search term", func() {
 			It("sends the request with the search term", func() {
-				_, err := marketplace.ListProducts(false, "tanzu")
+				_, err := marketplace.ListProducts(context.Background(), &pkg.ListProductsOptions{SearchTerm: "tanzu"})
 				Expect(err).ToNot(HaveOccurred())
 
 				By("including the search term", func() {
 					Expect(httpClient.GetCallCount()).To(Equal(1))
-					url := httpClient.GetArgsForCall(0)
+					_, url := httpClient.GetArgsForCall(0)
 					Expect(url.Query().Get("search")).To(Equal("tanzu"))
 				})
 			})
 		})
 
+		Context("with options", func() {
+			It("sends the sorting, page size and filters", func() {
+				filters := internal.Filters{}
+				filters.Add("categories", "Networking")
+				_, err := marketplace.ListProducts(context.Background(), &pkg.ListProductsOptions{
+					Sorting: &internal.Sorting{
+						Key:       internal.SortKeyUpdateDate,
+						Direction: internal.SortDirectionDescending,
+					},
+					PageSize: 50,
+					Filters:  filters,
+				})
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(httpClient.GetCallCount()).To(Equal(1))
+				_, url := httpClient.GetArgsForCall(0)
+				Expect(url.Query().Get("pagination")).To(Equal("{\"page\":1,\"pageSize\":50}"))
+				Expect(url.Query().Get("sortBy")).To(Equal("{\"key\":\"updatedOn\",\"direction\":\"DESC\"}"))
+				Expect(url.Query().Get("filters")).To(MatchJSON(`{"categories":["Networking"]}`))
+			})
+		})
+
 		Context("Multiple pages of results", func() {
 			BeforeEach(func() {
 				var products []*models.Product
@@ -146,16 +173,16 @@ var _ = Describe("Product", func() {
 			})
 
 			It("returns all results", func() {
-				products, err := marketplace.ListProducts(false, "")
+				products, err := marketplace.ListProducts(context.Background(), nil)
 				Expect(err).ToNot(HaveOccurred())
 
 				By("sending the correct requests", func() {
 					Expect(httpClient.GetCallCount()).To(Equal(2))
-					url := httpClient.GetArgsForCall(0)
+					_, url := httpClient.GetArgsForCall(0)
 					Expect(url.Path).To(Equal("/api/v1/products"))
 					Expect(url.Query().Get("pagination")).To(Equal("{\"page\":1,\"pageSize\":20}"))
 
-					url = httpClient.GetArgsForCall(1)
+					_, url = httpClient.GetArgsForCall(1)
 					Expect(url.Path).To(Equal("/api/v1/products"))
 					Expect(url.Query().Get("pagination")).To(Equal("{\"page\":2,\"pageSize\":20}"))
 				})
@@ -166,13 +193,210 @@ var _ = Describe("Product", func() {
 			})
 		})
 
+		Context("Many pages of results", func() {
+			var (
+				products []*models.Product
+				lock     sync.Mutex
+				inFlight int
+				maxSeen  int
+			)
+
+			BeforeEach(func() {
+				products = nil
+				for i := 0; i < 95; i++ {
+					products = append(products, test.CreateFakeProduct(
+						"",
+						fmt.Sprintf("My Super Product %d", i),
+						fmt.Sprintf("my-super-product-%d", i),
+						models.SolutionTypeImage))
+				}
+				inFlight = 0
+				maxSeen = 0
+
+				httpClient.GetStub = func(ctx context.Context, requestURL *url.URL) (*http.Response, error) {
+					lock.Lock()
+					inFlight++
+					if inFlight > maxSeen {
+						maxSeen = inFlight
+					}
+					lock.Unlock()
+					time.Sleep(5 * time.Millisecond)
+					defer func() {
+						lock.Lock()
+						inFlight--
+						lock.Unlock()
+					}()
+
+					pagination := &internal.Pagination{}
+					Expect(json.Unmarshal([]byte(requestURL.Query().Get("pagination")), pagination)).To(Succeed())
+					start := int((pagination.Page - 1) * pagination.PageSize)
+					end := start + int(pagination.PageSize)
+					if start > len(products) {
+						start = len(products)
+					}
+					if end > len(products) {
+						end = len(products)
+					}
+					page := products[start:end]
+					if pagination.Page == 3 {
+						// The first product on the next page shows up again, as if the list changed while it was fetched
+						page = append([]*models.Product{products[start-1]}, page[:len(page)-1]...)
+					}
+					return MakeJSONResponse(&pkg.ListProductResponse{
+						Response: &pkg.ListProductResponsePayload{
+							Products:   page,
+							StatusCode: http.StatusOK,
+							Params:     &pkg.ListProductResponseParams{ProductCount: len(products)},
+						},
+					}), nil
+				}
+			})
+
+			It("fetches the pages at the same time and returns them in order, without duplicates", func() {
+				result, err := marketplace.ListProducts(context.Background(), &pkg.ListProductsOptions{
+					AllOrgs:     true,
+					PageSize:    10,
+					Concurrency: 3,
+				})
+				Expect(err).ToNot(HaveOccurred())
+
+				By("fetching every page, and then the one after the last to find the product pushed off a page", func() {
+					Expect(httpClient.GetCallCount()).To(Equal(11))
+				})
+
+				By("limiting how many pages are fetched at once", func() {
+					Expect(maxSeen).To(BeNumerically(">", 1))
+					Expect(maxSeen).To(BeNumerically("<=", 3))
+				})
+
+				By("returning every product once, in order", func() {
+					var slugs []string
+					for _, product := range result {
+						slugs = append(slugs, product.Slug)
+					}
+					var expected []string
+					for _, product := range products[:29] {
+						expected = append(expected, product.Slug)
+					}
+					for _, product := range products[30:] {
+						expected = append(expected, product.Slug)
+					}
+					Expect(slugs).To(Equal(expected))
+				})
+			})
+
+			When("the server keeps returning the same page", func() {
+				BeforeEach(func() {
+					httpClient.GetStub = func(ctx context.Context, requestURL *url.URL) (*http.Response, error) {
+						return MakeJSONResponse(&pkg.ListProductResponse{
+							Response: &pkg.ListProductResponsePayload{
+								Products:   products[:10],
+								StatusCode: http.StatusOK,
+								Params:     &pkg.ListProductResponseParams{ProductCount: len(products)},
+							},
+						}), nil
+					}
+				})
+
+				It("stops after a page without new products", func() {
+					result, err := marketplace.ListProducts(context.Background(), &pkg.ListProductsOptions{
+						AllOrgs:  true,
+						PageSize: 10,
+					})
+					Expect(err).ToNot(HaveOccurred())
+					Expect(httpClient.GetCallCount()).To(Equal(11))
+					Expect(result).To(HaveLen(10))
+				})
+			})
+
+			When("there is a maximum number of results", func() {
+				It("stops early", func() {
+					result, err := marketplace.ListProducts(context.Background(), &pkg.ListProductsOptions{
+						AllOrgs:    true,
+						PageSize:   10,
+						MaxResults: 15,
+					})
+					Expect(err).ToNot(HaveOccurred())
+					Expect(httpClient.GetCallCount()).To(Equal(2))
+					Expect(result).To(HaveLen(15))
+					Expect(result[14].Slug).To(Equal("my-super-product-14"))
+				})
+			})
+
+			Describe("EachProductPage", func() {
+				It("passes each page in order, and stays quiet", func() {
+					var pages [][]*models.Product
+					err := marketplace.EachProductPage(context.Background(), &pkg.ListProductsOptions{PageSize: 10}, func(page []*models.Product) error {
+						pages = append(pages, page)
+						return nil
+					})
+					Expect(err).ToNot(HaveOccurred())
+					Expect(pages).To(HaveLen(10))
+					Expect(pages[0][0].Slug).To(Equal("my-super-product-0"))
+					Expect(pages[9][4].Slug).To(Equal("my-super-product-94"))
+					Expect(stderr.Contents()).To(BeEmpty())
+				})
+
+				When("showing progress", func() {
+					It("draws a progress bar", func() {
+						_, err := marketplace.ListProducts(context.Background(), &pkg.ListProductsOptions{PageSize: 10, ShowProgress: true})
+						Expect(err).ToNot(HaveOccurred())
+						Expect(stderr.Contents()).ToNot(BeEmpty())
+					})
+				})
+			})
+
+			Describe("EachProduct", func() {
+				It("stops when asked to", func() {
+					var slugs []string
+					err := marketplace.EachProduct(context.Background(), &pkg.ListProductsOptions{PageSize: 10, Concurrency: 2}, func(product *models.Product) error {
+						slugs = append(slugs, product.Slug)
+						if len(slugs) == 45 {
+							return pkg.ErrStopListing
+						}
+						return nil
+					})
+					Expect(err).ToNot(HaveOccurred())
+					Expect(slugs).To(HaveLen(45))
+
+					By("only fetching a few pages ahead", func() {
+						Expect(httpClient.GetCallCount()).To(BeNumerically("<=", 7))
+					})
+				})
+
+				It("returns other errors", func() {
+					err := marketplace.EachProduct(context.Background(), &pkg.ListProductsOptions{PageSize: 10}, func(product *models.Product) error {
+						return errors.New("something went wrong")
+					})
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(Equal("something went wrong"))
+				})
+			})
+
+			When("a page fails", func() {
+				It("returns the error", func() {
+					getPage := httpClient.GetStub
+					httpClient.GetStub = func(ctx context.Context, requestURL *url.URL) (*http.Response, error) {
+						if strings.Contains(requestURL.Query().Get("pagination"), `"page":4`) {
+							return nil, errors.New("request failed")
+						}
+						return getPage(ctx, requestURL)
+					}
+
+					_, err := marketplace.ListProducts(context.Background(), &pkg.ListProductsOptions{AllOrgs: true, PageSize: 10})
+					Expect(err).To(HaveOccurred())
+					Expect(err.Error()).To(Equal("sending the request for the list of products failed: request failed"))
+				})
+			})
+		})
+
 		Context("Error fetching products", func() {
 			BeforeEach(func() {
 				httpClient.GetReturns(nil, errors.New("request failed"))
 			})
 
 			It("prints the error", func() {
-				_, err := marketplace.ListProducts(false, "")
+				_, err := marketplace.ListProducts(context.Background(), nil)
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(Equal("sending the request for the list of products failed: request failed"))
 			})
@@ -188,7 +412,7 @@ var _ = Describe("Product", func() {
 			})
 
 			It("prints the error", func() {
-				_, err := marketplace.ListProducts(false, "")
+				_, err := marketplace.ListProducts(context.Background(), nil)
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(Equal("getting the list of products failed: (418) I'm a teapot: Teapots!"))
 			})
@@ -200,13 +424,42 @@ var _ = Describe("Product", func() {
 			})
 
 			It("prints the error", func() {
-				_, err := marketplace.ListProducts(false, "")
+				_, err := marketplace.ListProducts(context.Background(), nil)
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(Equal("failed to parse the list of products: invalid character 'T' looking for beginning of value"))
 			})
 		})
 	})
 
+	Describe("ListProductFilters", func() {
+		It("returns the filters advertised with the list of products", func() {
+			response := &pkg.ListProductResponse{
+				Response: &pkg.ListProductResponsePayload{
+					Products: []*models.Product{
+						test.CreateFakeProduct("", "My Super Product", "my-super-product", models.SolutionTypeImage),
+					},
+					AvailableFilters: map[string]interface{}{
+						"categories": []string{"Networking", "Security"},
+					},
+					Params:     &pkg.ListProductResponseParams{ProductCount: 1},
+					StatusCode: http.StatusOK,
+				},
+			}
+			httpClient.GetReturns(MakeJSONResponse(response), nil)
+
+			facets, err := marketplace.ListProductFilters(context.Background(), &pkg.ListProductsOptions{AllOrgs: true, SearchTerm: "tanzu"})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(facets).To(Equal([]*pkg.ProductFacet{
+				{Key: "categories", Values: []*pkg.ProductFacetValue{{Value: "Networking"}, {Value: "Security"}}},
+			}))
+
+			Expect(httpClient.GetCallCount()).To(Equal(1))
+			_, url := httpClient.GetArgsForCall(0)
+			Expect(url.Query().Get("managed")).To(Equal("false"))
+			Expect(url.Query().Get("search")).To(Equal("tanzu"))
+		})
+	})
+
 	Describe("GetProduct", func() {
 		BeforeEach(func() {
 			product := test.CreateFakeProduct(
@@ -227,12 +480,12 @@ var _ = Describe("Product", func() {
 		})
 
 		It("gets the product", func() {
-			product, err := marketplace.GetProduct("my-super-product")
+			product, err := marketplace.GetProduct(context.Background(), "my-super-product")
 			Expect(err).ToNot(HaveOccurred())
 
 			By("sending the correct request", func() {
 				Expect(httpClient.GetCallCount()).To(Equal(1))
-				url := httpClient.GetArgsForCall(0)
+				_, url := httpClient.GetArgsForCall(0)
 				Expect(url.Path).To(Equal("/api/v1/products/my-super-product"))
 				Expect(url.Query().Get("increaseViewCount")).To(Equal("false"))
 				Expect(url.Query().Get("isSlug")).To(Equal("true"))
@@ -249,7 +502,7 @@ var _ = Describe("Product", func() {
 			})
 
 			It("says there are no products", func() {
-				_, err := marketplace.GetProduct("my-super-product")
+				_, err := marketplace.GetProduct(context.Background(), "my-super-product")
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(Equal("product my-super-product not found"))
 			})
@@ -261,7 +514,7 @@ var _ = Describe("Product", func() {
 			})
 
 			It("prints the error", func() {
-				_, err := marketplace.GetProduct("my-super-product")
+				_, err := marketplace.GetProduct(context.Background(), "my-super-product")
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(Equal("sending the request for product my-super-product failed: request failed"))
 			})
@@ -277,7 +530,7 @@ var _ = Describe("Product", func() {
 			})
 
 			It("prints the error", func() {
-				_, err := marketplace.GetProduct("my-super-product")
+				_, err := marketplace.GetProduct(context.Background(), "my-super-product")
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(Equal("getting product my-super-product failed: (418)\nTeapots all the way down"))
 			})
@@ -289,7 +542,7 @@ var _ = Describe("Product", func() {
 			})
 
 			It("prints the error", func() {
-				_, err := marketplace.GetProduct("my-super-product")
+				_, err := marketplace.GetProduct(context.Background(), "my-super-product")
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(Equal("failed to parse the response for product my-super-product: invalid character 'T' looking for beginning of value"))
 			})
@@ -337,7 +590,7 @@ var _ = Describe("Product", func() {
 		})
 
 		It("returns the product with version specific details", func() {
-			product, version, err := marketplace.GetProductWithVersion("my-super-product", "0.1.2")
+			product, version, err := marketplace.GetProductWithVersion(context.Background(), "my-super-product", "0.1.2")
 			Expect(err).ToNot(HaveOccurred())
 
 			Expect(product.Slug).To(Equal("my-super-product"))
@@ -345,13 +598,13 @@ var _ = Describe("Product", func() {
 
 			By("sending the correct requests", func() {
 				Expect(httpClient.GetCallCount()).To(Equal(1))
-				url := httpClient.GetArgsForCall(0)
+				_, url := httpClient.GetArgsForCall(0)
 				Expect(url.Path).To(Equal("/api/v1/products/my-super-product"))
 				Expect(url.Query().Get("increaseViewCount")).To(Equal("false"))
 				Expect(url.Query().Get("isSlug")).To(Equal("true"))
 
 				Expect(httpClient.PostJSONCallCount()).To(Equal(1))
-				url, content := httpClient.PostJSONArgsForCall(0)
+				_, url, content := httpClient.PostJSONArgsForCall(0)
 				Expect(url.Path).To(Equal(fmt.Sprintf("/api/v1/products/%s/version-details", productId)))
 				Expect(url.Query().Get("versionNumber")).To(Equal("0.1.2"))
 				payload := content.(*pkg.VersionSpecificDetailsRequestPayload)
@@ -375,7 +628,7 @@ var _ = Describe("Product", func() {
 				}, nil)
 			})
 			It("returns the error", func() {
-				_, _, err := marketplace.GetProductWithVersion("my-super-product", "0.1.2")
+				_, _, err := marketplace.GetProductWithVersion(context.Background(), "my-super-product", "0.1.2")
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(Equal("getting product my-super-product failed: (418)\nget product failed"))
 			})
@@ -383,7 +636,7 @@ var _ = Describe("Product", func() {
 
 		Context("the product does not have that version", func() {
 			It("returns a version does not exist error", func() {
-				product, _, err := marketplace.GetProductWithVersion("my-super-product", "9.9.9")
+				product, _, err := marketplace.GetProductWithVersion(context.Background(), "my-super-product", "9.9.9")
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(Equal("product \"my-super-product\" does not have version 9.9.9"))
 
@@ -401,7 +654,7 @@ var _ = Describe("Product", func() {
 				}, nil)
 			})
 			It("returns an error", func() {
-				_, _, err := marketplace.GetProductWithVersion("my-super-product", "0.1.2")
+				_, _, err := marketplace.GetProductWithVersion(context.Background(), "my-super-product", "0.1.2")
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(Equal("getting product version details for my-super-product 0.1.2 failed: (418)\nget version specific details failed"))
 			})
@@ -414,7 +667,7 @@ var _ = Describe("Product", func() {
 				}, nil)
 			})
 			It("returns an error", func() {
-				_, _, err := marketplace.GetProductWithVersion("my-super-product", "0.1.2")
+				_, _, err := marketplace.GetProductWithVersion(context.Background(), "my-super-product", "0.1.2")
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(Equal("product version details for my-super-product 0.1.2 not found"))
 			})
@@ -436,7 +689,7 @@ var _ = Describe("Product", func() {
 			})
 
 			It("returns an error", func() {
-				_, _, err := marketplace.GetProductWithVersion("my-super-product", "0.1.2")
+				_, _, err := marketplace.GetProductWithVersion(context.Background(), "my-super-product", "0.1.2")
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(Equal("failed to parse the response for product my-super-product 0.1.2: bad response body"))
 			})
@@ -447,7 +700,7 @@ var _ = Describe("Product", func() {
 			})
 
 			It("returns an error", func() {
-				_, _, err := marketplace.GetProductWithVersion("my-super-product", "0.1.2")
+				_, _, err := marketplace.GetProductWithVersion(context.Background(), "my-super-product", "0.1.2")
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(ContainSubstring("failed to parse the response for product my-super-product 0.1.2:"))
 			})
@@ -0,0 +1,168 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: BSD-2-Clause
+
+package pkg
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const RedactedValue = "[redacted]"
+
+// sensitiveHeaders carry credentials, and are never logged
+var sensitiveHeaders = map[string]bool{
+	"authorization":        true,
+	"cookie":               true,
+	"csp-auth-token":       true,
+	"proxy-authorization":  true,
+	"set-cookie":           true,
+	"x-amz-security-token": true,
+}
+
+// sensitiveFields are query string parameters and payload fields that carry credentials or signatures
+var sensitiveFields = map[string]bool{
+	"access_token":         true,
+	"api_token":            true,
+	"client_secret":        true,
+	"code":                 true,
+	"code_verifier":        true,
+	"id_token":             true,
+	"password":             true,
+	"refresh_token":        true,
+	"signature":            true,
+	"x-amz-credential":     true,
+	"x-amz-security-token": true,
+	"x-amz-signature":      true,
+}
+
+// RedactURL returns the URL as a string, without any signatures or tokens in the query string
+func RedactURL(u *url.URL) string {
+	if u.RawQuery == "" {
+		return u.String()
+	}
+
+	redacted := *u
+	query := u.Query()
+	for key := range query {
+		if sensitiveFields[strings.ToLower(key)] {
+			query.Set(key, RedactedValue)
+		}
+	}
+	redacted.RawQuery = query.Encode()
+	return redacted.String()
+}
+
+// RedactError returns the error message, without any signatures or tokens in the URL of a failed request
+func RedactError(err error) string {
+	var urlError *url.Error
+	if !errors.As(err, &urlError) {
+		return err.Error()
+	}
+	requestURL, parseErr := url.Parse(urlError.URL)
+	if parseErr != nil {
+		return err.Error()
+	}
+	return strings.Replace(err.Error(), urlError.URL, RedactURL(requestURL), 1)
+}
+
+// RedactHeaders returns a copy of the headers, without any credentials
+func RedactHeaders(headers http.Header) http.Header {
+	redacted := headers.Clone()
+	for key := range redacted {
+		if sensitiveHeaders[strings.ToLower(key)] {
+			redacted[key] = []string{RedactedValue}
+		}
+	}
+	return redacted
+}
+
+// RedactPayload returns the payload without any credentials, if it is a form or JSON object.
+// Other payloads are returned as they are.
+func RedactPayload(contentType string, payload []byte) []byte {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case mediaType == "application/x-www-form-urlencoded":
+		values, err := url.ParseQuery(string(payload))
+		if err != nil {
+			return payload
+		}
+		for key := range values {
+			if sensitiveFields[strings.ToLower(key)] {
+				values.Set(key, RedactedValue)
+			}
+		}
+		return []byte(values.Encode())
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		var object interface{}
+		decoder := json.NewDecoder(bytes.NewReader(payload))
+		decoder.UseNumber()
+		if decoder.Decode(&object) != nil {
+			return payload
+		}
+		object, redacted := redactJSON(object)
+		if !redacted {
+			return payload
+		}
+		encoded := &bytes.Buffer{}
+		encoder := json.NewEncoder(encoded)
+		encoder.SetEscapeHTML(false)
+		if encoder.Encode(object) != nil {
+			return payload
+		}
+		return bytes.TrimSuffix(encoded.Bytes(), []byte("\n"))
+	}
+	return payload
+}
+
+// redactJSON removes credentials from every level of a decoded JSON value. Fields with sensitive names are replaced,
+// and so are the signatures in URLs, like presigned download URLs.
+func redactJSON(value interface{}) (interface{}, bool) {
+	redacted := false
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		for key, field := range typed {
+			if sensitiveFields[strings.ToLower(key)] {
+				typed[key] = RedactedValue
+				redacted = true
+				continue
+			}
+			if field, changed := redactJSON(field); changed {
+				typed[key] = field
+				redacted = true
+			}
+		}
+	case []interface{}:
+		for index, item := range typed {
+			if item, changed := redactJSON(item); changed {
+				typed[index] = item
+				redacted = true
+			}
+		}
+	case string:
+		return redactURLString(typed)
+	}
+	return value, redacted
+}
+
+// redactURLString redacts the value if it is a URL with credentials or signatures in its query string
+func redactURLString(value string) (string, bool) {
+	if !strings.Contains(value, "?") {
+		return value, false
+	}
+	u, err := url.Parse(value)
+	if err != nil || u.Scheme == "" || u.RawQuery == "" {
+		return value, false
+	}
+	for key := range u.Query() {
+		if sensitiveFields[strings.ToLower(key)] {
+			return RedactURL(u), true
+		}
+	}
+	return value, false
+}
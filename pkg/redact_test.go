@@ -0,0 +1,105 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: BSD-2-Clause
+
+package pkg_test
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/vmware-labs/marketplace-cli/v2/pkg"
+)
+
+var _ = Describe("Redacting", func() {
+	Describe("RedactURL", func() {
+		It("removes signatures from the query string", func() {
+			presignedURL, err := url.Parse("https://bucket.s3.amazonaws.com/file.txt?X-Amz-Credential=AKIA&X-Amz-Signature=abc123&response-content-type=text%2Fplain")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(pkg.RedactURL(presignedURL)).To(Equal("https://bucket.s3.amazonaws.com/file.txt?X-Amz-Credential=%5Bredacted%5D&X-Amz-Signature=%5Bredacted%5D&response-content-type=text%2Fplain"))
+		})
+
+		It("leaves URLs without a query string alone", func() {
+			plainURL, err := url.Parse("https://marketplace.example.com/api/v1/products")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(pkg.RedactURL(plainURL)).To(Equal("https://marketplace.example.com/api/v1/products"))
+		})
+	})
+
+	Describe("RedactHeaders", func() {
+		It("removes credentials", func() {
+			headers := http.Header{
+				"Accept":         []string{"application/json"},
+				"Csp-Auth-Token": []string{"my-access-token"},
+				"Authorization":  []string{"Bearer my-access-token"},
+			}
+			redacted := pkg.RedactHeaders(headers)
+			Expect(redacted.Get("Accept")).To(Equal("application/json"))
+			Expect(redacted.Get("csp-auth-token")).To(Equal(pkg.RedactedValue))
+			Expect(redacted.Get("Authorization")).To(Equal(pkg.RedactedValue))
+
+			By("not changing the original headers", func() {
+				Expect(headers.Get("csp-auth-token")).To(Equal("my-access-token"))
+			})
+		})
+	})
+
+	Describe("RedactPayload", func() {
+		It("removes credentials from forms", func() {
+			payload := pkg.RedactPayload("application/x-www-form-urlencoded", []byte("grant_type=refresh_token&refresh_token=my-api-token"))
+			Expect(string(payload)).To(Equal("grant_type=refresh_token&refresh_token=%5Bredacted%5D"))
+		})
+
+		It("removes credentials from JSON objects", func() {
+			payload := pkg.RedactPayload("application/json; charset=utf-8", []byte(`{"access_token":"my-access-token","expires_in":1799}`))
+			Expect(payload).To(MatchJSON(`{"access_token":"[redacted]","expires_in":1799}`))
+		})
+
+		It("removes signatures from presigned URLs nested in JSON", func() {
+			payload := pkg.RedactPayload("application/json", []byte(`{
+				"response": {
+					"presignedurl": "https://bucket.s3.amazonaws.example/my-file.ova?X-Amz-Algorithm=AWS4-HMAC-SHA256&X-Amz-Credential=my-credential&X-Amz-Signature=my-signature",
+					"message": "Download link generated",
+					"statuscode": 200
+				}
+			}`))
+			Expect(string(payload)).ToNot(ContainSubstring("my-credential"))
+			Expect(string(payload)).ToNot(ContainSubstring("my-signature"))
+			Expect(payload).To(MatchJSON(`{
+				"response": {
+					"presignedurl": "https://bucket.s3.amazonaws.example/my-file.ova?X-Amz-Algorithm=AWS4-HMAC-SHA256&X-Amz-Credential=%5Bredacted%5D&X-Amz-Signature=%5Bredacted%5D",
+					"message": "Download link generated",
+					"statuscode": 200
+				}
+			}`))
+		})
+
+		It("removes credentials from objects in arrays", func() {
+			payload := pkg.RedactPayload("application/json", []byte(`[{"tokens":[{"refresh_token":"my-refresh-token","created":1655000000123}]}]`))
+			Expect(payload).To(MatchJSON(`[{"tokens":[{"refresh_token":"[redacted]","created":1655000000123}]}]`))
+		})
+
+		It("leaves other payloads alone", func() {
+			payload := pkg.RedactPayload("application/json", []byte(`{"displayname":"My product","logourl":"https://example.com/logo.png?size=large"}`))
+			Expect(string(payload)).To(Equal(`{"displayname":"My product","logourl":"https://example.com/logo.png?size=large"}`))
+
+			payload = pkg.RedactPayload("application/octet-stream", []byte("access_token=abc"))
+			Expect(string(payload)).To(Equal("access_token=abc"))
+		})
+	})
+
+	Describe("RedactError", func() {
+		It("removes signatures from the URL of a failed request", func() {
+			err := &url.Error{
+				Op:  "Get",
+				URL: "https://bucket.s3.amazonaws.com/file.txt?X-Amz-Signature=abc123",
+				Err: errors.New("connection reset by peer"),
+			}
+			Expect(pkg.RedactError(err)).To(Equal(`Get "https://bucket.s3.amazonaws.com/file.txt?X-Amz-Signature=%5Bredacted%5D": connection reset by peer`))
+		})
+	})
+})
@@ -0,0 +1,330 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: BSD-2-Clause
+
+package pkg
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/vmware-labs/marketplace-cli/v2/internal/models"
+)
+
+// ResponseCache keeps the responses of product reads on disk, so that commands run one after another do not fetch
+// the same product again. Entries are reused until they are older than the TTL, then revalidated with the ETag or
+// Last-Modified of the cached response, if the gateway provided them.
+type ResponseCache struct {
+	Dir string
+	TTL time.Duration
+
+	// Identity is who the responses are for, so that responses are never shared between users or organizations
+	Identity func() string
+	Now      func() time.Time
+}
+
+type CachedResponse struct {
+	Method       string    `json:"method"`
+	URL          string    `json:"url"`
+	Products     []string  `json:"products"`
+	StoredAt     time.Time `json:"stored_at"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	ContentType  string    `json:"content_type,omitempty"`
+	Body         []byte    `json:"body"`
+}
+
+func NewResponseCache(dir string, ttl time.Duration, identity func() string) *ResponseCache {
+	return &ResponseCache{
+		Dir:      dir,
+		TTL:      ttl,
+		Identity: identity,
+		Now:      time.Now,
+	}
+}
+
+// Key identifies a response by the request method, host, path, query, payload and the identity it was fetched for
+func (c *ResponseCache) Key(method string, requestURL *url.URL, content []byte) string {
+	identity := ""
+	if c.Identity != nil {
+		identity = c.Identity()
+	}
+
+	hash := sha256.New()
+	for _, part := range []string{method, requestURL.Host, requestURL.Path, requestURL.Query().Encode(), identity} {
+		_, _ = hash.Write([]byte(part))
+		_, _ = hash.Write([]byte{0})
+	}
+	_, _ = hash.Write(content)
+	return fmt.Sprintf("%x", hash.Sum(nil))
+}
+
+// Get returns the cached response, if there is one, and whether it is still fresh enough to use without revalidating
+func (c *ResponseCache) Get(key string) (*CachedResponse, bool) {
+	if c.Dir == "" {
+		return nil, false
+	}
+
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+
+	entry := &CachedResponse{}
+	err = json.Unmarshal(data, entry)
+	if err != nil {
+		// A corrupt entry is simply fetched again
+		return nil, false
+	}
+	return entry, c.Now().Before(entry.StoredAt.Add(c.TTL))
+}
+
+func (c *ResponseCache) Set(key string, entry *CachedResponse) error {
+	if c.Dir == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode the cached response: %w", err)
+	}
+
+	err = os.MkdirAll(c.Dir, 0700)
+	if err != nil {
+		return fmt.Errorf("failed to create the response cache directory: %w", err)
+	}
+
+	err = os.WriteFile(c.path(key), data, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to write the cached response: %w", err)
+	}
+	return nil
+}
+
+// Invalidate removes every cached response for the given products, by ID or slug
+func (c *ResponseCache) Invalidate(products ...string) error {
+	paths, err := c.entries()
+	if err != nil {
+		return err
+	}
+
+	for _, path := range paths {
+		entry := &CachedResponse{}
+		data, err := os.ReadFile(path)
+		if err == nil {
+			err = json.Unmarshal(data, entry)
+		}
+		if err == nil && !entry.isFor(products) {
+			continue
+		}
+
+		// Entries that cannot be read are removed too, rather than risking a stale product
+		err = os.Remove(path)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to remove the cached response: %w", err)
+		}
+	}
+	return nil
+}
+
+// Clear removes every cached response. Other files in the cache directory are left alone, because the directory can
+// be shared with other programs.
+func (c *ResponseCache) Clear() error {
+	paths, err := c.entries()
+	if err != nil {
+		return err
+	}
+
+	for _, path := range paths {
+		err = os.Remove(path)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("failed to remove the cached response: %w", err)
+		}
+	}
+
+	// Only removes the directory if nothing else is in it
+	_ = os.Remove(c.Dir)
+	return nil
+}
+
+// entries are the paths of the cached responses, which are named after their key
+func (c *ResponseCache) entries() ([]string, error) {
+	if c.Dir == "" {
+		return nil, nil
+	}
+
+	files, err := os.ReadDir(c.Dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to read the response cache: %w", err)
+	}
+
+	var paths []string
+	for _, file := range files {
+		key := strings.TrimSuffix(file.Name(), ".json")
+		if !file.IsDir() && key != file.Name() && isCacheKey(key) {
+			paths = append(paths, filepath.Join(c.Dir, file.Name()))
+		}
+	}
+	return paths, nil
+}
+
+// isCacheKey is true for the hex encoded SHA-256 hashes made by Key
+func isCacheKey(name string) bool {
+	if len(name) != sha256.Size*2 {
+		return false
+	}
+	_, err := hex.DecodeString(name)
+	return err == nil
+}
+
+func (c *ResponseCache) path(key string) string {
+	return filepath.Join(c.Dir, key+".json")
+}
+
+func (r *CachedResponse) isFor(products []string) bool {
+	for _, product := range products {
+		if product == "" {
+			continue
+		}
+		for _, cached := range r.Products {
+			if cached == product {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Response makes a new response from the cached one
+func (r *CachedResponse) Response() *http.Response {
+	header := http.Header{}
+	if r.ContentType != "" {
+		header.Set("Content-Type", r.ContentType)
+	}
+	if r.ETag != "" {
+		header.Set("ETag", r.ETag)
+	}
+	if r.LastModified != "" {
+		header.Set("Last-Modified", r.LastModified)
+	}
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Header:        header,
+		ContentLength: int64(len(r.Body)),
+		Body:          io.NopCloser(bytes.NewReader(r.Body)),
+	}
+}
+
+type freshReadsKey struct{}
+
+// WithFreshReads marks a context for reading products that are about to be changed. Cached responses are always
+// revalidated with the gateway, so that an edit made elsewhere is not overwritten with a stale copy of the product.
+func WithFreshReads(ctx context.Context) context.Context {
+	return context.WithValue(ctx, freshReadsKey{}, true)
+}
+
+func wantsFreshReads(ctx context.Context) bool {
+	fresh, _ := ctx.Value(freshReadsKey{}).(bool)
+	return fresh
+}
+
+// cachedRequest sends a request for one or more products, using the response cache if it is enabled.
+// If payload is set, it is sent as a JSON body.
+func (m *Marketplace) cachedRequest(ctx context.Context, method string, requestURL *url.URL, payload interface{}, products ...string) (*http.Response, error) {
+	if m.Cache == nil {
+		if payload != nil {
+			return m.Client.PostJSON(ctx, requestURL, payload)
+		}
+		return m.Client.Get(ctx, requestURL)
+	}
+
+	headers := map[string]string{}
+	var content []byte
+	var body io.Reader
+	if payload != nil {
+		var err error
+		content, err = json.Marshal(payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode request payload: %w", err)
+		}
+		headers["Content-Type"] = "application/json"
+		body = bytes.NewReader(content)
+	}
+
+	key := m.Cache.Key(method, requestURL, content)
+	entry, fresh := m.Cache.Get(key)
+	if fresh && !wantsFreshReads(ctx) {
+		return entry.Response(), nil
+	}
+	if entry != nil {
+		if entry.ETag != "" {
+			headers["If-None-Match"] = entry.ETag
+		}
+		if entry.LastModified != "" {
+			headers["If-Modified-Since"] = entry.LastModified
+		}
+	}
+
+	resp, err := m.Client.SendRequest(ctx, method, requestURL, headers, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotModified && entry != nil {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+		entry.StoredAt = m.Cache.Now()
+		// Failing to cache the response only means the next command has to fetch it again
+		_ = m.Cache.Set(key, entry)
+		return entry.Response(), nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return resp, nil
+	}
+
+	content, err = io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the response for %s: %w", requestURL.Path, err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(content))
+	resp.ContentLength = int64(len(content))
+
+	_ = m.Cache.Set(key, &CachedResponse{
+		Method:       method,
+		URL:          RedactURL(requestURL),
+		Products:     products,
+		StoredAt:     m.Cache.Now(),
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		ContentType:  resp.Header.Get("Content-Type"),
+		Body:         content,
+	})
+	return resp, nil
+}
+
+// invalidateCache removes the cached responses for a product after it was changed
+func (m *Marketplace) invalidateCache(product *models.Product) {
+	if m.Cache == nil {
+		return
+	}
+	err := m.Cache.Invalidate(product.ProductId, product.Slug)
+	if err != nil && m.Output != nil {
+		_, _ = fmt.Fprintf(m.Output, "Warning: %s\n", err.Error())
+	}
+}
@@ -0,0 +1,234 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: BSD-2-Clause
+
+package pkg_test
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gbytes"
+	"github.com/vmware-labs/marketplace-cli/v2/internal/models"
+	"github.com/vmware-labs/marketplace-cli/v2/pkg"
+	"github.com/vmware-labs/marketplace-cli/v2/pkg/pkgfakes"
+	"github.com/vmware-labs/marketplace-cli/v2/test"
+)
+
+var _ = Describe("ResponseCache", func() {
+	var (
+		dir         string
+		now         time.Time
+		identity    string
+		product     *models.Product
+		httpClient  *pkgfakes.FakeHTTPClient
+		cache       *pkg.ResponseCache
+		marketplace *pkg.Marketplace
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "mkpcli-response-cache-test")
+		Expect(err).ToNot(HaveOccurred())
+
+		now = time.Date(2022, time.June, 1, 12, 0, 0, 0, time.UTC)
+		identity = "my-org-id/someone@example.com"
+		cache = pkg.NewResponseCache(dir, 5*time.Minute, func() string { return identity })
+		cache.Now = func() time.Time { return now }
+
+		product = test.CreateFakeProduct("", "My Super Product", "my-super-product", models.SolutionTypeChart)
+		test.AddVerions(product, "1.2.3")
+
+		httpClient = &pkgfakes.FakeHTTPClient{}
+		httpClient.SendRequestStub = func(ctx context.Context, method string, requestURL *url.URL, headers map[string]string, content io.Reader) (*http.Response, error) {
+			if headers["If-None-Match"] == `"version-1"` {
+				return &http.Response{
+					StatusCode: http.StatusNotModified,
+					Body:       ioutil.NopCloser(strings.NewReader("")),
+				}, nil
+			}
+			response := MakeJSONResponse(&pkg.GetProductResponse{
+				Response: &pkg.GetProductResponsePayload{
+					StatusCode: http.StatusOK,
+					Data:       product,
+				},
+			})
+			response.Header = http.Header{"Etag": []string{`"version-1"`}}
+			return response, nil
+		}
+		httpClient.PutStub = PutProductEchoResponse
+
+		marketplace = &pkg.Marketplace{
+			Client: httpClient,
+			Output: NewBuffer(),
+			Cache:  cache,
+		}
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	It("reuses the cached product", func() {
+		first, err := marketplace.GetProduct(context.Background(), "my-super-product")
+		Expect(err).ToNot(HaveOccurred())
+		second, err := marketplace.GetProduct(context.Background(), "my-super-product")
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(second).To(Equal(first))
+		Expect(httpClient.SendRequestCallCount()).To(Equal(1))
+		Expect(httpClient.GetCallCount()).To(Equal(0))
+	})
+
+	When("the cached product is older than the TTL", func() {
+		It("revalidates it with the ETag", func() {
+			_, err := marketplace.GetProduct(context.Background(), "my-super-product")
+			Expect(err).ToNot(HaveOccurred())
+
+			now = now.Add(10 * time.Minute)
+			cached, err := marketplace.GetProduct(context.Background(), "my-super-product")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cached.Slug).To(Equal("my-super-product"))
+
+			Expect(httpClient.SendRequestCallCount()).To(Equal(2))
+			_, method, _, headers, _ := httpClient.SendRequestArgsForCall(1)
+			Expect(method).To(Equal("GET"))
+			Expect(headers).To(HaveKeyWithValue("If-None-Match", `"version-1"`))
+
+			By("keeping it for another TTL", func() {
+				_, err := marketplace.GetProduct(context.Background(), "my-super-product")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(httpClient.SendRequestCallCount()).To(Equal(2))
+			})
+		})
+	})
+
+	When("the product is read in order to change it", func() {
+		It("revalidates the cached product, even if it is fresh", func() {
+			_, err := marketplace.GetProduct(context.Background(), "my-super-product")
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = marketplace.GetProduct(pkg.WithFreshReads(context.Background()), "my-super-product")
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(httpClient.SendRequestCallCount()).To(Equal(2))
+			_, _, _, headers, _ := httpClient.SendRequestArgsForCall(1)
+			Expect(headers).To(HaveKeyWithValue("If-None-Match", `"version-1"`))
+		})
+	})
+
+	When("someone else gets the product", func() {
+		It("does not use the other identity's cached product", func() {
+			_, err := marketplace.GetProduct(context.Background(), "my-super-product")
+			Expect(err).ToNot(HaveOccurred())
+
+			identity = "other-org-id/someone-else@example.com"
+			_, err = marketplace.GetProduct(context.Background(), "my-super-product")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(httpClient.SendRequestCallCount()).To(Equal(2))
+		})
+	})
+
+	It("caches the version details", func() {
+		_, _, err := marketplace.GetProductWithVersion(context.Background(), "my-super-product", "1.2.3")
+		Expect(err).ToNot(HaveOccurred())
+		_, _, err = marketplace.GetProductWithVersion(context.Background(), "my-super-product", "1.2.3")
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(httpClient.SendRequestCallCount()).To(Equal(2))
+		_, method, requestURL, headers, _ := httpClient.SendRequestArgsForCall(1)
+		Expect(method).To(Equal("POST"))
+		Expect(requestURL.Path).To(Equal("/api/v1/products/" + product.ProductId + "/version-details"))
+		Expect(headers).To(HaveKeyWithValue("Content-Type", "application/json"))
+		Expect(httpClient.PostJSONCallCount()).To(Equal(0))
+	})
+
+	When("the product is updated", func() {
+		It("removes the cached responses for that product", func() {
+			_, _, err := marketplace.GetProductWithVersion(context.Background(), "my-super-product", "1.2.3")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(httpClient.SendRequestCallCount()).To(Equal(2))
+
+			_, err = marketplace.PutProduct(context.Background(), product, false)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, _, err = marketplace.GetProductWithVersion(context.Background(), "my-super-product", "1.2.3")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(httpClient.SendRequestCallCount()).To(Equal(4))
+			_, _, _, headers, _ := httpClient.SendRequestArgsForCall(2)
+			Expect(headers).ToNot(HaveKey("If-None-Match"))
+		})
+
+		It("keeps the cached responses for other products", func() {
+			_, err := marketplace.GetProduct(context.Background(), "my-super-product")
+			Expect(err).ToNot(HaveOccurred())
+
+			other := test.CreateFakeProduct("", "My Other Product", "my-other-product", models.SolutionTypeChart)
+			_, err = marketplace.PutProduct(context.Background(), other, false)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = marketplace.GetProduct(context.Background(), "my-super-product")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(httpClient.SendRequestCallCount()).To(Equal(1))
+		})
+	})
+
+	When("the request fails", func() {
+		It("does not cache the response", func() {
+			httpClient.SendRequestStub = nil
+			httpClient.SendRequestReturns(&http.Response{
+				StatusCode: http.StatusInternalServerError,
+				Body:       ioutil.NopCloser(strings.NewReader("something went wrong")),
+			}, nil)
+
+			_, err := marketplace.GetProduct(context.Background(), "my-super-product")
+			Expect(err).To(HaveOccurred())
+
+			files, err := os.ReadDir(dir)
+			if err == nil {
+				Expect(files).To(BeEmpty())
+			}
+		})
+	})
+
+	Describe("Clear", func() {
+		It("removes every cached response", func() {
+			_, err := marketplace.GetProduct(context.Background(), "my-super-product")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cache.Clear()).To(Succeed())
+			Expect(dir).ToNot(BeADirectory())
+
+			_, err = marketplace.GetProduct(context.Background(), "my-super-product")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(httpClient.SendRequestCallCount()).To(Equal(2))
+		})
+
+		When("the cache directory is shared with other files", func() {
+			It("only removes the cached responses", func() {
+				foreign := filepath.Join(dir, "settings.json")
+				Expect(os.WriteFile(foreign, []byte(`{"products":["my-super-product"]}`), 0600)).To(Succeed())
+				_, err := marketplace.GetProduct(context.Background(), "my-super-product")
+				Expect(err).ToNot(HaveOccurred())
+
+				Expect(cache.Invalidate("my-super-product")).To(Succeed())
+				Expect(foreign).To(BeAnExistingFile())
+
+				_, err = marketplace.GetProduct(context.Background(), "my-super-product")
+				Expect(err).ToNot(HaveOccurred())
+				Expect(cache.Clear()).To(Succeed())
+				Expect(foreign).To(BeAnExistingFile())
+				files, err := os.ReadDir(dir)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(files).To(HaveLen(1))
+			})
+		})
+	})
+})
@@ -0,0 +1,132 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: BSD-2-Clause
+
+package pkg
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy decides which failed requests are sent again, and how long to wait before each attempt
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first. Less than two disables retrying.
+	MaxAttempts int
+
+	// The backoff starts at InitialBackoff and doubles after each attempt, up to MaxBackoff
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+
+	// MaxRetryAfter is the longest wait that the server can ask for with the Retry-After header.
+	// If it asks to wait longer, the request is not sent again. Zero means no limit.
+	MaxRetryAfter time.Duration
+
+	// RetryWrites allows retrying requests that might change something, like POST and PUT.
+	// Only enable this if sending the same request twice is safe.
+	RetryWrites bool
+}
+
+// CanRetry is true if requests with the given method may be sent again
+func (p *RetryPolicy) CanRetry(method string) bool {
+	if p == nil || p.MaxAttempts < 2 {
+		return false
+	}
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return p.RetryWrites
+}
+
+// ShouldRetry is true if the request failed in a way that might succeed if sent again:
+// a connection error, too many requests, or a server error
+func ShouldRetry(resp *http.Response, err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, ErrNotRecorded) {
+		return false
+	}
+	if err != nil {
+		return true
+	}
+	if resp == nil {
+		return false
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	return resp.StatusCode >= 500 && resp.StatusCode != http.StatusNotImplemented
+}
+
+// Backoff is how long to wait after the given attempt, starting at 1.
+// The wait is jittered to between half and all of the exponential backoff, so that clients do not retry in lockstep.
+func (p *RetryPolicy) Backoff(attempt int) time.Duration {
+	backoff := p.InitialBackoff
+	for i := 1; i < attempt && backoff < p.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	if p.MaxBackoff > 0 && backoff > p.MaxBackoff {
+		backoff = p.MaxBackoff
+	}
+	if backoff <= 0 {
+		return 0
+	}
+
+	half := backoff / 2
+	return half + time.Duration(rand.Int63n(int64(backoff-half)+1))
+}
+
+// Wait is how long to wait before sending the request again. If the server asked to wait, with the Retry-After header,
+// that is used instead of the backoff. False means the server asked to wait longer than MaxRetryAfter, so the
+// request should not be sent again.
+func (p *RetryPolicy) Wait(attempt int, resp *http.Response) (time.Duration, bool) {
+	if resp != nil {
+		if retryAfter, ok := ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+			if p.MaxRetryAfter > 0 && retryAfter > p.MaxRetryAfter {
+				return 0, false
+			}
+			return retryAfter, true
+		}
+	}
+	return p.Backoff(attempt), true
+}
+
+//go:generate counterfeiter . SleepFunc
+type SleepFunc func(ctx context.Context, duration time.Duration) error
+
+// Sleep waits for the duration, or until the context is done
+func Sleep(ctx context.Context, duration time.Duration) error {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// ParseRetryAfter reads a Retry-After header, which is either a number of seconds or an HTTP date
+func ParseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		wait := date.Sub(now)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+	return 0, false
+}
@@ -0,0 +1,139 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: BSD-2-Clause
+
+package pkg_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/vmware-labs/marketplace-cli/v2/pkg"
+)
+
+var _ = Describe("RetryPolicy", func() {
+	var policy *pkg.RetryPolicy
+
+	BeforeEach(func() {
+		policy = &pkg.RetryPolicy{
+			MaxAttempts:    3,
+			InitialBackoff: time.Second,
+			MaxBackoff:     5 * time.Second,
+			MaxRetryAfter:  time.Minute,
+		}
+	})
+
+	Describe("CanRetry", func() {
+		It("retries reads", func() {
+			Expect(policy.CanRetry("GET")).To(BeTrue())
+			Expect(policy.CanRetry("HEAD")).To(BeTrue())
+			Expect(policy.CanRetry("POST")).To(BeFalse())
+			Expect(policy.CanRetry("PUT")).To(BeFalse())
+		})
+
+		It("retries writes when allowed", func() {
+			policy.RetryWrites = true
+			Expect(policy.CanRetry("POST")).To(BeTrue())
+			Expect(policy.CanRetry("PUT")).To(BeTrue())
+		})
+
+		It("does not retry when there is only one attempt", func() {
+			policy.MaxAttempts = 1
+			Expect(policy.CanRetry("GET")).To(BeFalse())
+
+			var noPolicy *pkg.RetryPolicy
+			Expect(noPolicy.CanRetry("GET")).To(BeFalse())
+		})
+	})
+
+	Describe("ShouldRetry", func() {
+		It("retries connection errors, too many requests, and server errors", func() {
+			Expect(pkg.ShouldRetry(nil, errors.New("connection refused"))).To(BeTrue())
+			Expect(pkg.ShouldRetry(&http.Response{StatusCode: http.StatusTooManyRequests}, nil)).To(BeTrue())
+			Expect(pkg.ShouldRetry(&http.Response{StatusCode: http.StatusBadGateway}, nil)).To(BeTrue())
+			Expect(pkg.ShouldRetry(&http.Response{StatusCode: http.StatusServiceUnavailable}, nil)).To(BeTrue())
+		})
+
+		It("does not retry other responses", func() {
+			Expect(pkg.ShouldRetry(&http.Response{StatusCode: http.StatusOK}, nil)).To(BeFalse())
+			Expect(pkg.ShouldRetry(&http.Response{StatusCode: http.StatusNotFound}, nil)).To(BeFalse())
+			Expect(pkg.ShouldRetry(&http.Response{StatusCode: http.StatusNotImplemented}, nil)).To(BeFalse())
+		})
+
+		It("does not retry requests that were not recorded", func() {
+			Expect(pkg.ShouldRetry(nil, fmt.Errorf("%w for GET https://example.com", pkg.ErrNotRecorded))).To(BeFalse())
+		})
+	})
+
+	Describe("Backoff", func() {
+		It("doubles after each attempt, with jitter", func() {
+			Expect(policy.Backoff(1)).To(BeNumerically(">=", 500*time.Millisecond))
+			Expect(policy.Backoff(1)).To(BeNumerically("<=", time.Second))
+			Expect(policy.Backoff(2)).To(BeNumerically(">=", time.Second))
+			Expect(policy.Backoff(2)).To(BeNumerically("<=", 2*time.Second))
+		})
+
+		It("stops at the maximum backoff", func() {
+			Expect(policy.Backoff(10)).To(BeNumerically(">=", 2500*time.Millisecond))
+			Expect(policy.Backoff(10)).To(BeNumerically("<=", 5*time.Second))
+		})
+	})
+
+	Describe("Wait", func() {
+		It("uses the Retry-After header, even if it is longer than the maximum backoff", func() {
+			wait, ok := policy.Wait(1, &http.Response{Header: http.Header{"Retry-After": []string{"30"}}})
+			Expect(ok).To(BeTrue())
+			Expect(wait).To(Equal(30 * time.Second))
+		})
+
+		It("gives up if Retry-After is longer than the maximum Retry-After", func() {
+			_, ok := policy.Wait(1, &http.Response{Header: http.Header{"Retry-After": []string{"120"}}})
+			Expect(ok).To(BeFalse())
+		})
+
+		It("does not limit Retry-After without a maximum", func() {
+			policy.MaxRetryAfter = 0
+			wait, ok := policy.Wait(1, &http.Response{Header: http.Header{"Retry-After": []string{"120"}}})
+			Expect(ok).To(BeTrue())
+			Expect(wait).To(Equal(2 * time.Minute))
+		})
+
+		It("uses the backoff without a Retry-After header", func() {
+			wait, ok := policy.Wait(1, &http.Response{})
+			Expect(ok).To(BeTrue())
+			Expect(wait).To(BeNumerically("<=", time.Second))
+		})
+	})
+})
+
+var _ = Describe("ParseRetryAfter", func() {
+	now := time.Date(2022, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	It("parses a number of seconds", func() {
+		wait, ok := pkg.ParseRetryAfter("120", now)
+		Expect(ok).To(BeTrue())
+		Expect(wait).To(Equal(2 * time.Minute))
+	})
+
+	It("parses an HTTP date", func() {
+		wait, ok := pkg.ParseRetryAfter("Tue, 01 Mar 2022 12:00:30 GMT", now)
+		Expect(ok).To(BeTrue())
+		Expect(wait).To(Equal(30 * time.Second))
+	})
+
+	It("does not wait for a date in the past", func() {
+		wait, ok := pkg.ParseRetryAfter("Tue, 01 Mar 2022 11:00:00 GMT", now)
+		Expect(ok).To(BeTrue())
+		Expect(wait).To(BeZero())
+	})
+
+	It("ignores invalid values", func() {
+		_, ok := pkg.ParseRetryAfter("soon", now)
+		Expect(ok).To(BeFalse())
+		_, ok = pkg.ParseRetryAfter("", now)
+		Expect(ok).To(BeFalse())
+	})
+})
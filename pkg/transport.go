@@ -0,0 +1,92 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: BSD-2-Clause
+
+package pkg
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// TransportConfig describes how to reach the network, for example from behind a corporate proxy
+type TransportConfig struct {
+	// ResponseTimeout is how long to wait for the server to start responding. No limit if 0
+	ResponseTimeout time.Duration
+	// ProxyURL is the proxy to send all requests through. If empty, the HTTPS_PROXY and NO_PROXY environment variables are used
+	ProxyURL string
+	// CABundle is a PEM file with certificate authorities to trust, in addition to the system ones
+	CABundle string
+	// ClientCert and ClientKey are PEM files with the certificate to present to servers that ask for one
+	ClientCert string
+	ClientKey  string
+	// InsecureSkipVerify turns off checking server certificates. Only for debugging
+	InsecureSkipVerify bool
+}
+
+// NewHTTPClient makes an HTTP client from the transport config. Reading the response body, like a large download,
+// is not limited by the response timeout.
+func NewHTTPClient(config *TransportConfig) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.ResponseHeaderTimeout = config.ResponseTimeout
+
+	if config.ProxyURL != "" {
+		proxyURL, err := url.Parse(config.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL %q: %w", config.ProxyURL, err)
+		}
+		if proxyURL.Scheme == "" || proxyURL.Host == "" {
+			return nil, fmt.Errorf("invalid proxy URL %q: must include the scheme and host, like http://proxy.example.com:8080", config.ProxyURL)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	tlsConfig, err := config.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+	transport.TLSClientConfig = tlsConfig
+
+	return &http.Client{Transport: transport}, nil
+}
+
+func (config *TransportConfig) tlsConfig() (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: config.InsecureSkipVerify,
+	}
+
+	if config.CABundle != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+
+		bundle, err := os.ReadFile(config.CABundle)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the CA bundle %s: %w", config.CABundle, err)
+		}
+		if !pool.AppendCertsFromPEM(bundle) {
+			return nil, fmt.Errorf("no certificates found in the CA bundle %s", config.CABundle)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if config.ClientCert != "" || config.ClientKey != "" {
+		if config.ClientCert == "" || config.ClientKey == "" {
+			return nil, errors.New("both a client certificate and a client key are needed")
+		}
+		certificate, err := tls.LoadX509KeyPair(config.ClientCert, config.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load the client certificate %s: %w", config.ClientCert, err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	return tlsConfig, nil
+}
@@ -0,0 +1,209 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: BSD-2-Clause
+
+package pkg_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/vmware-labs/marketplace-cli/v2/pkg"
+)
+
+func writePEM(path, blockType string, data []byte) {
+	err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: data}), 0600)
+	Expect(err).ToNot(HaveOccurred())
+}
+
+func makeClientCertificate(certPath, keyPath string) *x509.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	Expect(err).ToNot(HaveOccurred())
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "mkpcli-test-client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		IsCA:         true,
+
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	Expect(err).ToNot(HaveOccurred())
+	certificate, err := x509.ParseCertificate(der)
+	Expect(err).ToNot(HaveOccurred())
+
+	keyData, err := x509.MarshalECPrivateKey(key)
+	Expect(err).ToNot(HaveOccurred())
+
+	writePEM(certPath, "CERTIFICATE", der)
+	writePEM(keyPath, "EC PRIVATE KEY", keyData)
+	return certificate
+}
+
+var _ = Describe("NewHTTPClient", func() {
+	var (
+		dir    string
+		server *httptest.Server
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "mkpcli-transport-test")
+		Expect(err).ToNot(HaveOccurred())
+
+		server = httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	It("does not trust unknown certificate authorities", func() {
+		server.StartTLS()
+		client, err := pkg.NewHTTPClient(&pkg.TransportConfig{})
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = client.Get(server.URL)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("certificate"))
+	})
+
+	Context("a CA bundle is given", func() {
+		It("trusts the certificate authorities in the bundle", func() {
+			server.StartTLS()
+			caBundle := filepath.Join(dir, "ca.pem")
+			writePEM(caBundle, "CERTIFICATE", server.Certificate().Raw)
+
+			client, err := pkg.NewHTTPClient(&pkg.TransportConfig{CABundle: caBundle})
+			Expect(err).ToNot(HaveOccurred())
+
+			response, err := client.Get(server.URL)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.StatusCode).To(Equal(http.StatusOK))
+		})
+
+		When("the CA bundle does not exist", func() {
+			It("returns an error", func() {
+				_, err := pkg.NewHTTPClient(&pkg.TransportConfig{CABundle: filepath.Join(dir, "missing.pem")})
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(HavePrefix("failed to read the CA bundle " + filepath.Join(dir, "missing.pem")))
+			})
+		})
+
+		When("the CA bundle has no certificates", func() {
+			It("returns an error", func() {
+				caBundle := filepath.Join(dir, "ca.pem")
+				Expect(os.WriteFile(caBundle, []byte("not a certificate"), 0600)).To(Succeed())
+
+				_, err := pkg.NewHTTPClient(&pkg.TransportConfig{CABundle: caBundle})
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("no certificates found in the CA bundle " + caBundle))
+			})
+		})
+	})
+
+	Context("insecure skip verify is set", func() {
+		It("does not check the server certificate", func() {
+			server.StartTLS()
+			client, err := pkg.NewHTTPClient(&pkg.TransportConfig{InsecureSkipVerify: true})
+			Expect(err).ToNot(HaveOccurred())
+
+			response, err := client.Get(server.URL)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.StatusCode).To(Equal(http.StatusOK))
+		})
+	})
+
+	Context("a client certificate is given", func() {
+		var certPath, keyPath string
+
+		BeforeEach(func() {
+			certPath = filepath.Join(dir, "client.pem")
+			keyPath = filepath.Join(dir, "client-key.pem")
+			certificate := makeClientCertificate(certPath, keyPath)
+
+			clientCAs := x509.NewCertPool()
+			clientCAs.AddCert(certificate)
+			server.TLS = &tls.Config{
+				ClientAuth: tls.RequireAndVerifyClientCert,
+				ClientCAs:  clientCAs,
+			}
+			server.StartTLS()
+		})
+
+		It("presents the certificate to the server", func() {
+			client, err := pkg.NewHTTPClient(&pkg.TransportConfig{
+				ClientCert:         certPath,
+				ClientKey:          keyPath,
+				InsecureSkipVerify: true,
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			response, err := client.Get(server.URL)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.StatusCode).To(Equal(http.StatusOK))
+		})
+
+		When("the client key is missing", func() {
+			It("returns an error", func() {
+				_, err := pkg.NewHTTPClient(&pkg.TransportConfig{ClientCert: certPath})
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("both a client certificate and a client key are needed"))
+			})
+		})
+
+		When("the client certificate cannot be loaded", func() {
+			It("returns an error", func() {
+				_, err := pkg.NewHTTPClient(&pkg.TransportConfig{ClientCert: keyPath, ClientKey: keyPath})
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(HavePrefix("failed to load the client certificate " + keyPath))
+			})
+		})
+	})
+
+	Context("a proxy is given", func() {
+		It("sends requests through the proxy", func() {
+			var proxiedRequest *http.Request
+			server.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				proxiedRequest = r
+				w.WriteHeader(http.StatusOK)
+			})
+			server.Start()
+
+			client, err := pkg.NewHTTPClient(&pkg.TransportConfig{ProxyURL: server.URL})
+			Expect(err).ToNot(HaveOccurred())
+
+			response, err := client.Get("http://marketplace.example.com/api/v1/products")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(response.StatusCode).To(Equal(http.StatusOK))
+			Expect(proxiedRequest.URL.String()).To(Equal("http://marketplace.example.com/api/v1/products"))
+		})
+
+		When("the proxy URL is not valid", func() {
+			It("returns an error", func() {
+				_, err := pkg.NewHTTPClient(&pkg.TransportConfig{ProxyURL: "proxy.example.com:8080"})
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring("invalid proxy URL \"proxy.example.com:8080\""))
+			})
+		})
+	})
+})
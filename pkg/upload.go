@@ -4,6 +4,7 @@
 package pkg
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -25,13 +26,35 @@ func (c *CredentialsResponse) AWSCredentials() aws.Credentials {
 		AccessKeyID:     c.AccessID,
 		SecretAccessKey: c.AccessKey,
 		SessionToken:    c.SessionToken,
+		CanExpire:       !c.Expiration.IsZero(),
 		Expires:         c.Expiration,
 	}
 }
 
-func (m *Marketplace) GetUploadCredentials() (*CredentialsResponse, error) {
+// UploadCredentialsProvider starts with the given upload credentials, and fetches new ones whenever the previous
+// ones are about to expire
+func (m *Marketplace) UploadCredentialsProvider(initial *CredentialsResponse) aws.CredentialsProvider {
+	provider := aws.CredentialsProviderFunc(func(ctx context.Context) (aws.Credentials, error) {
+		if initial != nil {
+			credentials := initial
+			initial = nil
+			return credentials.AWSCredentials(), nil
+		}
+
+		credentials, err := m.GetUploadCredentials(ctx)
+		if err != nil {
+			return aws.Credentials{}, err
+		}
+		return credentials.AWSCredentials(), nil
+	})
+	return aws.NewCredentialsCache(provider, func(options *aws.CredentialsCacheOptions) {
+		options.ExpiryWindow = time.Minute
+	})
+}
+
+func (m *Marketplace) GetUploadCredentials(ctx context.Context) (*CredentialsResponse, error) {
 	requestURL := MakeURL(m.GetAPIHost(), "/aws/credentials/generate", nil)
-	response, err := m.Client.Get(requestURL)
+	response, err := m.Client.Get(ctx, requestURL)
 	if err != nil {
 		return nil, err
 	}
@@ -53,13 +76,41 @@ func (m *Marketplace) GetUploadCredentials() (*CredentialsResponse, error) {
 	return credsResponse, nil
 }
 
-func (m *Marketplace) GetUploader(orgID string) (internal.Uploader, error) {
+// GetStorageBucketRegion asks S3 which region the storage bucket is in.
+// The request is sent directly, so that the CSP access token is not sent to S3.
+func (m *Marketplace) GetStorageBucketRegion(ctx context.Context) (string, error) {
+	requestURL := MakeURL(fmt.Sprintf("%s.s3.amazonaws.com", m.StorageBucket), "", nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, requestURL.String(), nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to build %s request: %w", requestURL.String(), err)
+	}
+
+	response, err := m.Client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to look up the storage bucket %s: %w", m.StorageBucket, err)
+	}
+	if response.Body != nil {
+		_ = response.Body.Close()
+	}
+
+	if response.StatusCode == http.StatusNotFound {
+		return "", fmt.Errorf("the storage bucket %s does not exist", m.StorageBucket)
+	}
+
+	region := response.Header.Get("x-amz-bucket-region")
+	if region == "" {
+		return "", fmt.Errorf("failed to look up the region of the storage bucket %s: %s", m.StorageBucket, response.Status)
+	}
+	return region, nil
+}
+
+func (m *Marketplace) GetUploader(ctx context.Context, orgID string) (internal.Uploader, error) {
 	if m.uploader == nil {
-		credentials, err := m.GetUploadCredentials()
+		credentials, err := m.GetUploadCredentials(ctx)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get upload credentials: %w", err)
 		}
-		client := internal.NewS3Client(m.StorageRegion, credentials.AWSCredentials())
+		client := internal.NewS3Client(m.StorageRegion, m.UploadCredentialsProvider(credentials), m.StorageClient)
 		return internal.NewS3Uploader(m.StorageBucket, m.StorageRegion, orgID, client, m.Output), nil
 	}
 	return m.uploader, nil
@@ -4,6 +4,7 @@
 package pkg_test
 
 import (
+	"context"
 	"errors"
 	"net/http"
 	"time"
@@ -43,7 +44,7 @@ var _ = Describe("Upload", func() {
 		})
 
 		It("gets the credentials", func() {
-			creds, err := marketplace.GetUploadCredentials()
+			creds, err := marketplace.GetUploadCredentials(context.Background())
 			Expect(err).ToNot(HaveOccurred())
 			Expect(creds.AccessID).To(Equal("my-access-id"))
 			Expect(creds.AccessKey).To(Equal("my-access-key"))
@@ -51,7 +52,7 @@ var _ = Describe("Upload", func() {
 
 			By("requesting the creds from the Marketplace", func() {
 				Expect(httpClient.GetCallCount()).To(Equal(1))
-				url := httpClient.GetArgsForCall(0)
+				_, url := httpClient.GetArgsForCall(0)
 				Expect(url.String()).To(Equal("https://marketplace.api.example.com/aws/credentials/generate"))
 			})
 		})
@@ -61,7 +62,7 @@ var _ = Describe("Upload", func() {
 				httpClient.GetReturns(nil, errors.New("get credentials failed"))
 			})
 			It("returns an error", func() {
-				_, err := marketplace.GetUploadCredentials()
+				_, err := marketplace.GetUploadCredentials(context.Background())
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(Equal("get credentials failed"))
 			})
@@ -75,7 +76,7 @@ var _ = Describe("Upload", func() {
 			})
 
 			It("returns an error", func() {
-				_, err := marketplace.GetUploadCredentials()
+				_, err := marketplace.GetUploadCredentials(context.Background())
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(Equal("failed to fetch credentials: 418"))
 			})
@@ -86,13 +87,46 @@ var _ = Describe("Upload", func() {
 				httpClient.GetReturns(MakeStringResponse("this is not valid json"), nil)
 			})
 			It("returns an error", func() {
-				_, err := marketplace.GetUploadCredentials()
+				_, err := marketplace.GetUploadCredentials(context.Background())
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(Equal("invalid character 'h' in literal true (expecting 'r')"))
 			})
 		})
 	})
 
+	Describe("UploadCredentialsProvider", func() {
+		It("fetches new credentials when the previous ones expire", func() {
+			httpClient.GetReturns(MakeJSONResponse(&pkg.CredentialsResponse{
+				AccessID:   "my-new-access-id",
+				AccessKey:  "my-new-access-key",
+				Expiration: time.Now().Add(time.Hour),
+			}), nil)
+
+			provider := marketplace.UploadCredentialsProvider(&pkg.CredentialsResponse{
+				AccessID:   "my-access-id",
+				AccessKey:  "my-access-key",
+				Expiration: time.Now().Add(30 * time.Second),
+			})
+			creds, err := provider.Retrieve(context.Background())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(creds.AccessKeyID).To(Equal("my-access-id"))
+			Expect(httpClient.GetCallCount()).To(Equal(0))
+
+			By("refreshing credentials that expire within a minute", func() {
+				creds, err = provider.Retrieve(context.Background())
+				Expect(err).ToNot(HaveOccurred())
+				Expect(creds.AccessKeyID).To(Equal("my-new-access-id"))
+			})
+
+			By("reusing credentials that are still valid", func() {
+				creds, err = provider.Retrieve(context.Background())
+				Expect(err).ToNot(HaveOccurred())
+				Expect(creds.AccessKeyID).To(Equal("my-new-access-id"))
+				Expect(httpClient.GetCallCount()).To(Equal(1))
+			})
+		})
+	})
+
 	Describe("GetUploader", func() {
 		BeforeEach(func() {
 			response := &pkg.CredentialsResponse{
@@ -104,12 +138,12 @@ var _ = Describe("Upload", func() {
 			httpClient.GetReturns(MakeJSONResponse(response), nil)
 		})
 		It("creates an uploader with upload credentials", func() {
-			uploader, err := marketplace.GetUploader("my-org")
+			uploader, err := marketplace.GetUploader(context.Background(), "my-org")
 			Expect(err).ToNot(HaveOccurred())
 
 			By("requesting the upload credentials", func() {
 				Expect(httpClient.GetCallCount()).To(Equal(1))
-				url := httpClient.GetArgsForCall(0)
+				_, url := httpClient.GetArgsForCall(0)
 				Expect(url.String()).To(Equal("https://marketplace.api.example.com/aws/credentials/generate"))
 			})
 			Expect(uploader).ToNot(BeNil())
@@ -120,7 +154,7 @@ var _ = Describe("Upload", func() {
 				httpClient.GetReturns(nil, errors.New("get credentials failed"))
 			})
 			It("returns an error", func() {
-				_, err := marketplace.GetUploader("my-org")
+				_, err := marketplace.GetUploader(context.Background(), "my-org")
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(Equal("failed to get upload credentials: get credentials failed"))
 			})
@@ -133,9 +167,51 @@ var _ = Describe("Upload", func() {
 				marketplace.SetUploader(uploader)
 			})
 			It("returns that uploader", func() {
-				Expect(marketplace.GetUploader("doesn't matter")).To(Equal(uploader))
+				Expect(marketplace.GetUploader(context.Background(), "doesn't matter")).To(Equal(uploader))
 				Expect(httpClient.GetCallCount()).To(Equal(0))
 			})
 		})
 	})
+	Describe("GetStorageBucketRegion", func() {
+		BeforeEach(func() {
+			marketplace.StorageBucket = "my-bucket"
+			httpClient.DoReturns(&http.Response{
+				Status:     http.StatusText(http.StatusForbidden),
+				StatusCode: http.StatusForbidden,
+				Header:     http.Header{"X-Amz-Bucket-Region": []string{"us-west-2"}},
+			}, nil)
+		})
+
+		It("gets the region of the bucket from S3", func() {
+			region, err := marketplace.GetStorageBucketRegion(context.Background())
+			Expect(err).ToNot(HaveOccurred())
+			Expect(region).To(Equal("us-west-2"))
+
+			By("sending the request without the CSP access token", func() {
+				Expect(httpClient.DoCallCount()).To(Equal(1))
+				req := httpClient.DoArgsForCall(0)
+				Expect(req.Method).To(Equal("HEAD"))
+				Expect(req.URL.String()).To(Equal("https://my-bucket.s3.amazonaws.com"))
+				Expect(req.Header.Get("csp-auth-token")).To(BeEmpty())
+			})
+		})
+
+		When("the bucket does not exist", func() {
+			It("returns an error", func() {
+				httpClient.DoReturns(&http.Response{StatusCode: http.StatusNotFound}, nil)
+				_, err := marketplace.GetStorageBucketRegion(context.Background())
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("the storage bucket my-bucket does not exist"))
+			})
+		})
+
+		When("the request fails", func() {
+			It("returns an error", func() {
+				httpClient.DoReturns(nil, errors.New("no route to host"))
+				_, err := marketplace.GetStorageBucketRegion(context.Background())
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(Equal("failed to look up the storage bucket my-bucket: no route to host"))
+			})
+		})
+	})
 })
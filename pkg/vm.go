@@ -4,6 +4,7 @@
 package pkg
 
 import (
+	"context"
 	"fmt"
 	"time"
 
@@ -15,17 +16,17 @@ func makeUniqueFileID() string {
 	return fmt.Sprintf("fileuploader%d.url", now)
 }
 
-func (m *Marketplace) UploadVM(vmFile string, product *models.Product, version *models.Version) (*models.Product, error) {
+func (m *Marketplace) UploadVM(ctx context.Context, vmFile string, product *models.Product, version *models.Version) (*models.Product, error) {
 	hashString, err := Hash(vmFile, models.HashAlgoSHA1)
 	if err != nil {
 		return nil, err
 	}
 
-	uploader, err := m.GetUploader(product.PublisherDetails.OrgId)
+	uploader, err := m.GetUploader(ctx, product.PublisherDetails.OrgId)
 	if err != nil {
 		return nil, err
 	}
-	filename, fileUrl, err := uploader.UploadProductFile(vmFile)
+	filename, fileUrl, err := uploader.UploadProductFile(ctx, vmFile)
 	if err != nil {
 		return nil, err
 	}
@@ -44,5 +45,5 @@ func (m *Marketplace) UploadVM(vmFile string, product *models.Product, version *
 		},
 	}
 
-	return m.PutProduct(product, version.IsNewVersion)
+	return m.PutProduct(ctx, product, version.IsNewVersion)
 }
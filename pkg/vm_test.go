@@ -4,6 +4,7 @@
 package pkg_test
 
 import (
+	"context"
 	"errors"
 	"io/ioutil"
 	"os"
@@ -56,12 +57,12 @@ var _ = Describe("VM", func() {
 			product := test.CreateFakeProduct("", "Hyperspace Database", "hyperspace-database", models.SolutionTypeISO)
 			test.AddVerions(product, "1.2.3")
 
-			updatedProduct, err := marketplace.UploadVM(vmFilePath, product, &models.Version{Number: "1.2.3"})
+			updatedProduct, err := marketplace.UploadVM(context.Background(), vmFilePath, product, &models.Version{Number: "1.2.3"})
 			Expect(err).ToNot(HaveOccurred())
 
 			By("uploading the file", func() {
 				Expect(uploader.UploadProductFileCallCount()).To(Equal(1))
-				uploadedFilePath := uploader.UploadProductFileArgsForCall(0)
+				_, uploadedFilePath := uploader.UploadProductFileArgsForCall(0)
 				Expect(uploadedFilePath).To(Equal(vmFilePath))
 			})
 
@@ -83,7 +84,7 @@ var _ = Describe("VM", func() {
 				product := test.CreateFakeProduct("", "Hyperspace Database", "hyperspace-database", models.SolutionTypeISO)
 				test.AddVerions(product, "1.2.3")
 
-				_, err := marketplace.UploadVM("this/file/does/not/exist", product, &models.Version{Number: "1.2.3"})
+				_, err := marketplace.UploadVM(context.Background(), "this/file/does/not/exist", product, &models.Version{Number: "1.2.3"})
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(Equal("failed to open this/file/does/not/exist: open this/file/does/not/exist: no such file or directory"))
 			})
@@ -99,7 +100,7 @@ var _ = Describe("VM", func() {
 				product := test.CreateFakeProduct("", "Hyperspace Database", "hyperspace-database", models.SolutionTypeISO)
 				test.AddVerions(product, "1.2.3")
 
-				_, err := marketplace.UploadVM(vmFilePath, product, &models.Version{Number: "1.2.3"})
+				_, err := marketplace.UploadVM(context.Background(), vmFilePath, product, &models.Version{Number: "1.2.3"})
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(Equal("failed to get upload credentials: get uploader failed"))
 			})
@@ -113,7 +114,7 @@ var _ = Describe("VM", func() {
 				product := test.CreateFakeProduct("", "Hyperspace Database", "hyperspace-database", models.SolutionTypeISO)
 				test.AddVerions(product, "1.2.3")
 
-				_, err := marketplace.UploadVM(vmFilePath, product, &models.Version{Number: "1.2.3"})
+				_, err := marketplace.UploadVM(context.Background(), vmFilePath, product, &models.Version{Number: "1.2.3"})
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(Equal("upload product file failed"))
 			})
@@ -127,7 +128,7 @@ var _ = Describe("VM", func() {
 				product := test.CreateFakeProduct("", "Hyperspace Database", "hyperspace-database", models.SolutionTypeISO)
 				test.AddVerions(product, "1.2.3")
 
-				_, err := marketplace.UploadVM(vmFilePath, product, &models.Version{Number: "1.2.3"})
+				_, err := marketplace.UploadVM(context.Background(), vmFilePath, product, &models.Version{Number: "1.2.3"})
 				Expect(err).To(HaveOccurred())
 				Expect(err.Error()).To(Equal("sending the update for product \"hyperspace-database\" failed: put product failed"))
 			})
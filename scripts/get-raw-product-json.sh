@@ -3,9 +3,16 @@
 # This script wraps around the CLI to get the JSON structure of a product directly from the Marketplace without
 # trying to unmarshal it into the structure.
 # This is helpful in diagnosing times when the Marketplace adds a field that the Marketplace CLI does not know about yet.
+# An optional gjson path picks out part of the product, for example: displayname
 
 if [[ -z "$1" ]]; then
-  echo "USAGE: $0 <product slug>"
+  echo "USAGE: $0 <product slug> [gjson path]"
+  exit 1
 fi
 
-mkpcli curl "/api/v1/products/${1}?increaseViewCount=false&isSlug=true"
+query="response.data"
+if [[ -n "$2" ]]; then
+  query="response.data.${2}"
+fi
+
+mkpcli curl "/api/v1/products/${1}?increaseViewCount=false&isSlug=true" --query "${query}"
@@ -0,0 +1,39 @@
+// Copyright 2022 VMware, Inc.
+// SPDX-License-Identifier: BSD-2-Clause
+
+package features_test
+
+import (
+	. "github.com/bunniesandbeatings/goerkin"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/onsi/gomega/gbytes"
+	. "github.com/vmware-labs/marketplace-cli/v2/test"
+)
+
+var _ = Describe("Replaying recorded requests", func() {
+	steps := NewSteps()
+
+	Scenario("Replaying a product", func() {
+		steps.Given("the environment variable MKPCLI_HOST is set to marketplace.example.com")
+		steps.When("running mkpcli product get --product hyperspace-database --replay fixtures/product-get --output json")
+		steps.Then("the command exits without error")
+		steps.And("the printed configuration has displayname with the value Hyperspace Database")
+		steps.And("the printed configuration has publisherdetails.orgdisplayname with the value Hyperspace Inc.")
+	})
+
+	Scenario("Replaying a request that was not recorded", func() {
+		steps.Given("the environment variable MKPCLI_HOST is set to marketplace.example.com")
+		steps.When("running mkpcli product get --product some-other-product --replay fixtures/product-get")
+		steps.Then("the command exits with an error")
+		steps.And("the missing recording is reported")
+	})
+
+	steps.Define(func(define Definitions) {
+		DefineCommonSteps(define)
+
+		define.Then(`^the missing recording is reported$`, func() {
+			Eventually(CommandSession.Err).Should(Say("no recorded response for GET https://marketplace.example.com/api/v1/products/some-other-product"))
+		})
+	})
+})